@@ -1,26 +1,177 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/wallacegibbon/sysfs-monitor-tui/internal/monitor"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"time"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "find" {
+		if len(os.Args) != 3 {
+			fmt.Fprintln(os.Stderr, "usage: sysfs-check find <pattern>")
+			os.Exit(2)
+		}
+		find(os.Args[2])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		report(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		flags := flag.NewFlagSet("bench", flag.ExitOnError)
+		asJSON := flags.Bool("json", false, "print the latencies as JSON")
+		flags.Parse(os.Args[2:])
+		r := monitor.Bench()
+		if *asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			enc.Encode(r)
+			return
+		}
+		printBench(os.Stdout, r)
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+		sudoHint := flags.Bool("sudo-hint", false, "also list the sensor files that would become readable as root")
+		flags.Parse(os.Args[2:])
+		checks := monitor.Doctor()
+		printDoctor(os.Stdout, checks)
+		if *sudoHint {
+			printSudoHint(os.Stdout, monitor.SudoHint())
+		}
+		if !monitor.DoctorOK(checks) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	groups := flag.String("groups", "", "comma-separated providers to print, e.g. thermal,fans (default: all)")
+	var sensorWait monitor.SensorWait
+	flag.Var(&sensorWait, "wait-for-sensors", "wait up to 30s (or =DURATION) for a temperature or battery to appear before checking")
+	brief := flag.Bool("brief", false, "print only the temperatures and battery line of the compact view")
+	color := flag.Bool("color", false, "color the --brief line as the compact view does")
+	fromSocket := flag.Bool("from-socket", false, "print the --brief line of the running --single-instance monitor instead of reading sysfs")
+	flag.Parse()
+	if sensorWait > 0 && !monitor.WaitForSensors(time.Duration(sensorWait)) {
+		fmt.Fprintf(os.Stderr, "sysfs-check: no sensors appeared within %s\n", time.Duration(sensorWait))
+	}
+	if *fromSocket {
+		printInstanceBrief(*color)
+		return
+	}
+	if *brief {
+		printBrief(*color)
+		return
+	}
+	var names []string
+	if *groups != "" {
+		names = strings.Split(*groups, ",")
+	}
+	checks, err := monitor.CheckProviders(names...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sysfs-check: --groups: %v\n", err)
+		os.Exit(2)
+	}
+	wanted := func(name string) bool {
+		return len(names) == 0 || slices.Contains(names, name)
+	}
+
 	fmt.Println("Testing sysfs monitoring...")
+	if virt := monitor.DetectVirtualization(); virt != "" {
+		fmt.Printf("Running in a virtual machine (%s); hardware sensors are typically unavailable\n", virt)
+	}
+
+	// Failures are reported after the readings that could be taken
+	var failed []error
+	if wanted("thermal") {
+		failed = append(failed, printTemperatures()...)
+	}
+	if wanted("battery") {
+		failed = append(failed, printBattery()...)
+	}
+	failed = append(failed, printGroups(os.Stdout, checks, len(names) > 0)...)
+
+	// Permission failures are expected without root: one line per class
+	gaps, failed := monitor.PermissionGaps(failed...)
+	printPermissionGaps(os.Stdout, gaps)
+
+	if len(failed) > 0 {
+		fmt.Fprintln(os.Stderr, "\nRead failures:")
+		for _, err := range failed {
+			fmt.Fprintf(os.Stderr, "  %v\n", strings.ReplaceAll(err.Error(), "\n", "\n    "))
+		}
+		os.Exit(1)
+	}
+}
+
+// printBrief prints the compact view's summary line, read once
+func printBrief(color bool) {
+	if color {
+		// Piped output would otherwise be detected as colorless
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	}
+	m := monitor.NewMonitor().Refresh()
+	defer m.Close()
+	fmt.Println(monitor.Summary(m.Snapshot(), 0, color))
+}
+
+// printInstanceBrief prints the summary line of the running instance's
+// latest snapshot, falling back to printBrief, with the reason on stderr,
+// when there is none or it is stale
+func printInstanceBrief(color bool) {
+	path := monitor.InstanceSocketPath(monitor.DefaultInstanceDir())
+	snap, err := monitor.ReadInstanceSnapshot(path, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sysfs-check: %v; reading sysfs directly\n", err)
+		printBrief(color)
+		return
+	}
+	if color {
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	}
+	fmt.Println(monitor.Summary(snap, 0, color))
+}
 
-	temps := monitor.ReadTemperatures()
+// printTemperatures prints the temperatures, returning the read failures
+func printTemperatures() []error {
+	temps, err := monitor.ReadTemperaturesE()
 	fmt.Printf("Found %d temperature sensors:\n", len(temps))
 	for _, t := range temps {
+		if t.Asleep {
+			fmt.Printf("  %s: asleep (device runtime suspended, not read)\n", t.Name)
+			continue
+		}
 		fmt.Printf("  %s: %.1f°C (high %.1f, critical %.1f)\n", t.Name, t.Value, t.High, t.Critical)
 	}
+	return sectionErrors("temperatures", err)
+}
 
-	battery := monitor.ReadBatteryStatus()
+// printBattery prints the battery, returning the read failures
+func printBattery() []error {
+	battery, err := monitor.ReadBatteryStatusE()
 	fmt.Printf("\nBattery status:\n")
 	if battery.Capacity == 0 && battery.Status == "" {
 		fmt.Println("  No battery information")
 	} else {
 		fmt.Printf("  Capacity: %d%%\n", battery.Capacity)
+		if battery.CapacitySuspect {
+			fmt.Printf("  Suspect capacity reading: raw %d\n", battery.RawCapacity)
+		}
 		fmt.Printf("  Status: %s\n", battery.Status)
+		fmt.Printf("  AC: %s\n", battery.ACDescription())
 		if battery.Voltage > 0 {
 			fmt.Printf("  Voltage: %.2fV\n", battery.Voltage)
 		}
@@ -43,4 +194,235 @@ func main() {
 			fmt.Printf("  Capacity Level: %s\n", battery.CapacityLevel)
 		}
 	}
+	return sectionErrors("battery", err)
+}
+
+// sectionErrors prefixes each error err joins with the section it came
+// from, so that monitor.PermissionGaps can tell them apart
+func sectionErrors(section string, err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, err := range joined.Unwrap() {
+			errs = append(errs, sectionErrors(section, err)...)
+		}
+		return errs
+	}
+	return []error{fmt.Errorf("%s: %w", section, err)}
+}
+
+// printGroups prints every group the providers discovered, one line per
+// sensor with its state when not ok, returning the discovery and read
+// failures. With named set, providers that found nothing say so. Readings
+// hidden for lack of permission are left out, their failures returned.
+func printGroups(w io.Writer, checks []monitor.ProviderCheck, named bool) []error {
+	var failed []error
+	for _, check := range checks {
+		failed = append(failed, sectionErrors(check.Provider, check.Err)...)
+		failed = append(failed, check.Denied...)
+		if len(check.Groups) == 0 && named {
+			fmt.Fprintf(w, "\n%s: nothing found\n", check.Provider)
+		}
+		for _, group := range check.Groups {
+			fmt.Fprintf(w, "\n%s (%s):\n", group.Name, check.Provider)
+			width := 0
+			for _, r := range group.Readings {
+				width = max(width, len(r.Name))
+			}
+			for _, r := range group.Readings {
+				if r.Denied {
+					continue
+				}
+				line := fmt.Sprintf("  %-*s  %s", width+1, r.Name+":", r.Value)
+				if r.State != monitor.StateOK {
+					line += " [" + r.State.String() + "]"
+				}
+				fmt.Fprintln(w, strings.TrimRight(line, " "))
+				if r.Err != "" {
+					failed = append(failed, fmt.Errorf("%s/%s: %s", group.Name, r.Name, r.Err))
+				}
+			}
+		}
+	}
+	return failed
+}
+
+// printPermissionGaps prints one line per class of sensors that couldn't be
+// read for lack of permission
+func printPermissionGaps(w io.Writer, gaps []monitor.PermissionGap) {
+	if len(gaps) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\nDegraded mode:")
+	for _, gap := range gaps {
+		fmt.Fprintf(w, "  %s\n", gap)
+	}
+	fmt.Fprintln(w, "Run `sysfs-check doctor --sudo-hint` to list the files.")
+}
+
+// printSudoHint lists the files root could read
+func printSudoHint(w io.Writer, paths []string) {
+	if len(paths) == 0 {
+		fmt.Fprintln(w, "\nNo sensor file needs root.")
+		return
+	}
+	fmt.Fprintln(w, "\nReadable as root:")
+	for _, path := range paths {
+		fmt.Fprintf(w, "  %s\n", path)
+	}
+}
+
+// find prints the attributes matching pattern and how discovery treats them
+func find(pattern string) {
+	matches := monitor.FindAttributes(pattern)
+	if len(matches) == 0 {
+		fmt.Printf("No attributes match %q\n", pattern)
+		return
+	}
+	for _, m := range matches {
+		fmt.Printf("/sys/%s = %s\n    %s\n", m.Path, m.Value, m.Status)
+	}
+}
+
+// printDoctor prints one line per environment check, followed by the fix
+// of those that didn't pass
+func printDoctor(w io.Writer, checks []monitor.DoctorCheck) {
+	width := 0
+	for _, check := range checks {
+		width = max(width, len(check.Name))
+	}
+	for _, check := range checks {
+		fmt.Fprintf(w, "%-4s  %-*s  %s\n", check.Status, width, check.Name, check.Detail)
+		if check.Status != monitor.DoctorPass && check.Fix != "" {
+			fmt.Fprintf(w, "      %-*s  fix: %s\n", width, "", check.Fix)
+		}
+	}
+	if monitor.DoctorOK(checks) {
+		fmt.Fprintln(w, "\nThe monitor has readings to show.")
+	} else {
+		fmt.Fprintln(w, "\nThe monitor would show nothing useful; see the failed checks.")
+	}
+}
+
+// printBench prints the read latencies of each file and chip, flagging
+// the slow ones, and the interval they allow
+func printBench(w io.Writer, r monitor.BenchResult) {
+	if len(r.Files) == 0 {
+		fmt.Fprintln(w, "No sensor attributes found")
+		return
+	}
+	width := len("File")
+	for _, file := range r.Files {
+		width = max(width, len(file.Path))
+	}
+	chipWidth := len("Chip")
+	for _, chip := range r.Chips {
+		chipWidth = max(chipWidth, len(chip.Name))
+	}
+	fmt.Fprintf(w, "%-*s  %-*s  %5s  %8s  %8s  %8s\n", width, "File", chipWidth, "Chip", "Reads", "p50", "p95", "Max")
+	for _, file := range r.Files {
+		fmt.Fprintf(w, "%-*s  %-*s  %5d  %8s  %8s  %8s", width, file.Path, chipWidth, file.Chip, file.Reads,
+			latency(file.P50), latency(file.P95), latency(file.Max))
+		if file.Slow() {
+			fmt.Fprintf(w, "  slow")
+		}
+		if file.Err != "" {
+			fmt.Fprintf(w, "  %s", file.Err)
+		} else if file.Capped {
+			fmt.Fprintf(w, "  capped")
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "\n%-*s  %5s  %8s  %8s  %8s\n", chipWidth, "Chip", "Files", "p50", "p95", "Max")
+	for _, chip := range r.Chips {
+		fmt.Fprintf(w, "%-*s  %5d  %8s  %8s  %8s", chipWidth, chip.Name, chip.Files, latency(chip.P50), latency(chip.P95), latency(chip.Max))
+		if chip.Slow > 0 {
+			fmt.Fprintf(w, "  %d slow", chip.Slow)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "\nA refresh spends about %s reading %d files; use an --interval of %s or more.\n",
+		latency(r.Refresh), len(r.Files), r.Interval)
+	for _, chip := range r.Chips {
+		if chip.Slow > 0 {
+			fmt.Fprintf(w, "Reads above %s are flagged slow; the slow chips hold up every refresh.\n", monitor.BenchSlowRead)
+			break
+		}
+	}
+}
+
+// latency formats a read time in milliseconds, e.g. "0.04ms", or in
+// seconds from one second
+func latency(d time.Duration) string {
+	if d >= time.Second {
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+	return fmt.Sprintf("%.2fms", float64(d)/float64(time.Millisecond))
+}
+
+// report summarizes the history file written by `sysfs-monitor-tui --history`
+func report(args []string) {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	since := flags.String("since", "24h", "start of the report: a duration back from now (24h, 7d) or an RFC 3339 time")
+	path := flags.String("history", monitor.DefaultHistoryPath(), "history file to read")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	flags.Parse(args)
+
+	start, err := monitor.ParseSince(*since, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sysfs-check report: --since: %v\n", err)
+		os.Exit(2)
+	}
+	records, err := monitor.ReadHistory(*path, start)
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Printf("No history at %s; start the monitor with --history to record one\n", *path)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sysfs-check report: %v\n", err)
+		os.Exit(1)
+	}
+	if len(records) == 0 {
+		fmt.Printf("No readings in %s since %s\n", *path, start.Format(time.RFC3339))
+		os.Exit(1)
+	}
+
+	r := monitor.NewReport(records)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(r)
+		return
+	}
+	fmt.Printf("Report from %s to %s (%d readings)\n", r.From.Format("2006-01-02 15:04"), r.To.Format("2006-01-02 15:04"), r.Records)
+	if len(r.Sensors) > 0 {
+		fmt.Printf("\n%-24s %8s %8s %8s %10s %10s %10s %9s\n", "Sensor", "Min", "Avg", "Max", "OK", "Warning", "Critical", "Crossings")
+		for _, s := range r.Sensors {
+			fmt.Printf("%-24s %6.1f°C %6.1f°C %6.1f°C %10s %10s %10s %9d\n", s.Name, s.Min, s.Avg, s.Max,
+				seconds(s.OKSeconds), seconds(s.WarningSeconds), seconds(s.CriticalSeconds), s.Crossings)
+		}
+	}
+	if b := r.Battery; b != nil {
+		fmt.Printf("\nBattery:\n")
+		fmt.Printf("  Capacity: %d%% to %d%% (deepest discharge %d%%)\n", b.MinCapacity, b.MaxCapacity, b.MinCapacity)
+		fmt.Printf("  Charge sessions: %d (%.2f full cycles discharged)\n", b.ChargeSessions, b.FullCycles)
+		if b.DrainPerHour > 0 {
+			fmt.Printf("  Average drain: %.1f%%/h", b.DrainPerHour)
+			if b.DischargePower > 0 {
+				fmt.Printf(" (%.1f W)", b.DischargePower)
+			}
+			fmt.Println()
+		}
+		if b.Energy != nil {
+			fmt.Printf("  Energy: %s\n", b.Energy)
+		}
+	}
+}
+
+// seconds formats a time spent in a state, e.g. "12m0s"
+func seconds(s float64) string {
+	return time.Duration(s * float64(time.Second)).Round(time.Second).String()
 }
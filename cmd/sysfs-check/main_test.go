@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wallacegibbon/sysfs-monitor-tui/internal/monitor"
+)
+
+// TestMain registers the fake provider once: the registry is global and
+// rejects a second registration, which -count=2 would otherwise make
+func TestMain(m *testing.M) {
+	monitor.RegisterProvider(monitor.NewProvider("fake-check", func(string) ([]monitor.SensorGroup, error) {
+		return []monitor.SensorGroup{{Name: "Fake", Sensors: []monitor.Sensor{
+			monitor.NewGenericSensor("pump", func() (string, bool, bool, error) { return "2100 RPM", false, false, nil }),
+			monitor.NewGenericSensor("coolant", func() (string, bool, bool, error) { return "48°C", true, false, nil }),
+			monitor.NewGenericSensor("flow", func() (string, bool, bool, error) { return "", false, false, errors.New("timed out") }),
+		}}}, nil
+	}))
+	os.Exit(m.Run())
+}
+
+func TestPrintGroupsFromRegisteredProvider(t *testing.T) {
+	checks, err := monitor.CheckProviders("fake-check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	failed := printGroups(&sb, checks, true)
+	want := `
+Fake (fake-check):
+  pump:     2100 RPM
+  coolant:  48°C [warning]
+  flow:
+`
+	if sb.String() != want {
+		t.Errorf("unexpected output:\n%q\nwant:\n%q", sb.String(), want)
+	}
+	if len(failed) != 1 || failed[0].Error() != "Fake/flow: timed out" {
+		t.Errorf("expected the failed read, got %v", failed)
+	}
+}
+
+func TestPrintDoctor(t *testing.T) {
+	var sb strings.Builder
+	printDoctor(&sb, []monitor.DoctorCheck{
+		{Name: "sysfs mounted", Detail: "/sys/class is present"},
+		{Name: "readable sensors", Status: monitor.DoctorFail, Detail: "no temperature or battery could be read", Fix: "load the sensor drivers"},
+	})
+	want := `pass  sysfs mounted     /sys/class is present
+FAIL  readable sensors  no temperature or battery could be read
+                        fix: load the sensor drivers
+
+The monitor would show nothing useful; see the failed checks.
+`
+	if sb.String() != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
+
+func TestPrintPermissionGaps(t *testing.T) {
+	denied := &fs.PathError{Op: "open", Path: "/sys/class/powercap/intel-rapl:0/energy_uj", Err: fs.ErrPermission}
+	failed := sectionErrors("temperatures", errors.Join(errors.New("temp3_input: bad value"), denied))
+	gaps, failed := monitor.PermissionGaps(failed...)
+	if len(failed) != 1 || failed[0].Error() != "temperatures: temp3_input: bad value" {
+		t.Errorf("expected the other failure kept, got %v", failed)
+	}
+	var sb strings.Builder
+	printPermissionGaps(&sb, gaps)
+	want := "\nDegraded mode:\n  powercap: needs root — 1 sensor unavailable\nRun `sysfs-check doctor --sudo-hint` to list the files.\n"
+	if sb.String() != want {
+		t.Errorf("unexpected output:\n%q\nwant:\n%q", sb.String(), want)
+	}
+}
+
+func TestPrintBench(t *testing.T) {
+	var sb strings.Builder
+	printBench(&sb, monitor.BenchResult{
+		Files: []monitor.BenchFile{
+			{Path: "class/thermal/thermal_zone0/temp", Chip: "acpitz", Reads: 20,
+				Latencies: monitor.Latencies{P50: 40 * time.Microsecond, P95: 60 * time.Microsecond, Max: 90 * time.Microsecond}},
+			{Path: "class/hwmon/hwmon3/fan1_input", Chip: "thinkpad", Reads: 20,
+				Latencies: monitor.Latencies{P50: 55 * time.Millisecond, P95: 80 * time.Millisecond, Max: 120 * time.Millisecond}},
+			{Path: "class/power_supply/BAT0/energy_now", Chip: "BAT0", Reads: 1, Capped: true, Err: "no answer within 2s",
+				Latencies: monitor.Latencies{P50: 2 * time.Second, P95: 2 * time.Second, Max: 2 * time.Second}},
+		},
+		Chips: []monitor.BenchChip{
+			{Name: "acpitz", Files: 1, Latencies: monitor.Latencies{P50: 40 * time.Microsecond, P95: 60 * time.Microsecond, Max: 90 * time.Microsecond}},
+			{Name: "thinkpad", Files: 1, Slow: 1, Latencies: monitor.Latencies{P50: 55 * time.Millisecond, P95: 80 * time.Millisecond, Max: 120 * time.Millisecond}},
+		},
+		Refresh:  80 * time.Millisecond,
+		Interval: 200 * time.Millisecond,
+	})
+	want := `File                                Chip      Reads       p50       p95       Max
+class/thermal/thermal_zone0/temp    acpitz       20    0.04ms    0.06ms    0.09ms
+class/hwmon/hwmon3/fan1_input       thinkpad     20   55.00ms   80.00ms  120.00ms  slow
+class/power_supply/BAT0/energy_now  BAT0          1     2.00s     2.00s     2.00s  slow  no answer within 2s
+
+Chip      Files       p50       p95       Max
+acpitz        1    0.04ms    0.06ms    0.09ms
+thinkpad      1   55.00ms   80.00ms  120.00ms  1 slow
+
+A refresh spends about 80.00ms reading 3 files; use an --interval of 200ms or more.
+Reads above 50ms are flagged slow; the slow chips hold up every refresh.
+`
+	if sb.String() != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
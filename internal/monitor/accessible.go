@@ -0,0 +1,165 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The accessible view is for screen readers and braille displays: strictly
+// linear, one reading per line with its state spelled out, and no emoji,
+// box drawing or colors. The state changes of the last refresh are
+// announced first, so they are read before the readings.
+
+// WithAccessible replaces the views with the accessible one
+func WithAccessible() Option {
+	return func(m *Monitor) {
+		m.accessible = true
+	}
+}
+
+// spokenUnits replaces the unit symbols of group readings by words
+var spokenUnits = strings.NewReplacer("°C", " degrees", "°F", " degrees Fahrenheit", "%", " percent", "…", "")
+
+// RenderAccessible renders a snapshot as plain lines, e.g. "CPU package:
+// 64.5 degrees, OK" and "Battery: 15 percent, WARNING, discharging", after
+// a line for each of changes, the events of the last refresh
+func RenderAccessible(snap Snapshot, changes []Event, view ViewState) string {
+	var lines []string
+	for _, event := range changes {
+		lines = append(lines, "Alert: "+spokenEvent(event, view))
+	}
+	if snap.Hostname != "" {
+		host := "Host: " + snap.Hostname
+		if snap.Demo {
+			host += ", demo data"
+		}
+		lines = append(lines, host)
+	}
+
+	if len(snap.Temperatures) == 0 {
+		lines = append(lines, "No temperature sensors")
+	}
+	for _, sensor := range snap.Temperatures {
+		lines = append(lines, sensor.Name+": "+spokenTemperature(sensor, view))
+	}
+
+	if bat := snap.Battery; bat.Present() {
+		words := []string{fmt.Sprintf("%d percent", bat.Capacity), strings.ToUpper(snap.BatteryCapacityState.String())}
+		if bat.Status != "" {
+			words = append(words, strings.ToLower(bat.Status))
+		}
+		if state := BatteryHealthState(bat.Health); state != StateOK {
+			words = append(words, "health "+strings.ToLower(bat.Health))
+		}
+		if snap.AdapterUnderpowered {
+			words = append(words, "adapter underpowered")
+		}
+		lines = append(lines, "Battery: "+strings.Join(words, ", "))
+	}
+
+	for _, group := range snap.Groups {
+		for _, reading := range group.Readings {
+			words := []string{view.Numbers.localize(spokenUnits.Replace(reading.Value)), strings.ToUpper(reading.State.String())}
+			if reading.Muted {
+				words = append(words, "muted")
+			}
+			if reading.Frozen {
+				words = append(words, "possibly frozen")
+			}
+			lines = append(lines, fmt.Sprintf("%s, %s: %s", group.Name, reading.Name, strings.Join(words, ", ")))
+		}
+	}
+
+	if view.Status != "" {
+		lines = append(lines, "Status: "+view.Status)
+	}
+	if view.Toast != "" {
+		lines = append(lines, "Notice: "+view.Toast)
+	}
+	updated := "Updated at " + snap.Time.Format("15:04:05")
+	if view.Paused {
+		updated += ", paused"
+	}
+	lines = append(lines, updated)
+	return strings.Join(lines, "\n")
+}
+
+// spokenTemperature is a temperature's value and state in words
+func spokenTemperature(sensor TemperatureSensor, view ViewState) string {
+	if sensor.Asleep {
+		return "asleep, not read"
+	}
+	words := []string{spokenDegrees(sensor.Value, view), strings.ToUpper(sensor.State().String())}
+	if view.Unit == Raw && sensor.Raw != "" {
+		words = append(words, "raw "+sensor.Raw)
+	}
+	if sensor.Stale {
+		words = append(words, "stale")
+	}
+	if sensor.Muted {
+		words = append(words, "muted")
+	}
+	if sensor.Frozen {
+		words = append(words, "possibly frozen")
+	}
+	return strings.Join(words, ", ")
+}
+
+// spokenDegrees is a Celsius value in the unit, in words
+func spokenDegrees(celsius float64, view ViewState) string {
+	value := view.Numbers.localize(fmt.Sprintf("%.1f", view.Unit.convert(celsius)))
+	switch view.Unit {
+	case Fahrenheit:
+		return value + " degrees Fahrenheit"
+	case Kelvin:
+		return value + " kelvin"
+	}
+	return value + " degrees"
+}
+
+// spokenEvent describes an event, e.g. "CPU package changed from OK to
+// WARNING, 85.0 degrees"
+func spokenEvent(event Event, view ViewState) string {
+	switch event.Type {
+	case EventProfileChanged:
+		return fmt.Sprintf("profile changed from %s to %s", event.Profile.From, event.Profile.To)
+	case EventSensorAdded, EventSensorRemoved:
+		return "sensors changed, " + event.Sensors.String()
+	case EventPowerChanged:
+		return "power " + fmt.Sprint(event.Value)
+	case EventSuspendDetected:
+		return "system " + fmt.Sprint(event.Value)
+	}
+	name := event.Sensor
+	if event.Group != "" {
+		name = event.Group + ", " + name
+	}
+	if event.Type == EventSensorFrozen {
+		return fmt.Sprintf("%s possibly frozen, %s", name, event.Frozen.describe())
+	}
+	text := fmt.Sprintf("%s changed from %s to %s", name, strings.ToUpper(event.From.String()), strings.ToUpper(event.To.String()))
+	switch value := event.Value.(type) {
+	case float64:
+		text += ", " + spokenDegrees(value, view)
+	case int:
+		text += fmt.Sprintf(", %d percent", value)
+	default:
+		text += ", " + view.Numbers.localize(spokenUnits.Replace(fmt.Sprint(value)))
+	}
+	return text
+}
+
+// lastChanges returns the events of the last refresh
+func (m Monitor) lastChanges() []Event {
+	var changes []Event
+	for i := len(m.history) - 1; i >= 0 && m.history[i].Time.Equal(m.lastUpdate); i-- {
+		changes = append([]Event{m.history[i]}, changes...)
+	}
+	return changes
+}
+
+// AccessibleView renders the last refresh as the accessible view does, for
+// printing without the TUI
+func (m Monitor) AccessibleView() string {
+	return RenderAccessible(m.Snapshot(), m.lastChanges(), m.viewState(m.clock.Now()))
+}
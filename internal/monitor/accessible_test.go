@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderAccessible(t *testing.T) {
+	snap := Snapshot{
+		Hostname: "box1",
+		Time:     renderTime,
+		Temperatures: []TemperatureSensor{
+			{Name: "CPU package", Value: 64.5, High: 80, Critical: 100},
+			{Name: "Composite", Value: 85, High: 80, Critical: 100, Muted: true},
+			{Name: "iwlwifi", Asleep: true},
+		},
+		Battery:              BatteryStatus{Capacity: 15, Status: "Discharging"},
+		BatteryCapacityState: StateWarning,
+		Groups: []GroupSnapshot{{Name: "Fans", Readings: []SensorReading{
+			{Name: "cpu_fan", Value: "2100 RPM"},
+			{Name: "coolant", Value: "48°C", State: StateCritical},
+		}}},
+	}
+	changes := []Event{
+		{Time: renderTime, Sensor: "Composite", From: StateOK, To: StateWarning, Value: 85.0},
+		{Time: renderTime, Sensor: "coolant", Group: "Fans", From: StateWarning, To: StateCritical, Value: "48°C"},
+		{Time: renderTime, Type: EventProfileChanged, Sensor: "Profile", Profile: &ProfileChange{From: "day", To: "night"}},
+	}
+	want := `Alert: Composite changed from OK to WARNING, 85.0 degrees
+Alert: Fans, coolant changed from WARNING to CRITICAL, 48 degrees
+Alert: profile changed from day to night
+Host: box1
+CPU package: 64.5 degrees, OK
+Composite: 85.0 degrees, OK, muted
+iwlwifi: asleep, not read
+Battery: 15 percent, WARNING, discharging
+Fans, cpu_fan: 2100 RPM, OK
+Fans, coolant: 48 degrees, CRITICAL
+Updated at 12:30:00`
+	if got := RenderAccessible(snap, changes, ViewState{}); got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	got := RenderAccessible(snap, nil, ViewState{Unit: Fahrenheit, Numbers: DecimalComma})
+	if !strings.Contains(got, "CPU package: 148,1 degrees Fahrenheit, OK\n") || strings.HasPrefix(got, "Alert") {
+		t.Errorf("expected localized Fahrenheit readings without alerts, got:\n%s", got)
+	}
+}
+
+func TestAccessibleViewAnnouncesLastRefresh(t *testing.T) {
+	clock := &fakeClock{now: renderTime}
+	m := NewMonitor(WithClock(clock), WithDemo(DefaultDemoSeed), WithAccessible())
+	m.width, m.height = 80, 24
+	m = m.Refresh()
+	m.history = append(m.history, Event{Time: m.lastUpdate, Sensor: "Core 0", From: StateOK, To: StateCritical, Value: 101.0})
+
+	view := m.View()
+	if !strings.HasPrefix(view, "Alert: Core 0 changed from OK to CRITICAL, 101.0 degrees\n") {
+		t.Errorf("expected the change announced first, got:\n%s", view)
+	}
+	if strings.ContainsAny(view, "🌡🔋│─╭\x1b") {
+		t.Errorf("expected plain text, got:\n%s", view)
+	}
+
+	// Announced once: the next refresh has nothing new
+	clock.now = clock.now.Add(2 * time.Second)
+	m = m.Refresh()
+	if view := m.View(); strings.Contains(view, "Core 0 changed") {
+		t.Errorf("expected the change announced once, got:\n%s", view)
+	}
+}
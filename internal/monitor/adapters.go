@@ -1,6 +1,14 @@
 package monitor
 
-import "fmt"
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
 
 // TemperatureSensorAdapter adapts TemperatureSensor to the Sensor interface
 type TemperatureSensorAdapter struct {
@@ -12,51 +20,134 @@ func (t TemperatureSensorAdapter) Name() string {
 }
 
 func (t TemperatureSensorAdapter) Value() string {
-	return fmt.Sprintf("%.1f°C", t.TemperatureSensor.Value)
+	if t.TemperatureSensor.Asleep {
+		return asleepText
+	}
+	return formatMeasurement(KindTemperature, t.TemperatureSensor.Value)
+}
+
+func (t TemperatureSensorAdapter) Kind() Kind {
+	return KindTemperature
+}
+
+func (t TemperatureSensorAdapter) Measurement() (float64, bool) {
+	return t.TemperatureSensor.Value, !t.TemperatureSensor.Asleep
 }
 
 func (t TemperatureSensorAdapter) Warning() bool {
-	return t.TemperatureSensor.Value >= t.TemperatureSensor.High
+	return !t.TemperatureSensor.Asleep && (t.TemperatureSensor.Value >= t.TemperatureSensor.High || t.TemperatureSensor.Alarm == StateWarning)
 }
 
 func (t TemperatureSensorAdapter) Critical() bool {
-	return t.TemperatureSensor.Value >= t.TemperatureSensor.Critical
+	return !t.TemperatureSensor.Asleep && (t.TemperatureSensor.Value >= t.TemperatureSensor.Critical || t.TemperatureSensor.Alarm == StateCritical)
+}
+
+func (t TemperatureSensorAdapter) Alarm() State {
+	return t.TemperatureSensor.Alarm
 }
 
+// Refresh re-reads the value from the sensor's sysfs file, a thermal zone's
+// temp or an hwmon *_input; the thresholds are static. On failure the
+// previous value is kept. Sensors without a path have nothing to re-read,
+// and those of a suspended hwmon device are left asleep.
 func (t TemperatureSensorAdapter) Refresh() error {
-	// Temperature sensors are refreshed via batch readTemperatures()
-	// Individual refresh not supported; rely on global update
+	if t.TemperatureSensor.Path == "" {
+		return nil
+	}
+	if strings.HasSuffix(t.TemperatureSensor.Path, "_input") {
+		if t.TemperatureSensor.Asleep = chipAsleep(filepath.Dir(t.TemperatureSensor.Path), nil); t.TemperatureSensor.Asleep {
+			return nil
+		}
+	}
+	path := valueFilePath(*t.TemperatureSensor)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	value, err := parseMillidegrees(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	t.TemperatureSensor.Value = value
+	t.TemperatureSensor.Raw = rawReading(data)
+	t.TemperatureSensor.Alarm = readAlarms(t.TemperatureSensor.alarms)
 	return nil
 }
 
-// BatterySensorAdapter adapts BatteryStatus to the Sensor interface
+// BatterySensorAdapter adapts BatteryStatus to the Sensor interface, with
+// the same state as the Battery panel: capacity thresholds, health mapping
+// and deep-discharge risk
 type BatterySensorAdapter struct {
 	*BatteryStatus
+	// Thresholds are the capacity thresholds; zero means
+	// DefaultBatteryThresholds
+	Thresholds BatteryThresholds
+	// NotCharging replaces Thresholds while a charge limit holds the battery
+	NotCharging *BatteryThresholds
+
+	// root is the sysfs root Refresh reads, sysfsRoot when empty
+	root string
 }
 
 func (b BatterySensorAdapter) Name() string {
 	return "Battery"
 }
 
+// Value shows the capacity, the status (⚡ while charging) and the power
+// drawn, e.g. "85% ⚡ 31 W" or "60% Discharging 9.5 W"
 func (b BatterySensorAdapter) Value() string {
-	return fmt.Sprintf("%d%%", b.BatteryStatus.Capacity)
+	value := formatMeasurement(KindPercentage, float64(b.BatteryStatus.Capacity))
+	switch status := b.BatteryStatus.Status; status {
+	case "":
+	case "Charging":
+		value += " ⚡"
+	default:
+		value += " " + status
+	}
+	if power := b.BatteryStatus.Power; power > 0 {
+		value += " " + strconv.FormatFloat(math.Round(power*10)/10, 'f', -1, 64) + " W"
+	}
+	return value
+}
+
+func (b BatterySensorAdapter) Kind() Kind {
+	return KindPercentage
+}
+
+func (b BatterySensorAdapter) Measurement() (float64, bool) {
+	return float64(b.BatteryStatus.Capacity), true
+}
+
+func (b BatterySensorAdapter) state() State {
+	t := b.Thresholds
+	if t == (BatteryThresholds{}) {
+		t = DefaultBatteryThresholds
+	}
+	return b.BatteryStatus.State(t, b.NotCharging)
 }
 
 func (b BatterySensorAdapter) Warning() bool {
-	return b.BatteryStatus.Capacity < 20
+	return b.state() == StateWarning
 }
 
 func (b BatterySensorAdapter) Critical() bool {
-	return b.BatteryStatus.Capacity < 10
+	return b.state() == StateCritical
 }
 
+// Refresh re-reads the battery from sysfs into the adapted status
 func (b BatterySensorAdapter) Refresh() error {
-	// Battery status is refreshed via batch readBatteryStatus()
+	*b.BatteryStatus = readBatteryStatus(cmp.Or(b.root, sysfsRoot))
 	return nil
 }
 
 // CreateSensorGroups creates default sensor groups from existing data
 func CreateSensorGroups(temps []TemperatureSensor, battery BatteryStatus) []SensorGroup {
+	return createSensorGroups(sysfsRoot, temps, battery)
+}
+
+// createSensorGroups is CreateSensorGroups with the battery refreshed from
+// root
+func createSensorGroups(root string, temps []TemperatureSensor, battery BatteryStatus) []SensorGroup {
 	groups := []SensorGroup{}
 
 	// Temperature group
@@ -75,7 +166,7 @@ func CreateSensorGroups(temps []TemperatureSensor, battery BatteryStatus) []Sens
 	if battery.Capacity > 0 || battery.Status != "" {
 		groups = append(groups, SensorGroup{
 			Name:    "Battery",
-			Sensors: []Sensor{BatterySensorAdapter{&battery}},
+			Sensors: []Sensor{BatterySensorAdapter{BatteryStatus: &battery, root: root}},
 		})
 	}
 
@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// The energy a battery delivers and takes in is counted from the deltas of
+// energy_now between readings, like RAPL energy counters, both over the
+// session and in the report of the history file. A drop is discharged
+// energy whatever the status says, so a charger flapping between two
+// readings or too weak to keep up is accounted as it happened. A rise
+// counts as charged only with the charger online at either reading: a rise
+// on battery is the gauge recalibrating. Deltas across a suspend are left
+// out, the energy then used not being the session's.
+
+// EnergyTotals are the energy a battery delivered and took in, in
+// watt-hours
+type EnergyTotals struct {
+	Discharged float64 `json:"discharged_wh"`
+	Charged    float64 `json:"charged_wh"`
+}
+
+// IsZero reports whether no energy was counted
+func (e EnergyTotals) IsZero() bool {
+	return e.Discharged == 0 && e.Charged == 0
+}
+
+// String describes the totals, e.g. "used 18.4 Wh on battery, recharged
+// 22.1 Wh"
+func (e EnergyTotals) String() string {
+	return fmt.Sprintf("used %.1f Wh on battery, recharged %.1f Wh", e.Discharged, e.Charged)
+}
+
+// add counts the energy between two readings; suspended leaves them out
+func (e *EnergyTotals) add(prev, cur BatteryStatus, suspended bool) {
+	if suspended || prev.Energy <= 0 || cur.Energy <= 0 {
+		return
+	}
+	switch delta := cur.Energy - prev.Energy; {
+	case delta < 0:
+		e.Discharged -= delta
+	case delta > 0 && (prev.ACOnline || cur.ACOnline):
+		e.Charged += delta
+	}
+}
+
+// trackEnergy counts the energy between two battery readings, the gap
+// between them counting as suspended as in trackPower
+func (m *Monitor) trackEnergy(prev, cur BatteryStatus, prevRead, now time.Time) {
+	if !prev.Present() || !cur.Present() || prevRead.IsZero() {
+		return
+	}
+	suspended := now.Sub(prevRead) > 3*m.refreshInterval() && !m.resumedAt.After(prevRead)
+	m.energy.add(prev, cur, suspended)
+}
@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnergyAccounting(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	m := NewMonitor(WithClock(clock), WithInterval(2*time.Second))
+	read := func(energy float64, status string, ac bool, after time.Duration) {
+		clock.now = clock.now.Add(after)
+		m.setBattery(BatteryStatus{Capacity: 50, Status: status, ACOnline: ac, Energy: energy}, clock.now)
+	}
+
+	read(40, "Discharging", false, 0)
+	read(39, "Discharging", false, 2*time.Second)
+	// The gauge recalibrates upward on battery: not charged energy
+	read(41, "Discharging", false, 2*time.Second)
+	read(40.5, "Discharging", false, 2*time.Second)
+	// Suspended for an hour: the energy then used isn't counted
+	read(35, "Discharging", false, time.Hour)
+	// The charger flaps: plugged in, too weak, unplugged, plugged again
+	read(36, "Charging", true, 2*time.Second)
+	read(35.5, "Charging", true, 2*time.Second)
+	read(35.2, "Discharging", false, 2*time.Second)
+	read(37.2, "Charging", true, 2*time.Second)
+
+	want := EnergyTotals{Discharged: 1 + 0.5 + 0.5 + 0.3, Charged: 1 + 2}
+	got := m.Snapshot().BatteryEnergy
+	if math.Abs(got.Discharged-want.Discharged) > 1e-9 || math.Abs(got.Charged-want.Charged) > 1e-9 {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := got.String(); got != "used 2.3 Wh on battery, recharged 3.0 Wh" {
+		t.Errorf("unexpected description %q", got)
+	}
+
+	m.width, m.height = 80, 24
+	m.showBatteryGraph = true
+	m.recordBattery(clock.now)
+	if title := strings.SplitN(m.View(), "\n", 2)[0]; !strings.Contains(title, "used 2.3 Wh on battery, recharged 3.0 Wh") {
+		t.Errorf("expected the totals on the graph's title line, got %q", title)
+	}
+}
+
+func TestReportEnergy(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	record := func(minutes int, energy float64, ac bool) HistoryRecord {
+		status := "Discharging"
+		if ac {
+			status = "Charging"
+		}
+		return HistoryRecord{Time: start.Add(time.Duration(minutes) * time.Minute), Battery: &HistoryBattery{Capacity: 50, Status: status, ACOnline: ac, Energy: energy}}
+	}
+	report := NewReport([]HistoryRecord{
+		record(0, 50, false),
+		record(1, 49, false),
+		// Not monitored for an hour
+		record(61, 40, false),
+		record(62, 39.5, false),
+		record(63, 42, true),
+	})
+	if e := report.Battery.Energy; e == nil || *e != (EnergyTotals{Discharged: 1.5, Charged: 2.5}) {
+		t.Errorf("expected 1.5 Wh used and 2.5 Wh recharged, got %+v", e)
+	}
+
+	if report := NewReport([]HistoryRecord{{Time: start, Battery: &HistoryBattery{Capacity: 50}}}); report.Battery.Energy != nil {
+		t.Errorf("expected no energy without energy_now, got %+v", report.Battery.Energy)
+	}
+}
@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// minChargePower is the smoothed charge power below which no time to full
+// is estimated: a trickle charge projects hours that never come true
+const minChargePower = 1.0
+
+// ChargeEstimate is when a charging battery will reach its target
+type ChargeEstimate struct {
+	// Remaining is the time left to the target at the smoothed charge power
+	Remaining time.Duration
+	// At is the projected wall-clock time, from the battery's last reading
+	At time.Time
+	// Target is the capacity charging stops at: the charge limit, or 100
+	Target int
+}
+
+// String describes the estimate, e.g. "~1h 20m, at 15:42 (limit 80%)"
+func (e ChargeEstimate) String() string {
+	s := fmt.Sprintf("~%s, at %s", formatETA(e.Remaining), e.At.Format("15:04"))
+	if e.Target < 100 {
+		s += fmt.Sprintf(" (limit %d%%)", e.Target)
+	}
+	return s
+}
+
+// formatETA formats a time left to the minute, e.g. "1h 20m" or "35m"
+func formatETA(d time.Duration) string {
+	minutes := int(max(d.Round(time.Minute), time.Minute) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+// chargePower averages the power of the charging samples within powerWindow
+// of the last one, so the draw before the adapter was plugged in is left
+// out; 0 unless the last sample is charging
+func (m Monitor) chargePower() float64 {
+	samples := m.batteryHistory
+	if len(samples) == 0 || !samples[len(samples)-1].Charging {
+		return 0
+	}
+	since := samples[len(samples)-1].Time.Add(-powerWindow)
+	var sum float64
+	var n int
+	for i := len(samples) - 1; i >= 0 && samples[i].Charging && !samples[i].Time.Before(since); i-- {
+		sum += samples[i].Power
+		n++
+	}
+	return sum / float64(n)
+}
+
+// chargeEstimate projects when the battery reaches its charge limit, or
+// full without one. It is nil unless the battery is charging at
+// minChargePower or more and exposes its full energy.
+func (m Monitor) chargeEstimate() *ChargeEstimate {
+	bat := m.batteryStatus
+	power := m.chargePower()
+	if bat.Status != "Charging" || power < minChargePower || bat.EnergyFull <= 0 {
+		return nil
+	}
+	target := 100
+	if bat.ChargeLimit > 0 {
+		target = bat.ChargeLimit
+	}
+	goal := bat.EnergyFull * float64(target) / 100
+	energy := bat.Energy
+	if energy <= 0 {
+		energy = bat.EnergyFull * float64(bat.Capacity) / 100
+	}
+	if energy >= goal {
+		return nil
+	}
+	remaining := time.Duration((goal - energy) / power * float64(time.Hour))
+	return &ChargeEstimate{Remaining: remaining, At: m.batteryRead.Add(remaining), Target: target}
+}
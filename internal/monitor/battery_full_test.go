@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// chargingMonitor records a battery charging at each of the powers, 10s
+// apart
+func chargingMonitor(bat BatteryStatus, powers ...float64) Monitor {
+	m := NewMonitor()
+	start := time.Date(2024, 5, 1, 14, 0, 0, 0, time.Local)
+	for i, power := range powers {
+		bat.Power = power
+		m.batteryStatus = bat
+		m.batteryRead = start.Add(time.Duration(i) * 10 * time.Second)
+		m.recordBattery(m.batteryRead)
+	}
+	return m
+}
+
+func TestChargeEstimate(t *testing.T) {
+	bat := BatteryStatus{Capacity: 40, Status: "Charging", ACOnline: true, Energy: 20, EnergyFull: 50}
+
+	// Only the charging samples count: 30 W → 20 Wh to go takes 40 minutes
+	discharging := bat
+	discharging.Status = "Discharging"
+	m := chargingMonitor(discharging, 12)
+	for i, power := range []float64{28, 32, 30} {
+		bat.Power = power
+		m.batteryStatus = bat
+		m.recordBattery(m.batteryRead.Add(time.Duration(i+1) * 10 * time.Second))
+	}
+	m.batteryRead = m.batteryRead.Add(30 * time.Second)
+	full := m.Snapshot().BatteryFull
+	if full == nil || full.Remaining != 60*time.Minute || full.Target != 100 {
+		t.Fatalf("expected an hour to 100%%, got %+v", full)
+	}
+	if want := "~1h 00m, at 15:00"; full.String() != want {
+		t.Errorf("expected %q, got %q", want, full.String())
+	}
+
+	// A charge limit is the target: 40 Wh at 80% of 50 Wh
+	limited := bat
+	limited.ChargeLimit = 80
+	full = chargingMonitor(limited, 30, 30).Snapshot().BatteryFull
+	if full == nil || full.Remaining != 40*time.Minute || full.Target != 80 {
+		t.Fatalf("expected 40 minutes to the 80%% limit, got %+v", full)
+	}
+	if want := "~40m, at 14:40 (limit 80%)"; full.String() != want {
+		t.Errorf("expected %q, got %q", want, full.String())
+	}
+
+	// Without energy_now the capacity places the battery
+	noEnergy := bat
+	noEnergy.Energy = 0
+	if full := chargingMonitor(noEnergy, 30).Snapshot().BatteryFull; full == nil || full.Remaining != 60*time.Minute {
+		t.Errorf("expected an hour from 40%% of 50 Wh, got %+v", full)
+	}
+
+	for name, tt := range map[string]struct {
+		bat    BatteryStatus
+		powers []float64
+	}{
+		"trickle":      {bat, []float64{0.5, 0.6}},
+		"not charging": {BatteryStatus{Capacity: 80, Status: "Not charging", ACOnline: true, Energy: 40, EnergyFull: 50, ChargeLimit: 80}, []float64{30}},
+		"at the limit": {BatteryStatus{Capacity: 80, Status: "Charging", Energy: 40, EnergyFull: 50, ChargeLimit: 80}, []float64{30}},
+		"no full":      {BatteryStatus{Capacity: 40, Status: "Charging", Energy: 20}, []float64{30}},
+	} {
+		if full := chargingMonitor(tt.bat, tt.powers...).Snapshot().BatteryFull; full != nil {
+			t.Errorf("%s: expected no estimate, got %+v", name, full)
+		}
+	}
+}
+
+func TestChargeEstimateRendered(t *testing.T) {
+	m := chargingMonitor(BatteryStatus{Capacity: 40, Status: "Charging", ACOnline: true, Energy: 20, EnergyFull: 50, ChargeLimit: 80}, 30)
+	m.width, m.height = 80, 24
+	if view := m.View(); !strings.Contains(view, "Full: ~40m, at 14:40 (limit 80%)") {
+		t.Errorf("expected the time to the charge limit, got:\n%s", view)
+	}
+}
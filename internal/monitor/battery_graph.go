@@ -0,0 +1,204 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxBatteryHistory caps the capacity samples kept for the battery graph.
+// When it is reached every other sample is dropped, so the graph keeps
+// covering the whole session at a lower resolution.
+const maxBatteryHistory = 4096
+
+// graphGutter is the width of the graph's percentage axis, e.g. "100%│"
+const graphGutter = 5
+
+// dischargingColor shades the graph while the battery isn't charging
+const dischargingColor = "245"
+
+// BatterySample is one battery reading of the capacity graph
+type BatterySample struct {
+	Time     time.Time
+	Capacity int
+	Charging bool // Charging or Full
+	ACOnline bool
+	Power    float64 // watts, 0 if not exposed
+}
+
+// recordBattery adds the battery reading of a refresh to the graph history
+func (m *Monitor) recordBattery(now time.Time) {
+	bat := m.batteryStatus
+	if !bat.Present() {
+		return
+	}
+	m.batteryHistory = append(m.batteryHistory, BatterySample{
+		Time:     now,
+		Capacity: bat.Capacity,
+		Charging: bat.Status == "Charging" || bat.Status == "Full",
+		ACOnline: bat.ACOnline,
+		Power:    bat.Power,
+	})
+	m.powerPeak = max(m.powerPeak, bat.Power)
+	if len(m.batteryHistory) > maxBatteryHistory {
+		thinned := make([]BatterySample, 0, maxBatteryHistory/2+1)
+		for i := 0; i < len(m.batteryHistory); i += 2 {
+			thinned = append(thinned, m.batteryHistory[i])
+		}
+		m.batteryHistory = thinned
+	}
+}
+
+// BatteryHistory returns the battery samples of the session, oldest first
+func (m Monitor) BatteryHistory() []BatterySample {
+	return append([]BatterySample(nil), m.batteryHistory...)
+}
+
+// graphColumn is what one column of the battery graph shows
+type graphColumn struct {
+	set       bool
+	capacity  int
+	charging  bool
+	suspended bool // no readings for longer than the gap, e.g. suspend
+	marker    string
+}
+
+// graphColumns spreads the samples over cols columns by time. A column takes
+// the last sample falling into it; columns between two samples carry the
+// earlier one, or are marked suspended when the samples are more than gap
+// apart. Markers flag where the adapter was plugged (▲) or unplugged (▼).
+func graphColumns(samples []BatterySample, cols int, gap time.Duration) []graphColumn {
+	columns := make([]graphColumn, cols)
+	if len(samples) == 0 {
+		return columns
+	}
+	first := samples[0].Time
+	span := samples[len(samples)-1].Time.Sub(first)
+	column := func(t time.Time) int {
+		if span <= 0 {
+			return 0
+		}
+		return int(float64(t.Sub(first)) / float64(span) * float64(cols-1))
+	}
+
+	prevCol := -1
+	for i, s := range samples {
+		col := column(s.Time)
+		if i > 0 {
+			prev := samples[i-1]
+			suspended := s.Time.Sub(prev.Time) > gap
+			for c := prevCol + 1; c < col; c++ {
+				columns[c] = graphColumn{set: true, capacity: prev.Capacity, charging: prev.Charging, suspended: suspended}
+			}
+			if s.ACOnline != prev.ACOnline {
+				columns[col].marker = "▼"
+				if s.ACOnline {
+					columns[col].marker = "▲"
+				}
+			}
+		}
+		columns[col].set = true
+		columns[col].capacity = s.Capacity
+		columns[col].charging = s.Charging
+		prevCol = col
+	}
+	return columns
+}
+
+// RenderBatteryGraph plots the battery capacity over the session on a fixed
+// 0–100% axis. The area under the curve is green while charging and grey
+// while discharging; gaps longer than gap between samples (suspend) are
+// hatched. Smaller panes get fewer rows and columns, down to 20×10.
+func RenderBatteryGraph(samples []BatterySample, width, height int, theme Theme, gap time.Duration) string {
+	return renderBatteryGraph(samples, EnergyTotals{}, width, height, theme, gap)
+}
+
+// renderBatteryGraph is RenderBatteryGraph with the session's energy
+// totals on the title line, as the monitor shows it
+func renderBatteryGraph(samples []BatterySample, energy EnergyTotals, width, height int, theme Theme, gap time.Duration) string {
+	var sb strings.Builder
+	faint := lipgloss.NewStyle().Faint(true)
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(theme.Title)).Render("Battery Capacity"))
+	if len(samples) == 0 {
+		sb.WriteString("\n\n  No battery readings yet\n\n")
+		sb.WriteString(faint.Render("esc: back"))
+		return sb.String()
+	}
+	last := samples[len(samples)-1]
+	fmt.Fprintf(&sb, " %d%%", last.Capacity)
+	if !energy.IsZero() {
+		sb.WriteString(faint.Render(" · " + truncateWidth(energy.String(), max(width-len("Battery Capacity 100% · "), 0))))
+	}
+	sb.WriteString("\n")
+
+	// Title, axis, times and help take a line each
+	rows := max(height-4, 2)
+	cols := max(width-graphGutter, 2)
+	columns := graphColumns(samples, cols, gap)
+	charging := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.OK))
+	discharging := lipgloss.NewStyle().Foreground(lipgloss.Color(dischargingColor))
+
+	for i := 0; i < rows; i++ {
+		level := rows - 1 - i
+		label := ""
+		switch {
+		case i == 0:
+			label = "100%"
+		case i == rows-1:
+			label = "0%"
+		case rows >= 5 && i == rows/2:
+			label = "50%"
+		}
+		fmt.Fprintf(&sb, "%4s│", label)
+		for _, col := range columns {
+			switch {
+			case !col.set:
+				sb.WriteString(" ")
+			case col.suspended:
+				sb.WriteString(faint.Render("╱"))
+			default:
+				style := discharging
+				if col.charging {
+					style = charging
+				}
+				sb.WriteString(style.Render(graphCell(col.capacity, level, rows)))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("    └")
+	for _, col := range columns {
+		if col.marker != "" {
+			sb.WriteString(col.marker)
+		} else {
+			sb.WriteString("─")
+		}
+	}
+	sb.WriteString("\n")
+
+	start, end := samples[0].Time.Format("15:04"), last.Time.Format("15:04")
+	times := start
+	if cols >= len(start)+len(end)+1 {
+		times = start + strings.Repeat(" ", cols-len(start)-len(end)) + end
+	}
+	sb.WriteString(strings.Repeat(" ", graphGutter) + times + "\n")
+	sb.WriteString(faint.Render(truncateWidth("▲ plugged ▼ unplugged ╱ suspended | esc: back", width)))
+	return sb.String()
+}
+
+// graphCell draws the level-th row from the bottom of a column showing
+// capacity: full below the curve, a half block where it ends in the upper
+// half of a row, blank above
+func graphCell(capacity, level, rows int) string {
+	fill := float64(capacity) * float64(rows) / 100
+	switch full := int(fill); {
+	case level < full:
+		return "█"
+	case level == full && fill-float64(full) >= 0.5:
+		return "▄"
+	}
+	return " "
+}
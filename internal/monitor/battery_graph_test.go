@@ -0,0 +1,112 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRecordBatteryThinsHistory(t *testing.T) {
+	m := NewMonitor()
+	m.batteryStatus = BatteryStatus{Capacity: 50, Status: "Discharging"}
+	start := time.Now()
+	for i := 0; i <= maxBatteryHistory; i++ {
+		m.recordBattery(start.Add(time.Duration(i) * time.Second))
+	}
+	history := m.BatteryHistory()
+	if len(history) != maxBatteryHistory/2+1 {
+		t.Fatalf("expected the history thinned to %d samples, got %d", maxBatteryHistory/2+1, len(history))
+	}
+	if !history[0].Time.Equal(start) || !history[len(history)-1].Time.Equal(start.Add(maxBatteryHistory*time.Second)) {
+		t.Errorf("expected the thinned history to still span the session")
+	}
+
+	m.batteryStatus = BatteryStatus{}
+	m.recordBattery(start)
+	if len(m.BatteryHistory()) != len(history) {
+		t.Errorf("expected no sample without a battery")
+	}
+}
+
+func TestGraphColumns(t *testing.T) {
+	start := time.Now()
+	samples := []BatterySample{
+		{Time: start, Capacity: 80},
+		{Time: start.Add(10 * time.Second), Capacity: 79},
+		// Suspended for a while, then plugged in
+		{Time: start.Add(60 * time.Second), Capacity: 70, Charging: true, ACOnline: true},
+		{Time: start.Add(70 * time.Second), Capacity: 72, Charging: true, ACOnline: true},
+		{Time: start.Add(80 * time.Second), Capacity: 73},
+	}
+	columns := graphColumns(samples, 9, 20*time.Second)
+
+	var marks, kinds strings.Builder
+	for _, c := range columns {
+		switch {
+		case c.suspended:
+			kinds.WriteString("s")
+		case c.charging:
+			kinds.WriteString("c")
+		default:
+			kinds.WriteString("d")
+		}
+		if c.marker != "" {
+			marks.WriteString(c.marker)
+		} else {
+			marks.WriteString(".")
+		}
+	}
+	if got := kinds.String(); got != "ddssssccd" {
+		t.Errorf("unexpected column kinds %q", got)
+	}
+	if got := marks.String(); got != "......▲.▼" {
+		t.Errorf("unexpected markers %q", got)
+	}
+	if columns[8].capacity != 73 {
+		t.Errorf("expected the last column at 73%%, got %d", columns[8].capacity)
+	}
+}
+
+func TestRenderBatteryGraphFitsSmallPanes(t *testing.T) {
+	start := time.Now()
+	var samples []BatterySample
+	for i := 0; i < 100; i++ {
+		samples = append(samples, BatterySample{Time: start.Add(time.Duration(i) * time.Minute), Capacity: 100 - i, ACOnline: i > 50, Charging: i > 50})
+	}
+	for _, size := range []struct{ width, height int }{{20, 10}, {80, 24}} {
+		lines := strings.Split(RenderBatteryGraph(samples, size.width, size.height, DefaultTheme, time.Hour), "\n")
+		if len(lines) != size.height {
+			t.Errorf("%dx%d: expected %d lines, got %d", size.width, size.height, size.height, len(lines))
+		}
+		for _, line := range lines {
+			if w := lipgloss.Width(line); w > size.width {
+				t.Errorf("%dx%d: line %q is %d cells wide", size.width, size.height, line, w)
+			}
+		}
+		if !strings.HasPrefix(lines[1], "100%│") || !strings.HasPrefix(lines[size.height-4], "  0%│") {
+			t.Errorf("%dx%d: expected the 0-100%% axis, got %q and %q", size.width, size.height, lines[1], lines[size.height-4])
+		}
+	}
+
+	if got := RenderBatteryGraph(nil, 40, 10, DefaultTheme, time.Minute); !strings.Contains(got, "No battery readings yet") {
+		t.Errorf("expected a placeholder without samples, got %q", got)
+	}
+}
+
+func TestBatteryGraphKey(t *testing.T) {
+	m := NewMonitor()
+	m.width, m.height = 80, 24
+	m.batteryStatus = BatteryStatus{Capacity: 64, Status: "Charging", ACOnline: true}
+	m.recordBattery(time.Now())
+
+	m = sendKeys(m, "g")
+	if view := m.View(); !strings.Contains(view, "Battery Capacity 64%") {
+		t.Errorf("expected the battery graph, got:\n%s", view)
+	}
+	m = sendKeys(m, "esc")
+	if m.showBatteryGraph {
+		t.Errorf("expected esc to close the graph")
+	}
+}
@@ -0,0 +1,38 @@
+package monitor
+
+import "time"
+
+// powerWindow is how far back the smoothed battery power averages
+const powerWindow = 30 * time.Second
+
+// BatteryPower is the battery power draw over the session, in watts
+type BatteryPower struct {
+	// Average is the mean of the readings of the last powerWindow
+	Average float64
+	// Peak is the highest reading since the start or the last reset
+	Peak float64
+}
+
+// batteryPower averages the power of the samples within powerWindow of the
+// last one; power_now swings too much tick to tick to read on its own
+func (m Monitor) batteryPower() BatteryPower {
+	power := BatteryPower{Peak: m.powerPeak}
+	samples := m.batteryHistory
+	if len(samples) == 0 {
+		return power
+	}
+	since := samples[len(samples)-1].Time.Add(-powerWindow)
+	var sum float64
+	var n int
+	for i := len(samples) - 1; i >= 0 && !samples[i].Time.Before(since); i-- {
+		sum += samples[i].Power
+		n++
+	}
+	power.Average = sum / float64(n)
+	return power
+}
+
+// resetSessionPeaks forgets the peaks recorded so far
+func (m *Monitor) resetSessionPeaks() {
+	m.powerPeak = m.batteryStatus.Power
+}
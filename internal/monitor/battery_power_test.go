@@ -0,0 +1,31 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatteryPowerSmoothing(t *testing.T) {
+	m := NewMonitor()
+	start := time.Now()
+	for i, power := range []float64{57.3, 3, 45, 3, 12, 6} {
+		m.batteryStatus = BatteryStatus{Capacity: 80, Status: "Discharging", Power: power}
+		m.recordBattery(start.Add(time.Duration(i) * 10 * time.Second))
+	}
+	// The first two samples are more than 30s before the last
+	power := m.Snapshot().BatteryPower
+	if power.Average != 16.5 || power.Peak != 57.3 {
+		t.Errorf("expected a 16.5 W average and a 57.3 W peak, got %+v", power)
+	}
+
+	m.width, m.height = 80, 24
+	if view := m.View(); !strings.Contains(view, "Power: 16.5 W") || !strings.Contains(view, "(now 6.0, peak 57.3)") {
+		t.Errorf("expected the smoothed power with the current and peak values, got:\n%s", view)
+	}
+
+	m = sendKeys(m, "x")
+	if got := m.Snapshot().BatteryPower.Peak; got != 6 {
+		t.Errorf("expected x to reset the peak to the current reading, got %v", got)
+	}
+}
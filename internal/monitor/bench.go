@@ -0,0 +1,257 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// `sysfs-check bench` times the reads of every attribute the monitor reads
+// on each refresh, to tell whether a machine's embedded controller or SMBus
+// chips can keep up with a short --interval. Files are read one at a time,
+// never in parallel, so each latency is the file's own; each file has a
+// time budget, so a slow or hung one can't hold the run up for long.
+
+const (
+	// BenchReads is how many times Bench reads each attribute
+	BenchReads = 20
+
+	// BenchSlowRead is the latency above which an attribute is flagged
+	BenchSlowRead = 50 * time.Millisecond
+
+	// benchFileBudget bounds the time spent reading one attribute; a read
+	// still pending past it is abandoned
+	benchFileBudget = 2 * time.Second
+
+	// benchIntervalStep rounds the suggested interval up
+	benchIntervalStep = 100 * time.Millisecond
+)
+
+// benchHwmonPatterns are the hwmon attributes the readers read on
+// refresh; labels and limits are read once, at discovery
+var benchHwmonPatterns = []string{
+	"temp*_input", "fan*_input", "in*_input", "curr*_input",
+	"power*_average", "power*_input", "humidity*_input",
+	"pwm[0-9]", "pwm[0-9][0-9]", "pwm*_enable", "*_alarm", "alarms", "*_fault",
+}
+
+// benchSupplyFiles are the power supply attributes read on refresh
+var benchSupplyFiles = []string{
+	"status", "online", "capacity", "energy_now", "charge_now",
+	"voltage_now", "current_now", "power_now", "temp",
+}
+
+// Latencies are the median, 95th percentile and maximum of a set of read
+// times
+type Latencies struct {
+	P50, P95, Max time.Duration
+}
+
+// BenchFile is the read latency of one attribute
+type BenchFile struct {
+	// Path is relative to /sys, e.g. "class/hwmon/hwmon2/temp1_input"
+	Path string
+	// Chip is the hwmon chip, thermal zone type or power supply the file
+	// belongs to
+	Chip  string
+	Reads int
+	Latencies
+	// Capped is set when the file's time budget ran out before all its
+	// reads; Err when a read failed or never returned
+	Capped bool
+	Err    string
+}
+
+// Slow reports whether a read of the file took longer than BenchSlowRead
+func (f BenchFile) Slow() bool {
+	return f.Max > BenchSlowRead
+}
+
+// BenchChip sums up the reads of the files of one chip
+type BenchChip struct {
+	Name string
+	// Files counts the chip's files, Slow those flagged slow
+	Files, Slow int
+	Latencies
+}
+
+// BenchResult is the outcome of Bench
+type BenchResult struct {
+	Files []BenchFile
+	Chips []BenchChip
+	// Refresh is the time a refresh spends reading every file, from their
+	// 95th percentiles
+	Refresh time.Duration
+	// Interval is the shortest --interval that leaves the reads half of
+	// each refresh or less
+	Interval time.Duration
+}
+
+// benchTarget is an attribute to time and the chip it belongs to
+type benchTarget struct {
+	path, chip string
+}
+
+// Bench reads every attribute the monitor reads on refresh BenchReads
+// times, one file after the other, and reports their latencies. Chips
+// runtime-suspended are left out, since reading them would wake them.
+func Bench() BenchResult {
+	return bench(sysfsRoot, BenchReads, benchFileBudget, timeRead, time.After)
+}
+
+// benchRead reads a file, returning how long the read took
+type benchRead func(path string) (time.Duration, error)
+
+// timeRead reads a file, timed on the wall clock
+func timeRead(path string) (time.Duration, error) {
+	start := time.Now()
+	_, err := os.ReadFile(path)
+	return time.Since(start), err
+}
+
+// bench times the reads of read, each given up on when after's channel
+// fires, so tests can inject both the latencies and the timeouts
+func bench(root string, reads int, budget time.Duration, read benchRead, after func(time.Duration) <-chan time.Time) BenchResult {
+	var result BenchResult
+	samples := make(map[string][]time.Duration)
+	var chips []string
+	for _, target := range benchTargets(root) {
+		file, times := benchFile(target.path, reads, budget, read, after)
+		file.Path, _ = filepath.Rel(root, target.path)
+		file.Chip = target.chip
+		result.Files = append(result.Files, file)
+		if !slices.Contains(chips, target.chip) {
+			chips = append(chips, target.chip)
+		}
+		samples[target.chip] = append(samples[target.chip], times...)
+		result.Refresh += file.P95
+	}
+	for _, name := range chips {
+		chip := BenchChip{Name: name, Latencies: latencies(samples[name])}
+		for _, file := range result.Files {
+			if file.Chip == name {
+				chip.Files++
+				if file.Slow() {
+					chip.Slow++
+				}
+			}
+		}
+		result.Chips = append(result.Chips, chip)
+	}
+	steps := math.Ceil(float64(2*result.Refresh) / float64(benchIntervalStep))
+	result.Interval = max(time.Duration(steps)*benchIntervalStep, benchIntervalStep)
+	return result
+}
+
+// benchFile times the reads of one file until reads are done, a read
+// fails or the budget runs out
+func benchFile(path string, reads int, budget time.Duration, read benchRead, after func(time.Duration) <-chan time.Time) (BenchFile, []time.Duration) {
+	var file BenchFile
+	var times []time.Duration
+	var spent time.Duration
+	for range reads {
+		if spent >= budget {
+			file.Capped = true
+			break
+		}
+		d, ok, err := timedRead(path, budget-spent, read, after)
+		spent += d
+		if !ok {
+			// Abandoned: it may finish in the background, but no
+			// other read of the file is started
+			times = append(times, d)
+			file.Capped = true
+			file.Err = fmt.Sprintf("no answer within %s", budget)
+			break
+		}
+		if err != nil {
+			file.Err = err.Error()
+			break
+		}
+		times = append(times, d)
+	}
+	file.Reads = len(times)
+	file.Latencies = latencies(times)
+	return file, times
+}
+
+// timedRead reads a file, giving up after timeout; ok is false then
+func timedRead(path string, timeout time.Duration, read benchRead, after func(time.Duration) <-chan time.Time) (time.Duration, bool, error) {
+	type outcome struct {
+		d   time.Duration
+		err error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		d, err := read(path)
+		done <- outcome{d, err}
+	}()
+	select {
+	case o := <-done:
+		return o.d, true, o.err
+	case <-after(timeout):
+		return timeout, false, nil
+	}
+}
+
+// latencies returns the nearest-rank percentiles of read times
+func latencies(times []time.Duration) Latencies {
+	if len(times) == 0 {
+		return Latencies{}
+	}
+	sorted := slices.Sorted(slices.Values(times))
+	rank := func(p float64) time.Duration {
+		return sorted[int(math.Ceil(p*float64(len(sorted))))-1]
+	}
+	return Latencies{P50: rank(0.5), P95: rank(0.95), Max: sorted[len(sorted)-1]}
+}
+
+// benchTargets lists the attributes the readers read on each refresh
+func benchTargets(root string) []benchTarget {
+	var targets []benchTarget
+	zones, _ := filepath.Glob(filepath.Join(root, thermalClassPath, "thermal_zone*"))
+	for _, zone := range zones {
+		path := filepath.Join(zone, "temp")
+		if _, err := os.Stat(path); err == nil {
+			targets = append(targets, benchTarget{path, readTrimmed(filepath.Join(zone, "type"))})
+		}
+	}
+
+	hwmonPaths, _ := filepath.Glob(filepath.Join(root, hwmonClassPath, "hwmon*"))
+	names := make(map[string]int)
+	for _, hwmonPath := range hwmonPaths {
+		names[readTrimmed(filepath.Join(hwmonPath, "name"))]++
+	}
+	for _, hwmonPath := range hwmonPaths {
+		if chipAsleep(hwmonPath, nil) {
+			continue
+		}
+		chip := readTrimmed(filepath.Join(hwmonPath, "name"))
+		if names[chip] > 1 {
+			chip += " (" + filepath.Base(hwmonPath) + ")"
+		}
+		var files []string
+		for _, pattern := range benchHwmonPatterns {
+			matches, _ := filepath.Glob(filepath.Join(hwmonPath, pattern))
+			files = append(files, matches...)
+		}
+		slices.Sort(files)
+		for _, file := range slices.Compact(files) {
+			targets = append(targets, benchTarget{file, chip})
+		}
+	}
+
+	supplies, _ := filepath.Glob(filepath.Join(root, powerSupplyClassPath, "*"))
+	for _, supply := range supplies {
+		for _, name := range benchSupplyFiles {
+			path := filepath.Join(supply, name)
+			if _, err := os.Stat(path); err == nil {
+				targets = append(targets, benchTarget{path, filepath.Base(supply)})
+			}
+		}
+	}
+	return targets
+}
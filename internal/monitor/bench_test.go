@@ -0,0 +1,100 @@
+package monitor
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLatencies(t *testing.T) {
+	var times []time.Duration
+	for i := 20; i >= 1; i-- {
+		times = append(times, time.Duration(i)*time.Millisecond)
+	}
+	got := latencies(times)
+	if got.P50 != 10*time.Millisecond || got.P95 != 19*time.Millisecond || got.Max != 20*time.Millisecond {
+		t.Errorf("unexpected percentiles %+v", got)
+	}
+	if got := latencies(nil); got != (Latencies{}) {
+		t.Errorf("expected no latencies without reads, got %+v", got)
+	}
+}
+
+func TestBench(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/thermal/thermal_zone0/type":               "acpitz\n",
+		"class/thermal/thermal_zone0/temp":               "45000\n",
+		"class/hwmon/hwmon0/name":                        "thinkpad\n",
+		"class/hwmon/hwmon0/temp1_input":                 "50000\n",
+		"class/hwmon/hwmon0/fan1_input":                  "2100\n",
+		"class/hwmon/hwmon0/pwm1":                        "128\n",
+		"class/hwmon/hwmon0/pwm1_enable":                 "2\n",
+		"class/hwmon/hwmon0/temp1_label":                 "CPU\n",
+		"class/hwmon/hwmon1/name":                        "nvme\n",
+		"class/hwmon/hwmon1/temp1_input":                 "38000\n",
+		"class/hwmon/hwmon1/device/power/runtime_status": "suspended\n",
+		"class/power_supply/BAT0/type":                   "Battery\n",
+		"class/power_supply/BAT0/capacity":               "80\n",
+		"class/power_supply/BAT0/energy_now":             "40000000\n",
+		"class/power_supply/BAT0/energy_full":            "50000000\n",
+	})
+
+	// The EC answers the fan slowly, and not at all for the battery's
+	// energy: its read gives up once the read has blocked
+	hang, blocked := make(chan struct{}), make(chan time.Time)
+	defer close(hang)
+	read := func(path string) (time.Duration, error) {
+		switch filepath.Base(path) {
+		case "fan1_input":
+			return 60 * time.Millisecond, nil
+		case "energy_now":
+			blocked <- time.Time{}
+			<-hang
+		case "capacity":
+			return 0, errors.New("input/output error")
+		}
+		return time.Millisecond, nil
+	}
+	after := func(time.Duration) <-chan time.Time {
+		return blocked
+	}
+	result := bench(root, 4, 150*time.Millisecond, read, after)
+
+	var paths []string
+	files := make(map[string]BenchFile)
+	for _, file := range result.Files {
+		paths = append(paths, file.Path)
+		files[filepath.Base(file.Path)] = file
+	}
+	// The suspended chip isn't woken; labels aren't read on refresh
+	want := "class/thermal/thermal_zone0/temp,class/hwmon/hwmon0/fan1_input,class/hwmon/hwmon0/pwm1,class/hwmon/hwmon0/pwm1_enable,class/hwmon/hwmon0/temp1_input,class/power_supply/BAT0/capacity,class/power_supply/BAT0/energy_now"
+	if got := strings.Join(paths, ","); got != want {
+		t.Fatalf("unexpected files:\n%s\nwant:\n%s", got, want)
+	}
+
+	if f := files["temp"]; f.Reads != 4 || f.Capped || f.Slow() || f.Chip != "acpitz" || f.Err != "" || f.Max != time.Millisecond {
+		t.Errorf("expected four quick reads of the zone, got %+v", f)
+	}
+	// 3 reads of 60ms exhaust the 150ms budget
+	if f := files["fan1_input"]; f.Reads != 3 || !f.Capped || !f.Slow() || f.P95 != 60*time.Millisecond {
+		t.Errorf("expected the fan slow and capped by the budget after 3 reads, got %+v", f)
+	}
+	if f := files["energy_now"]; f.Reads != 1 || !f.Capped || f.Max != 150*time.Millisecond || f.Err != "no answer within 150ms" {
+		t.Errorf("expected the hung read abandoned, got %+v", f)
+	}
+	if f := files["capacity"]; f.Reads != 0 || f.Capped || f.Err != "input/output error" {
+		t.Errorf("expected the failed read reported, got %+v", f)
+	}
+
+	if len(result.Chips) != 3 || result.Chips[1].Name != "thinkpad" || result.Chips[1].Files != 4 || result.Chips[1].Slow != 1 {
+		t.Fatalf("unexpected chips %+v", result.Chips)
+	}
+	// 1ms for the zone and 3 thinkpad files, 60ms for the fan and 150ms
+	// abandoned on the energy: twice that, rounded up
+	if result.Refresh != 214*time.Millisecond || result.Interval != 500*time.Millisecond {
+		t.Errorf("expected 214ms of reads and a 500ms interval, got %s and %s", result.Refresh, result.Interval)
+	}
+}
@@ -0,0 +1,228 @@
+package monitor
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// The event bus hands the events the monitor records (state transitions,
+// sensor set changes, profile switches, power events and suspends) to
+// every consumer: the in-app alert history, the --events stream, toasts,
+// and the notifiers and loggers of embedding programs. record stays the
+// single place events are made and publishes them, so every consumer sees
+// the same transitions with the same hysteresis. Each subscription queues
+// its events, so a slow consumer delays neither the refresh nor the others,
+// and receives them in the order they were recorded.
+
+// maxBusQueue bounds the events queued for a consumer that stopped
+// reading; the oldest are dropped first
+const maxBusQueue = 1024
+
+// EventType tells what an Event is about
+type EventType int
+
+const (
+	// EventStateChanged is a reading's transition between alert states
+	EventStateChanged EventType = iota
+	// EventSensorAdded is rediscovery finding new temperatures
+	EventSensorAdded
+	// EventSensorRemoved is rediscovery losing temperatures
+	EventSensorRemoved
+	// EventProfileChanged is a switch of the threshold profile
+	EventProfileChanged
+	// EventPowerChanged is the charger being plugged in or unplugged, the
+	// battery becoming full or another change of its status
+	EventPowerChanged
+	// EventSensorFrozen is a reading marked frozen
+	EventSensorFrozen
+	// EventSuspendDetected is a refresh finding the machine slept since
+	// the previous one
+	EventSuspendDetected
+)
+
+// eventTypeNames are the names of the event types in the event stream
+var eventTypeNames = []string{
+	EventStateChanged:    "state_changed",
+	EventSensorAdded:     "sensor_added",
+	EventSensorRemoved:   "sensor_removed",
+	EventProfileChanged:  "profile_changed",
+	EventPowerChanged:    "power_changed",
+	EventSensorFrozen:    "sensor_frozen",
+	EventSuspendDetected: "suspend_detected",
+}
+
+func (t EventType) String() string {
+	if t < 0 || int(t) >= len(eventTypeNames) {
+		return fmt.Sprintf("EventType(%d)", int(t))
+	}
+	return eventTypeNames[t]
+}
+
+// MarshalText encodes the type by name, e.g. "state_changed"
+func (t EventType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// EventBus publishes events to its subscriptions
+type EventBus struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]bool
+	closed bool
+}
+
+// NewEventBus creates a bus; WithEventBus makes a monitor publish on it
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[*Subscription]bool)}
+}
+
+// WithEventBus publishes every recorded event on bus, shared with
+// subscribers outside the monitor, instead of a bus of the monitor's own
+func WithEventBus(bus *EventBus) Option {
+	return func(m *Monitor) {
+		m.bus = bus
+	}
+}
+
+// Subscription receives the events published after Subscribe
+type Subscription struct {
+	bus   *EventBus
+	types []EventType
+	out   chan Event
+	// ready is signaled when events are queued, done closed on
+	// unsubscribing
+	ready chan struct{}
+	done  chan struct{}
+
+	mu      sync.Mutex
+	queue   []Event
+	dropped int
+}
+
+// Subscribe returns a subscription to the events of the given types, all
+// of them when none are given. Its events must be read from Events until
+// Unsubscribe.
+func (b *EventBus) Subscribe(types ...EventType) *Subscription {
+	s := b.subscribe(types)
+	s.out = make(chan Event)
+	go s.deliver()
+	return s
+}
+
+// subscribe returns a subscription without the goroutine feeding Events:
+// its consumer takes the queue itself with take, on ready or, running with
+// the publisher, right after Publish
+func (b *EventBus) subscribe(types []EventType) *Subscription {
+	s := &Subscription{
+		bus:   b,
+		types: types,
+		ready: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+	b.mu.Lock()
+	if b.closed {
+		close(s.done)
+	} else {
+		b.subs[s] = true
+	}
+	b.mu.Unlock()
+	return s
+}
+
+// Publish queues events for every subscription, in order. It never waits
+// for the consumers.
+func (b *EventBus) Publish(events ...Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subs {
+		s.push(events)
+	}
+}
+
+// Close ends every subscription
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for s := range b.subs {
+		delete(b.subs, s)
+		close(s.done)
+	}
+}
+
+// Events returns the channel the events arrive on. It is closed after
+// Unsubscribe or the bus's Close, dropping the events not read yet.
+func (s *Subscription) Events() <-chan Event {
+	return s.out
+}
+
+// Dropped returns how many events were dropped because the consumer fell
+// more than maxBusQueue events behind
+func (s *Subscription) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Unsubscribe stops the events; it may be called more than once
+func (s *Subscription) Unsubscribe() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if s.bus.subs[s] {
+		delete(s.bus.subs, s)
+		close(s.done)
+	}
+}
+
+// push queues the events of the subscribed types
+func (s *Subscription) push(events []Event) {
+	s.mu.Lock()
+	for _, event := range events {
+		if len(s.types) == 0 || slices.Contains(s.types, event.Type) {
+			s.queue = append(s.queue, event)
+		}
+	}
+	if over := len(s.queue) - maxBusQueue; over > 0 {
+		s.queue = append([]Event(nil), s.queue[over:]...)
+		s.dropped += over
+	}
+	s.mu.Unlock()
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// take returns the queued events and empties the queue
+func (s *Subscription) take() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := s.queue
+	s.queue = nil
+	return events
+}
+
+// deliver hands the queued events to the consumer until unsubscribed
+func (s *Subscription) deliver() {
+	defer close(s.out)
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.ready:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		event := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+		select {
+		case s.out <- event:
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receive reads n events from a subscription, failing after a second
+func receive(t *testing.T, s *Subscription, n int) []Event {
+	t.Helper()
+	var events []Event
+	timeout := time.After(time.Second)
+	for len(events) < n {
+		select {
+		case event, ok := <-s.Events():
+			if !ok {
+				t.Fatalf("subscription closed after %d of %d events", len(events), n)
+			}
+			events = append(events, event)
+		case <-timeout:
+			t.Fatalf("timed out after %d of %d events", len(events), n)
+		}
+	}
+	return events
+}
+
+func TestEventBusDeliversInOrder(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+	all := bus.Subscribe()
+	power := bus.Subscribe(EventPowerChanged, EventProfileChanged)
+
+	bus.Publish(
+		Event{Sensor: "CPU", To: StateWarning},
+		Event{Type: EventPowerChanged, Sensor: "Power", Power: &PowerChange{Event: PowerUnplugged}},
+		Event{Sensor: "CPU", To: StateCritical},
+		Event{Type: EventProfileChanged, Sensor: "Profile", Profile: &ProfileChange{From: "day", To: "night"}},
+	)
+	got := receive(t, all, 4)
+	if got[0].To != StateWarning || got[2].To != StateCritical || got[3].Type != EventProfileChanged {
+		t.Errorf("expected the events in order, got %+v", got)
+	}
+	got = receive(t, power, 2)
+	if got[0].Type != EventPowerChanged || got[1].Type != EventProfileChanged {
+		t.Errorf("expected the power and profile events alone, got %+v", got)
+	}
+
+	power.Unsubscribe()
+	power.Unsubscribe()
+	if _, ok := <-power.Events(); ok {
+		t.Error("expected the channel closed after Unsubscribe")
+	}
+}
+
+func TestEventBusNeverBlocksPublish(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+	stuck := bus.Subscribe()
+
+	const published = maxBusQueue + 10
+	done := make(chan struct{})
+	go func() {
+		for i := range published {
+			bus.Publish(Event{Sensor: "CPU", Value: i})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish waited for a consumer that doesn't read")
+	}
+
+	// The stuck consumer lost the oldest events, the rest come in order
+	if n := stuck.Dropped(); n == 0 || n > 10 {
+		t.Errorf("expected up to 10 events dropped, got %d", n)
+	}
+	got := receive(t, stuck, published-stuck.Dropped())
+	for i := 1; i < len(got); i++ {
+		if got[i].Value.(int) <= got[i-1].Value.(int) {
+			t.Fatalf("expected the kept events in order, got %v after %v", got[i].Value, got[i-1].Value)
+		}
+	}
+	if last := got[len(got)-1].Value; last != published-1 {
+		t.Errorf("expected the last event kept, got %v", last)
+	}
+}
+
+func TestEventBusConcurrentSubscribers(t *testing.T) {
+	bus := NewEventBus()
+	const subscribers, sensors, rounds = 8, 4, 50
+
+	// Each subscriber checks that every sensor's events arrive in order
+	var wg sync.WaitGroup
+	errs := make(chan error, subscribers)
+	for range subscribers {
+		s := bus.Subscribe()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := make(map[string]int)
+			for event := range s.Events() {
+				n := event.Value.(int)
+				if prev, ok := last[event.Sensor]; ok && n != prev+1 {
+					errs <- fmt.Errorf("%s: %d after %d", event.Sensor, n, prev)
+					return
+				}
+				last[event.Sensor] = n
+				if n == rounds-1 && len(last) == sensors {
+					done := true
+					for _, v := range last {
+						done = done && v == rounds-1
+					}
+					if done {
+						s.Unsubscribe()
+					}
+				}
+			}
+		}()
+	}
+
+	// Subscriptions come and go while publishing
+	var churn sync.WaitGroup
+	churn.Add(1)
+	go func() {
+		defer churn.Done()
+		for range rounds {
+			s := bus.Subscribe(EventStateChanged)
+			s.Unsubscribe()
+		}
+	}()
+	for i := range rounds {
+		events := make([]Event, sensors)
+		for j := range events {
+			events[j] = Event{Sensor: fmt.Sprintf("sensor%d", j), Value: i}
+		}
+		bus.Publish(events...)
+	}
+	churn.Wait()
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the subscribers")
+	}
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	bus.Close()
+	if _, ok := <-bus.Subscribe().Events(); ok {
+		t.Error("expected a subscription to a closed bus to be closed")
+	}
+}
+
+func TestMonitorPublishesRecordedEvents(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close()
+	s := bus.Subscribe()
+	var stream bytes.Buffer
+	m := NewMonitor(WithEventBus(bus), WithEventWriter(&stream))
+	m.record([]Event{
+		{Sensor: "CPU", From: StateOK, To: StateCritical, Value: 101.0},
+		{Type: EventSuspendDetected, Sensor: "System", Value: "resumed after 52m"},
+	})
+
+	got := receive(t, s, 2)
+	if got[0].Sensor != "CPU" || got[0].SchemaVersion != SchemaVersion || got[1].Type != EventSuspendDetected {
+		t.Errorf("expected the events as recorded, got %+v", got)
+	}
+	// The alert history and the event stream are subscribers of the same bus
+	if history := m.AlertHistory(); len(history) != 2 || history[0].Sensor != "CPU" {
+		t.Errorf("expected the events in the alert history too, got %+v", history)
+	}
+	m.Close()
+	lines := strings.Split(strings.TrimSpace(stream.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"type":"state_changed","sensor":"CPU"`) || !strings.Contains(lines[1], `"type":"suspend_detected"`) {
+		t.Errorf("expected the events in the stream, written by Close at the latest, got:\n%s", stream.String())
+	}
+}
@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// chipSummary is the min/avg/max row of an hwmon chip's temperatures,
+// computed from the readings already taken
+type chipSummary struct {
+	label         string
+	min, avg, max float64
+	// state is the hottest channel's, which colors the row
+	state State
+	// first is the position of the chip's first channel in the list
+	first    int
+	channels int
+}
+
+// chipSummaries summarizes every hwmon chip with two temperature channels
+// or more, by the chip directory of their paths; thermal zones belong to no
+// chip. Asleep channels are left out of the figures, and chips with all of
+// them asleep get no summary. hidden marks the channels of the chips with
+// more than summarizeOver channels, which show as their summary alone; 0
+// hides none.
+func chipSummaries(temps []TemperatureSensor, summarizeOver int) ([]chipSummary, map[int]bool) {
+	var summaries []chipSummary
+	var dirs []string
+	var members [][]int
+	position := make(map[string]int)
+	for i, sensor := range temps {
+		if sensor.chip == "" {
+			continue
+		}
+		dir := filepath.Dir(sensor.Path)
+		at, ok := position[dir]
+		if !ok {
+			at = len(summaries)
+			position[dir] = at
+			summaries = append(summaries, chipSummary{label: sensor.chip, first: i})
+			dirs = append(dirs, dir)
+			members = append(members, nil)
+		}
+		members[at] = append(members[at], i)
+	}
+
+	// Chips sharing a driver, such as several NVMe drives, are told apart
+	// by their hwmon directory
+	labels := make(map[string]int)
+	for _, s := range summaries {
+		labels[s.label]++
+	}
+	var kept []chipSummary
+	var hidden map[int]bool
+	for at, s := range summaries {
+		channels := members[at]
+		awake := 0
+		for _, i := range channels {
+			sensor := temps[i]
+			if sensor.Asleep {
+				continue
+			}
+			if awake == 0 || sensor.Value < s.min {
+				s.min = sensor.Value
+			}
+			if awake == 0 || sensor.Value > s.max {
+				s.max, s.state = sensor.Value, sensor.State()
+			}
+			s.avg += sensor.Value
+			awake++
+		}
+		if len(channels) < 2 || awake == 0 {
+			continue
+		}
+		s.avg /= float64(awake)
+		s.channels = len(channels)
+		if labels[s.label] > 1 {
+			s.label += " (" + filepath.Base(dirs[at]) + ")"
+		}
+		kept = append(kept, s)
+		if summarizeOver > 0 && len(channels) > summarizeOver {
+			if hidden == nil {
+				hidden = make(map[int]bool)
+			}
+			for _, i := range channels {
+				hidden[i] = true
+			}
+		}
+	}
+	return kept, hidden
+}
+
+// line formats the summary for the temperature list, e.g.
+// "coretemp: 42 / 51 / 68°C", colored by the hottest channel, with the
+// channel count when they are hidden
+func (s chipSummary) line(theme Theme, view ViewState, hidden bool) string {
+	figures := fmt.Sprintf("%.0f / %.0f / %.0f%s", view.Unit.convert(s.min), view.Unit.convert(s.avg), view.Unit.convert(s.max), view.Unit.symbol())
+	line := fmt.Sprintf("  %s: %s", s.label, theme.stateStyle(s.state).Render(view.Numbers.localize(figures)))
+	if hidden {
+		line += lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf(" (%d channels)", s.channels))
+	}
+	return line
+}
@@ -0,0 +1,90 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func writeChipFixture(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":          "coretemp\n",
+		"class/hwmon/hwmon0/temp1_input":   "42000\n",
+		"class/hwmon/hwmon0/temp2_input":   "43000\n",
+		"class/hwmon/hwmon0/temp3_input":   "68000\n",
+		"class/hwmon/hwmon0/temp3_max":     "65000\n",
+		"class/hwmon/hwmon1/name":          "nvme\n",
+		"class/hwmon/hwmon1/temp1_input":   "38000\n",
+		"class/hwmon/hwmon2/name":          "nvme\n",
+		"class/hwmon/hwmon2/temp1_input":   "40000\n",
+		"class/hwmon/hwmon2/temp2_input":   "44000\n",
+		"class/hwmon/hwmon3/name":          "nvme\n",
+		"class/hwmon/hwmon3/temp1_input":   "30000\n",
+		"class/hwmon/hwmon3/temp2_input":   "32000\n",
+		"class/thermal/thermal_zone0/type": "acpitz\n",
+		"class/thermal/thermal_zone0/temp": "47000\n",
+	})
+	return root
+}
+
+func TestChipSummaries(t *testing.T) {
+	temps := readTemperatures(writeChipFixture(t))
+	summaries, hidden := chipSummaries(temps, 0)
+	if len(summaries) != 3 || hidden != nil {
+		t.Fatalf("expected the three chips with several channels summarized, got %+v, hidden %v", summaries, hidden)
+	}
+	core := summaries[0]
+	if core.label != "coretemp" || core.min != 42 || core.avg != 51 || core.max != 68 || core.state != StateWarning || core.channels != 3 {
+		t.Errorf("expected coretemp 42/51/68 warning, got %+v", core)
+	}
+	if summaries[1].label != "nvme (hwmon2)" || summaries[2].label != "nvme (hwmon3)" {
+		t.Errorf("expected the NVMe chips told apart, got %q and %q", summaries[1].label, summaries[2].label)
+	}
+
+	if _, hidden := chipSummaries(temps, 2); len(hidden) != 3 || !hidden[core.first] {
+		t.Errorf("expected only the coretemp channels hidden past 2, got %v", hidden)
+	}
+
+	// Asleep channels don't count
+	for i := range temps {
+		if temps[i].chip == "coretemp" {
+			temps[i].Asleep = true
+		}
+	}
+	if summaries, _ := chipSummaries(temps, 0); len(summaries) != 2 {
+		t.Errorf("expected no summary for a chip asleep, got %+v", summaries)
+	}
+}
+
+func TestChipSummaryRows(t *testing.T) {
+	root := writeChipFixture(t)
+	m := NewMonitor()
+	m.temperatureSensors = readTemperatures(root)
+	snap := m.Snapshot()
+
+	full := ansi.Strip(RenderFull(snap, 200, 40, DefaultTheme, ViewState{}))
+	if !strings.Contains(full, "  coretemp: 42 / 51 / 68°C ") || !strings.Contains(full, "hwmon0/temp1_input") {
+		t.Errorf("expected the summary above the channels:\n%s", full)
+	}
+
+	summarized := ansi.Strip(RenderFull(snap, 200, 40, DefaultTheme, ViewState{SummarizeChips: 2, Unit: Fahrenheit}))
+	if !strings.Contains(summarized, "coretemp: 108 / 124 / 154°F (3 channels)") || strings.Contains(summarized, "hwmon0/temp") {
+		t.Errorf("expected coretemp as its summary alone:\n%s", summarized)
+	}
+	if !strings.Contains(summarized, "hwmon2/temp1_input") {
+		t.Errorf("expected smaller chips listed:\n%s", summarized)
+	}
+
+	// Hidden channels can't be selected, but stay in the snapshot for the
+	// exporters
+	m.config.SummarizeChips = 2
+	if rows := m.rows(); len(rows) != len(m.temperatureSensors)-3 {
+		t.Errorf("expected the hidden channels skipped, got %d rows", len(rows))
+	}
+	if got := len(m.Snapshot().Temperatures); got != 9 {
+		t.Errorf("expected every channel in the snapshot, got %d", got)
+	}
+}
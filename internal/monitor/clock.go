@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Clock is the monitor's source of time: the refresh timestamps, countdowns
+// and every delayed message (ticks, footer redraws, debounced saves) go
+// through it. Tests inject a fake one to travel in time; embedders can tie
+// the refreshes to their own scheduler.
+type Clock interface {
+	Now() time.Time
+	// Tick returns a command delivering fn's message after d, like tea.Tick
+	Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd
+}
+
+// realClock is the wall clock, scheduling with bubbletea's timers
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+	return tea.Tick(d, fn)
+}
+
+// WithClock replaces the wall clock
+func WithClock(c Clock) Option {
+	return func(m *Monitor) {
+		m.clock = c
+	}
+}
+
+// tickMsg triggers the refresh due at due. Ticks scheduled before a pause
+// or an interval change are due at another time and ignored, so only one
+// refresh chain is ever running.
+type tickMsg struct {
+	due time.Time
+}
+
+// tick schedules the refresh due at nextRefresh
+func (m Monitor) tick() tea.Cmd {
+	due := m.nextRefresh
+	return m.clock.Tick(max(due.Sub(m.clock.Now()), 0), func(time.Time) tea.Msg {
+		return tickMsg{due: due}
+	})
+}
+
+// handleTick refreshes when the tick is the one currently due
+func (m Monitor) handleTick(msg tickMsg) (Monitor, tea.Cmd) {
+	if m.paused || m.preloading() || !msg.due.Equal(m.nextRefresh) {
+		return m, nil
+	}
+	m = m.Refresh()
+	return m, tea.Batch(m.tick(), m.emitSnapshot())
+}
+
+// SetInterval changes the time between refreshes. The next refresh moves to
+// one new interval after the last one, right away if that has passed.
+func (m Monitor) SetInterval(d time.Duration) (Monitor, tea.Cmd) {
+	if d <= 0 {
+		return m, nil
+	}
+	m.interval = d
+	if m.paused {
+		return m, nil
+	}
+	m.nextRefresh = m.lastUpdate.Add(m.refreshInterval())
+	return m, m.tick()
+}
+
+// togglePause stops or resumes the refreshes. A resumed monitor refreshes
+// right away, since its readings are stale.
+func (m Monitor) togglePause() (Monitor, tea.Cmd) {
+	m.paused = !m.paused
+	if m.paused {
+		m.status = "Paused"
+		return m, nil
+	}
+	m.nextRefresh = m.clock.Now()
+	m.resumedAt = m.nextRefresh
+	return m, m.tick()
+}
+
+// countdownMsg redraws the next-refresh countdown in the footer
+type countdownMsg struct{}
+
+func (m Monitor) countdown() tea.Cmd {
+	return m.clock.Tick(countdownInterval, func(time.Time) tea.Msg {
+		return countdownMsg{}
+	})
+}
@@ -0,0 +1,80 @@
+package monitor
+
+import "time"
+
+// NTP steps and manual changes move the wall clock under the monitor, which
+// made "Last updated" and the rates misleading. Refreshes compare the wall
+// time elapsed with a monotonic clock: intervals are measured on the latter,
+// and a jump resets the rates and is noted in the footer for a few
+// refreshes.
+
+const (
+	// clockJumpThreshold is how far the wall clock may drift from the
+	// monotonic one between refreshes before it counts as a jump; NTP slews
+	// stay far below it
+	clockJumpThreshold = 2 * time.Second
+
+	// clockJumpRefreshes is for how many refreshes the footer notes a jump
+	clockJumpRefreshes = 3
+)
+
+// MonotonicClock is a Clock that also tells the time on a clock that only
+// moves forward, including across suspends. Without it, as with a Clock
+// from WithClock that doesn't implement it, jumps go unnoticed.
+type MonotonicClock interface {
+	Clock
+	// Monotonic returns the time elapsed since an arbitrary origin
+	Monotonic() time.Duration
+}
+
+// monotonicStart is the origin of the monotonic clock where there is no
+// boot time to read
+var monotonicStart = time.Now()
+
+// Monotonic is the time since boot, suspends included, or else since the
+// start, which Go measures on the monotonic clock
+func (realClock) Monotonic() time.Duration {
+	if d, ok := bootTime(); ok {
+		return d
+	}
+	return time.Since(monotonicStart)
+}
+
+// monotonic reads the clock's monotonic time, if it has one
+func (m Monitor) monotonic() (time.Duration, bool) {
+	if c, ok := m.clock.(MonotonicClock); ok {
+		return c.Monotonic(), true
+	}
+	return 0, false
+}
+
+// checkClock measures the time since the last refresh on the monotonic
+// clock and looks for a wall-clock jump in it. A jump resets the network
+// rates, whose counters would otherwise be divided by the jumped interval.
+func (m *Monitor) checkClock() time.Duration {
+	wall := m.clock.Now().Sub(m.lastUpdate)
+	mono, ok := m.monotonic()
+	if !ok {
+		return wall
+	}
+	elapsed := mono - m.monoUpdate
+	if jump := wall - elapsed; jump > clockJumpThreshold || jump < -clockJumpThreshold {
+		m.clockJump = jump
+		m.clockJumpLeft = clockJumpRefreshes
+		if m.network != nil {
+			m.network.reset()
+		}
+	} else if m.clockJumpLeft > 0 {
+		m.clockJumpLeft--
+	}
+	return elapsed
+}
+
+// clockChanged returns the last jump of the wall clock while the footer
+// notes it, 0 otherwise
+func (m Monitor) clockChanged() time.Duration {
+	if m.clockJumpLeft == 0 {
+		return 0
+	}
+	return m.clockJump
+}
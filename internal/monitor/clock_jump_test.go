@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// jumpingClock is a fakeClock with a monotonic time, whose wall time a test
+// can move alone
+type jumpingClock struct {
+	*fakeClock
+	mono time.Duration
+}
+
+func (c *jumpingClock) Monotonic() time.Duration {
+	return c.mono
+}
+
+// step moves both clocks forward by d
+func (c *jumpingClock) step(d time.Duration) {
+	c.now = c.now.Add(d)
+	c.mono += d
+}
+
+func newJumpingMonitor(t *testing.T) (Monitor, *jumpingClock) {
+	t.Helper()
+	clock := &jumpingClock{fakeClock: &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}}
+	m := NewMonitor(WithClock(clock), WithDemo(DefaultDemoSeed))
+	clock.step(DefaultInterval)
+	return m.Refresh(), clock
+}
+
+func TestClockJumpNotedInFooter(t *testing.T) {
+	for _, jump := range []time.Duration{time.Hour, -time.Hour} {
+		m, clock := newJumpingMonitor(t)
+		m.width, m.height = 100, 40
+		if strings.Contains(ansi.Strip(m.View()), "clock changed") {
+			t.Fatalf("unexpected clock change before the jump:\n%s", ansi.Strip(m.View()))
+		}
+
+		clock.now = clock.now.Add(jump)
+		clock.step(DefaultInterval)
+		m = m.Refresh()
+		if m.clockJump != jump {
+			t.Errorf("expected a %v jump, got %v", jump, m.clockJump)
+		}
+		footer := footerLine(m.View())
+		if want := "Last updated: " + clock.now.Format("15:04:05") + " (clock changed)"; !strings.Contains(footer, want) {
+			t.Errorf("expected %q in the footer, got %q", want, footer)
+		}
+
+		for range clockJumpRefreshes - 1 {
+			clock.step(DefaultInterval)
+			m = m.Refresh()
+		}
+		if !strings.Contains(footerLine(m.View()), "clock changed") {
+			t.Errorf("expected the jump noted for %d refreshes", clockJumpRefreshes)
+		}
+		clock.step(DefaultInterval)
+		m = m.Refresh()
+		if footer := footerLine(m.View()); strings.Contains(footer, "clock changed") {
+			t.Errorf("expected the note gone after %d refreshes, got %q", clockJumpRefreshes, footer)
+		}
+	}
+}
+
+func TestClockJumpMeasuresMonotonicTime(t *testing.T) {
+	m, clock := newJumpingMonitor(t)
+	clock.now = clock.now.Add(-10 * time.Minute)
+	clock.step(3 * time.Second)
+	if elapsed := m.checkClock(); elapsed != 3*time.Second {
+		t.Errorf("expected 3s elapsed on the monotonic clock, got %v", elapsed)
+	}
+
+	// Drift below the threshold isn't a jump
+	m, clock = newJumpingMonitor(t)
+	clock.now = clock.now.Add(clockJumpThreshold / 2)
+	clock.step(DefaultInterval)
+	m = m.Refresh()
+	if m.clockChanged() != 0 {
+		t.Errorf("expected no jump for %v of drift, got %v", clockJumpThreshold/2, m.clockChanged())
+	}
+}
+
+func TestClockJumpResetsRates(t *testing.T) {
+	root := t.TempDir()
+	writeNetCounters(t, root, "eth0", "1000\n", "0\n")
+	m, clock := newJumpingMonitor(t)
+	m.network = newNetworkCollector(root, NetworkConfig{})
+	m.network.groups(clock.now)
+
+	// Stepped back by a minute, the bytes of 2s would be divided by -58s
+	writeNetCounters(t, root, "eth0", "3000\n", "0\n")
+	clock.now = clock.now.Add(-time.Minute)
+	clock.step(DefaultInterval)
+	m = m.Refresh()
+	groups := m.network.groups(clock.now)
+	if got := groups[0].Sensors[0].Value(); got != formatRate(0, IECBytes, false) {
+		t.Errorf("expected the rate reset after the jump, got %s", got)
+	}
+
+	writeNetCounters(t, root, "eth0", "7000\n", "0\n")
+	clock.step(DefaultInterval)
+	m = m.Refresh()
+	groups = m.network.groups(clock.now)
+	if got, want := groups[0].Sensors[0].Value(), formatRate(2000, IECBytes, false); got != want {
+		t.Errorf("expected %s once the clock is steady, got %s", want, got)
+	}
+}
+
+func TestWithoutMonotonicClock(t *testing.T) {
+	m, clock := newClockedMonitor(DefaultInterval)
+	clock.now = clock.now.Add(time.Hour)
+	m = m.Refresh()
+	if m.clockChanged() != 0 {
+		t.Error("a clock without monotonic time can't tell a jump")
+	}
+}
+
+func TestRealClockMonotonic(t *testing.T) {
+	var clock realClock
+	before := clock.Monotonic()
+	time.Sleep(10 * time.Millisecond)
+	if elapsed := clock.Monotonic() - before; elapsed < 10*time.Millisecond || elapsed > time.Second {
+		t.Errorf("expected about 10ms on the monotonic clock, got %v", elapsed)
+	}
+}
@@ -0,0 +1,132 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeClock is a Clock whose time only moves when a test advances it
+type fakeClock struct {
+	now    time.Time
+	timers []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	fn func(time.Time) tea.Msg
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// Tick registers the timer right away; advance delivers its message
+func (c *fakeClock) Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+	c.timers = append(c.timers, fakeTimer{at: c.now.Add(d), fn: fn})
+	return nil
+}
+
+// advance moves the clock forward by d, delivering the message of every
+// timer falling due to the monitor in time order, including timers the
+// monitor schedules on the way
+func (c *fakeClock) advance(m Monitor, d time.Duration) Monitor {
+	until := c.now.Add(d)
+	for {
+		next := -1
+		for i, timer := range c.timers {
+			if !timer.at.After(until) && (next < 0 || timer.at.Before(c.timers[next].at)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		timer := c.timers[next]
+		c.timers = append(c.timers[:next], c.timers[next+1:]...)
+		c.now = timer.at
+		m, _ = m.Update(timer.fn(timer.at))
+	}
+	c.now = until
+	return m
+}
+
+// newClockedMonitor returns a demo monitor, so refreshes never touch sysfs,
+// running on a fake clock; every refresh adds a battery sample
+func newClockedMonitor(interval time.Duration) (Monitor, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	m := NewMonitor(WithClock(clock), WithDemo(DefaultDemoSeed), WithInterval(interval))
+	m.Init()
+	return m, clock
+}
+
+func TestTicksFollowTheClock(t *testing.T) {
+	m, clock := newClockedMonitor(2 * time.Second)
+	start := clock.now
+
+	m = clock.advance(m, 5*time.Second)
+	if n := len(m.BatteryHistory()); n != 2 {
+		t.Fatalf("expected refreshes at 2s and 4s, got %d", n)
+	}
+	if !m.lastUpdate.Equal(start.Add(4*time.Second)) || !m.nextRefresh.Equal(start.Add(6*time.Second)) {
+		t.Errorf("expected the last refresh at 4s and the next at 6s, got %v and %v", m.lastUpdate.Sub(start), m.nextRefresh.Sub(start))
+	}
+	m.width, m.height = 80, 24
+	if view := m.View(); !strings.Contains(view, "next in 1s") {
+		t.Errorf("expected the countdown to follow the fake clock, got:\n%s", view)
+	}
+}
+
+func TestPauseAndResume(t *testing.T) {
+	m, clock := newClockedMonitor(2 * time.Second)
+	m = clock.advance(m, 3*time.Second)
+
+	m = sendKeys(m, "p")
+	m = clock.advance(m, time.Minute)
+	if n := len(m.BatteryHistory()); n != 1 {
+		t.Fatalf("expected no refresh while paused, got %d samples", n)
+	}
+	m.width, m.height = 80, 24
+	if view := m.View(); !strings.Contains(view, "| paused") {
+		t.Errorf("expected the footer to show the pause, got:\n%s", view)
+	}
+
+	// Resuming refreshes right away, then every interval again
+	resumed := clock.now
+	m = sendKeys(m, "p")
+	m = clock.advance(m, 0)
+	if n := len(m.BatteryHistory()); n != 2 || !m.lastUpdate.Equal(resumed) {
+		t.Fatalf("expected a refresh on resume, got %d samples, last at %v", n, m.lastUpdate)
+	}
+	m = clock.advance(m, 4*time.Second)
+	if n := len(m.BatteryHistory()); n != 4 {
+		t.Errorf("expected one refresh chain after resuming, got %d samples", n)
+	}
+}
+
+func TestSetInterval(t *testing.T) {
+	m, clock := newClockedMonitor(2 * time.Second)
+	start := clock.now
+	m = clock.advance(m, 3*time.Second)
+
+	// The tick already scheduled for 4s is dropped
+	m, _ = m.SetInterval(10 * time.Second)
+	m = clock.advance(m, 8*time.Second)
+	if n := len(m.BatteryHistory()); n != 1 {
+		t.Fatalf("expected no refresh before 12s, got %d samples", n)
+	}
+	m = clock.advance(m, 11*time.Second)
+	if n := len(m.BatteryHistory()); n != 3 || !m.lastUpdate.Equal(start.Add(22*time.Second)) {
+		t.Errorf("expected refreshes at 12s and 22s, got %d samples, last at %v", n, m.lastUpdate.Sub(start))
+	}
+
+	// A shorter interval that has already passed refreshes at once
+	m = clock.advance(m, 5*time.Second)
+	m, _ = m.SetInterval(time.Second)
+	m = clock.advance(m, 0)
+	if !m.lastUpdate.Equal(start.Add(27 * time.Second)) {
+		t.Errorf("expected an immediate refresh, last at %v", m.lastUpdate.Sub(start))
+	}
+}
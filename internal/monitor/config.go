@@ -0,0 +1,225 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Config is the user-edited configuration file
+type Config struct {
+	// Hostname labels snapshots, events and metrics instead of the system
+	// host name, e.g. when aggregating several machines
+	Hostname string `json:"hostname,omitempty"`
+
+	// Overrides replaces the sysfs thresholds of temperature sensors, keyed
+	// by sensor name
+	Overrides map[string]ThresholdOverride `json:"overrides,omitempty"`
+
+	// MinValidTemperature is the lowest reading in Celsius accepted as real;
+	// lower readings are dropped as bogus (default -100)
+	MinValidTemperature *float64 `json:"min_valid_temperature,omitempty"`
+
+	// Interval is the time between refreshes, e.g. "5s" (default 2s);
+	// --interval takes precedence
+	Interval string `json:"interval,omitempty"`
+
+	// StaleTimeout is how long a temperature whose reads fail transiently
+	// keeps showing its last value, e.g. "1m" (default 30s)
+	StaleTimeout string `json:"stale_timeout,omitempty"`
+
+	// Offsets corrects temperature readings, keyed by sensor name or a glob
+	// matched against the name or value file path, in degrees Celsius
+	Offsets map[string]float64 `json:"offsets,omitempty"`
+
+	// Profiles adjust thresholds by time of day or power source; the first
+	// matching profile applies
+	Profiles []Profile `json:"profiles,omitempty"`
+
+	// NotChargingThresholds are the battery capacity thresholds used while
+	// the AC adapter is online but a charge limit holds charging
+	NotChargingThresholds *BatteryThresholds `json:"not_charging_thresholds,omitempty"`
+
+	// ByteUnits is "iec" (KiB, 1024-based, the default) or "si" (kB,
+	// 1000-based) for every size and rate
+	ByteUnits string `json:"byte_units,omitempty"`
+
+	// NetworkRates is "bytes" (the default) or "bits" per second
+	NetworkRates string `json:"network_rates,omitempty"`
+
+	// SelfRSSLimitMB is the resident memory, in MiB, above which the Self
+	// group (--self) warns
+	SelfRSSLimitMB uint64 `json:"self_rss_limit_mb,omitempty"`
+
+	// HistoryMaxMB is the size, in MiB, at which the --history file is
+	// rotated to history.jsonl.1, replacing the previous one (default 64)
+	HistoryMaxMB uint64 `json:"history_max_mb,omitempty"`
+
+	// UnderpoweredTicks is how many consecutive refreshes the battery must
+	// discharge on AC before the adapter is reported as underpowered
+	UnderpoweredTicks int `json:"underpowered_ticks,omitempty"`
+
+	// DisabledProviders names discovery providers to skip, e.g. "backlight"
+	DisabledProviders []string `json:"disabled_providers,omitempty"`
+
+	// Theme is "dark" (the default) or "light", for terminals with a light
+	// background
+	Theme string `json:"theme,omitempty"`
+
+	// Network selects the interfaces whose throughput is shown
+	Network *NetworkConfig `json:"network,omitempty"`
+
+	// Scripts configures the sensor scripts of sensors.d
+	Scripts *ScriptsConfig `json:"scripts,omitempty"`
+
+	// FanCheck enables the "Fan response" check of the Fans group
+	FanCheck *FanCheckConfig `json:"fan_check,omitempty"`
+
+	// Exclude hides the group sensors matching any of its filters, e.g.
+	// "kind=voltage"
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Mute ignores the alerts of the sensors matching any of its globs,
+	// matched against temperature names and paths and "Group/name" of
+	// group sensors; they are still shown
+	Mute []string `json:"mute,omitempty"`
+
+	// WakeOnRead lists the runtime-suspended devices whose temperatures
+	// are read anyway, waking them up, as globs matched against the hwmon
+	// chip name ("amdgpu") or the device (PCI address "0000:01:00.0" or
+	// block device "nvme0n1"). Others are shown asleep until they resume.
+	WakeOnRead []string `json:"wake_on_read,omitempty"`
+
+	// SensorChangeToast shows a toast when rediscovery adds or removes
+	// temperatures; the alert history records the change either way
+	SensorChangeToast bool `json:"sensor_change_toast,omitempty"`
+
+	// UnchangedTimes marks slow-moving group readings (percentages and
+	// text) in the full view with how long they have been unchanged
+	UnchangedTimes bool `json:"unchanged_times,omitempty"`
+
+	// FrozenTicks is how many refreshes a reading may hold the exact same
+	// value while others change before it is marked frozen (default 300);
+	// a negative value turns the check off
+	FrozenTicks int `json:"frozen_ticks,omitempty"`
+
+	// SummarizeChips shows the hwmon chips with more than this many
+	// temperature channels as their min/avg/max row alone, e.g. 8 for a
+	// 16-core coretemp; 0 (the default) lists every channel
+	SummarizeChips int `json:"summarize_chips,omitempty"`
+
+	// Footer and CompactFooter replace the footer templates of the full
+	// and compact views, e.g. "{{.LastUpdate.Format \"15:04\"}} | every
+	// {{.Interval}}"; see FooterContext for the fields
+	Footer        string `json:"footer,omitempty"`
+	CompactFooter string `json:"compact_footer,omitempty"`
+}
+
+// ThresholdOverride holds user-defined thresholds for one sensor, in Celsius
+type ThresholdOverride struct {
+	High     float64 `json:"high"`
+	Critical float64 `json:"critical"`
+}
+
+// DefaultConfigPath returns $XDG_CONFIG_HOME/sysfs-monitor-tui/config.json
+func DefaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "sysfs-monitor-tui", "config.json")
+}
+
+// LoadConfig reads the config file at path. A missing file yields an empty
+// config and no error.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	for _, p := range cfg.Profiles {
+		if err := p.validate(); err != nil {
+			return cfg, err
+		}
+	}
+	if _, ok := themes[cfg.Theme]; cfg.Theme != "" && !ok {
+		return cfg, fmt.Errorf("unknown theme %q", cfg.Theme)
+	}
+	if cfg.ByteUnits != "" && !slices.Contains(byteUnitsNames, cfg.ByteUnits) {
+		return cfg, fmt.Errorf("unknown byte_units %q, want %s", cfg.ByteUnits, strings.Join(byteUnitsNames, " or "))
+	}
+	if cfg.NetworkRates != "" && !slices.Contains(networkRatesNames, cfg.NetworkRates) {
+		return cfg, fmt.Errorf("unknown network_rates %q, want %s", cfg.NetworkRates, strings.Join(networkRatesNames, " or "))
+	}
+	if cfg.Scripts != nil {
+		if err := cfg.Scripts.validate(); err != nil {
+			return cfg, err
+		}
+	}
+	if cfg.FanCheck != nil {
+		if err := cfg.FanCheck.validate(); err != nil {
+			return cfg, err
+		}
+	}
+	if cfg.Interval != "" {
+		if d, err := time.ParseDuration(cfg.Interval); err != nil {
+			return cfg, fmt.Errorf("interval: %w", err)
+		} else if d <= 0 {
+			return cfg, fmt.Errorf("interval: %q is not positive", cfg.Interval)
+		}
+	}
+	if cfg.StaleTimeout != "" {
+		if _, err := time.ParseDuration(cfg.StaleTimeout); err != nil {
+			return cfg, fmt.Errorf("stale_timeout: %w", err)
+		}
+	}
+	if _, err := parseExclude(cfg.Exclude); err != nil {
+		return cfg, err
+	}
+	if err := validateMute(cfg.Mute); err != nil {
+		return cfg, err
+	}
+	if _, err := ParseFooter(cfg.Footer); err != nil {
+		return cfg, fmt.Errorf("footer: %w", err)
+	}
+	if _, err := ParseFooter(cfg.CompactFooter); err != nil {
+		return cfg, fmt.Errorf("compact_footer: %w", err)
+	}
+	for _, pattern := range cfg.WakeOnRead {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return cfg, fmt.Errorf("wake_on_read %q: %w", pattern, err)
+		}
+	}
+	return cfg, nil
+}
+
+// RefreshInterval returns the interval setting, 0 when unset. LoadConfig
+// has validated it.
+func (c Config) RefreshInterval() time.Duration {
+	d, _ := time.ParseDuration(c.Interval)
+	return d
+}
+
+// Save writes the config to path, creating its directory if needed
+func (c Config) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
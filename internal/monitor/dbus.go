@@ -0,0 +1,440 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DBusName is the well-known name of the D-Bus service, which exports the
+// readings on the session bus for desktop applets
+const DBusName = "org.sysfsmonitor.Monitor1"
+
+// DefaultDBusSensors is how many of the hottest temperatures get an object
+// of their own
+const DefaultDBusSensors = 5
+
+const (
+	dbusPath            = "/org/sysfsmonitor/Monitor1"
+	dbusSensorsPath     = dbusPath + "/Sensors"
+	dbusSensorInterface = "org.sysfsmonitor.Sensor1"
+	dbusPropertiesIface = "org.freedesktop.DBus.Properties"
+	dbusIntrospectable  = "org.freedesktop.DBus.Introspectable"
+	dbusPeer            = "org.freedesktop.DBus.Peer"
+
+	// dbusReconnect is the time between attempts to reach a missing bus
+	dbusReconnect = 30 * time.Second
+
+	// dbusDoNotQueue is the RequestName flag failing the request when
+	// another connection owns the name, instead of waiting in line for it
+	dbusDoNotQueue = uint32(4)
+	// dbusPrimaryOwner is the RequestName reply granting the name
+	dbusPrimaryOwner = uint32(1)
+)
+
+// DBusService exports the latest snapshot on the session bus as DBusName:
+// a GetSnapshot method returning it as JSON, the worst state and counts as
+// properties whose PropertiesChanged signal is sent after every refresh,
+// and an object per hottest temperature under /org/sysfsmonitor/Monitor1/Sensors.
+// It reads the same snapshots as PrometheusHandler. The bus going away
+// only stops the service until it can connect again.
+type DBusService struct {
+	latest  func() (Snapshot, bool)
+	sensors int
+	updates chan Snapshot
+	done    chan struct{}
+	close   sync.Once
+
+	// dial connects and authenticates; tests replace it
+	dial func() (io.ReadWriteCloser, error)
+}
+
+// NewDBusService creates a service exporting the snapshots latest returns,
+// with objects for the sensors hottest temperatures. Start connects it.
+func NewDBusService(latest func() (Snapshot, bool), sensors int) *DBusService {
+	return &DBusService{
+		latest:  latest,
+		sensors: sensors,
+		updates: make(chan Snapshot, 1),
+		done:    make(chan struct{}),
+		dial:    dialSessionBus,
+	}
+}
+
+// Publish queues the PropertiesChanged signal of a refresh, replacing one
+// not sent yet. It never blocks, whether or not the bus is connected.
+func (s *DBusService) Publish(snap Snapshot) {
+	select {
+	case <-s.updates:
+	default:
+	}
+	select {
+	case s.updates <- snap:
+	default:
+	}
+}
+
+// Start connects to the session bus, registers DBusName and serves in the
+// background until Close. Only a failure of this first connection is
+// returned, such as another monitor owning the name; after losing the bus
+// the service tries to reconnect every 30s.
+func (s *DBusService) Start() error {
+	session, err := s.connect()
+	if err != nil {
+		return err
+	}
+	go s.run(session)
+	return nil
+}
+
+func (s *DBusService) run(session *dbusSession) {
+	for {
+		if session != nil {
+			s.serve(session)
+		}
+		select {
+		case <-s.done:
+			return
+		case <-time.After(dbusReconnect):
+		}
+		session, _ = s.connect()
+	}
+}
+
+// connect dials the bus and registers the service on it
+func (s *DBusService) connect() (*dbusSession, error) {
+	conn, err := s.dial()
+	if err != nil {
+		return nil, err
+	}
+	session, err := registerDBus(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return session, nil
+}
+
+// Close stops the service and disconnects from the bus
+func (s *DBusService) Close() {
+	s.close.Do(func() { close(s.done) })
+}
+
+// dbusConn numbers and writes the messages of a connection
+type dbusConn struct {
+	mu     sync.Mutex
+	w      io.Writer
+	serial uint32
+}
+
+func (c *dbusConn) send(m dbusMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.serial++
+	m.Serial = c.serial
+	_, err := c.w.Write(m.marshal())
+	return err
+}
+
+// dbusSession is a connection that owns DBusName
+type dbusSession struct {
+	rw io.ReadWriteCloser
+	c  *dbusConn
+	r  *bufio.Reader
+}
+
+// registerDBus says Hello on a connected bus and requests DBusName,
+// without queueing: the name is refused while another monitor keeps it.
+func registerDBus(rw io.ReadWriteCloser) (*dbusSession, error) {
+	session := &dbusSession{rw: rw, c: &dbusConn{w: rw}, r: bufio.NewReader(rw)}
+	name, sig := dbusBody(DBusName, dbusDoNotQueue)
+	for _, m := range []dbusMessage{
+		{Member: "Hello"},
+		{Member: "RequestName", Body: name, Signature: sig},
+	} {
+		m.Type = dbusMethodCall
+		m.Destination, m.Path, m.Interface = "org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus"
+		if err := session.c.send(m); err != nil {
+			return nil, err
+		}
+	}
+	requested := session.c.serial
+
+	// The Hello reply and the NameAcquired signal come first
+	for {
+		m, err := readDBusMessage(session.r)
+		if err != nil {
+			return nil, err
+		}
+		if m.ReplySerial != requested || m.Type != dbusMethodReturn && m.Type != dbusError {
+			continue
+		}
+		if m.Type == dbusError {
+			return nil, fmt.Errorf("dbus: requesting %s: %s", DBusName, m.ErrorName)
+		}
+		d := m.decoder()
+		if reply := d.uint32(); m.Signature != "u" || d.err != nil || reply != dbusPrimaryOwner {
+			return nil, fmt.Errorf("dbus: %s is owned by another process", DBusName)
+		}
+		return session, nil
+	}
+}
+
+// serve answers method calls on a registered session, sending the queued
+// signals, until the connection fails or the service is closed
+func (s *DBusService) serve(session *dbusSession) error {
+	defer session.rw.Close()
+	c := session.c
+
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			m, err := readDBusMessage(session.r)
+			if err != nil {
+				errc <- err
+				return
+			}
+			if m.Type != dbusMethodCall {
+				continue
+			}
+			reply := s.handle(m)
+			if m.Flags&dbusNoReplyExpected != 0 {
+				continue
+			}
+			reply.ReplySerial, reply.Destination = m.Serial, m.Sender
+			if err := c.send(reply); err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case snap := <-s.updates:
+			body, sig := dbusBody(DBusName, monitorProperties(snap), []string{})
+			err := c.send(dbusMessage{
+				Type: dbusSignal, Path: dbusPath, Interface: dbusPropertiesIface, Member: "PropertiesChanged",
+				Body: body, Signature: sig,
+			})
+			if err != nil {
+				return err
+			}
+		case err := <-errc:
+			return err
+		case <-s.done:
+			return nil
+		}
+	}
+}
+
+// handle answers a method call
+func (s *DBusService) handle(m dbusMessage) dbusMessage {
+	switch {
+	case m.Interface == dbusPeer && m.Member == "Ping":
+		return dbusReply()
+	case m.Interface == dbusIntrospectable && m.Member == "Introspect":
+		if xml, ok := s.introspect(m.Path); ok {
+			return dbusReply(xml)
+		}
+		return dbusErrorReply("org.freedesktop.DBus.Error.UnknownObject", "No object at "+m.Path)
+	}
+
+	snap, ok := s.latest()
+	if !ok {
+		return dbusErrorReply("org.sysfsmonitor.Error.NoReadings", "No readings yet")
+	}
+	iface, props, found := s.object(m.Path, snap)
+	if !found {
+		return dbusErrorReply("org.freedesktop.DBus.Error.UnknownObject", "No object at "+m.Path)
+	}
+
+	switch {
+	case m.Path == dbusPath && (m.Interface == DBusName || m.Interface == "") && m.Member == "GetSnapshot":
+		data, err := json.Marshal(snap)
+		if err != nil {
+			return dbusErrorReply("org.freedesktop.DBus.Error.Failed", err.Error())
+		}
+		return dbusReply(string(data))
+	case m.Interface == dbusPropertiesIface && (m.Member == "Get" || m.Member == "GetAll"):
+		args, err := m.strings()
+		if err != nil || len(args) == 0 || m.Member == "Get" && len(args) != 2 {
+			return dbusErrorReply("org.freedesktop.DBus.Error.InvalidArgs", "Expected an interface name")
+		}
+		if args[0] != iface && args[0] != "" {
+			return dbusErrorReply("org.freedesktop.DBus.Error.UnknownInterface", "No interface "+args[0])
+		}
+		if m.Member == "GetAll" {
+			return dbusReply(props)
+		}
+		for _, p := range props {
+			if p.name == args[1] {
+				var e dbusEncoder
+				e.variant(p.value)
+				return dbusMessage{Type: dbusMethodReturn, Body: e.buf, Signature: "v"}
+			}
+		}
+		return dbusErrorReply("org.freedesktop.DBus.Error.UnknownProperty", "No property "+args[1])
+	case m.Interface == dbusPropertiesIface && m.Member == "Set":
+		return dbusErrorReply("org.freedesktop.DBus.Error.PropertyReadOnly", "Properties are read-only")
+	}
+	return dbusErrorReply("org.freedesktop.DBus.Error.UnknownMethod", fmt.Sprintf("No method %s.%s", m.Interface, m.Member))
+}
+
+// object returns the interface and properties of the object at path
+func (s *DBusService) object(path string, snap Snapshot) (string, dbusProperties, bool) {
+	if path == dbusPath {
+		return DBusName, monitorProperties(snap), true
+	}
+	index, ok := strings.CutPrefix(path, dbusSensorsPath+"/")
+	if !ok {
+		return "", nil, false
+	}
+	i, err := strconv.Atoi(index)
+	hottest := hottestTemperatures(snap.Temperatures, s.sensors)
+	if err != nil || i < 0 || i >= len(hottest) {
+		return "", nil, false
+	}
+	t := hottest[i]
+	return dbusSensorInterface, dbusProperties{
+		{"Name", t.Name},
+		{"Value", t.Value},
+		{"State", t.State().String()},
+		{"High", t.High},
+		{"Critical", t.Critical},
+		{"Path", t.Path},
+	}, true
+}
+
+// monitorProperties are the properties of the monitor object
+func monitorProperties(snap Snapshot) dbusProperties {
+	return dbusProperties{
+		{"Worst", snap.Worst.String()},
+		{"OK", uint32(snap.Counts.OK)},
+		{"Warning", uint32(snap.Counts.Warning)},
+		{"Critical", uint32(snap.Counts.Critical)},
+		{"Updated", snap.Time.Unix()},
+	}
+}
+
+// hottestTemperatures returns up to n temperatures, hottest first
+func hottestTemperatures(temps []TemperatureSensor, n int) []TemperatureSensor {
+	sorted := append([]TemperatureSensor(nil), temps...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Value > sorted[j].Value })
+	return sorted[:min(n, len(sorted))]
+}
+
+// introspect describes the object at path and its children
+func (s *DBusService) introspect(path string) (string, bool) {
+	var sb strings.Builder
+	sb.WriteString(`<!DOCTYPE node PUBLIC "-//freedesktop//DTD D-BUS Object Introspection 1.0//EN" "http://www.freedesktop.org/standards/dbus/1.0/introspect.dtd">` + "\n<node>\n")
+	property := func(name, typ string) {
+		fmt.Fprintf(&sb, "    <property name=%q type=%q access=\"read\"/>\n", name, typ)
+	}
+	switch {
+	case path == dbusPath:
+		sb.WriteString("  <interface name=\"" + DBusName + "\">\n")
+		sb.WriteString("    <method name=\"GetSnapshot\"><arg name=\"json\" type=\"s\" direction=\"out\"/></method>\n")
+		property("Worst", "s")
+		property("OK", "u")
+		property("Warning", "u")
+		property("Critical", "u")
+		property("Updated", "x")
+		sb.WriteString("  </interface>\n")
+		sb.WriteString("  <node name=\"Sensors\"/>\n")
+	case path == dbusSensorsPath:
+		for i := range s.sensors {
+			fmt.Fprintf(&sb, "  <node name=\"%d\"/>\n", i)
+		}
+	case strings.HasPrefix(path, dbusSensorsPath+"/"):
+		i, err := strconv.Atoi(strings.TrimPrefix(path, dbusSensorsPath+"/"))
+		if err != nil || i < 0 || i >= s.sensors {
+			return "", false
+		}
+		sb.WriteString("  <interface name=\"" + dbusSensorInterface + "\">\n")
+		property("Name", "s")
+		property("Value", "d")
+		property("State", "s")
+		property("High", "d")
+		property("Critical", "d")
+		property("Path", "s")
+		sb.WriteString("  </interface>\n")
+	case path == "/" || strings.HasPrefix(dbusPath, path+"/"):
+		child, _, _ := strings.Cut(strings.TrimPrefix(dbusPath, strings.TrimSuffix(path, "/")+"/"), "/")
+		fmt.Fprintf(&sb, "  <node name=%q/>\n", child)
+	default:
+		return "", false
+	}
+	sb.WriteString("</node>\n")
+	return sb.String(), true
+}
+
+func dbusReply(args ...any) dbusMessage {
+	body, sig := dbusBody(args...)
+	return dbusMessage{Type: dbusMethodReturn, Body: body, Signature: sig}
+}
+
+func dbusErrorReply(name, text string) dbusMessage {
+	body, sig := dbusBody(text)
+	return dbusMessage{Type: dbusError, ErrorName: name, Body: body, Signature: sig}
+}
+
+// dialSessionBus connects to the session bus and authenticates
+func dialSessionBus() (io.ReadWriteCloser, error) {
+	addr, err := sessionBusAddress(os.Getenv("DBUS_SESSION_BUS_ADDRESS"), os.Getenv("XDG_RUNTIME_DIR"))
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.Dial("unix", addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := dbusAuth(conn, os.Getuid()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// sessionBusAddress returns the socket of the first unix address in
+// DBUS_SESSION_BUS_ADDRESS, an abstract one starting with "@", or the bus
+// socket of the runtime directory
+func sessionBusAddress(env, runtimeDir string) (string, error) {
+	if env == "" {
+		if runtimeDir == "" {
+			return "", errors.New("dbus: no session bus (DBUS_SESSION_BUS_ADDRESS is unset)")
+		}
+		return filepath.Join(runtimeDir, "bus"), nil
+	}
+	for _, address := range strings.Split(env, ";") {
+		params, ok := strings.CutPrefix(address, "unix:")
+		if !ok {
+			continue
+		}
+		for _, param := range strings.Split(params, ",") {
+			key, value, _ := strings.Cut(param, "=")
+			value, err := url.PathUnescape(value)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "path":
+				return value, nil
+			case "abstract":
+				return "@" + value, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("dbus: no unix socket in %q", env)
+}
@@ -0,0 +1,230 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionBusAddress(t *testing.T) {
+	tests := []struct{ env, runtimeDir, want string }{
+		{"unix:path=/run/user/1000/bus", "", "/run/user/1000/bus"},
+		{"unix:abstract=/tmp/dbus-abc,guid=123", "", "@/tmp/dbus-abc"},
+		{"tcp:host=localhost,port=1;unix:path=/tmp/a%20b", "", "/tmp/a b"},
+		{"", "/run/user/1000", "/run/user/1000/bus"},
+	}
+	for _, tt := range tests {
+		if got, err := sessionBusAddress(tt.env, tt.runtimeDir); err != nil || got != tt.want {
+			t.Errorf("sessionBusAddress(%q, %q) = %q, %v; expected %q", tt.env, tt.runtimeDir, got, err, tt.want)
+		}
+	}
+	for _, env := range []string{"tcp:host=localhost,port=1", ""} {
+		if _, err := sessionBusAddress(env, ""); err == nil {
+			t.Errorf("expected no session bus in %q", env)
+		}
+	}
+}
+
+func TestDBusAuth(t *testing.T) {
+	client, bus := net.Pipe()
+	defer client.Close()
+	go func() {
+		r := bufio.NewReader(bus)
+		line, _ := r.ReadString('\n')
+		if line != "\x00AUTH EXTERNAL 31303030\r\n" {
+			bus.Write([]byte("REJECTED EXTERNAL\r\n"))
+			return
+		}
+		bus.Write([]byte("OK 0123456789abcdef\r\n"))
+		r.ReadString('\n')
+		bus.Close()
+	}()
+	if err := dbusAuth(client, 1000); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDBusMessageRoundTrip(t *testing.T) {
+	body, sig := dbusBody("org.sysfsmonitor.Sensor1", "Name")
+	sent := dbusMessage{
+		Type: dbusMethodCall, Serial: 7, Path: "/org/sysfsmonitor/Monitor1/Sensors/0",
+		Interface: dbusPropertiesIface, Member: "Get", Destination: DBusName, Sender: ":1.42",
+		Body: body, Signature: sig,
+	}
+	got, err := readDBusMessage(strings.NewReader(string(sent.marshal())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := got.strings()
+	if err != nil || len(args) != 2 || args[1] != "Name" {
+		t.Errorf("expected the arguments back, got %q, %v", args, err)
+	}
+	got.Body, sent.Body, got.order = nil, nil, nil
+	if !reflect.DeepEqual(got, sent) {
+		t.Errorf("expected %+v, got %+v", sent, got)
+	}
+}
+
+// dbusTestBus is the bus side of a served connection in tests
+type dbusTestBus struct {
+	t      *testing.T
+	conn   net.Conn
+	serial uint32
+}
+
+func (b *dbusTestBus) call(path, iface, member string, args ...any) dbusMessage {
+	b.t.Helper()
+	b.serial++
+	body, sig := dbusBody(args...)
+	msg := dbusMessage{Type: dbusMethodCall, Serial: b.serial, Path: path, Interface: iface, Member: member, Sender: ":1.7", Body: body, Signature: sig}
+	if _, err := b.conn.Write(msg.marshal()); err != nil {
+		b.t.Fatal(err)
+	}
+	reply := b.read()
+	if reply.ReplySerial != b.serial || reply.Destination != ":1.7" {
+		b.t.Fatalf("expected the reply to %d for :1.7, got %+v", b.serial, reply)
+	}
+	return reply
+}
+
+// register answers the Hello and RequestName of a connecting service,
+// with reply to the latter, and returns the RequestName call
+func (b *dbusTestBus) register(reply uint32) dbusMessage {
+	b.t.Helper()
+	hello := b.read()
+	if hello.Member != "Hello" {
+		b.t.Fatalf("expected Hello first, got %+v", hello)
+	}
+	request := b.read()
+	if request.Member != "RequestName" {
+		b.t.Fatalf("expected the name requested, got %+v", request)
+	}
+	for _, answer := range []struct {
+		call dbusMessage
+		args []any
+	}{{hello, []any{":1.7"}}, {request, []any{reply}}} {
+		b.serial++
+		body, sig := dbusBody(answer.args...)
+		msg := dbusMessage{Type: dbusMethodReturn, Serial: b.serial, ReplySerial: answer.call.Serial, Destination: ":1.7", Body: body, Signature: sig}
+		if _, err := b.conn.Write(msg.marshal()); err != nil {
+			b.t.Fatal(err)
+		}
+	}
+	return request
+}
+
+// serveTestBus registers svc on a test bus granting it the name and serves
+// it in the background until done receives the end of serving
+func serveTestBus(t *testing.T, svc *DBusService) (bus *dbusTestBus, done chan error) {
+	t.Helper()
+	conn, served := net.Pipe()
+	bus = &dbusTestBus{t: t, conn: conn}
+	done = make(chan error, 1)
+	go func() {
+		session, err := registerDBus(served)
+		if err != nil {
+			done <- err
+			return
+		}
+		done <- svc.serve(session)
+	}()
+	if request := bus.register(dbusPrimaryOwner); request.decoder().string() != DBusName {
+		t.Errorf("expected %s requested", DBusName)
+	}
+	return bus, done
+}
+
+func (b *dbusTestBus) read() dbusMessage {
+	b.t.Helper()
+	b.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	msg, err := readDBusMessage(b.conn)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	return msg
+}
+
+func TestDBusService(t *testing.T) {
+	snap := Snapshot{
+		Hostname: "laptop",
+		Time:     time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Temperatures: []TemperatureSensor{
+			{Name: "Composite", Value: 41},
+			{Name: "Package id 0", Value: 88, High: 85, Critical: 100},
+			{Name: "acpitz", Value: 47},
+		},
+		Worst:  StateWarning,
+		Counts: StateCounts{OK: 2, Warning: 1},
+	}
+	svc := NewDBusService(func() (Snapshot, bool) { return snap, true }, 2)
+	bus, done := serveTestBus(t, svc)
+
+	reply := bus.call(dbusPath, DBusName, "GetSnapshot")
+	var got Snapshot
+	if args, err := reply.strings(); err != nil || json.Unmarshal([]byte(args[0]), &got) != nil || got.Hostname != "laptop" || got.Worst != StateWarning {
+		t.Errorf("expected the snapshot as JSON, got %+v (%v)", reply, err)
+	}
+
+	reply = bus.call(dbusSensorsPath+"/0", dbusPropertiesIface, "Get", dbusSensorInterface, "Name")
+	if name := reply.decoder().variant(); reply.Type != dbusMethodReturn || name != "Package id 0" {
+		t.Errorf("expected the hottest sensor first, got %v", name)
+	}
+	reply = bus.call(dbusPath, dbusPropertiesIface, "Get", DBusName, "Warning")
+	if count := reply.decoder().variant(); count != uint32(1) {
+		t.Errorf("expected 1 warning, got %v", count)
+	}
+	if reply = bus.call(dbusSensorsPath+"/2", dbusPropertiesIface, "Get", dbusSensorInterface, "Name"); reply.ErrorName != "org.freedesktop.DBus.Error.UnknownObject" {
+		t.Errorf("expected only the 2 hottest sensors exported, got %+v", reply)
+	}
+	if reply = bus.call(dbusPath, DBusName, "Reboot"); reply.Type != dbusError {
+		t.Errorf("expected an error for an unknown method, got %+v", reply)
+	}
+	if reply = bus.call("/", dbusIntrospectable, "Introspect"); !strings.Contains(string(reply.Body), `<node name="org"/>`) {
+		t.Errorf("expected the root to lead to the monitor, got %q", reply.Body)
+	}
+
+	svc.Publish(snap)
+	signal := bus.read()
+	if signal.Type != dbusSignal || signal.Member != "PropertiesChanged" || signal.Signature != "sa{sv}as" {
+		t.Errorf("expected PropertiesChanged after a refresh, got %+v", signal)
+	}
+
+	svc.Close()
+	go io.Copy(io.Discard, bus.conn)
+	if err := <-done; err != nil {
+		t.Errorf("expected Close to stop serving, got %v", err)
+	}
+}
+
+func TestDBusServiceLosesTheBus(t *testing.T) {
+	svc := NewDBusService(func() (Snapshot, bool) { return Snapshot{}, false }, 1)
+	bus, done := serveTestBus(t, svc)
+	if reply := bus.call(dbusPath, DBusName, "GetSnapshot"); reply.ErrorName != "org.sysfsmonitor.Error.NoReadings" {
+		t.Errorf("expected no readings before the first refresh, got %+v", reply)
+	}
+	bus.conn.Close()
+	if err := <-done; err == nil {
+		t.Error("expected the lost bus to end serving with an error")
+	}
+	// Publishing without a bus doesn't block
+	svc.Publish(Snapshot{})
+	svc.Publish(Snapshot{})
+}
+
+func TestDBusServiceNameTaken(t *testing.T) {
+	svc := NewDBusService(func() (Snapshot, bool) { return Snapshot{}, false }, 1)
+	conn, served := net.Pipe()
+	svc.dial = func() (io.ReadWriteCloser, error) { return served, nil }
+	bus := &dbusTestBus{t: t, conn: conn}
+	started := make(chan error, 1)
+	go func() { started <- svc.Start() }()
+	bus.register(3) // DBUS_REQUEST_NAME_REPLY_EXISTS
+	if err := <-started; err == nil || !strings.Contains(err.Error(), "owned by another process") {
+		t.Errorf("expected Start to fail while another monitor owns the name, got %v", err)
+	}
+}
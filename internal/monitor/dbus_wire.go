@@ -0,0 +1,443 @@
+package monitor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// The D-Bus wire protocol, as much of it as the service needs: messages
+// with basic types, variants, arrays and dicts, and the EXTERNAL
+// authentication of the session bus. See dbus.go for the service.
+
+// Message types and flags
+const (
+	dbusMethodCall   byte = 1
+	dbusMethodReturn byte = 2
+	dbusError        byte = 3
+	dbusSignal       byte = 4
+
+	dbusNoReplyExpected byte = 1
+)
+
+// Header field codes
+const (
+	dbusFieldPath        byte = 1
+	dbusFieldInterface   byte = 2
+	dbusFieldMember      byte = 3
+	dbusFieldErrorName   byte = 4
+	dbusFieldReplySerial byte = 5
+	dbusFieldDestination byte = 6
+	dbusFieldSender      byte = 7
+	dbusFieldSignature   byte = 8
+)
+
+// dbusMaxMessage is the largest message the bus allows
+const dbusMaxMessage = 1 << 27
+
+// dbusMessage is a message with its header fields; Body holds the
+// marshaled arguments, described by Signature
+type dbusMessage struct {
+	Type        byte
+	Flags       byte
+	Serial      uint32
+	Path        string
+	Interface   string
+	Member      string
+	ErrorName   string
+	ReplySerial uint32
+	Destination string
+	Sender      string
+	Signature   string
+	Body        []byte
+	// order is the byte order of a received message's body
+	order binary.ByteOrder
+}
+
+// dbusEncoder marshals values in little-endian order. Alignment is counted
+// from the start of buf, which starts at a message or body boundary.
+type dbusEncoder struct {
+	buf []byte
+}
+
+func (e *dbusEncoder) align(n int) {
+	for len(e.buf)%n != 0 {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *dbusEncoder) byte(b byte) {
+	e.buf = append(e.buf, b)
+}
+
+func (e *dbusEncoder) uint32(v uint32) {
+	e.align(4)
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, v)
+}
+
+func (e *dbusEncoder) uint64(v uint64) {
+	e.align(8)
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, v)
+}
+
+func (e *dbusEncoder) string(s string) {
+	e.uint32(uint32(len(s)))
+	e.buf = append(append(e.buf, s...), 0)
+}
+
+func (e *dbusEncoder) signature(s string) {
+	e.buf = append(append(append(e.buf, byte(len(s))), s...), 0)
+}
+
+// array writes an array whose elements, written by elements, align to
+// elemAlign
+func (e *dbusEncoder) array(elemAlign int, elements func()) {
+	e.align(4)
+	at := len(e.buf)
+	e.buf = append(e.buf, 0, 0, 0, 0)
+	e.align(elemAlign)
+	start := len(e.buf)
+	elements()
+	binary.LittleEndian.PutUint32(e.buf[at:], uint32(len(e.buf)-start))
+}
+
+// value writes a Go value as the D-Bus type dbusSignature gives it
+func (e *dbusEncoder) value(v any) {
+	switch v := v.(type) {
+	case string:
+		e.string(v)
+	case bool:
+		if v {
+			e.uint32(1)
+		} else {
+			e.uint32(0)
+		}
+	case uint32:
+		e.uint32(v)
+	case int64:
+		e.uint64(uint64(v))
+	case float64:
+		e.uint64(math.Float64bits(v))
+	case []string:
+		e.array(4, func() {
+			for _, s := range v {
+				e.string(s)
+			}
+		})
+	case dbusProperties:
+		e.array(8, func() {
+			for _, p := range v {
+				e.align(8)
+				e.string(p.name)
+				e.variant(p.value)
+			}
+		})
+	default:
+		panic(fmt.Sprintf("dbus: unsupported type %T", v))
+	}
+}
+
+func (e *dbusEncoder) variant(v any) {
+	e.signature(dbusSignature(v))
+	e.value(v)
+}
+
+// dbusSignature returns the D-Bus type of the values value writes
+func dbusSignature(v any) string {
+	switch v.(type) {
+	case string:
+		return "s"
+	case bool:
+		return "b"
+	case uint32:
+		return "u"
+	case int64:
+		return "x"
+	case float64:
+		return "d"
+	case []string:
+		return "as"
+	case dbusProperties:
+		return "a{sv}"
+	}
+	panic(fmt.Sprintf("dbus: unsupported type %T", v))
+}
+
+// dbusProperty is an entry of an a{sv} dict, kept in order
+type dbusProperty struct {
+	name  string
+	value any
+}
+
+type dbusProperties []dbusProperty
+
+// dbusBody marshals arguments into a body and its signature
+func dbusBody(args ...any) ([]byte, string) {
+	var e dbusEncoder
+	var sig strings.Builder
+	for _, arg := range args {
+		sig.WriteString(dbusSignature(arg))
+		e.value(arg)
+	}
+	return e.buf, sig.String()
+}
+
+// marshal encodes the message, always little-endian
+func (m dbusMessage) marshal() []byte {
+	var e dbusEncoder
+	e.byte('l')
+	e.byte(m.Type)
+	e.byte(m.Flags)
+	e.byte(1) // protocol version
+	e.uint32(uint32(len(m.Body)))
+	e.uint32(m.Serial)
+	e.array(8, func() {
+		field := func(code byte, sig string, write func()) {
+			e.align(8)
+			e.byte(code)
+			e.signature(sig)
+			write()
+		}
+		text := func(code byte, sig, s string) {
+			if s != "" {
+				field(code, sig, func() {
+					if sig == "g" {
+						e.signature(s)
+					} else {
+						e.string(s)
+					}
+				})
+			}
+		}
+		text(dbusFieldPath, "o", m.Path)
+		text(dbusFieldInterface, "s", m.Interface)
+		text(dbusFieldMember, "s", m.Member)
+		text(dbusFieldErrorName, "s", m.ErrorName)
+		if m.ReplySerial != 0 {
+			field(dbusFieldReplySerial, "u", func() { e.uint32(m.ReplySerial) })
+		}
+		text(dbusFieldDestination, "s", m.Destination)
+		text(dbusFieldSender, "s", m.Sender)
+		text(dbusFieldSignature, "g", m.Signature)
+	})
+	e.align(8)
+	return append(e.buf, m.Body...)
+}
+
+// dbusDecoder unmarshals values; the first error sticks and later reads
+// return zero values. base is the offset of buf in its message, for
+// alignment.
+type dbusDecoder struct {
+	buf   []byte
+	pos   int
+	base  int
+	order binary.ByteOrder
+	err   error
+}
+
+func (d *dbusDecoder) fail(format string, args ...any) {
+	if d.err == nil {
+		d.err = fmt.Errorf("dbus: "+format, args...)
+	}
+}
+
+func (d *dbusDecoder) align(n int) {
+	if pad := (n - (d.base+d.pos)%n) % n; d.err == nil {
+		if d.pos+pad > len(d.buf) {
+			d.fail("message too short")
+			return
+		}
+		d.pos += pad
+	}
+}
+
+func (d *dbusDecoder) take(n int) []byte {
+	if d.err != nil || n < 0 || d.pos+n > len(d.buf) {
+		d.fail("message too short")
+		return nil
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+func (d *dbusDecoder) byte() byte {
+	if b := d.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (d *dbusDecoder) uint32() uint32 {
+	d.align(4)
+	if b := d.take(4); b != nil {
+		return d.order.Uint32(b)
+	}
+	return 0
+}
+
+func (d *dbusDecoder) uint64() uint64 {
+	d.align(8)
+	if b := d.take(8); b != nil {
+		return d.order.Uint64(b)
+	}
+	return 0
+}
+
+func (d *dbusDecoder) string() string {
+	n := d.uint32()
+	if n > dbusMaxMessage {
+		d.fail("string too long")
+		return ""
+	}
+	s := d.take(int(n) + 1)
+	if len(s) == 0 {
+		return ""
+	}
+	return string(s[:n])
+}
+
+func (d *dbusDecoder) signature() string {
+	s := d.take(int(d.byte()) + 1)
+	if len(s) == 0 {
+		return ""
+	}
+	return string(s[:len(s)-1])
+}
+
+// variant reads a variant of a basic type
+func (d *dbusDecoder) variant() any {
+	switch sig := d.signature(); sig {
+	case "y":
+		return d.byte()
+	case "b":
+		return d.uint32() != 0
+	case "u":
+		return d.uint32()
+	case "i":
+		return int32(d.uint32())
+	case "x":
+		return int64(d.uint64())
+	case "t":
+		return d.uint64()
+	case "d":
+		return math.Float64frombits(d.uint64())
+	case "s", "o":
+		return d.string()
+	case "g":
+		return d.signature()
+	default:
+		d.fail("unsupported variant type %q", sig)
+		return nil
+	}
+}
+
+// decoder returns a decoder of the message's body
+func (m dbusMessage) decoder() *dbusDecoder {
+	order := m.order
+	if order == nil {
+		order = binary.LittleEndian
+	}
+	return &dbusDecoder{buf: m.Body, order: order}
+}
+
+// strings reads the message's arguments when they are all strings
+func (m dbusMessage) strings() ([]string, error) {
+	d := m.decoder()
+	args := make([]string, 0, len(m.Signature))
+	for _, t := range m.Signature {
+		if t != 's' && t != 'o' {
+			return nil, fmt.Errorf("dbus: expected string arguments, got %q", m.Signature)
+		}
+		args = append(args, d.string())
+	}
+	return args, d.err
+}
+
+// readDBusMessage reads the next message of either byte order
+func readDBusMessage(r io.Reader) (dbusMessage, error) {
+	fixed := make([]byte, 16)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return dbusMessage{}, err
+	}
+	var order binary.ByteOrder
+	switch fixed[0] {
+	case 'l':
+		order = binary.LittleEndian
+	case 'B':
+		order = binary.BigEndian
+	default:
+		return dbusMessage{}, fmt.Errorf("dbus: bad byte order %q", fixed[0])
+	}
+	bodyLen, fieldsLen := order.Uint32(fixed[4:]), order.Uint32(fixed[12:])
+	if bodyLen > dbusMaxMessage || fieldsLen > dbusMaxMessage {
+		return dbusMessage{}, errors.New("dbus: message too long")
+	}
+	padded := (fieldsLen + 7) &^ 7
+	rest := make([]byte, int(padded)+int(bodyLen))
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return dbusMessage{}, err
+	}
+
+	m := dbusMessage{
+		Type:   fixed[1],
+		Flags:  fixed[2],
+		Serial: order.Uint32(fixed[8:]),
+		Body:   rest[padded:],
+		order:  order,
+	}
+	d := &dbusDecoder{buf: rest[:fieldsLen], base: 16, order: order}
+	for d.pos < len(d.buf) && d.err == nil {
+		d.align(8)
+		code := d.byte()
+		value := d.variant()
+		s, _ := value.(string)
+		switch code {
+		case dbusFieldPath:
+			m.Path = s
+		case dbusFieldInterface:
+			m.Interface = s
+		case dbusFieldMember:
+			m.Member = s
+		case dbusFieldErrorName:
+			m.ErrorName = s
+		case dbusFieldReplySerial:
+			m.ReplySerial, _ = value.(uint32)
+		case dbusFieldDestination:
+			m.Destination = s
+		case dbusFieldSender:
+			m.Sender = s
+		case dbusFieldSignature:
+			m.Signature = s
+		}
+	}
+	return m, d.err
+}
+
+// dbusAuth authenticates a fresh connection with EXTERNAL, the uid the bus
+// checks against the socket's credentials
+func dbusAuth(rw io.ReadWriter, uid int) error {
+	hexUID := fmt.Sprintf("%x", fmt.Sprint(uid))
+	if _, err := io.WriteString(rw, "\x00AUTH EXTERNAL "+hexUID+"\r\n"); err != nil {
+		return err
+	}
+	// Read byte by byte: nothing after the line may be consumed
+	var line []byte
+	b := make([]byte, 1)
+	for !strings.HasSuffix(string(line), "\r\n") {
+		if _, err := io.ReadFull(rw, b); err != nil {
+			return err
+		}
+		if line = append(line, b[0]); len(line) > 512 {
+			return errors.New("dbus: authentication reply too long")
+		}
+	}
+	if reply := strings.TrimSpace(string(line)); !strings.HasPrefix(reply, "OK ") {
+		return fmt.Errorf("dbus: authentication rejected: %s", reply)
+	}
+	_, err := io.WriteString(rw, "BEGIN\r\n")
+	return err
+}
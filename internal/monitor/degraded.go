@@ -0,0 +1,207 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Running unprivileged, powercap energy counters and the attributes of
+// restricted EC drivers fail with EACCES. In degraded mode, the default
+// without root, those sensors are hidden and summarized in one line per
+// sysfs class instead of each being listed as an error.
+
+// PermissionGap is a sysfs class with attributes only root may read
+type PermissionGap struct {
+	// Class is the class directory, e.g. "powercap", or the first two
+	// directories of paths outside /sys/class
+	Class string
+	// Paths are the unreadable attributes, relative to /sys
+	Paths []string
+}
+
+// String describes the gap, e.g. "powercap: needs root — 4 sensors
+// unavailable"
+func (g PermissionGap) String() string {
+	sensors := "sensors"
+	if len(g.Paths) == 1 {
+		sensors = "sensor"
+	}
+	return fmt.Sprintf("%s: needs root — %d %s unavailable", g.Class, len(g.Paths), sensors)
+}
+
+// WithDegradedMode turns the summary of permission failures on or off. It
+// is on by default unless running as root, where a permission failure is
+// worth seeing on its own.
+func WithDegradedMode(on bool) Option {
+	return func(m *Monitor) {
+		m.degraded = on
+	}
+}
+
+// PermissionGaps separates the permission failures among errs, grouped by
+// sysfs class in order of appearance, from the other errors. Joined errors
+// are looked into.
+func PermissionGaps(errs ...error) ([]PermissionGap, []error) {
+	return permissionGaps(sysfsRoot, errs)
+}
+
+func permissionGaps(root string, errs []error) ([]PermissionGap, []error) {
+	var gaps []PermissionGap
+	var rest []error
+	for _, err := range flattenErrors(errs) {
+		path, ok := deniedPath(err)
+		if !ok {
+			rest = append(rest, err)
+			continue
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			rel = path
+		}
+		class := permissionClass(rel)
+		i := slices.IndexFunc(gaps, func(g PermissionGap) bool { return g.Class == class })
+		if i < 0 {
+			gaps = append(gaps, PermissionGap{Class: class})
+			i = len(gaps) - 1
+		}
+		if !slices.Contains(gaps[i].Paths, rel) {
+			gaps[i].Paths = append(gaps[i].Paths, rel)
+		}
+	}
+	return gaps, rest
+}
+
+// flattenErrors replaces the joined errors among errs by what they join
+func flattenErrors(errs []error) []error {
+	var flat []error
+	for _, err := range errs {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			flat = append(flat, flattenErrors(joined.Unwrap())...)
+		} else if err != nil {
+			flat = append(flat, err)
+		}
+	}
+	return flat
+}
+
+// deniedPath returns the file a permission failure is about
+func deniedPath(err error) (string, bool) {
+	var pathErr *fs.PathError
+	if !errors.As(err, &pathErr) || !errors.Is(pathErr.Err, fs.ErrPermission) {
+		return "", false
+	}
+	return pathErr.Path, true
+}
+
+// permissionClass returns the class an attribute relative to /sys belongs
+// to: "class/powercap/intel-rapl:0/energy_uj" is in "powercap"
+func permissionClass(rel string) string {
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	switch {
+	case len(parts) > 2 && parts[0] == "class":
+		return parts[1]
+	case len(parts) > 2:
+		return parts[0] + "/" + parts[1]
+	}
+	return filepath.ToSlash(filepath.Dir(rel))
+}
+
+// isDenied reports whether err is a permission failure degraded mode hides
+func (m Monitor) isDenied(err error) bool {
+	_, ok := deniedPath(err)
+	return m.degraded && ok
+}
+
+// deniedErrors returns the permission failures of the last refresh: the
+// temperatures that couldn't be read and the group sensors degraded mode
+// hides
+func (m Monitor) deniedErrors() []error {
+	if !m.degraded {
+		return nil
+	}
+	errs := append([]error(nil), m.temperatureDenied...)
+	for _, p := range providers {
+		errs = append(errs, permissionErrors(m.discoveryErrors[p.Name()])...)
+	}
+	for _, group := range m.extraGroups {
+		for _, sensor := range group.Sensors {
+			if refresh := m.sensorRefresh[group.Name+"/"+sensor.Name()]; m.isDenied(refresh.err) {
+				errs = append(errs, refresh.err)
+			}
+		}
+	}
+	return errs
+}
+
+// permissionErrors returns the permission failures err holds
+func permissionErrors(err error) []error {
+	var denied []error
+	for _, err := range flattenErrors([]error{err}) {
+		if _, ok := deniedPath(err); ok {
+			denied = append(denied, err)
+		}
+	}
+	return denied
+}
+
+// sudoHintPatterns are the attributes `sysfs-check doctor --sudo-hint`
+// tries to read: those of the classes sensors are discovered in, powercap
+// and the EC's debugfs registers
+var sudoHintPatterns = []string{
+	"class/thermal/*/*",
+	"class/hwmon/*/*",
+	"class/power_supply/*/*",
+	"class/powercap/*/*",
+	"class/backlight/*/*",
+	"kernel/debug/ec/*/io",
+}
+
+// SudoHint returns the sensor attributes under /sys that would become
+// readable as root, for `sysfs-check doctor --sudo-hint`
+func SudoHint() []string {
+	denied, _ := deniedPaths(sysfsRoot, sudoHintPatterns)
+	for i, rel := range denied {
+		denied[i] = filepath.Join(sysfsRoot, rel)
+	}
+	return denied
+}
+
+// writeOnly reports whether a file only takes writes, like sysfs attributes
+// without a show method
+func writeOnly(mode fs.FileMode) bool {
+	return mode.Perm()&0o222 != 0 && mode.Perm()&0o444 == 0
+}
+
+// deniedPaths tries to open the readable files matching the patterns under
+// root, returning those refused for lack of permission, relative to root,
+// and how many files were tried. Write-only attributes are left out: root
+// can't read them either.
+func deniedPaths(root string, patterns []string) ([]string, int) {
+	var denied []string
+	tried := 0
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(filepath.Join(root, pattern))
+		for _, path := range matches {
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() || writeOnly(info.Mode()) {
+				continue
+			}
+			tried++
+			f, err := os.Open(path)
+			if errors.Is(err, fs.ErrPermission) {
+				rel, _ := filepath.Rel(root, path)
+				denied = append(denied, rel)
+				continue
+			}
+			if err == nil {
+				f.Close()
+			}
+		}
+	}
+	return denied, tried
+}
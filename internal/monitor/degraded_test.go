@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// deniedError is the error reading path fails with for lack of permission
+func deniedError(path string) error {
+	return &fs.PathError{Op: "open", Path: path, Err: fs.ErrPermission}
+}
+
+func TestPermissionGaps(t *testing.T) {
+	gone := errors.New("gone")
+	gaps, rest := permissionGaps("/sys", []error{
+		deniedError("/sys/class/powercap/intel-rapl:0/energy_uj"),
+		errors.Join(
+			deniedError("/sys/class/hwmon/hwmon4/temp1_input"),
+			deniedError("/sys/class/powercap/intel-rapl:0:0/energy_uj"),
+			gone,
+		),
+		fmt.Errorf("fans: %w", deniedError("/sys/class/hwmon/hwmon4/fan1_input")),
+		deniedError("/sys/class/powercap/intel-rapl:0/energy_uj"),
+		deniedError("/sys/kernel/debug/ec/ec0/io"),
+	})
+	var lines []string
+	for _, gap := range gaps {
+		lines = append(lines, gap.String())
+	}
+	want := []string{
+		"powercap: needs root — 2 sensors unavailable",
+		"hwmon: needs root — 2 sensors unavailable",
+		"kernel/debug: needs root — 1 sensor unavailable",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected one line per class:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(lines, "\n"))
+	}
+	if len(rest) != 1 || rest[0] != gone {
+		t.Errorf("expected the other failures kept, got %v", rest)
+	}
+}
+
+func TestDegradedModeHidesDeniedSensors(t *testing.T) {
+	group := func() SensorGroup {
+		return SensorGroup{Name: "EC", Sensors: []Sensor{
+			NewGenericSensor("fan1", func() (string, bool, bool, error) { return "2100 RPM", false, false, nil }),
+			NewGenericSensor("fan2", func() (string, bool, bool, error) {
+				return "", false, false, deniedError(filepath.Join(sysfsRoot, "class/hwmon/hwmon3/fan2_input"))
+			}),
+		}}
+	}
+
+	m := NewMonitor(WithDegradedMode(true))
+	m.width, m.height = 80, 24
+	m.RegisterSensorGroup(group())
+	m.refreshGroups(time.Now())
+	snap := m.Snapshot()
+	if r := snap.Groups[0].Readings[1]; !r.Denied {
+		t.Errorf("expected the unreadable fan to be denied, got %+v", r)
+	}
+	if len(snap.PermissionGaps) != 1 || snap.PermissionGaps[0].Paths[0] != "class/hwmon/hwmon3/fan2_input" {
+		t.Errorf("expected one hwmon gap, got %+v", snap.PermissionGaps)
+	}
+	view := m.View()
+	if strings.Contains(view, "fan2") || !strings.Contains(view, "hwmon: needs root — 1 sensor unavailable") {
+		t.Errorf("expected the fan hidden behind the summary:\n%s", view)
+	}
+	if rows := m.rows(); len(rows) != 1 || rows[0].index != 0 {
+		t.Errorf("expected only the readable fan to be selectable, got %+v", rows)
+	}
+
+	// Without degraded mode the failure is listed like any other
+	m = NewMonitor(WithDegradedMode(false))
+	m.width, m.height = 80, 24
+	m.RegisterSensorGroup(group())
+	m.refreshGroups(time.Now())
+	if snap := m.Snapshot(); snap.Groups[0].Readings[1].Denied || snap.PermissionGaps != nil {
+		t.Errorf("expected nothing hidden, got %+v", snap)
+	}
+	if view := m.View(); !strings.Contains(view, "fan2") || strings.Contains(view, "needs root") {
+		t.Errorf("expected the fan with its error marker:\n%s", view)
+	}
+}
+
+func TestDeniedPaths(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/powercap/intel-rapl:0/energy_uj": "123\n",
+		"class/powercap/intel-rapl:0/name":      "package-0\n",
+		"class/hwmon/hwmon0/pwm1_enable":        "",
+	})
+	// Write-only attributes aren't tried: root can't read them either
+	if err := os.Chmod(filepath.Join(root, "class/hwmon/hwmon0/pwm1_enable"), 0o200); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(root, "class/powercap/intel-rapl:0/energy_uj"), 0o400); err != nil {
+		t.Fatal(err)
+	}
+	denied, tried := deniedPaths(root, sudoHintPatterns)
+	if tried != 2 {
+		t.Errorf("expected the two readable files tried, got %d", tried)
+	}
+	if os.Geteuid() == 0 {
+		if len(denied) != 0 {
+			t.Errorf("expected root to read everything, got %v", denied)
+		}
+		return
+	}
+	if len(denied) != 0 {
+		t.Errorf("expected files owned by the user to be readable, got %v", denied)
+	}
+	if err := os.Chmod(filepath.Join(root, "class/powercap/intel-rapl:0/energy_uj"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if denied, _ := deniedPaths(root, sudoHintPatterns); len(denied) != 1 || denied[0] != "class/powercap/intel-rapl:0/energy_uj" {
+		t.Errorf("expected the RAPL counter to need root, got %v", denied)
+	}
+}
@@ -0,0 +1,148 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// DefaultDemoSeed seeds the demo dataset when no seed is given
+const DefaultDemoSeed = 1
+
+// WithDemo replaces the sysfs readers with a synthetic dataset: a battery
+// that drains and recharges, temperatures that wander with the occasional
+// spike and a group of fans. The readings depend only on the seed and the
+// number of refreshes, so tests can render them deterministically. Nothing
+// is read from /sys, which makes the UI usable on any platform.
+func WithDemo(seed int64) Option {
+	return func(m *Monitor) {
+		m.demo = newDemoSource(seed)
+	}
+}
+
+// demoTemp is a wandering temperature of the demo dataset
+type demoTemp struct {
+	name           string
+	base           float64
+	high, critical float64
+	value          float64
+}
+
+// demoSource generates the demo readings, one step per refresh
+type demoSource struct {
+	rng      *rand.Rand
+	temps    []demoTemp
+	capacity float64
+	charging bool
+	full     int // refreshes left at 100% before unplugging
+	fans     []float64
+}
+
+func newDemoSource(seed int64) *demoSource {
+	rng := rand.New(rand.NewSource(seed))
+	d := &demoSource{
+		rng: rng,
+		temps: []demoTemp{
+			{name: "Package id 0", base: 58, high: 85, critical: 100},
+			{name: "Core 0", base: 55, high: 85, critical: 100},
+			{name: "Core 1", base: 56, high: 85, critical: 100},
+			{name: "Composite", base: 42, high: 70, critical: 80},
+			{name: "acpitz", base: 47, high: 90, critical: 105},
+		},
+		capacity: 40 + rng.Float64()*50,
+		fans:     []float64{1800, 1200},
+	}
+	for i := range d.temps {
+		d.temps[i].value = d.temps[i].base + rng.NormFloat64()*3
+	}
+	return d
+}
+
+// step advances the dataset by one refresh
+func (d *demoSource) step() {
+	for i := range d.temps {
+		t := &d.temps[i]
+		t.value += (t.base-t.value)*0.15 + d.rng.NormFloat64()*1.5
+		// A load spike now and then, to show warnings
+		if d.rng.Float64() < 0.03 {
+			t.value += 20 + d.rng.Float64()*15
+		}
+		t.value = math.Round(t.value*10) / 10
+	}
+
+	switch {
+	case d.charging && d.capacity >= 100:
+		d.capacity = 100
+		if d.full == 0 {
+			d.full = 10
+		} else if d.full--; d.full == 0 {
+			d.charging = false
+		}
+	case d.charging:
+		d.capacity += 1 + d.rng.Float64()
+	case d.capacity <= 8:
+		d.charging = true
+	default:
+		d.capacity -= 0.3 + d.rng.Float64()*0.4
+	}
+
+	hottest := d.temps[0].value
+	for i := range d.fans {
+		target := 1000 + (hottest-40)*40*float64(i+2)/2
+		d.fans[i] += (target-d.fans[i])*0.5 + d.rng.NormFloat64()*20
+	}
+}
+
+// battery returns the demo battery; the voltage follows the capacity and
+// the power flows in while charging and out while discharging
+func (d *demoSource) battery() BatteryStatus {
+	capacity := int(math.Round(min(max(d.capacity, 0), 100)))
+	status := BatteryStatus{
+		Capacity:   capacity,
+		Status:     "Discharging",
+		Voltage:    math.Round((11.1+1.5*float64(capacity)/100)*100) / 100,
+		Health:     "Good",
+		Energy:     math.Round(57*float64(capacity)) / 100,
+		EnergyFull: 57,
+	}
+	switch {
+	case d.charging && d.full > 0:
+		status.Status, status.ACOnline = "Full", true
+	case d.charging:
+		status.Status, status.ACOnline = "Charging", true
+		status.ACVoltage, status.ACCurrent = 20, 3.25
+		status.Current = 2.5
+		status.Power = math.Round(status.Voltage*status.Current*10) / 10
+	default:
+		status.Power = math.Round((9+(d.temps[0].value-50)/5)*10) / 10
+		status.Current = math.Round(status.Power/status.Voltage*100) / 100
+	}
+	return status
+}
+
+// temperatures returns the demo temperatures, labeled as demo paths
+func (d *demoSource) temperatures() []TemperatureSensor {
+	sensors := make([]TemperatureSensor, len(d.temps))
+	for i, t := range d.temps {
+		sensors[i] = TemperatureSensor{
+			Name:     t.name,
+			Value:    t.value,
+			High:     t.high,
+			Critical: t.critical,
+			Path:     fmt.Sprintf("demo/temp%d", i+1),
+		}
+	}
+	return sensors
+}
+
+// groups returns the demo's extra groups, read from the source on refresh
+func (d *demoSource) groups() []SensorGroup {
+	var fans []Sensor
+	for i := range d.fans {
+		fans = append(fans, NewGenericSensor(fmt.Sprintf("fan%d", i+1), func() (string, bool, bool, error) {
+			rpm := d.fans[i]
+			return formatMeasurement(KindFan, rpm), rpm > 3000, rpm > 4000, nil
+		}).SetKind(KindFan))
+	}
+	return []SensorGroup{{Name: "Fans (demo)", Sensors: fans}}
+}
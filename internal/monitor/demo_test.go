@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// demoSnapshot is the demo dataset of seed after the given refreshes, one
+// interval apart and ending at the golden render time
+func demoSnapshot(seed int64, refreshes int) Snapshot {
+	clock := &fakeClock{now: renderTime.Add(-time.Duration(refreshes) * DefaultInterval)}
+	m := NewMonitor(WithDemo(seed), WithHostname("laptop"), WithClock(clock))
+	for i := 0; i < refreshes; i++ {
+		clock.now = clock.now.Add(DefaultInterval)
+		m = m.Refresh()
+	}
+	return m.Snapshot()
+}
+
+func TestDemoIsDeterministic(t *testing.T) {
+	a, b := demoSnapshot(7, 20), demoSnapshot(7, 20)
+	if !a.Demo || len(a.Temperatures) != 5 || len(a.Groups) != 1 || a.Groups[0].Name != "Fans (demo)" {
+		t.Fatalf("expected the demo temperatures and fans, got %+v", a)
+	}
+	fans := func(s Snapshot) (values []string) {
+		for _, r := range s.Groups[0].Readings {
+			values = append(values, r.Value)
+		}
+		return values
+	}
+	if !reflect.DeepEqual(a.Temperatures, b.Temperatures) || a.Battery != b.Battery || !reflect.DeepEqual(fans(a), fans(b)) {
+		t.Errorf("expected the same readings for the same seed")
+	}
+	if c := demoSnapshot(8, 20); reflect.DeepEqual(a.Temperatures, c.Temperatures) {
+		t.Errorf("expected another seed to give other readings")
+	}
+}
+
+func TestDemoBatteryCycles(t *testing.T) {
+	d := newDemoSource(1)
+	var sawCharging, sawFull, sawWarning bool
+	low, prev := 100, -1
+	for i := 0; i < 2000; i++ {
+		d.step()
+		bat := d.battery()
+		if bat.Capacity < 0 || bat.Capacity > 100 {
+			t.Fatalf("capacity out of range: %d", bat.Capacity)
+		}
+		if bat.Status == "Discharging" && prev >= 0 && bat.Capacity > prev {
+			t.Errorf("capacity rose from %d to %d while discharging", prev, bat.Capacity)
+		}
+		low = min(low, bat.Capacity)
+		sawCharging = sawCharging || bat.Status == "Charging"
+		sawFull = sawFull || bat.Status == "Full"
+		for _, sensor := range d.temperatures() {
+			sawWarning = sawWarning || sensor.State() != StateOK
+		}
+		prev = bat.Capacity
+	}
+	if low > 10 || !sawCharging || !sawFull {
+		t.Errorf("expected the battery to drain below 10%% and recharge to full, got low %d%%", low)
+	}
+	if !sawWarning {
+		t.Errorf("expected an occasional temperature spike")
+	}
+}
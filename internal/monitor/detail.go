@@ -0,0 +1,318 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Sane bounds for user-entered thresholds, in Celsius
+const (
+	minThreshold = -50.0
+	maxThreshold = 200.0
+)
+
+// thresholdEdit is the state of the inline threshold editor
+type thresholdEdit struct {
+	field  int // 0 = High, 1 = Critical
+	inputs [2]string
+	err    string
+}
+
+// Editing reports whether the threshold editor has focus. It takes every
+// key, so a program wrapping the monitor should only handle its own
+// shortcuts, such as "q" to quit, while it is false.
+func (m Monitor) Editing() bool {
+	return m.edit != nil
+}
+
+func (m Monitor) handleEditKey(msg tea.KeyMsg) Monitor {
+	edit := *m.edit
+	switch key := msg.String(); key {
+	case "esc":
+		m.edit = nil
+		return m
+	case "tab", "shift+tab", "up", "down":
+		edit.field = 1 - edit.field
+	case "backspace":
+		if input := edit.inputs[edit.field]; input != "" {
+			edit.inputs[edit.field] = input[:len(input)-1]
+		}
+	case "enter":
+		override, err := parseThresholds(edit.inputs, m.unit)
+		if err != nil {
+			edit.err = err.Error()
+			break
+		}
+		sensor, _ := m.selectedSensor()
+		if m.config.Overrides == nil {
+			m.config.Overrides = make(map[string]ThresholdOverride)
+		}
+		m.config.Overrides[sensor.key()] = override
+		m.unsavedOverrides = true
+		m.arrangeTemperatures()
+		m.edit = nil
+		return m
+	default:
+		if len(key) == 1 && strings.ContainsAny(key, "0123456789.-") {
+			edit.inputs[edit.field] += key
+		}
+	}
+	m.edit = &edit
+	return m
+}
+
+// editInput formats a Celsius threshold for the editor, in the display unit
+// rounded to the decimal the view shows
+func editInput(celsius float64, unit TempUnit) string {
+	return strconv.FormatFloat(math.Round(unit.convert(celsius)*10)/10, 'f', -1, 64)
+}
+
+// parseThresholds validates the editor inputs, typed in unit, and returns
+// them in Celsius
+func parseThresholds(inputs [2]string, unit TempUnit) (ThresholdOverride, error) {
+	var values [2]float64
+	for i, input := range inputs {
+		v, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return ThresholdOverride{}, fmt.Errorf("%q is not a number", input)
+		}
+		// Rounded so a converted value doesn't carry float noise into
+		// the config
+		c := math.Round(unit.celsius(v)*100) / 100
+		if c < minThreshold || c > maxThreshold {
+			return ThresholdOverride{}, fmt.Errorf("%.1f%s is outside %.0f..%.0f%s", v, unit.symbol(), unit.convert(minThreshold), unit.convert(maxThreshold), unit.symbol())
+		}
+		values[i] = c
+	}
+	if values[0] >= values[1] {
+		return ThresholdOverride{}, fmt.Errorf("high must be below critical")
+	}
+	return ThresholdOverride{High: values[0], Critical: values[1]}, nil
+}
+
+// applyOverrides replaces sysfs thresholds with the configured overrides.
+// The slice is copied so earlier snapshots keep their values.
+func (m Monitor) applyOverrides(sensors []TemperatureSensor) []TemperatureSensor {
+	if len(m.config.Overrides) == 0 {
+		return sensors
+	}
+	result := append([]TemperatureSensor(nil), sensors...)
+	for i := range result {
+		if o, ok := lookupSensor(m.config.Overrides, result[i]); ok {
+			result[i].High = o.High
+			result[i].Critical = o.Critical
+		}
+	}
+	return result
+}
+
+// baseThresholds returns the thresholds of a temperature before the active
+// profile's offset, which the editor edits: its override, or else those read
+// from sysfs
+func (m Monitor) baseThresholds(sensor TemperatureSensor) (high, critical float64) {
+	if o, ok := lookupSensor(m.config.Overrides, sensor); ok {
+		return o.High, o.Critical
+	}
+	sensors := m.zoneSensors
+	if !m.expandZones {
+		sensors = collapseZones(sensors)
+	}
+	for _, s := range sensors {
+		if s.key() == sensor.key() {
+			return s.High, s.Critical
+		}
+	}
+	return sensor.High, sensor.Critical
+}
+
+func (m *Monitor) saveConfig() string {
+	if m.configPath == "" {
+		return "No config file configured"
+	}
+	if err := m.config.Save(m.configPath); err != nil {
+		return fmt.Sprintf("Saving config failed: %v", err)
+	}
+	m.unsavedOverrides = false
+	return "Saved thresholds to " + m.configPath
+}
+
+// selectedSensor returns the selected temperature sensor, if any
+func (m Monitor) selectedSensor() (TemperatureSensor, bool) {
+	r, ok := m.selectedRow()
+	if !ok || r.group >= 0 {
+		return TemperatureSensor{}, false
+	}
+	return m.temperatureSensors[r.index], true
+}
+
+func (m Monitor) isOverridden(sensor TemperatureSensor) bool {
+	_, ok := lookupSensor(m.config.Overrides, sensor)
+	return ok
+}
+
+// detailView renders the selected sensor; temperatures also show their
+// thresholds and can be edited
+func (m Monitor) detailView() string {
+	sensor, ok := m.selectedSensor()
+	if !ok {
+		return m.sensorDetailView()
+	}
+	var sb strings.Builder
+	faint := lipgloss.NewStyle().Faint(true)
+
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render(sensor.Name))
+	sb.WriteString("\n\n")
+	style := readingStyle(m.theme, sensor.State(), sensor.Muted)
+	if sensor.Asleep {
+		fmt.Fprintf(&sb, "  Value:    %s", faint.Render(asleepText+" (device suspended, see wake_on_read)"))
+	} else {
+		fmt.Fprintf(&sb, "  Value:    %s", style.Render(m.numbers.localize(formatTemp(sensor.Value, m.unit, 0))))
+	}
+	if offset, ok := m.offsetFor(sensor); ok && !sensor.Asleep {
+		// Offsets are Celsius deltas, so only the raw value converts
+		fmt.Fprintf(&sb, " %s", faint.Render(m.numbers.localize(fmt.Sprintf("(raw %s, offset %+.1f°C)", formatTemp(sensor.Value-offset, m.unit, 0), offset))))
+	}
+	if m.unit == Raw && sensor.Raw != "" {
+		fmt.Fprintf(&sb, " %s", faint.Render("(file "+sensor.Raw+")"))
+	}
+	sb.WriteString("\n")
+	if sensor.Alarm != StateOK && !sensor.Asleep {
+		fmt.Fprintf(&sb, "  Alarm:    %s\n", m.theme.stateStyle(sensor.Alarm).Render(alarmLatchedText))
+	}
+	if changed, ok := m.lastChange(temperatureStateKey(sensor.key())); ok {
+		fmt.Fprintf(&sb, "  Stable:   unchanged for %s\n", formatElapsed(m.clock.Now().Sub(changed)))
+	}
+
+	if m.edit != nil {
+		for i, label := range []string{"High:    ", "Critical:"} {
+			input := m.edit.inputs[i]
+			if i == m.edit.field {
+				input = lipgloss.NewStyle().Reverse(true).Render(input + "_")
+			}
+			fmt.Fprintf(&sb, "  %s [%s] %s\n", label, input, m.unit.symbol())
+		}
+		if m.edit.err != "" {
+			sb.WriteString(m.theme.stateStyle(StateCritical).Render("  " + m.edit.err))
+			sb.WriteString("\n")
+		}
+	} else {
+		marker := ""
+		if m.isOverridden(sensor) {
+			marker = faint.Render(" (override)")
+		}
+		fmt.Fprintf(&sb, "  High:     %s%s\n", m.numbers.localize(formatTemp(sensor.High, m.unit, 0)), marker)
+		fmt.Fprintf(&sb, "  Critical: %s%s\n", m.numbers.localize(formatTemp(sensor.Critical, m.unit, 0)), marker)
+		if sensor.Emergency != nil {
+			fmt.Fprintf(&sb, "  Emerg:    %s\n", m.numbers.localize(formatTemp(*sensor.Emergency, m.unit, 0)))
+		}
+		if sensor.LowCritical != nil {
+			fmt.Fprintf(&sb, "  Low Crit: %s\n", m.numbers.localize(formatTemp(*sensor.LowCritical, m.unit, 0)))
+		}
+	}
+	fmt.Fprintf(&sb, "  Path:     %s\n", sensor.Path)
+	if d, ok := m.stateTime[temperatureStateKey(sensor.key())]; ok {
+		fmt.Fprintf(&sb, "  Time:     %s this session\n", d)
+	}
+	if sensor.Muted {
+		sb.WriteString("  Alerts:   muted\n")
+	}
+	if len(sensor.Cooling) > 0 {
+		sb.WriteString("  Cooling:\n")
+		for _, b := range sensor.Cooling {
+			fmt.Fprintf(&sb, "    %s\n", m.coolingLine(b))
+		}
+	}
+
+	if m.status != "" {
+		sb.WriteString("\n  " + m.status + "\n")
+	}
+
+	sb.WriteString("\n")
+	if m.edit != nil {
+		sb.WriteString(faint.Render("enter: apply | tab: next field | esc: cancel"))
+	} else {
+		sb.WriteString(faint.Render("e: edit thresholds | s: save to config | " + muteHelp(sensor.Muted) + " | esc: back"))
+	}
+	return sb.String()
+}
+
+// coolingLine describes a cooling device bound to a thermal zone, e.g.
+// "Fan (cooling_device3): trip 0 active at 55.0°C, state 1/5"
+func (m Monitor) coolingLine(b CoolingBinding) string {
+	name := b.Device
+	if b.Type != "" {
+		name = fmt.Sprintf("%s (%s)", b.Type, b.Device)
+	}
+	if b.Path == "" {
+		return name + ": device missing"
+	}
+	trip := "no trip point"
+	if b.TripPoint >= 0 {
+		trip = strings.Join(strings.Fields(fmt.Sprintf("trip %d %s at %s", b.TripPoint, b.TripType, m.numbers.localize(formatTemp(b.TripTemp, m.unit, 0)))), " ")
+	}
+	cur, max, err := b.coolingState()
+	if err != nil {
+		return fmt.Sprintf("%s: %s, state unreadable", name, trip)
+	}
+	return fmt.Sprintf("%s: %s, state %d/%d", name, trip, cur, max)
+}
+
+// sensorDetailView renders the selected sensor of an extra group
+func (m Monitor) sensorDetailView() string {
+	r, _ := m.selectedRow()
+	group := m.extraGroups[r.group]
+	sensor := group.Sensors[r.index]
+	var sb strings.Builder
+	faint := lipgloss.NewStyle().Faint(true)
+
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render(sensor.Name()))
+	sb.WriteString("\n\n")
+	state := sensorState(sensor)
+	muted := m.isMuted(muteKey(group.Name, sensor.Name()))
+	style := readingStyle(m.theme, state, muted)
+	fmt.Fprintf(&sb, "  Value:    %s\n", style.Render(m.sensorValue(sensor)))
+	if muted {
+		fmt.Fprintf(&sb, "  State:    %s (alerts muted)\n", state)
+	} else {
+		fmt.Fprintf(&sb, "  State:    %s\n", state)
+	}
+	if alarmed, ok := sensor.(Alarmed); ok && alarmed.Alarm() != StateOK {
+		fmt.Fprintf(&sb, "  Alarm:    %s\n", m.theme.stateStyle(alarmed.Alarm()).Render(alarmLatchedText))
+	}
+	if changed, ok := m.lastChange(groupStateKey(group.Name, sensor.Name())); ok {
+		fmt.Fprintf(&sb, "  Stable:   unchanged for %s\n", formatElapsed(m.clock.Now().Sub(changed)))
+	}
+	fmt.Fprintf(&sb, "  Group:    %s\n", group.Name)
+	if d, ok := m.stateTime[groupStateKey(group.Name, sensor.Name())]; ok {
+		fmt.Fprintf(&sb, "  Time:     %s this session\n", d)
+	}
+	if refresh, ok := m.sensorRefresh[group.Name+"/"+sensor.Name()]; ok && refresh.err != nil {
+		fmt.Fprintf(&sb, "  Failed:   %s\n", m.theme.stateStyle(StateWarning).Render(refresh.err.Error()))
+		lastOK := "never"
+		if !refresh.lastSuccess.IsZero() {
+			lastOK = refresh.lastSuccess.Format("15:04:05")
+		}
+		fmt.Fprintf(&sb, "  Last OK:  %s\n", lastOK)
+	}
+	if refresh := m.GroupRefreshState(group.Name); refresh.Failures > 0 {
+		fmt.Fprintf(&sb, "  Refresh:  %d failures, next retry %s\n", refresh.Failures, refresh.RetryAt.Format("15:04:05"))
+		fmt.Fprintf(&sb, "  Error:    %s\n", refresh.Err)
+	}
+
+	if m.status != "" {
+		sb.WriteString("\n  " + m.status + "\n")
+	}
+
+	sb.WriteString("\n")
+	help := muteHelp(muted) + " | esc: back"
+	if _, ok := sensor.(Adjustable); ok {
+		help = "[/]: adjust | " + help
+	}
+	sb.WriteString(faint.Render(help))
+	return sb.String()
+}
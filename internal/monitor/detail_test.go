@@ -0,0 +1,216 @@
+package monitor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sendKeys feeds key presses to the monitor. Single characters are sent as
+// runes; other strings name special keys.
+func sendKeys(m Monitor, keys ...string) Monitor {
+	special := map[string]tea.KeyType{
+		"enter":     tea.KeyEnter,
+		"esc":       tea.KeyEsc,
+		"tab":       tea.KeyTab,
+		"backspace": tea.KeyBackspace,
+		"up":        tea.KeyUp,
+		"down":      tea.KeyDown,
+	}
+	for _, key := range keys {
+		msg := keyMsg(key)
+		if t, ok := special[key]; ok {
+			msg = tea.KeyMsg{Type: t}
+		}
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func keyMsg(key string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+func newDetailMonitor() Monitor {
+	m := NewMonitor()
+	m.width, m.height = 80, 24
+	m.zoneSensors = []TemperatureSensor{
+		{Name: "CPU", Value: 65.0, High: 80.0, Critical: 100.0, Path: "thermal_zone0"},
+		{Name: "GPU", Value: 72.5, High: 85.0, Critical: 105.0, Path: "thermal_zone1"},
+	}
+	m.arrangeTemperatures()
+	return m
+}
+
+func TestThresholdEditApplies(t *testing.T) {
+	m := newDetailMonitor()
+	m = sendKeys(m, "down", "down", "enter")
+	if !m.detail {
+		t.Fatal("expected detail view to open")
+	}
+	if !strings.Contains(m.View(), "GPU") {
+		t.Error("detail view should show the selected sensor")
+	}
+
+	m = sendKeys(m, "e", "backspace", "backspace", "6", "0", "tab", "backspace", "backspace", "backspace", "7", "0", "enter")
+	if m.edit != nil {
+		t.Fatalf("expected editor to close, error: %q", m.edit.err)
+	}
+	gpu := m.temperatureSensors[1]
+	if gpu.High != 60 || gpu.Critical != 70 {
+		t.Errorf("expected thresholds 60/70, got %.1f/%.1f", gpu.High, gpu.Critical)
+	}
+	if gpu.State() != StateCritical {
+		t.Error("new thresholds should apply to coloring immediately")
+	}
+	if m.temperatureSensors[0].High != 80 {
+		t.Error("other sensors must keep their thresholds")
+	}
+	if !strings.Contains(m.View(), "(override)") {
+		t.Error("detail view should mark overridden thresholds")
+	}
+
+	// Overrides survive the next refresh
+	refreshed := m.applyOverrides([]TemperatureSensor{{Name: "GPU", Value: 50, High: 85, Critical: 105}})
+	if refreshed[0].High != 60 || refreshed[0].Critical != 70 {
+		t.Errorf("override not reapplied after refresh: %+v", refreshed[0])
+	}
+
+	m = sendKeys(m, "esc")
+	if m.detail {
+		t.Error("esc should close the detail view")
+	}
+	if !strings.Contains(m.View(), " *") {
+		t.Error("list should mark overridden sensors")
+	}
+}
+
+func TestThresholdEditWithProfile(t *testing.T) {
+	m := newDetailMonitor()
+	m.profile = &Profile{Name: "quiet", HighOffset: -10}
+	m.arrangeTemperatures()
+
+	// The editor shows the thresholds before the offset, the CPU's 80
+	// rather than the 70 in effect
+	m = sendKeys(m, "down", "enter", "e")
+	if m.edit == nil || m.edit.inputs != [2]string{"80", "100"} {
+		t.Fatalf("expected the editor filled without the profile's offset, got %+v", m.edit)
+	}
+	m = sendKeys(m, "backspace", "backspace", "7", "5", "enter")
+	if o := m.config.Overrides["CPU"]; o.High != 75 || o.Critical != 100 {
+		t.Errorf("expected the override stored without the offset, got %+v", o)
+	}
+	if cpu := m.temperatureSensors[0]; cpu.Name != "CPU" || cpu.High != 65 {
+		t.Errorf("expected the offset applied once to the override, got %+v", cpu)
+	}
+	m = sendKeys(m, "e")
+	if m.edit == nil || m.edit.inputs != [2]string{"75", "100"} {
+		t.Errorf("expected the editor filled with the override, got %+v", m.edit)
+	}
+}
+
+func TestThresholdEditValidation(t *testing.T) {
+	m := newDetailMonitor()
+	m = sendKeys(m, "down", "enter", "e", "tab", "backspace", "backspace", "backspace", "5", "0", "enter")
+	if m.edit == nil || !strings.Contains(m.edit.err, "below critical") {
+		t.Fatalf("expected high >= critical to be rejected, got %+v", m.edit)
+	}
+
+	m = sendKeys(m, "backspace", "backspace", "9", "9", "9", "enter")
+	if m.edit == nil || !strings.Contains(m.edit.err, "outside") {
+		t.Fatalf("expected out of range threshold to be rejected, got %+v", m.edit)
+	}
+
+	if !m.Editing() {
+		t.Error("expected the editor to keep focus for the wrapping program")
+	}
+	m = sendKeys(m, "esc")
+	if m.edit != nil || len(m.config.Overrides) != 0 {
+		t.Error("esc should cancel without applying")
+	}
+	if m.temperatureSensors[0].High != 80 {
+		t.Error("cancelled edit must not change thresholds")
+	}
+}
+
+func TestThresholdSaveToConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "config.json")
+	m := newDetailMonitor()
+	WithConfig(path, Config{})(&m)
+	m = sendKeys(m, "down", "enter", "e", "backspace", "backspace", "7", "5", "enter", "s")
+	if !strings.Contains(m.status, "Saved") {
+		t.Fatalf("expected save status, got %q", m.status)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o := cfg.Overrides["CPU"]; o.High != 75 || o.Critical != 100 {
+		t.Errorf("unexpected saved override: %+v", o)
+	}
+}
+
+func TestLoadConfigMissing(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("missing config should not be an error: %v", err)
+	}
+	if len(cfg.Overrides) != 0 {
+		t.Error("expected empty config")
+	}
+}
+
+func TestDetailShowsFileValueInRawMode(t *testing.T) {
+	m := newDetailMonitor()
+	m.temperatureSensors[0].Raw = "65000"
+	m = sendKeys(m, "down", "enter")
+	if strings.Contains(m.View(), "65000") {
+		t.Error("the file's integer should only show in raw mode")
+	}
+	m = sendKeys(m, "u", "u", "u")
+	if m.unit != Raw {
+		t.Fatalf("expected u to cycle to raw, got %s", m.unit)
+	}
+	if view := m.View(); !strings.Contains(view, "65.0°C") || !strings.Contains(view, "(file 65000)") {
+		t.Errorf("expected the parsed value with the file's integer:\n%s", view)
+	}
+	if m = sendKeys(m, "u"); m.unit != Celsius {
+		t.Errorf("expected u to wrap around to Celsius, got %s", m.unit)
+	}
+}
+
+func TestThresholdEditInDisplayUnit(t *testing.T) {
+	m := newDetailMonitor()
+	m = sendKeys(m, "u", "down", "enter", "e")
+	if m.unit != Fahrenheit || m.edit == nil || m.edit.inputs != [2]string{"176", "212"} {
+		t.Fatalf("expected the editor filled in Fahrenheit, got %s %+v", m.unit, m.edit)
+	}
+	if view := m.View(); !strings.Contains(view, "[176_] °F") {
+		t.Errorf("expected the unit next to the fields:\n%s", view)
+	}
+
+	m = sendKeys(m, "backspace", "backspace", "backspace", "5", "0", "0", "enter")
+	if m.edit == nil || !strings.Contains(m.edit.err, "500.0°F is outside -58..392°F") {
+		t.Fatalf("expected the range in Fahrenheit, got %+v", m.edit)
+	}
+	m = sendKeys(m, "backspace", "backspace", "backspace", "1", "6", "7", "enter")
+	if o := m.config.Overrides["CPU"]; o.High != 75 || o.Critical != 100 {
+		t.Errorf("expected the thresholds stored in Celsius, got %+v", o)
+	}
+}
+
+func TestDetailAlignsThresholds(t *testing.T) {
+	m := newDetailMonitor()
+	emergency, lowCritical := 110.0, -40.0
+	m.zoneSensors[0].Emergency, m.zoneSensors[0].LowCritical = &emergency, &lowCritical
+	m.arrangeTemperatures()
+	view := sendKeys(m, "down", "enter").View()
+	for _, line := range []string{"  High:     80.0°C", "  Emerg:    110.0°C", "  Low Crit: -40.0°C"} {
+		if !strings.Contains(view, line) {
+			t.Errorf("expected %q in the detail view:\n%s", line, view)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package monitor
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// DoctorStatus is the outcome of an environment check
+type DoctorStatus int
+
+const (
+	DoctorPass DoctorStatus = iota
+	// DoctorWarn is a problem the monitor works around or that hides some
+	// readings
+	DoctorWarn
+	// DoctorFail is a problem leaving the monitor with nothing useful
+	DoctorFail
+)
+
+func (s DoctorStatus) String() string {
+	return [...]string{"pass", "warn", "FAIL"}[s]
+}
+
+// DoctorCheck is the result of one check of sysfs-check doctor
+type DoctorCheck struct {
+	Name   string
+	Status DoctorStatus
+	Detail string // what was found
+	Fix    string // the suggested remediation, for warnings and failures
+}
+
+// DoctorOK reports whether no check failed, i.e. whether the monitor would
+// show readings
+func DoctorOK(checks []DoctorCheck) bool {
+	for _, check := range checks {
+		if check.Status == DoctorFail {
+			return false
+		}
+	}
+	return true
+}
+
+// doctorClassPaths are the sysfs classes the readers discover sensors in
+var doctorClassPaths = []string{thermalClassPath, hwmonClassPath, powerSupplyClassPath}
+
+// clocksourcePath holds the kernel's current clock source
+const clocksourcePath = "devices/system/clocksource/clocksource0/current_clocksource"
+
+const (
+	// maxDeniedPaths caps the unreadable files a check lists
+	maxDeniedPaths = 3
+
+	// doctorSleep is how long the clock check waits for the clock to move
+	doctorSleep = 20 * time.Millisecond
+)
+
+// doctorTerminal is what the terminal checks look at
+type doctorTerminal struct {
+	tty     bool // stdout is a terminal
+	profile termenv.Profile
+	getenv  func(string) string
+}
+
+// Doctor runs the environment checks of `sysfs-check doctor` against /sys
+// and the terminal on stdout: sysfs and its classes, readable sensors,
+// permissions, the clock and the terminal's capabilities. It sleeps briefly
+// to see the clock advance.
+func Doctor() []DoctorCheck {
+	start := time.Now()
+	time.Sleep(doctorSleep)
+	elapsed := time.Since(start)
+
+	info, err := os.Stdout.Stat()
+	term := doctorTerminal{
+		tty:     err == nil && info.Mode()&os.ModeCharDevice != 0,
+		profile: lipgloss.ColorProfile(),
+		getenv:  os.Getenv,
+	}
+	return doctor(sysfsRoot, term, doctorSleep, elapsed)
+}
+
+func doctor(root string, term doctorTerminal, slept, elapsed time.Duration) []DoctorCheck {
+	checks := []DoctorCheck{checkSysfsMounted(root)}
+	if checks[0].Status == DoctorFail {
+		// Nothing else under root can be checked
+		return append(checks, checkTerminal(term)...)
+	}
+	for _, class := range doctorClassPaths {
+		checks = append(checks, checkClass(root, class))
+	}
+	checks = append(checks,
+		checkSensors(root),
+		checkPermissions(root),
+		checkClock(root, slept, elapsed),
+	)
+	return append(checks, checkTerminal(term)...)
+}
+
+func checkSysfsMounted(root string) DoctorCheck {
+	check := DoctorCheck{Name: "sysfs mounted"}
+	if _, err := os.Stat(filepath.Join(root, "class")); err != nil {
+		check.Status = DoctorFail
+		check.Detail = fmt.Sprintf("%s/class: %v", root, errors.Unwrap(err))
+		check.Fix = "mount it with `mount -t sysfs sysfs /sys`; in a container, bind-mount the host's /sys read-only"
+		return check
+	}
+	check.Detail = root + "/class is present"
+	return check
+}
+
+func checkClass(root, class string) DoctorCheck {
+	check := DoctorCheck{Name: class}
+	entries, err := os.ReadDir(filepath.Join(root, class))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		check.Status = DoctorWarn
+		check.Detail = "missing"
+		check.Fix = classFixes[class]
+	case err != nil:
+		check.Status = DoctorFail
+		check.Detail = fmt.Sprintf("unreadable: %v", errors.Unwrap(err))
+		check.Fix = "sysfs is restricted for this user; in a container run with access to the host's /sys, or run as root"
+	case len(entries) == 0:
+		check.Status = DoctorWarn
+		check.Detail = "present, no devices"
+		check.Fix = classFixes[class]
+	default:
+		check.Detail = fmt.Sprintf("%d devices", len(entries))
+	}
+	return check
+}
+
+// classFixes suggest how to populate an empty or missing class
+var classFixes = map[string]string{
+	thermalClassPath:     "load the thermal drivers of the platform (acpi thermal, or the SoC's thermal driver on ARM)",
+	hwmonClassPath:       "load the hwmon driver of the chip (coretemp or k10temp for CPUs, nct6775/it87 for boards; `sensors-detect` finds them)",
+	powerSupplyClassPath: "expected on desktops; on a laptop load the battery driver (acpi battery, or the EC's driver)",
+}
+
+func checkSensors(root string) DoctorCheck {
+	check := DoctorCheck{Name: "readable sensors"}
+	temps, err := readTemperaturesE(root)
+	awake := 0
+	for _, sensor := range temps {
+		if !sensor.Asleep {
+			awake++
+		}
+	}
+	battery := readBatteryStatus(root).Present()
+	switch {
+	case awake > 0 || battery:
+		check.Detail = fmt.Sprintf("%d temperatures", awake)
+		if battery {
+			check.Detail += " and a battery"
+		}
+		if err != nil {
+			check.Status = DoctorWarn
+			check.Detail += fmt.Sprintf(", %d unreadable", len(joinedErrors(err)))
+			check.Fix = "run `sysfs-check` to see the read failures"
+		}
+	default:
+		check.Status = DoctorFail
+		check.Detail = "no temperature or battery could be read"
+		check.Fix = "load the sensor drivers (`sensors-detect`)"
+		if virt := detectVirtualization(root); virt != "" {
+			check.Fix = fmt.Sprintf("running under %s, which usually has no hardware sensors; run on the host", virt)
+		} else if err != nil {
+			check.Fix = "run `sysfs-check` to see why the sensors failed to read"
+		}
+	}
+	return check
+}
+
+// joinedErrors returns the errors an errors.Join holds, or err alone
+func joinedErrors(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// checkPermissions looks for sensor files only root may read: RAPL energy
+// counters (root-only since Linux 5.10), hwmon inputs of restricted EC
+// drivers such as dell-smm-hwmon, and the EC's debugfs registers
+func checkPermissions(root string) DoctorCheck {
+	check := DoctorCheck{Name: "permissions"}
+	denied, tried := deniedPaths(root, []string{
+		"class/powercap/*/energy_uj",
+		"class/hwmon/hwmon*/*_input",
+		"kernel/debug/ec/ec*/io",
+	})
+	if len(denied) == 0 {
+		check.Detail = fmt.Sprintf("%d sensor files readable", tried)
+		return check
+	}
+	check.Status = DoctorWarn
+	check.Detail = fmt.Sprintf("%d files need root: %s", len(denied), strings.Join(denied[:min(len(denied), maxDeniedPaths)], ", "))
+	if len(denied) > maxDeniedPaths {
+		check.Detail += ", …"
+	}
+	check.Fix = "run as root, or grant read access with a udev rule (e.g. `MODE=\"0444\"` for powercap energy_uj); load dell-smm-hwmon with restricted=0; `sysfs-check doctor --sudo-hint` lists every such file"
+	return check
+}
+
+// checkClock checks what rate sensors divide by: the monotonic clock must
+// advance by about the time slept, and a jiffies clock source only ticks
+// every few milliseconds
+func checkClock(root string, slept, elapsed time.Duration) DoctorCheck {
+	check := DoctorCheck{Name: "clock"}
+	source := readTrimmed(filepath.Join(root, clocksourcePath))
+	switch {
+	case elapsed < slept/2 || elapsed > 50*slept:
+		check.Status = DoctorWarn
+		check.Detail = fmt.Sprintf("slept %v, the clock advanced %v", slept, elapsed)
+		check.Fix = "rates will be wrong; check the clock source and the system load"
+	case source == "jiffies":
+		check.Status = DoctorWarn
+		check.Detail = "clock source is jiffies"
+		check.Fix = "rates are coarse; boot with a hardware clock source (`clocksource=tsc`, or hpet)"
+	default:
+		check.Detail = "monotonic clock advances"
+		if source != "" {
+			check.Detail += ", clock source " + source
+		}
+	}
+	return check
+}
+
+// checkTerminal checks that stdout is a terminal drawing colors and the
+// symbols of the views
+func checkTerminal(term doctorTerminal) []DoctorCheck {
+	tty := DoctorCheck{Name: "terminal", Detail: "stdout is a terminal"}
+	if !term.tty {
+		tty.Status = DoctorWarn
+		tty.Detail = "stdout is not a terminal"
+		tty.Fix = "run doctor in the terminal the monitor runs in to check it"
+	}
+
+	colors := DoctorCheck{Name: "colors", Detail: profileNames[term.profile]}
+	if term.profile == termenv.Ascii {
+		colors.Status = DoctorWarn
+		colors.Fix = "states are told apart by letters only; unset NO_COLOR or set TERM to your terminal (e.g. xterm-256color)"
+		if term.getenv("NO_COLOR") != "" {
+			colors.Detail += " (NO_COLOR is set)"
+		}
+	}
+
+	symbols := DoctorCheck{Name: "symbols"}
+	locale := cmp.Or(firstEnv(term.getenv, "LC_ALL", "LC_CTYPE", "LANG"), "C")
+	utf8 := strings.Contains(strings.ToLower(strings.ReplaceAll(locale, "-", "")), "utf8")
+	switch {
+	case !utf8:
+		symbols.Status = DoctorWarn
+		symbols.Detail = fmt.Sprintf("locale %q is not UTF-8", locale)
+		symbols.Fix = "set a UTF-8 locale (e.g. LANG=en_US.UTF-8) for °, the bars and the emoji"
+	case term.getenv("TERM") == "linux":
+		symbols.Status = DoctorWarn
+		symbols.Detail = "the Linux console has no emoji glyphs"
+		symbols.Fix = "use a terminal emulator, or NO_COLOR=1 for letters instead of symbols"
+	default:
+		symbols.Detail = fmt.Sprintf("UTF-8, emoji drawn %d columns wide", ansi.StringWidth("🌡"))
+	}
+	return []DoctorCheck{tty, colors, symbols}
+}
+
+var profileNames = map[termenv.Profile]string{
+	termenv.TrueColor: "true color",
+	termenv.ANSI256:   "256 colors",
+	termenv.ANSI:      "16 colors",
+	termenv.Ascii:     "no colors",
+}
+
+// firstEnv returns the first of the variables that is set
+func firstEnv(getenv func(string) string, names ...string) string {
+	for _, name := range names {
+		if v := getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
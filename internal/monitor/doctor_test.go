@@ -0,0 +1,113 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muesli/termenv"
+)
+
+// doctorStatuses indexes the checks' statuses by name
+func doctorStatuses(checks []DoctorCheck) map[string]DoctorStatus {
+	statuses := make(map[string]DoctorStatus, len(checks))
+	for _, check := range checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+var goodTerminal = doctorTerminal{
+	tty:     true,
+	profile: termenv.ANSI256,
+	getenv: func(name string) string {
+		return map[string]string{"LANG": "en_US.UTF-8", "TERM": "xterm-256color"}[name]
+	},
+}
+
+func TestDoctorOnAWorkingMachine(t *testing.T) {
+	checks := doctor(filepath.Join("testdata", "machines", "intel-laptop"), goodTerminal, 20*time.Millisecond, 21*time.Millisecond)
+	for _, check := range checks {
+		if check.Status != DoctorPass {
+			t.Errorf("%s: expected a pass, got %s: %s", check.Name, check.Status, check.Detail)
+		}
+	}
+	if !DoctorOK(checks) {
+		t.Error("expected the machine to be usable")
+	}
+}
+
+func TestDoctorFailures(t *testing.T) {
+	// No sysfs at all: the terminal is still checked
+	checks := doctor(filepath.Join(t.TempDir(), "missing"), goodTerminal, 20*time.Millisecond, 21*time.Millisecond)
+	if statuses := doctorStatuses(checks); statuses["sysfs mounted"] != DoctorFail || len(checks) != 4 || DoctorOK(checks) {
+		t.Errorf("expected sysfs missing and the terminal checks only, got %+v", checks)
+	}
+
+	// Classes without a readable sensor, in a VM
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":      "acpi_fan\n",
+		"class/dmi/id/sys_vendor":      "QEMU\n",
+		clocksourcePath:                "jiffies\n",
+		"class/thermal/.keep":          "",
+		"class/power_supply/AC/type":   "Mains\n",
+		"class/power_supply/AC/online": "1\n",
+	})
+	checks = doctor(root, goodTerminal, 20*time.Millisecond, 21*time.Millisecond)
+	statuses := doctorStatuses(checks)
+	if statuses["readable sensors"] != DoctorFail || DoctorOK(checks) {
+		t.Errorf("expected no readable sensors to fail, got %+v", checks)
+	}
+	for _, check := range checks {
+		if check.Name == "readable sensors" && !strings.Contains(check.Fix, "QEMU") {
+			t.Errorf("expected the fix to point at the VM, got %q", check.Fix)
+		}
+		if check.Status != DoctorPass && check.Fix == "" {
+			t.Errorf("%s: expected a remediation", check.Name)
+		}
+	}
+	if statuses["clock"] != DoctorWarn {
+		t.Error("expected a jiffies clock source to warn")
+	}
+
+	// A clock that didn't advance
+	if check := checkClock(root, 20*time.Millisecond, 0); check.Status != DoctorWarn {
+		t.Errorf("expected a stopped clock to warn, got %+v", check)
+	}
+}
+
+func TestDoctorPermissions(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root reads every file")
+	}
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{"class/powercap/intel-rapl:0/energy_uj": "123\n"})
+	if err := os.Chmod(filepath.Join(root, "class/powercap/intel-rapl:0/energy_uj"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if check := checkPermissions(root); check.Status != DoctorWarn || !strings.Contains(check.Detail, "energy_uj") {
+		t.Errorf("expected the RAPL counter to need root, got %+v", check)
+	}
+}
+
+func TestDoctorTerminal(t *testing.T) {
+	term := doctorTerminal{
+		profile: termenv.Ascii,
+		getenv: func(name string) string {
+			return map[string]string{"LANG": "C", "NO_COLOR": "1"}[name]
+		},
+	}
+	for _, check := range checkTerminal(term) {
+		if check.Status != DoctorWarn || check.Fix == "" {
+			t.Errorf("%s: expected a warning with a fix, got %+v", check.Name, check)
+		}
+	}
+	for _, check := range checkTerminal(goodTerminal) {
+		if check.Status != DoctorPass {
+			t.Errorf("%s: expected a pass, got %+v", check.Name, check)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package monitor
+
+import "time"
+
+// refreshDynamicGroups replaces the groups whose sensors change between
+// refreshes, network interfaces and sensor scripts, with their latest
+// readings. They follow the groups discovered once, in that order, and are
+// told apart from them by SensorGroup.dynamic rather than by name, so a
+// script can't replace a built-in group.
+func (m *Monitor) refreshDynamicGroups(now time.Time) {
+	extra := make([]SensorGroup, 0, len(m.extraGroups))
+	taken := make(map[string]bool, len(m.extraGroups))
+	dynamic := false
+	for _, group := range m.extraGroups {
+		if group.dynamic {
+			dynamic = true
+			continue
+		}
+		extra = append(extra, group)
+		taken[group.Name] = true
+	}
+
+	var groups []SensorGroup
+	if m.network != nil {
+		for _, group := range m.network.groups(now) {
+			m.markGroupRead(group.Name, now)
+			groups = append(groups, group)
+			taken[group.Name] = true
+		}
+	}
+	if m.scripts != nil {
+		m.scripts.run(now)
+		groups = append(groups, m.scripts.groups(taken)...)
+		// Scripts report when their last run started, not this refresh
+		for name, t := range m.scripts.readTimes(taken) {
+			m.markGroupRead(name, t)
+		}
+	}
+	groups = m.excludeSensors(groups)
+	if len(groups) == 0 && !dynamic {
+		return
+	}
+	for i := range groups {
+		groups[i].dynamic = true
+	}
+	m.extraGroups = append(extra, groups...)
+}
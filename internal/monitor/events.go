@@ -0,0 +1,296 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxAlertHistory is the number of events kept for the alert history view
+const maxAlertHistory = 100
+
+// Event is a state transition of one reading, including recoveries to OK,
+// or another event of Type. Events are published on the monitor's bus,
+// whose subscribers include the --events stream and the in-app alert
+// history.
+type Event struct {
+	// SchemaVersion is the SchemaVersion of the event stream
+	SchemaVersion int       `json:"schema_version"`
+	Time          time.Time `json:"time"`
+	Host          string    `json:"host,omitempty"`
+	Type          EventType `json:"type"`
+	Sensor        string    `json:"sensor"`
+	Group         string    `json:"group,omitempty"`
+	// From and To are the states of a transition; they are left out of
+	// the JSON of other events (see MarshalJSON)
+	From State `json:"from"`
+	To   State `json:"to"`
+	// Value is a number for temperatures (Celsius) and battery capacity
+	// (percent), and the displayed string for other sensors
+	Value any `json:"value"`
+	// Threshold is the limit crossed, when the reading has one
+	Threshold *float64 `json:"threshold,omitempty"`
+	// Profile is set instead of a transition when the active threshold
+	// profile switched; Sensor is then "Profile"
+	Profile *ProfileChange `json:"profile,omitempty"`
+	// Sensors is set instead of a transition when rediscovery added or
+	// removed temperatures; Sensor is then "Sensors"
+	Sensors *SensorChange `json:"sensors,omitempty"`
+	// Power is set instead of a transition on a power event; Sensor is
+	// then "Power"
+	Power *PowerChange `json:"power,omitempty"`
+	// Frozen is set instead of a transition when the reading is first
+	// marked frozen; From and To are then its state
+	Frozen *FrozenChange `json:"frozen,omitempty"`
+}
+
+// MarshalJSON leaves from and to out of the events that aren't state
+// transitions, such as profile switches and power events, which would
+// otherwise read "ok" to "ok". Frozen readings keep them, set to their
+// state.
+func (e Event) MarshalJSON() ([]byte, error) {
+	type plain Event
+	if e.Type == EventStateChanged || e.Type == EventSensorFrozen {
+		return json.Marshal(plain(e))
+	}
+	return json.Marshal(struct {
+		plain
+		From *State `json:"from,omitempty"`
+		To   *State `json:"to,omitempty"`
+	}{plain: plain(e)})
+}
+
+// WithEventWriter writes every event to w as one JSON object per line, in
+// a goroutine subscribed to the monitor's bus; Close writes those still
+// queued
+func WithEventWriter(w io.Writer) Option {
+	return func(m *Monitor) {
+		m.eventsTo = w
+	}
+}
+
+// eventWriter is the subscriber writing the --events stream. Writing in its
+// own goroutine, it lets a FIFO's slow reader hold up neither the refresh
+// nor the other consumers.
+type eventWriter struct {
+	sub     *Subscription
+	enc     *json.Encoder
+	stopped chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+// newEventWriter subscribes a writer of w to bus
+func newEventWriter(bus *EventBus, w io.Writer) *eventWriter {
+	ew := &eventWriter{sub: bus.subscribe(nil), enc: json.NewEncoder(w), stopped: make(chan struct{})}
+	go ew.run()
+	return ew
+}
+
+func (w *eventWriter) run() {
+	defer close(w.stopped)
+	for {
+		select {
+		case <-w.sub.ready:
+			w.write(w.sub.take())
+		case <-w.sub.done:
+			w.write(w.sub.take())
+			return
+		}
+	}
+}
+
+func (w *eventWriter) write(events []Event) {
+	for _, event := range events {
+		if err := w.enc.Encode(event); err != nil {
+			w.mu.Lock()
+			w.err = err
+			w.mu.Unlock()
+		}
+	}
+}
+
+// failure returns the last write error since the previous call
+func (w *eventWriter) failure() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.err
+	w.err = nil
+	return err
+}
+
+// close writes the events still queued and stops the writer
+func (w *eventWriter) close() {
+	w.sub.Unsubscribe()
+	<-w.stopped
+}
+
+// Refresh reads all sensors once and records the resulting transitions. The
+// TUI calls it on every tick; it can also drive a monitor without a program.
+func (m Monitor) Refresh() Monitor {
+	prev := m.lastUpdate
+	elapsed := m.checkClock()
+	m = m.updateSensors()
+	m.lastUpdate = m.clock.Now()
+	m.monoUpdate, _ = m.monotonic()
+	m.nextRefresh = m.lastUpdate.Add(m.refreshInterval())
+	m.accrueStateTime(elapsed)
+	m.trackSuspend(prev, elapsed)
+	m.trackChanges(m.lastUpdate)
+	m.trackFrozen(m.lastUpdate)
+	m.record(append(m.pending, m.transitions(m.lastUpdate)...))
+	m.pending = nil
+	m.recordBattery(m.lastUpdate)
+	m.recordHistory(m.lastUpdate)
+	return m
+}
+
+// record stamps events and publishes them on the bus, then takes the
+// alert history's own subscription, which it consumes in place
+func (m *Monitor) record(events []Event) {
+	for i := range events {
+		events[i].SchemaVersion = SchemaVersion
+	}
+	if len(events) > 0 {
+		m.bus.Publish(events...)
+	}
+	m.history = append(m.history, m.historySub.take()...)
+	if len(m.history) > maxAlertHistory {
+		m.history = append([]Event(nil), m.history[len(m.history)-maxAlertHistory:]...)
+	}
+	if m.eventWriter != nil {
+		if err := m.eventWriter.failure(); err != nil {
+			m.status = fmt.Sprintf("Event stream: %v", err)
+		}
+	}
+}
+
+// AlertHistory returns the recorded events, oldest first
+func (m Monitor) AlertHistory() []Event {
+	return append([]Event(nil), m.history...)
+}
+
+// transitions compares every reading's state with the previous refresh.
+// Readings seen for the first time are compared with OK, so a sensor that is
+// already critical at startup produces an event.
+func (m *Monitor) transitions(now time.Time) []Event {
+	var events []Event
+	states := make(map[string]State)
+	check := func(key string, event Event) {
+		states[key] = event.To
+		if from := m.states[key]; from != event.To {
+			event.Time = now
+			event.Host = m.hostname
+			event.From = from
+			events = append(events, event)
+		}
+	}
+
+	for _, sensor := range m.temperatureSensors {
+		event := Event{Sensor: sensor.key(), To: sensor.State(), Value: sensor.Value}
+		limit := sensor.High
+		switch {
+		case event.To == StateCritical && sensor.belowLowCritical():
+			limit = *sensor.LowCritical
+		case event.To == StateCritical:
+			limit = sensor.Critical
+		}
+		if limit > 0 {
+			event.Threshold = &limit
+		}
+		check(temperatureStateKey(sensor.key()), event)
+	}
+
+	if bat := m.batteryStatus; bat.Present() {
+		event := Event{Sensor: "Battery", To: m.batteryState(), Value: bat.Capacity}
+		// Only capacity has a numeric threshold; health and adapter warnings don't
+		if state := m.batteryCapacityState(); state != StateOK && state == event.To {
+			t := bat.thresholds(m.currentBatteryThresholds(), m.notChargingThresholds)
+			limit := float64(t.Warning)
+			if state == StateCritical {
+				limit = float64(t.Critical)
+			}
+			event.Threshold = &limit
+		}
+		check(batteryStateKey, event)
+	}
+
+	for _, group := range m.extraGroups {
+		for _, sensor := range group.Sensors {
+			event := Event{Sensor: sensor.Name(), Group: group.Name, To: m.groupSensorState(group.Name, sensor), Value: m.sensorValue(sensor)}
+			check(groupStateKey(group.Name, sensor.Name()), event)
+		}
+	}
+
+	m.states = states
+	return events
+}
+
+// alertsView renders the alert history, newest first
+func (m Monitor) alertsView() string {
+	var sb strings.Builder
+	faint := lipgloss.NewStyle().Faint(true)
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render("Alert History"))
+	sb.WriteString("\n\n")
+	if len(m.history) == 0 {
+		sb.WriteString("  No alerts yet\n")
+	}
+	// Leave room for the title and help lines
+	shown := 0
+	for i := len(m.history) - 1; i >= 0 && (m.height == 0 || shown < m.height-4); i-- {
+		event := m.history[i]
+		name := event.Sensor
+		if event.Group != "" {
+			name = event.Group + "/" + name
+		}
+		switch event.Type {
+		case EventProfileChanged:
+			fmt.Fprintf(&sb, "  %s %s %s → %s\n", event.Time.Format("15:04:05"), padRight("Profile", 24), event.Profile.From, event.Profile.To)
+			shown++
+			continue
+		case EventSensorAdded, EventSensorRemoved:
+			sb.WriteString(sensorChangeLine(event))
+			shown++
+			continue
+		case EventPowerChanged, EventSuspendDetected:
+			sb.WriteString(eventLine(event))
+			shown++
+			continue
+		case EventSensorFrozen:
+			sb.WriteString(m.frozenChangeLine(event))
+			shown++
+			continue
+		}
+		style := m.theme.stateStyle(event.To)
+		fmt.Fprintf(&sb, "  %s %s %s → %s  %s\n",
+			event.Time.Format("15:04:05"), padRight(name, 24), event.From, style.Render(event.To.String()), m.eventValue(event))
+		shown++
+	}
+	sb.WriteString("\n")
+	sb.WriteString(faint.Render("a/esc: back"))
+	return sb.String()
+}
+
+// eventLine is the alert history line of an event its value describes, a
+// power event or a suspend
+func eventLine(event Event) string {
+	return fmt.Sprintf("  %s %s %s\n", event.Time.Format("15:04:05"), padRight(event.Sensor, 24), event.Value)
+}
+
+// eventValue formats an event value like the main view does
+func (m Monitor) eventValue(event Event) string {
+	switch value := event.Value.(type) {
+	case float64:
+		return m.numbers.localize(formatTemp(value, m.unit, 0))
+	case int:
+		return m.numbers.localize(fmt.Sprintf("%d%%", value))
+	default:
+		return fmt.Sprint(value)
+	}
+}
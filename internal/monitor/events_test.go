@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransitionsIncludeRecovery(t *testing.T) {
+	var out bytes.Buffer
+	m := NewMonitor(WithEventWriter(&out), WithHostname("box1"))
+	m.width, m.height = 80, 24
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	m.temperatureSensors = []TemperatureSensor{{Name: "CPU", Value: 50, High: 80, Critical: 95}}
+	m.batteryStatus = BatteryStatus{Capacity: 80, Status: "Discharging"}
+	// No events for OK readings
+	m.record(m.transitions(now))
+
+	m.temperatureSensors[0].Value = 97
+	m.batteryStatus.Capacity = 15
+	m.record(m.transitions(now))
+	m.temperatureSensors[0].Value = 60
+	m.record(m.transitions(now))
+	m.record(m.transitions(now))
+
+	// Close writes the events the stream's goroutine hasn't yet
+	m.Close()
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{
+		`{"schema_version":1,"time":"2026-01-02T03:04:05Z","host":"box1","type":"state_changed","sensor":"CPU","from":"ok","to":"critical","value":97,"threshold":95}`,
+		`{"schema_version":1,"time":"2026-01-02T03:04:05Z","host":"box1","type":"state_changed","sensor":"Battery","from":"ok","to":"critical","value":15,"threshold":20}`,
+		`{"schema_version":1,"time":"2026-01-02T03:04:05Z","host":"box1","type":"state_changed","sensor":"CPU","from":"critical","to":"ok","value":60,"threshold":80}`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d events, got %d:\n%s", len(want), len(lines), out.String())
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("event %d:\n got %s\nwant %s", i, lines[i], want[i])
+		}
+	}
+
+	// The alert history is built from the same events
+	history := m.AlertHistory()
+	if len(history) != len(want) {
+		t.Fatalf("expected %d history entries, got %d", len(want), len(history))
+	}
+	for i, event := range history {
+		data, _ := json.Marshal(event)
+		if string(data) != want[i] {
+			t.Errorf("history %d: got %s", i, data)
+		}
+	}
+	m = sendKeys(m, "a")
+	if view := m.View(); !strings.Contains(view, "Alert History") || !strings.Contains(view, "critical → ok") {
+		t.Errorf("expected alert history view:\n%s", view)
+	}
+}
+
+func TestTransitionsExtraGroups(t *testing.T) {
+	m := NewMonitor()
+	m.RegisterSensorGroup(SensorGroup{Name: "Fans", Sensors: []Sensor{newStaticSensor("fan1", true, false)}})
+	events := m.transitions(time.Now())
+	if len(events) != 1 || events[0].Group != "Fans" || events[0].To != StateWarning || events[0].Threshold != nil {
+		t.Errorf("expected one fan warning event without threshold, got %+v", events)
+	}
+}
+
+func TestEventJSONOmitsStatesOfOtherEvents(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		event Event
+		want  string
+	}{
+		{
+			Event{Time: now, Type: EventProfileChanged, Sensor: "Profile", Value: "night", Profile: &ProfileChange{From: "day", To: "night"}},
+			`{"schema_version":0,"time":"2026-01-02T03:04:05Z","type":"profile_changed","sensor":"Profile","value":"night","profile":{"from":"day","to":"night"}}`,
+		},
+		{
+			Event{Time: now, Type: EventSensorAdded, Sensor: "Sensors", Value: "+nvme", Sensors: &SensorChange{Added: []string{"nvme"}}},
+			`{"schema_version":0,"time":"2026-01-02T03:04:05Z","type":"sensor_added","sensor":"Sensors","value":"+nvme","sensors":{"added":["nvme"]}}`,
+		},
+		{
+			Event{Time: now, Type: EventSuspendDetected, Sensor: "System", Value: "resumed after 1h0m"},
+			`{"schema_version":0,"time":"2026-01-02T03:04:05Z","type":"suspend_detected","sensor":"System","value":"resumed after 1h0m"}`,
+		},
+		// Transitions keep both states, recoveries to OK included
+		{
+			Event{Time: now, Type: EventStateChanged, Sensor: "CPU", From: StateWarning, To: StateOK, Value: 60.0},
+			`{"schema_version":0,"time":"2026-01-02T03:04:05Z","type":"state_changed","sensor":"CPU","from":"warning","to":"ok","value":60}`,
+		},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.event.Type, data, tt.want)
+		}
+	}
+}
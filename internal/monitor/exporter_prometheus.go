@@ -0,0 +1,172 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const metricPrefix = "sysfs_monitor_"
+
+// metricFamily collects the samples of one metric so HELP and TYPE are
+// written once
+type metricFamily struct {
+	name, help, kind string
+	samples          []string
+}
+
+type metricSet struct {
+	families []*metricFamily
+	byName   map[string]*metricFamily
+	// common labels every sample starts with
+	common [][2]string
+}
+
+func (s *metricSet) add(name, kind, help string, labels [][2]string, value float64) {
+	f := s.byName[name]
+	if f == nil {
+		f = &metricFamily{name: name, help: help, kind: kind}
+		if s.byName == nil {
+			s.byName = make(map[string]*metricFamily)
+		}
+		s.byName[name] = f
+		s.families = append(s.families, f)
+	}
+	labels = append(append([][2]string(nil), s.common...), labels...)
+	var sb strings.Builder
+	sb.WriteString(name)
+	if len(labels) > 0 {
+		sb.WriteString("{")
+		for i, label := range labels {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			fmt.Fprintf(&sb, "%s=\"%s\"", label[0], escapeLabel(label[1]))
+		}
+		sb.WriteString("}")
+	}
+	fmt.Fprintf(&sb, " %g", value)
+	f.samples = append(f.samples, sb.String())
+}
+
+// escapeLabel escapes a label value as the exposition format requires
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// metricName turns a unit into a metric name component
+func metricName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return '_'
+	}, s)
+}
+
+// kindMetrics names the gauge of a group reading's measurement by its kind;
+// info readings only have their state
+var kindMetrics = map[Kind]struct{ name, help string }{
+	KindTemperature: {"sensor_temperature_celsius", "Temperature of a group sensor."},
+	KindFan:         {"sensor_fan_rpm", "Fan speed of a group sensor."},
+	KindPower:       {"sensor_power_watts", "Power of a group sensor."},
+	KindVoltage:     {"sensor_voltage_volts", "Voltage of a group sensor."},
+	KindCurrent:     {"sensor_current_amperes", "Current of a group sensor."},
+	KindPercentage:  {"sensor_percent", "Percentage of a group sensor."},
+	KindRate:        {"sensor_rate_bytes_per_second", "Rate of a group sensor."},
+}
+
+// WritePrometheus writes a snapshot in the Prometheus text exposition
+// format. Counter sensors are exported as counters with a _total suffix and
+// their raw cumulative value; everything else is a gauge, and group readings
+// with a measurement get one named after their kind, and every section has
+// the time its readings were taken. Every sample is labeled with the
+// snapshot's host name.
+func WritePrometheus(w io.Writer, snap Snapshot) error {
+	var set metricSet
+	if snap.Hostname != "" {
+		set.common = [][2]string{{"host", snap.Hostname}}
+	}
+	for _, t := range snap.Temperatures {
+		if t.Asleep {
+			continue
+		}
+		set.add(metricPrefix+"temperature_celsius", "gauge", "Temperature reading.", [][2]string{{"sensor", t.key()}}, t.Value)
+	}
+	if headroom, ok := snap.Headroom(); ok {
+		set.add(metricPrefix+"thermal_headroom_celsius", "gauge", "Smallest margin of a temperature below its critical threshold.", [][2]string{{"sensor", headroom.Sensor}}, headroom.Degrees)
+	}
+	if bat := snap.Battery; bat.Present() {
+		set.add(metricPrefix+"battery_capacity_percent", "gauge", "Battery capacity.", nil, float64(bat.Capacity))
+		if bat.Voltage > 0 {
+			set.add(metricPrefix+"battery_voltage_volts", "gauge", "Battery voltage.", nil, bat.Voltage)
+		}
+		if bat.Power > 0 {
+			set.add(metricPrefix+"battery_power_watts", "gauge", "Battery power draw.", nil, bat.Power)
+		}
+		online := 0.0
+		if bat.ACOnline {
+			online = 1
+		}
+		set.add(metricPrefix+"ac_online", "gauge", "Whether an AC adapter is online.", nil, online)
+	}
+	for _, group := range snap.Groups {
+		for _, r := range group.Readings {
+			labels := [][2]string{{"group", group.Name}, {"sensor", r.Name}}
+			if r.CounterUnit != "" {
+				unit := metricName(r.CounterUnit)
+				set.add(metricPrefix+unit+"_total", "counter", "Cumulative "+r.CounterUnit+" counted by the sensor.", labels, r.Counter)
+			}
+			if metric, ok := kindMetrics[r.Kind]; ok && r.Measurement != nil && r.CounterUnit == "" {
+				set.add(metricPrefix+metric.name, "gauge", metric.help, labels, *r.Measurement)
+			}
+			set.add(metricPrefix+"sensor_state", "gauge", "Sensor alert state (0 ok, 1 warning, 2 critical).", labels, float64(r.State))
+		}
+	}
+	// Sections refresh at their own rates, so each tells when it was read
+	readAt := func(section string, t time.Time) {
+		if !t.IsZero() {
+			set.add(metricPrefix+"last_read_timestamp_seconds", "gauge", "Unix time the readings of a section were taken.", [][2]string{{"section", section}}, float64(t.UnixMilli())/1000)
+		}
+	}
+	readAt("Temperatures", snap.TemperaturesTime)
+	if snap.Battery.Present() {
+		readAt("Battery", snap.BatteryTime)
+	}
+	for _, group := range snap.Groups {
+		readAt(group.Name, group.Time)
+	}
+	set.add(metricPrefix+"worst_state", "gauge", "Most severe alert state across all readings.", nil, float64(snap.Worst))
+	set.add(metricPrefix+"schema_version", "gauge", "Version of the metric names and meanings.", nil, SchemaVersion)
+
+	for _, f := range set.families {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind); err != nil {
+			return err
+		}
+		for _, sample := range f.samples {
+			if _, err := fmt.Fprintln(w, sample); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PrometheusHandler serves the snapshot returned by latest on every scrape.
+// Until the first refresh latest reports false and the handler returns 503.
+func PrometheusHandler(latest func() (Snapshot, bool)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snap, ok := latest()
+		if !ok {
+			http.Error(w, "no readings yet", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		WritePrometheus(w, snap)
+	})
+}
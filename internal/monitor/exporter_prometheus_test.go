@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// counterSensor shows a rate but exposes its cumulative counter
+type counterSensor struct {
+	*GenericSensor
+	total float64
+}
+
+func (c counterSensor) Counter() (float64, string) {
+	return c.total, "bytes"
+}
+
+func TestWritePrometheus(t *testing.T) {
+	m := NewMonitor(WithHostname("box1"))
+	m.temperatureSensors = []TemperatureSensor{{Name: `Core "0"`, Value: 65.5, High: 80, Critical: 100}}
+	m.batteryStatus = BatteryStatus{Capacity: 80, Status: "Discharging", Voltage: 12.5}
+	m.RegisterSensorGroup(SensorGroup{Name: "Network", Sensors: []Sensor{
+		counterSensor{NewGenericSensor("eth0 rx", nil), 123456789},
+		newStaticSensor("link", true, false),
+	}})
+
+	var sb strings.Builder
+	if err := WritePrometheus(&sb, m.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP sysfs_monitor_temperature_celsius Temperature reading.
+# TYPE sysfs_monitor_temperature_celsius gauge
+sysfs_monitor_temperature_celsius{host="box1",sensor="Core \"0\""} 65.5
+# HELP sysfs_monitor_thermal_headroom_celsius Smallest margin of a temperature below its critical threshold.
+# TYPE sysfs_monitor_thermal_headroom_celsius gauge
+sysfs_monitor_thermal_headroom_celsius{host="box1",sensor="Core \"0\""} 34.5
+# HELP sysfs_monitor_battery_capacity_percent Battery capacity.
+# TYPE sysfs_monitor_battery_capacity_percent gauge
+sysfs_monitor_battery_capacity_percent{host="box1"} 80
+# HELP sysfs_monitor_battery_voltage_volts Battery voltage.
+# TYPE sysfs_monitor_battery_voltage_volts gauge
+sysfs_monitor_battery_voltage_volts{host="box1"} 12.5
+# HELP sysfs_monitor_ac_online Whether an AC adapter is online.
+# TYPE sysfs_monitor_ac_online gauge
+sysfs_monitor_ac_online{host="box1"} 0
+# HELP sysfs_monitor_bytes_total Cumulative bytes counted by the sensor.
+# TYPE sysfs_monitor_bytes_total counter
+sysfs_monitor_bytes_total{host="box1",group="Network",sensor="eth0 rx"} 1.23456789e+08
+# HELP sysfs_monitor_sensor_state Sensor alert state (0 ok, 1 warning, 2 critical).
+# TYPE sysfs_monitor_sensor_state gauge
+sysfs_monitor_sensor_state{host="box1",group="Network",sensor="eth0 rx"} 0
+sysfs_monitor_sensor_state{host="box1",group="Network",sensor="link"} 1
+# HELP sysfs_monitor_worst_state Most severe alert state across all readings.
+# TYPE sysfs_monitor_worst_state gauge
+sysfs_monitor_worst_state{host="box1"} 1
+# HELP sysfs_monitor_schema_version Version of the metric names and meanings.
+# TYPE sysfs_monitor_schema_version gauge
+sysfs_monitor_schema_version{host="box1"} 1
+`
+	if sb.String() != want {
+		t.Errorf("unexpected exposition:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
+
+func TestWritePrometheusKinds(t *testing.T) {
+	rpm, percent := 1200.0, 40.0
+	snap := Snapshot{Groups: []GroupSnapshot{
+		{Name: "Fans", Readings: []SensorReading{{Name: "fan1", Value: "1200 RPM", Kind: KindFan, Measurement: &rpm}}},
+		{Name: "Display", Readings: []SensorReading{
+			{Name: "Backlight", Value: "40%", Kind: KindPercentage, Measurement: &percent},
+			{Name: "Platform profile", Value: "balanced", Kind: KindInfo},
+		}},
+	}}
+	var sb strings.Builder
+	if err := WritePrometheus(&sb, snap); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	for _, want := range []string{
+		"# TYPE sysfs_monitor_sensor_fan_rpm gauge\nsysfs_monitor_sensor_fan_rpm{group=\"Fans\",sensor=\"fan1\"} 1200\n",
+		"sysfs_monitor_sensor_percent{group=\"Display\",sensor=\"Backlight\"} 40\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "Platform profile") != 1 {
+		t.Errorf("expected only the state of an info reading:\n%s", out)
+	}
+}
+
+func TestPrometheusHandlerBeforeFirstRefresh(t *testing.T) {
+	h := PrometheusHandler(func() (Snapshot, bool) { return Snapshot{}, false })
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 before the first refresh, got %d", rec.Code)
+	}
+}
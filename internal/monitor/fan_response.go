@@ -0,0 +1,175 @@
+package monitor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultFanCheckTicks is how many consecutive refreshes a temperature may
+// stay above High with idle fans before the fan check fails
+const DefaultFanCheckTicks = 5
+
+// fanResponseName names the synthetic sensor of the fan check
+const fanResponseName = "Fan response"
+
+// FanCheckConfig enables the fan check: a "Fan response" sensor in the
+// Fans group that goes critical when a temperature stays above its High
+// threshold while its fans are stopped or no faster than when it crossed
+// High, the classic failure of a dead fan or a stuck fan curve. Fans
+// holding the highest speed they were read at count as responding.
+type FanCheckConfig struct {
+	// Ticks is how many consecutive refreshes the condition must last
+	// before the check fails (default 5)
+	Ticks int `json:"ticks,omitempty"`
+	// Pairs assigns fans to temperatures by name, e.g.
+	// {"Package id 0": ["CPU fan"]}, for temperatures whose fans aren't on
+	// the same hwmon chip. Other temperatures keep the same-chip pairing.
+	Pairs map[string][]string `json:"pairs,omitempty"`
+}
+
+func (c FanCheckConfig) validate() error {
+	if c.Ticks < 0 {
+		return fmt.Errorf("fan_check ticks: %d is negative", c.Ticks)
+	}
+	return nil
+}
+
+// fanResponseSensor is the result of the fan check, updated by the monitor
+// after each refresh (see checkFanResponse)
+type fanResponseSensor struct {
+	// hot counts the consecutive refreshes each temperature has been above
+	// High with idle fans
+	hot map[string]int
+	// since holds the speed of the paired fans, by path, when each hot
+	// temperature crossed High
+	since map[string]map[string]int64
+	// failing names the temperature whose fans don't respond, if any
+	failing string
+}
+
+func (s *fanResponseSensor) Name() string {
+	return fanResponseName
+}
+
+func (s *fanResponseSensor) Value() string {
+	if s.failing != "" {
+		return s.failing + " hot, fans idle"
+	}
+	return "OK"
+}
+
+func (s *fanResponseSensor) Warning() bool {
+	return false
+}
+
+func (s *fanResponseSensor) Critical() bool {
+	return s.failing != ""
+}
+
+// Refresh does nothing: the check is derived from readings the monitor
+// already has
+func (s *fanResponseSensor) Refresh() error {
+	return nil
+}
+
+// addFanCheck adds the fan response sensor to the Fans group when the
+// config enables the check and fans were discovered
+func (m *Monitor) addFanCheck() {
+	if m.config.FanCheck == nil {
+		return
+	}
+	for i := range m.extraGroups {
+		if m.extraGroups[i].Name == fansGroupName {
+			m.extraGroups[i].Sensors = append(m.extraGroups[i].Sensors, &fanResponseSensor{})
+			return
+		}
+	}
+}
+
+// checkFanResponse updates the fan response sensor from the temperatures
+// and fan speeds of the refresh
+func (m *Monitor) checkFanResponse() {
+	var check *fanResponseSensor
+	var fans []*FanSensor
+	for _, group := range m.extraGroups {
+		if group.Name != fansGroupName {
+			continue
+		}
+		for _, sensor := range group.Sensors {
+			switch s := sensor.(type) {
+			case *fanResponseSensor:
+				check = s
+			case *FanSensor:
+				fans = append(fans, s)
+			}
+		}
+	}
+	if check == nil {
+		return
+	}
+	if m.config.FanCheck == nil {
+		check.hot, check.since, check.failing = nil, nil, ""
+		return
+	}
+	ticks := DefaultFanCheckTicks
+	if m.config.FanCheck.Ticks > 0 {
+		ticks = m.config.FanCheck.Ticks
+	}
+
+	hot := make(map[string]int)
+	since := make(map[string]map[string]int64)
+	check.failing = ""
+	for _, sensor := range m.temperatureSensors {
+		paired := m.pairedFans(sensor, fans)
+		if sensor.Value < sensor.High || len(paired) == 0 {
+			continue
+		}
+		key := sensor.key()
+		speeds, ok := check.since[key]
+		if !ok {
+			speeds = make(map[string]int64, len(paired))
+			for _, fan := range paired {
+				speeds[fan.path] = fan.rpm
+			}
+		}
+		since[key] = speeds
+		idle := true
+		for _, fan := range paired {
+			idle = idle && fan.idle(speeds[fan.path])
+		}
+		if !idle {
+			continue
+		}
+		hot[key] = check.hot[key] + 1
+		if hot[key] > ticks && check.failing == "" {
+			check.failing = sensor.Name
+		}
+	}
+	check.hot, check.since = hot, since
+}
+
+// pairedFans returns the fans cooling a temperature: those configured for
+// it, otherwise the fans on its hwmon chip
+func (m Monitor) pairedFans(sensor TemperatureSensor, fans []*FanSensor) []*FanSensor {
+	var paired []*FanSensor
+	if names, ok := lookupSensor(m.config.FanCheck.Pairs, sensor); ok {
+		for _, fan := range fans {
+			for _, name := range names {
+				if fan.Name() == name {
+					paired = append(paired, fan)
+				}
+			}
+		}
+		return paired
+	}
+	if !strings.HasSuffix(sensor.Path, "_input") {
+		return nil
+	}
+	for _, fan := range fans {
+		if fan.chip() == filepath.Dir(sensor.Path) {
+			paired = append(paired, fan)
+		}
+	}
+	return paired
+}
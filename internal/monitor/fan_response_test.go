@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFanResponse(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":        "nct6775\n",
+		"class/hwmon/hwmon0/temp1_input": "60000\n",
+		"class/hwmon/hwmon0/temp1_label": "CPUTIN\n",
+		"class/hwmon/hwmon0/temp1_max":   "80000\n",
+		"class/hwmon/hwmon0/fan1_input":  "0\n",
+		"class/hwmon/hwmon0/fan2_input":  "900\n",
+		"class/hwmon/hwmon0/fan2_label":  "CPU fan\n",
+		"class/hwmon/hwmon1/name":        "coretemp\n",
+		"class/hwmon/hwmon1/temp1_input": "50000\n",
+		"class/hwmon/hwmon1/temp1_label": "Package id 0\n",
+		"class/hwmon/hwmon1/temp1_max":   "90000\n",
+	})
+	m := NewMonitor()
+	m.config.FanCheck = &FanCheckConfig{Ticks: 2}
+	m.discoverGroups(root)
+	m.addFanCheck()
+	cooling := m.extraGroups[len(m.extraGroups)-1]
+	if cooling.Name != fansGroupName || len(cooling.Sensors) != 3 {
+		t.Fatalf("expected two fans and the check in the Fans group, got %+v", cooling)
+	}
+	check := cooling.Sensors[2]
+
+	write := func(rel, value string) {
+		if err := os.WriteFile(filepath.Join(root, rel), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	refresh := func() {
+		m.temperatureSensors = readTemperatures(root)
+		for _, sensor := range cooling.Sensors {
+			sensor.Refresh()
+		}
+		m.checkFanResponse()
+	}
+
+	// Hot, but the CPU fan reacts
+	write("class/hwmon/hwmon0/temp1_input", "85000")
+	for _, rpm := range []string{"1200", "1500", "1800", "2100"} {
+		write("class/hwmon/hwmon0/fan2_input", rpm)
+		refresh()
+	}
+	if check.Critical() {
+		t.Fatalf("expected OK while the fan speeds up, got %q", check.Value())
+	}
+
+	// Holding its top speed, the fan is doing what it can
+	for range 5 {
+		refresh()
+	}
+	if check.Critical() {
+		t.Fatalf("expected OK for a fan at steady maximum speed, got %q", check.Value())
+	}
+
+	// The fan falls back below its speed at the crossing and stays there:
+	// critical after more than 2 refreshes
+	write("class/hwmon/hwmon0/fan2_input", "1000")
+	for range 2 {
+		refresh()
+	}
+	if check.Critical() {
+		t.Fatal("expected OK for up to 2 refreshes")
+	}
+	refresh()
+	if !check.Critical() || check.Value() != "CPUTIN hot, fans idle" {
+		t.Errorf("expected the check to fail, got %q", check.Value())
+	}
+
+	// Cooling down clears it
+	write("class/hwmon/hwmon0/temp1_input", "70000")
+	refresh()
+	if check.Critical() {
+		t.Errorf("expected OK once below High, got %q", check.Value())
+	}
+
+	// A configured pairing replaces the same chip: coretemp has no fans
+	m.config.FanCheck.Pairs = map[string][]string{"Package id 0": {"CPU fan"}}
+	write("class/hwmon/hwmon1/temp1_input", "95000")
+	for range 3 {
+		refresh()
+	}
+	if check.Value() != "Package id 0 hot, fans idle" {
+		t.Errorf("expected the paired temperature to fail, got %q", check.Value())
+	}
+}
+
+func TestFanCheckNeedsConfig(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":       "thinkpad\n",
+		"class/hwmon/hwmon0/fan1_input": "2400\n",
+		"class/hwmon/hwmon0/fan1_min":   "3000\n",
+	})
+	m := NewMonitor()
+	m.discoverGroups(root)
+	m.addFanCheck()
+	cooling := m.extraGroups[len(m.extraGroups)-1]
+	if len(cooling.Sensors) != 1 {
+		t.Fatalf("expected only the fan without fan_check, got %+v", cooling.Sensors)
+	}
+	if fan := cooling.Sensors[0]; fan.Name() != "thinkpad_fan1" || fan.Value() != "2400 RPM" || !fan.Warning() {
+		t.Errorf("expected a warning below fan1_min, got %s %s", fan.Name(), fan.Value())
+	}
+}
@@ -0,0 +1,142 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// The footer of both views is a text/template over a FooterContext, so it
+// can be trimmed or extended from the config. It is kept to one line,
+// elided from the right when wider than the view.
+
+const (
+	// DefaultFooter is the template of the full view's footer
+	DefaultFooter = `{{if .Host}}{{.Host}} | {{end}}Last updated: {{.LastUpdate.Format "15:04:05"}}{{if .ClockJump}} (clock changed){{end}}` +
+		`{{if .Paused}} | paused{{else if .Idle}} | idle{{else if .NextIn}} | next in {{.NextIn}}{{end}}` +
+		`{{if .Profile}} | profile: {{.Profile}}{{end}} | Press 'q' to quit`
+
+	// DefaultCompactFooter is the template of the compact view's footer
+	DefaultCompactFooter = `Updated: {{.LastUpdate.Format "15:04:05"}}{{if .ClockJump}} (clock changed){{end}}`
+)
+
+var (
+	defaultFooter        = template.Must(ParseFooter(DefaultFooter))
+	defaultCompactFooter = template.Must(ParseFooter(DefaultCompactFooter))
+)
+
+// FooterContext is what the footer templates are executed with
+type FooterContext struct {
+	// LastUpdate is the time of the oldest readings shown, or of the last
+	// refresh when sections lagging by more than an interval show their
+	// own age
+	LastUpdate time.Time
+	// ClockJump is how far the wall clock jumped, for a few refreshes
+	// after an NTP step or a manual change made LastUpdate misleading
+	ClockJump time.Duration
+	// Interval is the time between refreshes
+	Interval time.Duration
+	Unit     TempUnit
+	// Paused and Idle are set while refreshes are paused or slowed down by
+	// the low-power mode; NextIn is the countdown to the next refresh, e.g.
+	// "3s", empty when none is scheduled
+	Paused bool
+	Idle   bool
+	NextIn string
+	// Host is the host name, with the demo notice, when the title line
+	// that otherwise shows it is hidden
+	Host string
+	// Profile is the active threshold profile
+	Profile string
+	// FilterText is the sensor filter being typed; the views have none
+	// yet, so it is empty
+	FilterText string
+	// WarningCount and CriticalCount count the readings in each state
+	WarningCount  int
+	CriticalCount int
+	// ScrollPercent is how far the view is scrolled; the views don't
+	// scroll yet, so it is 0
+	ScrollPercent int
+}
+
+// ParseFooter parses a footer template, checking that it executes over a
+// FooterContext
+func ParseFooter(text string) (*template.Template, error) {
+	tmpl, err := template.New("footer").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(new(strings.Builder), FooterContext{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// WithFooter replaces the footer templates of the full and compact views,
+// parsed by ParseFooter; nil keeps a default
+func WithFooter(full, compact *template.Template) Option {
+	return func(m *Monitor) {
+		m.footer = full
+		m.compactFooter = compact
+	}
+}
+
+// footerTemplates parses the footer templates of a config; LoadConfig has
+// checked them
+func footerTemplates(cfg Config) (full, compact *template.Template) {
+	if cfg.Footer != "" {
+		full, _ = ParseFooter(cfg.Footer)
+	}
+	if cfg.CompactFooter != "" {
+		compact, _ = ParseFooter(cfg.CompactFooter)
+	}
+	return full, compact
+}
+
+// footerContext collects what the footers show
+func footerContext(snap Snapshot, view ViewState, host string, now time.Time) FooterContext {
+	// The oldest readings' time, unless the sections lagging by more than
+	// an interval carry their own age
+	updated := snap.Oldest()
+	if snap.diverged(view.Interval) {
+		updated = snap.Time
+	}
+	ctx := FooterContext{
+		LastUpdate:    updated,
+		ClockJump:     view.ClockJump,
+		Interval:      view.Interval,
+		Unit:          view.Unit,
+		Paused:        view.Paused,
+		Idle:          view.Idle,
+		Profile:       snap.Profile,
+		WarningCount:  snap.Counts.Warning,
+		CriticalCount: snap.Counts.Critical,
+	}
+	if !view.Paused && !view.Idle && !view.NextRefresh.IsZero() {
+		ctx.NextIn = untilRefresh(view.NextRefresh, now)
+	}
+	if view.HideTitle {
+		ctx.Host = host
+	}
+	return ctx
+}
+
+// renderFooter executes a footer template, tmpl or else fallback, elided to
+// width (0 for no limit). Newlines are flattened to keep it to one line.
+func renderFooter(tmpl, fallback *template.Template, ctx FooterContext, width int) string {
+	if tmpl == nil {
+		tmpl = fallback
+	}
+	var sb strings.Builder
+	var text string
+	if err := tmpl.Execute(&sb, ctx); err != nil {
+		text = fmt.Sprintf("footer: %v", err)
+	} else {
+		text = strings.TrimSpace(strings.ReplaceAll(sb.String(), "\n", " "))
+	}
+	if width > 0 {
+		text = truncateWidth(text, width)
+	}
+	return text
+}
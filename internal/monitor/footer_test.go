@@ -0,0 +1,100 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func footerSnapshot() Snapshot {
+	return Snapshot{
+		Hostname:     "box1",
+		Time:         renderTime,
+		Temperatures: []TemperatureSensor{{Name: "CPU", Value: 85, High: 80, Critical: 100, Path: "thermal_zone0"}},
+		Counts:       StateCounts{OK: 3, Warning: 2, Critical: 1},
+	}
+}
+
+// footerLine returns the last line of a view, unstyled
+func footerLine(out string) string {
+	lines := strings.Split(ansi.Strip(out), "\n")
+	return lines[len(lines)-1]
+}
+
+func TestDefaultFooters(t *testing.T) {
+	snap := footerSnapshot()
+	view := ViewState{Now: renderTime, NextRefresh: renderTime.Add(3 * time.Second), HideTitle: true}
+	if got, want := footerLine(RenderFull(snap, 120, 24, DefaultTheme, view)), "box1 | Last updated: 12:30:00 | next in 3s | Press 'q' to quit"; got != want {
+		t.Errorf("full footer %q, want %q", got, want)
+	}
+	view.Paused = true
+	if got, want := footerLine(RenderFull(snap, 120, 24, DefaultTheme, view)), "box1 | Last updated: 12:30:00 | paused | Press 'q' to quit"; got != want {
+		t.Errorf("paused footer %q, want %q", got, want)
+	}
+	if got, want := footerLine(RenderCompact(snap, 80, DefaultTheme, view)), "Updated: 12:30:00"; got != want {
+		t.Errorf("compact footer %q, want %q", got, want)
+	}
+}
+
+func TestCustomFooter(t *testing.T) {
+	full, err := ParseFooter(`{{.LastUpdate.Format "15:04"}} every {{.Interval}} in {{.Unit}}
+{{- if .CriticalCount}} | {{.CriticalCount}} critical, {{.WarningCount}} warning{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact, err := ParseFooter(`{{if .Paused}}paused{{else}}next in {{.NextIn}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMonitor(WithFooter(full, compact))
+	view := m.viewState(renderTime)
+	view.Interval = 2 * time.Second
+	view.NextRefresh = renderTime.Add(time.Second)
+	snap := footerSnapshot()
+
+	if got, want := footerLine(RenderFull(snap, 120, 24, DefaultTheme, view)), "12:30 every 2s in celsius | 1 critical, 2 warning"; got != want {
+		t.Errorf("full footer %q, want %q", got, want)
+	}
+	if got, want := footerLine(RenderCompact(snap, 80, DefaultTheme, view)), "next in 1s"; got != want {
+		t.Errorf("compact footer %q, want %q", got, want)
+	}
+}
+
+func TestFooterElidedToWidth(t *testing.T) {
+	tmpl, err := ParseFooter("first line\nsecond line | {{.Profile}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap := footerSnapshot()
+	snap.Profile = "quiet"
+	view := ViewState{Footer: tmpl}
+	if got, want := footerLine(RenderFull(snap, 120, 24, DefaultTheme, view)), "first line second line | quiet"; got != want {
+		t.Errorf("footer %q, want one line %q", got, want)
+	}
+	if got, want := footerLine(RenderFull(snap, 16, 24, DefaultTheme, view)), "first line seco…"; got != want {
+		t.Errorf("footer %q, want %q", got, want)
+	}
+}
+
+func TestParseFooterRejectsUnknownFields(t *testing.T) {
+	if _, err := ParseFooter("{{.Uptime}}"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+	if _, err := ParseFooter("{{if .Paused}}"); err == nil {
+		t.Error("expected an error for an unclosed action")
+	}
+}
+
+func TestLoadConfigRejectsBadFooter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"compact_footer": "{{.Battery}}"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil || !strings.HasPrefix(err.Error(), "compact_footer: ") {
+		t.Errorf("expected a compact_footer error, got %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TempUnit selects how temperatures are displayed. Readings and thresholds
+// are always stored in Celsius.
+type TempUnit int
+
+const (
+	Celsius TempUnit = iota
+	Fahrenheit
+	Kelvin
+	// Raw shows Celsius along with the integer each reading's sysfs file
+	// holds, for comparing with the kernel's view
+	Raw
+)
+
+var tempUnitNames = []string{"celsius", "fahrenheit", "kelvin", "raw"}
+
+func (u TempUnit) String() string {
+	return tempUnitNames[u]
+}
+
+// parseTempUnit is the inverse of String; unknown names yield Celsius
+func parseTempUnit(s string) TempUnit {
+	for i, name := range tempUnitNames {
+		if s == name {
+			return TempUnit(i)
+		}
+	}
+	return Celsius
+}
+
+func (u TempUnit) symbol() string {
+	switch u {
+	case Fahrenheit:
+		return "°F"
+	case Kelvin:
+		return "K"
+	}
+	return "°C"
+}
+
+func (u TempUnit) convert(celsius float64) float64 {
+	switch u {
+	case Fahrenheit:
+		return celsius*9/5 + 32
+	case Kelvin:
+		return celsius + 273.15
+	}
+	return celsius
+}
+
+// celsius is the inverse of convert, for temperatures typed in the unit
+func (u TempUnit) celsius(v float64) float64 {
+	switch u {
+	case Fahrenheit:
+		return (v - 32) * 5 / 9
+	case Kelvin:
+		return v - 273.15
+	}
+	return v
+}
+
+// formatTempDelta renders a difference of Celsius temperatures in the unit,
+// without decimals
+func formatTempDelta(celsius float64, unit TempUnit) string {
+	if unit == Fahrenheit {
+		celsius = celsius * 9 / 5
+	}
+	return fmt.Sprintf("%.0f%s", celsius, unit.symbol())
+}
+
+// formatTemp renders a Celsius value in the unit with one decimal, padding
+// the number to width (0 for no padding).
+func formatTemp(celsius float64, unit TempUnit, width int) string {
+	return fmt.Sprintf("%*.1f%s", width, unit.convert(celsius), unit.symbol())
+}
+
+// ByteUnits selects the prefixes used for sizes and rates: binary (KiB, MiB,
+// 1024-based) or decimal (kB, MB, 1000-based).
+type ByteUnits int
+
+const (
+	IECBytes ByteUnits = iota
+	SIBytes
+)
+
+var byteUnitsNames = []string{"iec", "si"}
+
+// networkRatesNames are the values of the network_rates setting
+var networkRatesNames = []string{"bytes", "bits"}
+
+func (u ByteUnits) String() string {
+	return byteUnitsNames[u]
+}
+
+func (u ByteUnits) base() float64 {
+	if u == SIBytes {
+		return 1000
+	}
+	return 1024
+}
+
+func (u ByteUnits) prefixes() []string {
+	if u == SIBytes {
+		return []string{"", "k", "M", "G", "T", "P"}
+	}
+	return []string{"", "Ki", "Mi", "Gi", "Ti", "Pi"}
+}
+
+// scale reduces n to below the unit base, returning the scaled value and its
+// prefix. Values that would round up to the base (999.96 kB) move up a prefix.
+func (u ByteUnits) scale(n float64) (float64, string) {
+	prefixes := u.prefixes()
+	i := 0
+	for i < len(prefixes)-1 && n >= u.base()-0.05 {
+		n /= u.base()
+		i++
+	}
+	return n, prefixes[i]
+}
+
+// formatBytes renders a size: whole bytes below the unit base, otherwise one
+// decimal with a prefix, e.g. "1023 B", "1.0 KiB", "1.5 MB"
+func formatBytes(n float64, units ByteUnits) string {
+	if n < units.base() {
+		return fmt.Sprintf("%.0f B", n)
+	}
+	value, prefix := units.scale(n)
+	return fmt.Sprintf("%.1f %sB", value, prefix)
+}
+
+// formatRate renders a rate given in bytes per second, in bytes ("1.5 MB/s")
+// or, with bits set, in bits ("12.0 Mbit/s"), using the same prefixes as sizes
+func formatRate(bytesPerSec float64, units ByteUnits, bits bool) string {
+	if !bits {
+		return formatBytes(bytesPerSec, units) + "/s"
+	}
+	n := bytesPerSec * 8
+	if n < units.base() {
+		return fmt.Sprintf("%.0f bit/s", n)
+	}
+	value, prefix := units.scale(n)
+	return fmt.Sprintf("%.1f %sbit/s", value, prefix)
+}
+
+// padRight pads s with spaces to width terminal cells. Widths are measured
+// like lipgloss does, so wide characters (CJK, most emoji) count as two
+// cells and styling escapes as none; strings already wider are unchanged.
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-lipgloss.Width(s)))
+}
+
+// truncateWidth shortens s to at most width cells, ending with "…" when cut.
+// Wide characters are never split, and trailing spaces before the ellipsis
+// are dropped.
+func truncateWidth(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width < 1 {
+		return ""
+	}
+	return strings.TrimRight(ansi.Truncate(s, width-1, ""), " ") + "…"
+}
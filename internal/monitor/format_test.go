@@ -0,0 +1,160 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n          float64
+		iec, si    string
+		iecBitRate string
+		siBitRate  string
+		iecRate    string
+	}{
+		{999, "999 B", "999 B", "7.8 Kibit/s", "8.0 kbit/s", "999 B/s"},
+		{1000, "1000 B", "1.0 kB", "7.8 Kibit/s", "8.0 kbit/s", "1000 B/s"},
+		{1023, "1023 B", "1.0 kB", "8.0 Kibit/s", "8.2 kbit/s", "1023 B/s"},
+		{1024, "1.0 KiB", "1.0 kB", "8.0 Kibit/s", "8.2 kbit/s", "1.0 KiB/s"},
+		{1_500_000, "1.4 MiB", "1.5 MB", "11.4 Mibit/s", "12.0 Mbit/s", "1.4 MiB/s"},
+		{999_999, "976.6 KiB", "1.0 MB", "7.6 Mibit/s", "8.0 Mbit/s", "976.6 KiB/s"},
+		{100, "100 B", "100 B", "800 bit/s", "800 bit/s", "100 B/s"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n, IECBytes); got != tt.iec {
+			t.Errorf("formatBytes(%v, IEC) = %q, want %q", tt.n, got, tt.iec)
+		}
+		if got := formatBytes(tt.n, SIBytes); got != tt.si {
+			t.Errorf("formatBytes(%v, SI) = %q, want %q", tt.n, got, tt.si)
+		}
+		if got := formatRate(tt.n, IECBytes, true); got != tt.iecBitRate {
+			t.Errorf("formatRate(%v, IEC, bits) = %q, want %q", tt.n, got, tt.iecBitRate)
+		}
+		if got := formatRate(tt.n, SIBytes, true); got != tt.siBitRate {
+			t.Errorf("formatRate(%v, SI, bits) = %q, want %q", tt.n, got, tt.siBitRate)
+		}
+		if got := formatRate(tt.n, IECBytes, false); got != tt.iecRate {
+			t.Errorf("formatRate(%v, IEC, bytes) = %q, want %q", tt.n, got, tt.iecRate)
+		}
+	}
+}
+
+// byteSensor is a ByteValued sensor with a fixed reading
+type byteSensor struct {
+	*GenericSensor
+	n    float64
+	rate bool
+}
+
+func (b byteSensor) Bytes() (float64, bool) {
+	return b.n, b.rate
+}
+
+func TestByteUnitsToggle(t *testing.T) {
+	m := NewMonitor(WithConfig("", Config{ByteUnits: "si", NetworkRates: "bits"}))
+	m.width, m.height = 80, 24
+	m.RegisterSensorGroup(SensorGroup{Name: "Network", Sensors: []Sensor{
+		byteSensor{NewGenericSensor("rx", nil), 1_500_000, true},
+		byteSensor{NewGenericSensor("used", nil), 1024, false},
+	}})
+	if got := m.sensorValue(m.extraGroups[0].Sensors[0]); got != "12.0 Mbit/s" {
+		t.Errorf("expected configured SI bit rate, got %q", got)
+	}
+
+	m = sendKeys(m, "b", "B")
+	view := m.View()
+	for _, want := range []string{"1.4 MiB/s", "1.0 KiB"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in view after toggling units:\n%s", want, view)
+		}
+	}
+	if got := m.Snapshot().Groups[0].Readings[0].Value; got != "1.4 MiB/s" {
+		t.Errorf("expected snapshot to use the same units, got %q", got)
+	}
+}
+
+func TestPadRightCountsCells(t *testing.T) {
+	for _, s := range []string{"fan1", "Señal Ñandú", "温度センサー", "🌀 风扇"} {
+		if w := lipgloss.Width(padRight(s, 20)); w != 20 {
+			t.Errorf("%q padded to %d cells, want 20", s, w)
+		}
+	}
+	if got := padRight("a very long sensor name", 8); got != "a very long sensor name" {
+		t.Errorf("expected wider strings unchanged, got %q", got)
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"Composite", 20, "Composite"},
+		{"nvme0n1 Composite", 9, "nvme0n1…"},
+		{"温度センサー", 7, "温度セ…"},
+		{"温度センサー", 6, "温度…"},
+		{"🔥🔥🔥", 4, "🔥…"},
+		{"abc", 0, ""},
+	}
+	for _, tt := range tests {
+		got := truncateWidth(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("truncateWidth(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if w := lipgloss.Width(got); w > tt.width {
+			t.Errorf("truncateWidth(%q, %d) is %d cells wide", tt.s, tt.width, w)
+		}
+	}
+}
+
+func TestTempUnits(t *testing.T) {
+	tests := []struct {
+		unit  TempUnit
+		want  string
+		delta string
+	}{
+		{Celsius, "45.0°C", "10°C"},
+		{Fahrenheit, "113.0°F", "18°F"},
+		{Kelvin, "318.1K", "10K"},
+		{Raw, "45.0°C", "10°C"},
+	}
+	for _, tt := range tests {
+		if got := formatTemp(45, tt.unit, 0); got != tt.want {
+			t.Errorf("formatTemp(45, %s) = %q, want %q", tt.unit, got, tt.want)
+		}
+		if got := formatTempDelta(10, tt.unit); got != tt.delta {
+			t.Errorf("formatTempDelta(10, %s) = %q, want %q", tt.unit, got, tt.delta)
+		}
+		if got := parseTempUnit(tt.unit.String()); got != tt.unit {
+			t.Errorf("parseTempUnit(%q) = %s", tt.unit.String(), got)
+		}
+	}
+}
+
+func TestLoadConfigRejectsUnknownByteUnits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"byte_units": "IEC"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), `unknown byte_units "IEC"`) {
+		t.Errorf("expected a byte_units error, got %v", err)
+	}
+}
+
+func TestLoadConfigRejectsUnknownNetworkRates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	for _, rates := range []string{"bit", "Bits"} {
+		if err := os.WriteFile(path, []byte(`{"network_rates": "`+rates+`"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil || err.Error() != `unknown network_rates "`+rates+`", want bytes or bits` {
+			t.Errorf("%s: expected a network_rates error, got %v", rates, err)
+		}
+	}
+}
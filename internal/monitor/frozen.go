@@ -0,0 +1,221 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// A failing chip, typically a Super I/O monitor, may go on returning the
+// same values forever, which looks healthy. A reading whose value stays
+// bit-identical for frozen_ticks refreshes of its own, while other
+// readings of its hwmon chip moved in the meantime, is marked "frozen?"
+// and recorded once in the alert history. A reading without a chip, alone
+// on it, or on a chip none of whose readings moved since, is judged
+// against the other readings of the system instead, which catches a chip
+// frozen whole. Readings that legitimately hold are exempt: slow kinds such
+// as percentages and text, rates of idle interfaces, and readings of 0, a
+// stopped fan or an unloaded rail.
+
+// DefaultFrozenTicks is how many refreshes a reading may hold the exact
+// same value before it is marked frozen
+const DefaultFrozenTicks = 300
+
+// steadyKinds are the kinds whose readings may hold legitimately
+var steadyKinds = map[Kind]bool{
+	KindPercentage: true,
+	KindRate:       true,
+	KindInfo:       true,
+}
+
+// FrozenChange is recorded in the alert history when a reading is first
+// marked frozen
+type FrozenChange struct {
+	// Since is when the reading last changed, Ticks the refreshes since
+	Since time.Time `json:"since"`
+	Ticks int       `json:"ticks"`
+}
+
+// frozenReading is a reading's value when it last changed
+type frozenReading struct {
+	// chip is the hwmon directory of the reading, empty when it has none
+	chip string
+	bits uint64
+	// read is when the reading was last read, so readings not refreshed
+	// with the others (throttled groups, stale temperatures) don't count
+	read  time.Time
+	since time.Time
+	// moved is the tick of the last change, ticks the refreshes since
+	moved, ticks int
+	frozen       bool
+}
+
+// frozenTicks returns the configured frozen_ticks, 0 when the check is off
+func (m Monitor) frozenTicks() int {
+	switch ticks := m.config.FrozenTicks; {
+	case ticks < 0:
+		return 0
+	case ticks > 0:
+		return ticks
+	}
+	return DefaultFrozenTicks
+}
+
+// chipAttribute is a group sensor reading an hwmon attribute, whose
+// directory is its chip
+type chipAttribute interface {
+	attribute() string
+}
+
+// readingChip returns the hwmon directory of a value file, empty for a
+// file outside hwmon such as a thermal zone's
+func readingChip(path string) string {
+	if !strings.Contains(path, "/hwmon/") {
+		return ""
+	}
+	return filepath.Dir(path)
+}
+
+// trackFrozen counts the refreshes each reading has held its value and
+// queues a history entry for the readings becoming frozen. Called by
+// Refresh; readings gone from the display are forgotten. Every reading is
+// updated before any is judged, so the movement of a refresh counts the
+// same whatever the order of the readings.
+func (m *Monitor) trackFrozen(now time.Time) {
+	limit := m.frozenTicks()
+	if limit == 0 {
+		m.frozenReadings, m.frozenChipMoved = nil, nil
+		return
+	}
+	m.frozenTick++
+	type tracked struct {
+		key   string
+		event Event
+	}
+	var order []tracked
+	readings := make(map[string]frozenReading, len(m.frozenReadings))
+	siblings := make(map[string]int)
+	track := func(key, chip string, value float64, read time.Time, event Event) {
+		r, ok := m.frozenReadings[key]
+		switch {
+		case ok && r.read.Equal(read):
+			// Not read again since
+		case !ok || math.Float64bits(value) != r.bits:
+			r = frozenReading{bits: math.Float64bits(value), since: now, moved: m.frozenTick}
+		default:
+			r.ticks++
+		}
+		r.chip, r.read = chip, read
+		readings[key] = r
+		siblings[chip]++
+		order = append(order, tracked{key, event})
+	}
+	for _, sensor := range m.temperatureSensors {
+		if sensor.Asleep || sensor.Stale || sensor.Value == 0 {
+			continue
+		}
+		track(temperatureStateKey(sensor.key()), readingChip(valueFilePath(sensor)), sensor.Value, m.temperaturesRead,
+			Event{Sensor: sensor.key(), To: sensor.State(), Value: sensor.Value})
+	}
+	for _, group := range m.extraGroups {
+		for _, sensor := range group.Sensors {
+			measured, ok := sensor.(Measured)
+			if !ok || steadyKinds[SensorKind(sensor)] {
+				continue
+			}
+			value, ok := measured.Measurement()
+			if !ok || value == 0 {
+				continue
+			}
+			var chip string
+			if a, ok := sensor.(chipAttribute); ok {
+				chip = readingChip(a.attribute())
+			}
+			track(groupStateKey(group.Name, sensor.Name()), chip, value, m.groupsRead[group.Name],
+				Event{Sensor: sensor.Name(), Group: group.Name, To: m.groupSensorState(group.Name, sensor), Value: m.sensorValue(sensor)})
+		}
+	}
+
+	// The chips, and the system, that moved this refresh
+	chipMoved := make(map[string]int, len(m.frozenChipMoved))
+	for chip, tick := range m.frozenChipMoved {
+		if siblings[chip] > 0 {
+			chipMoved[chip] = tick
+		}
+	}
+	for _, r := range readings {
+		if r.moved == m.frozenTick {
+			chipMoved[r.chip] = m.frozenTick
+			m.frozenMoved = m.frozenTick
+		}
+	}
+
+	var becoming []Event
+	for _, t := range order {
+		r := readings[t.key]
+		if r.frozen || r.ticks < limit {
+			continue
+		}
+		// Another reading of the chip moved since, or else, the chip
+		// holding whole, one of the system
+		moved := r.chip != "" && siblings[r.chip] > 1 && chipMoved[r.chip] > r.moved
+		if !moved && (r.chip == "" || chipMoved[r.chip] <= r.moved) {
+			moved = m.frozenMoved > r.moved
+		}
+		if !moved {
+			continue
+		}
+		r.frozen = true
+		readings[t.key] = r
+		event := t.event
+		event.Time, event.Host, event.Type, event.From = now, m.hostname, EventSensorFrozen, event.To
+		event.Frozen = &FrozenChange{Since: r.since, Ticks: r.ticks}
+		becoming = append(becoming, event)
+	}
+	m.frozenReadings, m.frozenChipMoved = readings, chipMoved
+	m.pending = append(m.pending, becoming...)
+	m.temperatureSensors = m.markFrozen(m.temperatureSensors)
+}
+
+// isFrozen reports whether the reading with the state key is marked frozen
+func (m Monitor) isFrozen(key string) bool {
+	return m.frozenReadings[key].frozen
+}
+
+// markFrozen marks the temperatures whose readings are frozen
+func (m Monitor) markFrozen(sensors []TemperatureSensor) []TemperatureSensor {
+	if len(m.frozenReadings) == 0 {
+		return sensors
+	}
+	result := append([]TemperatureSensor(nil), sensors...)
+	for i := range result {
+		result[i].Frozen = m.isFrozen(temperatureStateKey(result[i].key()))
+	}
+	return result
+}
+
+// frozenTag is the marker of a frozen reading
+func frozenTag(theme Theme, frozen bool) string {
+	if !frozen {
+		return ""
+	}
+	return " " + theme.stateStyle(StateWarning).Render("frozen?")
+}
+
+// describe tells how long the reading has held, e.g. "unchanged for
+// 300 refreshes since 11:55"
+func (c FrozenChange) describe() string {
+	return fmt.Sprintf("unchanged for %d refreshes since %s", c.Ticks, c.Since.Format("15:04"))
+}
+
+// frozenChangeLine is the alert history line of a reading becoming frozen
+func (m Monitor) frozenChangeLine(event Event) string {
+	name := event.Sensor
+	if event.Group != "" {
+		name = event.Group + "/" + name
+	}
+	return fmt.Sprintf("  %s %s %s %s, %s\n", event.Time.Format("15:04:05"), padRight(name, 24),
+		m.theme.stateStyle(StateWarning).Render("frozen?"), m.eventValue(event), event.Frozen.describe())
+}
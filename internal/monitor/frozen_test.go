@@ -0,0 +1,176 @@
+package monitor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// frozenMonitor returns a monitor checking for frozen readings every 3
+// refreshes, with a group of sensors, and a refresh of the group returning
+// how many refreshes were made
+func frozenMonitor(sensors ...Sensor) (Monitor, func(m *Monitor) int) {
+	m := NewMonitor()
+	m.config.FrozenTicks = 3
+	m.extraGroups = []SensorGroup{{Name: "Board", Sensors: sensors}}
+	now, refreshes := renderTime, 0
+	return m, func(m *Monitor) int {
+		now = now.Add(time.Second)
+		refreshes++
+		for _, s := range m.extraGroups[0].Sensors {
+			s.Refresh()
+		}
+		m.markGroupRead("Board", now)
+		m.trackFrozen(now)
+		return refreshes
+	}
+}
+
+// frozenNames returns the readings of the monitor marked frozen
+func frozenNames(m Monitor) []string {
+	var names []string
+	for _, reading := range m.Snapshot().Groups[0].Readings {
+		if reading.Frozen {
+			names = append(names, reading.Name)
+		}
+	}
+	return names
+}
+
+func TestFrozenReadings(t *testing.T) {
+	values := map[string]string{"Vcore": "1.10V", "fan1": "1200 RPM", "fan2": "0 RPM", "Brightness": "40%"}
+	sensor := func(name string, kind Kind) Sensor {
+		return NewGenericSensor(name, func() (string, bool, bool, error) {
+			return values[name], false, false, nil
+		}).SetKind(kind)
+	}
+	m, refresh := frozenMonitor(
+		sensor("Vcore", KindVoltage),
+		sensor("fan1", KindFan),
+		sensor("fan2", KindFan),
+		sensor("Brightness", KindPercentage),
+	)
+
+	// Nothing else moving: a steady system isn't a frozen one
+	var refreshes int
+	for range 5 {
+		refreshes = refresh(&m)
+	}
+	if got := frozenNames(m); len(got) != 0 || len(m.pending) != 0 {
+		t.Fatalf("expected no frozen reading while nothing moves, got %q", got)
+	}
+
+	// fan1 moves, Vcore doesn't: Vcore is frozen on that very refresh.
+	// The stopped fan and the percentage are exempt.
+	values["fan1"] = "1300 RPM"
+	refreshes = refresh(&m)
+	if got := frozenNames(m); len(got) != 1 || got[0] != "Vcore" {
+		t.Fatalf("expected Vcore frozen, got %q", got)
+	}
+	if len(m.pending) != 1 || m.pending[0].Frozen == nil || m.pending[0].Group != "Board" || m.pending[0].Type != EventSensorFrozen {
+		t.Fatalf("expected one frozen event for Vcore, got %+v", m.pending)
+	}
+	// Held since its first read
+	event := m.pending[0]
+	if event.Frozen.Ticks != refreshes-1 || !event.Frozen.Since.Equal(renderTime.Add(time.Second)) {
+		t.Errorf("expected Vcore held for %d refreshes since its first read, got %+v", refreshes-1, event.Frozen)
+	}
+	want := fmt.Sprintf("1.10V, unchanged for %d refreshes since 12:30", event.Frozen.Ticks)
+	if line := m.frozenChangeLine(event); !strings.Contains(line, "Board/Vcore") || !strings.Contains(line, want) {
+		t.Errorf("unexpected history line %q, want %q", line, want)
+	}
+
+	// Recorded once, cleared by a change
+	refresh(&m)
+	if len(m.pending) != 1 {
+		t.Errorf("expected the frozen event recorded once, got %d", len(m.pending))
+	}
+	values["Vcore"] = "1.11V"
+	refresh(&m)
+	if got := frozenNames(m); len(got) != 0 {
+		t.Errorf("expected Vcore thawed by a change, got %q", got)
+	}
+
+	// Readings of a group not read again don't count
+	before := m.frozenReadings[groupStateKey("Board", "fan1")].ticks
+	m.trackFrozen(renderTime.Add(time.Hour))
+	if after := m.frozenReadings[groupStateKey("Board", "fan1")].ticks; after != before {
+		t.Errorf("expected no tick without a read, got %d after %d", after, before)
+	}
+
+	m.config.FrozenTicks = -1
+	refresh(&m)
+	if m.frozenReadings != nil {
+		t.Error("expected a negative frozen_ticks to turn the check off")
+	}
+}
+
+// TestFrozenReadingsIgnoreOrder checks that a reading is judged against
+// the movement of the whole refresh, whether the moving reading comes
+// before or after it
+func TestFrozenReadingsIgnoreOrder(t *testing.T) {
+	for _, order := range [][]string{{"Vcore", "fan1"}, {"fan1", "Vcore"}} {
+		values := map[string]string{"Vcore": "1.10V", "fan1": "1200 RPM"}
+		var sensors []Sensor
+		for _, name := range order {
+			kind := KindVoltage
+			if name == "fan1" {
+				kind = KindFan
+			}
+			sensors = append(sensors, NewGenericSensor(name, func() (string, bool, bool, error) {
+				return values[name], false, false, nil
+			}).SetKind(kind))
+		}
+		m, refresh := frozenMonitor(sensors...)
+		for range 4 {
+			refresh(&m)
+		}
+		values["fan1"] = "1300 RPM"
+		refresh(&m)
+		if got := frozenNames(m); len(got) != 1 || got[0] != "Vcore" {
+			t.Errorf("order %q: expected Vcore frozen on the refresh fan1 moved, got %q", order, got)
+		}
+	}
+}
+
+// TestFrozenReadingsByChip checks that readings are judged against their
+// own hwmon chip, and a chip frozen whole against the system
+func TestFrozenReadingsByChip(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon1/in0_input": "1100",
+		"class/hwmon/hwmon1/in1_input": "3300",
+		"class/hwmon/hwmon2/in0_input": "1800",
+		"class/hwmon/hwmon2/in1_input": "5000",
+	})
+	rail := func(path string) Sensor {
+		return &VoltageSensor{path: filepath.Join(root, path), name: path}
+	}
+	m, refresh := frozenMonitor(
+		rail("class/hwmon/hwmon1/in0_input"),
+		rail("class/hwmon/hwmon1/in1_input"),
+		rail("class/hwmon/hwmon2/in0_input"),
+		rail("class/hwmon/hwmon2/in1_input"),
+	)
+	for range 4 {
+		refresh(&m)
+	}
+	if got := frozenNames(m); len(got) != 0 {
+		t.Fatalf("expected nothing frozen while nothing moves, got %q", got)
+	}
+
+	// hwmon1/in0 moves: its sibling is frozen, and hwmon2, holding whole,
+	// is frozen against the system
+	writeSysfs(t, root, map[string]string{"class/hwmon/hwmon1/in0_input": "1150"})
+	refresh(&m)
+	got := frozenNames(m)
+	want := []string{"class/hwmon/hwmon1/in1_input", "class/hwmon/hwmon2/in0_input", "class/hwmon/hwmon2/in1_input"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %q frozen, got %q", want, got)
+	}
+	if chip := m.frozenReadings[groupStateKey("Board", "class/hwmon/hwmon1/in1_input")].chip; chip != filepath.Join(root, "class/hwmon/hwmon1") {
+		t.Errorf("expected the reading's chip to be its hwmon directory, got %q", chip)
+	}
+}
@@ -0,0 +1,132 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// groupBackoffCap bounds the delay between retries of a failing group
+const groupBackoffCap = 2 * time.Minute
+
+// groupRefresh tracks a sensor group whose every sensor failed to refresh
+// on consecutive attempts. Groups refreshing normally have no entry.
+type groupRefresh struct {
+	failures int
+	retryAt  time.Time
+	err      error
+}
+
+// GroupRefreshState describes the refresh backoff of a sensor group
+type GroupRefreshState struct {
+	Failures int       // consecutive failed refreshes, 0 when healthy
+	RetryAt  time.Time // next attempt while failing
+	Err      string    // last error while failing
+}
+
+// refreshGroups refreshes the extra groups that aren't backing off. A group
+// fails when all its sensors fail; its retries then back off exponentially
+// from twice the refresh interval up to groupBackoffCap, and the first
+// successful refresh clears the failure.
+func (m *Monitor) refreshGroups(now time.Time) {
+	for _, group := range m.extraGroups {
+		state := m.groupRefresh[group.Name]
+		if state != nil && now.Before(state.retryAt) {
+			continue
+		}
+		err := m.refreshGroup(group, now)
+		if err == nil {
+			delete(m.groupRefresh, group.Name)
+			m.markGroupRead(group.Name, now)
+			continue
+		}
+		if state == nil {
+			if m.groupRefresh == nil {
+				m.groupRefresh = make(map[string]*groupRefresh)
+			}
+			state = &groupRefresh{}
+			m.groupRefresh[group.Name] = state
+		}
+		state.failures++
+		state.err = err
+		state.retryAt = now.Add(m.groupBackoff(state.failures))
+	}
+}
+
+// refreshGroup refreshes every sensor of a group, returning an error only if
+// all of them failed. Individual failures leave the previous reading shown
+// and are recorded for the sensor's error marker.
+func (m *Monitor) refreshGroup(group SensorGroup, now time.Time) error {
+	var errs []error
+	for _, sensor := range group.Sensors {
+		if clocked, ok := sensor.(clockedSensor); ok {
+			clocked.useClock(m.clock)
+		}
+		err := sensor.Refresh()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		m.recordSensorRefresh(group.Name, sensor, err, now)
+	}
+	if len(errs) == 0 || len(errs) < len(group.Sensors) {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+func (m Monitor) groupBackoff(failures int) time.Duration {
+	delay := m.interval
+	for i := 0; i < failures && delay < groupBackoffCap; i++ {
+		delay *= 2
+	}
+	return min(delay, groupBackoffCap)
+}
+
+// GroupRefreshState returns the refresh backoff of the named group
+func (m Monitor) GroupRefreshState(name string) GroupRefreshState {
+	state := m.groupRefresh[name]
+	if state == nil {
+		return GroupRefreshState{}
+	}
+	return GroupRefreshState{Failures: state.failures, RetryAt: state.retryAt, Err: state.err.Error()}
+}
+
+// clockedSensor is implemented by sensors timing their refreshes, which the
+// monitor gives its clock before refreshing them
+type clockedSensor interface {
+	useClock(c Clock)
+}
+
+// sensorRefresh is the outcome of a sensor's last refresh
+type sensorRefresh struct {
+	err         error
+	lastSuccess time.Time
+}
+
+// recordSensorRefresh remembers how a sensor's refresh went, preferring what
+// an ErrorReporter says about itself
+func (m *Monitor) recordSensorRefresh(group string, sensor Sensor, err error, now time.Time) {
+	state := sensorRefresh{err: err}
+	if prev, ok := m.sensorRefresh[group+"/"+sensor.Name()]; ok {
+		state.lastSuccess = prev.lastSuccess
+	}
+	if err == nil {
+		state.lastSuccess = now
+	}
+	if reporter, ok := sensor.(ErrorReporter); ok {
+		state.err, state.lastSuccess = reporter.LastError(), reporter.LastSuccess()
+	}
+	if m.sensorRefresh == nil {
+		m.sensorRefresh = make(map[string]sensorRefresh)
+	}
+	m.sensorRefresh[group+"/"+sensor.Name()] = state
+}
+
+// groupBadge returns the header badge of a failing group, or "" if healthy
+func groupBadge(state GroupRefreshState, now time.Time) string {
+	if state.Failures == 0 {
+		return ""
+	}
+	wait := max(state.RetryAt.Sub(now), 0).Round(time.Second)
+	return fmt.Sprintf("⚠ read failing, retrying in %s", wait)
+}
@@ -0,0 +1,138 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroupRefreshBackoff(t *testing.T) {
+	calls := 0
+	failing := true
+	sensor := NewGenericSensor("psi", func() (string, bool, bool, error) {
+		calls++
+		if failing {
+			return "", false, false, errors.New("read /proc/pressure/cpu: no such file")
+		}
+		return "0.5%", false, false, nil
+	})
+	m := NewMonitor(WithInterval(2 * time.Second))
+	m.width, m.height = 80, 24
+	m.RegisterSensorGroup(SensorGroup{Name: "Pressure", Sensors: []Sensor{sensor}})
+
+	now := time.Now()
+	m.refreshGroups(now)
+	state := m.GroupRefreshState("Pressure")
+	if state.Failures != 1 || !state.RetryAt.Equal(now.Add(4*time.Second)) {
+		t.Fatalf("expected 1 failure retrying in 4s, got %+v", state)
+	}
+	if !strings.Contains(m.View(), "Pressure ⚠ read failing, retrying in") {
+		t.Errorf("expected failure badge on the group header:\n%s", m.View())
+	}
+
+	// No reads while backing off
+	m.refreshGroups(now.Add(2 * time.Second))
+	if calls != 1 {
+		t.Errorf("expected no refresh during backoff, got %d calls", calls)
+	}
+
+	// Backoff doubles, up to the cap
+	m.refreshGroups(now.Add(4 * time.Second))
+	if state := m.GroupRefreshState("Pressure"); state.Failures != 2 || !state.RetryAt.Equal(now.Add(12*time.Second)) {
+		t.Errorf("expected second failure retrying after 8s, got %+v", state)
+	}
+	if got := m.groupBackoff(20); got != groupBackoffCap {
+		t.Errorf("expected backoff capped at %s, got %s", groupBackoffCap, got)
+	}
+
+	// The first success resets the backoff
+	failing = false
+	m.refreshGroups(now.Add(12 * time.Second))
+	if state := m.GroupRefreshState("Pressure"); state.Failures != 0 {
+		t.Errorf("expected backoff reset after success, got %+v", state)
+	}
+	if strings.Contains(m.View(), "read failing") {
+		t.Error("expected badge to disappear after success")
+	}
+}
+
+func TestGroupRefreshPartialFailure(t *testing.T) {
+	m := NewMonitor()
+	m.RegisterSensorGroup(SensorGroup{Name: "Mixed", Sensors: []Sensor{
+		NewGenericSensor("ok", func() (string, bool, bool, error) { return "1", false, false, nil }),
+		NewGenericSensor("bad", func() (string, bool, bool, error) { return "", false, false, errors.New("gone") }),
+	}})
+	m.refreshGroups(time.Now())
+	if state := m.GroupRefreshState("Mixed"); state.Failures != 0 {
+		t.Errorf("expected a group with a working sensor to count as healthy, got %+v", state)
+	}
+}
+
+func TestSensorRefreshErrors(t *testing.T) {
+	calls := 0
+	flaky := NewGenericSensor("link", func() (string, bool, bool, error) {
+		calls++
+		if calls%2 == 0 {
+			return "", false, false, errors.New("carrier lost")
+		}
+		return fmt.Sprintf("up %d", calls), false, false, nil
+	})
+	steady := NewGenericSensor("mode", func() (string, bool, bool, error) {
+		return "auto", false, false, nil
+	})
+	m := NewMonitor()
+	m.width, m.height = 80, 24
+	m.RegisterSensorGroup(SensorGroup{Name: "Link", Sensors: []Sensor{flaky, steady}})
+
+	now := time.Now()
+	m.refreshGroups(now)
+	if flaky.LastError() != nil || flaky.LastSuccess().IsZero() {
+		t.Fatalf("expected a successful first refresh, got %v", flaky.LastError())
+	}
+	firstSuccess := flaky.LastSuccess()
+	if r := m.Snapshot().Groups[0].Readings[0]; r.Err != "" || r.Value != "up 1" {
+		t.Errorf("expected no error marker after a success, got %+v", r)
+	}
+
+	m.refreshGroups(now.Add(2 * time.Second))
+	if err := flaky.LastError(); err == nil || err.Error() != "carrier lost" {
+		t.Fatalf("expected the failure to be kept, got %v", err)
+	}
+	if !flaky.LastSuccess().Equal(firstSuccess) {
+		t.Errorf("expected the last success to stay at the first refresh")
+	}
+	r := m.Snapshot().Groups[0].Readings[0]
+	if r.Err != "carrier lost" || r.Value != "up 1" || !r.LastSuccess.Equal(firstSuccess) {
+		t.Errorf("expected the previous value with the error, got %+v", r)
+	}
+	if !strings.Contains(m.View(), "up 1 !") {
+		t.Errorf("expected an error marker next to the sensor:\n%s", m.View())
+	}
+	if m.GroupRefreshState("Link").Failures != 0 {
+		t.Errorf("expected the group to stay healthy while one sensor works")
+	}
+	m = sendKeys(m, "down", "enter")
+	if view := m.View(); !strings.Contains(view, "Failed:   carrier lost") || !strings.Contains(view, "Last OK:  "+firstSuccess.Format("15:04:05")) {
+		t.Errorf("expected the error in the detail view:\n%s", view)
+	}
+
+	m.refreshGroups(now.Add(4 * time.Second))
+	if r := m.Snapshot().Groups[0].Readings[0]; r.Err != "" || r.Value != "up 3" {
+		t.Errorf("expected the marker cleared after recovering, got %+v", r)
+	}
+}
+
+func TestGenericSensorUsesMonitorClock(t *testing.T) {
+	m, clock := newClockedMonitor(time.Second)
+	sensor := NewGenericSensor("mode", func() (string, bool, bool, error) {
+		return "auto", false, false, nil
+	})
+	m.RegisterSensorGroup(SensorGroup{Name: "Link", Sensors: []Sensor{sensor}})
+
+	m.refreshGroups(clock.Now())
+	if got := sensor.LastSuccess(); !got.Equal(clock.Now()) {
+		t.Errorf("expected the success stamped at the monitor's %v, got %v", clock.Now(), got)
+	}
+}
@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WithHangupReload makes SIGHUP reload the config file and rescan the sensor
+// script directory, as the "R" and "r" keys do. Only the interactive program
+// should use it, since it keeps SIGHUP from terminating the process.
+func WithHangupReload() Option {
+	return func(m *Monitor) {
+		m.hangup = make(chan os.Signal, 1)
+		signal.Notify(m.hangup, syscall.SIGHUP)
+	}
+}
+
+// hangupMsg is sent when the process receives SIGHUP
+type hangupMsg struct{}
+
+func (m Monitor) watchHangup() tea.Cmd {
+	if m.hangup == nil {
+		return nil
+	}
+	ch := m.hangup
+	return func() tea.Msg {
+		if _, ok := <-ch; !ok {
+			return nil
+		}
+		return hangupMsg{}
+	}
+}
@@ -0,0 +1,47 @@
+package monitor
+
+const (
+	// HeadroomWarning and HeadroomCritical are the thermal headroom, in
+	// degrees Celsius, below which it is a warning or critical
+	HeadroomWarning  = 15.0
+	HeadroomCritical = 5.0
+)
+
+// Headroom is how far the temperature closest to its critical threshold is
+// from it: the margin left before shutdown territory
+type Headroom struct {
+	Degrees float64 // Critical - Value in Celsius, negative once over
+	Sensor  string  // the limiting sensor
+}
+
+// State returns the alert state of the headroom
+func (h Headroom) State() State {
+	switch {
+	case h.Degrees < HeadroomCritical:
+		return StateCritical
+	case h.Degrees < HeadroomWarning:
+		return StateWarning
+	}
+	return StateOK
+}
+
+// ThermalHeadroom returns the smallest Critical - Value across sensors,
+// muted and asleep ones aside. ok is false when there are no such
+// temperatures.
+func ThermalHeadroom(sensors []TemperatureSensor) (headroom Headroom, ok bool) {
+	for _, sensor := range sensors {
+		if sensor.Muted || sensor.Asleep {
+			continue
+		}
+		degrees := sensor.Critical - sensor.Value
+		if !ok || degrees < headroom.Degrees {
+			headroom, ok = Headroom{Degrees: degrees, Sensor: sensor.Name}, true
+		}
+	}
+	return headroom, ok
+}
+
+// Headroom returns the thermal headroom of the snapshot's temperatures
+func (s Snapshot) Headroom() (Headroom, bool) {
+	return ThermalHeadroom(s.Temperatures)
+}
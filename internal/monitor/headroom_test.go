@@ -0,0 +1,40 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestThermalHeadroom(t *testing.T) {
+	if _, ok := ThermalHeadroom(nil); ok {
+		t.Error("expected no headroom without temperatures")
+	}
+
+	sensors := []TemperatureSensor{
+		{Name: "Package id 0", Value: 70, High: 80, Critical: 100},
+		{Name: "Composite (nvme0n1)", Value: 76, High: 80, Critical: 85},
+		{Name: "acpitz", Value: 40, High: 80, Critical: 100},
+	}
+	h, ok := ThermalHeadroom(sensors)
+	if !ok || h.Degrees != 9 || h.Sensor != "Composite (nvme0n1)" || h.State() != StateWarning {
+		t.Fatalf("expected 9°C warning from the NVMe drive, got %+v (%v)", h, h.State())
+	}
+	for degrees, want := range map[float64]State{15: StateOK, 14.9: StateWarning, 5: StateWarning, 4.9: StateCritical, -3: StateCritical} {
+		if got := (Headroom{Degrees: degrees}).State(); got != want {
+			t.Errorf("headroom %v: expected %v, got %v", degrees, want, got)
+		}
+	}
+
+	snap := Snapshot{Temperatures: sensors}
+	full := ansi.Strip(RenderFull(snap, 100, 30, DefaultTheme, ViewState{}))
+	if !strings.Contains(full, "Headroom: 9°C (Composite (nvme0n1))") {
+		t.Errorf("expected the headroom under Temperatures, got:\n%s", full)
+	}
+	// A margin converts without the Fahrenheit offset
+	full = ansi.Strip(RenderFull(snap, 100, 30, DefaultTheme, ViewState{Unit: Fahrenheit}))
+	if !strings.Contains(full, "Headroom: 16°F") {
+		t.Errorf("expected the headroom in Fahrenheit, got:\n%s", full)
+	}
+}
@@ -0,0 +1,208 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HistoryRecord is one refresh in the history file: the temperatures and the
+// battery, one JSON object per line
+type HistoryRecord struct {
+	// SchemaVersion is the SchemaVersion the record was written with
+	SchemaVersion int                       `json:"schema_version"`
+	Time          time.Time                 `json:"time"`
+	Host          string                    `json:"host,omitempty"`
+	Temperatures  map[string]HistoryReading `json:"temperatures,omitempty"`
+	Battery       *HistoryBattery           `json:"battery,omitempty"`
+}
+
+// HistoryReading is a temperature in Celsius and its state
+type HistoryReading struct {
+	Value float64 `json:"value"`
+	State State   `json:"state"`
+}
+
+// HistoryBattery is the battery part of a HistoryRecord
+type HistoryBattery struct {
+	Capacity int     `json:"capacity"`
+	Status   string  `json:"status"`
+	ACOnline bool    `json:"ac_online"`
+	Power    float64 `json:"power,omitempty"`  // watts
+	Energy   float64 `json:"energy,omitempty"` // watt-hours, from energy_now
+}
+
+// status is the part of a BatteryStatus the record keeps
+func (b HistoryBattery) status() BatteryStatus {
+	return BatteryStatus{Capacity: b.Capacity, Status: b.Status, ACOnline: b.ACOnline, Power: b.Power, Energy: b.Energy}
+}
+
+// DefaultHistoryPath returns $XDG_STATE_HOME/sysfs-monitor-tui/history.jsonl,
+// next to the UI state file
+func DefaultHistoryPath() string {
+	path := DefaultUIStatePath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), "history.jsonl")
+}
+
+// DefaultHistoryMaxMB is the size, in MiB, at which the history file is
+// rotated when the config doesn't set history_max_mb
+const DefaultHistoryMaxMB = 64
+
+// rotatedHistorySuffix names the previous history file, e.g.
+// "history.jsonl.1"; only that one generation is kept
+const rotatedHistorySuffix = ".1"
+
+// WithHistoryFile appends a HistoryRecord to path on every refresh, for
+// `sysfs-check report`. The file is created with its directory if needed,
+// and rotated to path.1 once it reaches the config's history_max_mb.
+func WithHistoryFile(path string) Option {
+	return func(m *Monitor) {
+		m.historyFile = &historyFile{path: path}
+	}
+}
+
+// historyFile is the open history file, shared by copies of the Monitor
+type historyFile struct {
+	path string
+	f    *os.File
+	size int64
+}
+
+// write appends a record, first rotating the file when it has reached
+// limit bytes
+func (h *historyFile) write(record HistoryRecord, limit int64) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if h.f != nil && h.size >= limit {
+		if err := h.close(); err != nil {
+			return err
+		}
+		if err := os.Rename(h.path, h.path+rotatedHistorySuffix); err != nil {
+			return err
+		}
+	}
+	if h.f == nil {
+		if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			return err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		h.f, h.size = f, info.Size()
+	}
+	n, err := h.f.Write(append(data, '\n'))
+	h.size += int64(n)
+	return err
+}
+
+func (h *historyFile) close() error {
+	if h.f == nil {
+		return nil
+	}
+	err := h.f.Close()
+	h.f, h.size = nil, 0
+	return err
+}
+
+// historyMaxBytes is the size at which the history file rotates
+func (c Config) historyMaxBytes() int64 {
+	if c.HistoryMaxMB == 0 {
+		return DefaultHistoryMaxMB << 20
+	}
+	return int64(c.HistoryMaxMB) << 20
+}
+
+// recordHistory writes the readings of a refresh to the history file
+func (m *Monitor) recordHistory(now time.Time) {
+	if m.historyFile == nil {
+		return
+	}
+	record := HistoryRecord{SchemaVersion: SchemaVersion, Time: now, Host: m.hostname}
+	if len(m.temperatureSensors) > 0 {
+		record.Temperatures = make(map[string]HistoryReading, len(m.temperatureSensors))
+		for _, sensor := range m.temperatureSensors {
+			if !sensor.Asleep {
+				record.Temperatures[sensor.key()] = HistoryReading{Value: sensor.Value, State: sensor.State()}
+			}
+		}
+	}
+	if bat := m.batteryStatus; bat.Present() {
+		record.Battery = &HistoryBattery{Capacity: bat.Capacity, Status: bat.Status, ACOnline: bat.ACOnline, Power: bat.Power, Energy: bat.Energy}
+	}
+	if err := m.historyFile.write(record, m.config.historyMaxBytes()); err != nil {
+		m.status = fmt.Sprintf("History: %v", err)
+	}
+}
+
+// ReadHistory reads the records of a history file from since on, those of
+// the rotated path.1 first. Lines that don't parse, such as one cut short
+// by a crash, are skipped. Records of an older schema are migrated to
+// SchemaVersion; one of a newer schema fails the read with a SchemaError.
+func ReadHistory(path string, since time.Time) ([]HistoryRecord, error) {
+	var records []HistoryRecord
+	err := ScanHistory(path, since, func(record HistoryRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+// ScanHistory is ReadHistory calling fn with each record in turn instead of
+// collecting them, stopping at the first error fn returns. Only the time of
+// the records before since is decoded. A missing rotated file is no error;
+// a missing path is.
+func ScanHistory(path string, since time.Time, fn func(HistoryRecord) error) error {
+	err := scanHistoryFile(path+rotatedHistorySuffix, since, fn)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return scanHistoryFile(path, since, fn)
+}
+
+func scanHistoryFile(path string, since time.Time, fn func(HistoryRecord) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var stamp struct {
+			Time time.Time `json:"time"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &stamp); err != nil || stamp.Time.Before(since) {
+			continue
+		}
+		var record HistoryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		version, err := checkSchema("history file "+path, record.SchemaVersion)
+		if err != nil {
+			return err
+		}
+		migrateHistory(&record, version)
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
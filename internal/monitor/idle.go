@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultIdleInterval is the time between refreshes while the terminal
+// doesn't have focus, when the low-power mode is on
+const DefaultIdleInterval = 30 * time.Second
+
+// WithIdleInterval turns on the low-power mode: while the terminal reports
+// that it lost focus (tea.BlurMsg, with the program's tea.WithReportFocus)
+// the monitor refreshes every d instead of every interval, and stops
+// redrawing the footer countdown. Regaining focus refreshes right away and
+// restores the interval. Zero leaves the mode off, for terminals that don't
+// report focus or report it wrongly.
+func WithIdleInterval(d time.Duration) Option {
+	return func(m *Monitor) {
+		m.idleInterval = max(d, 0)
+	}
+}
+
+// refreshInterval is the time between refreshes, longer while idle
+func (m Monitor) refreshInterval() time.Duration {
+	if m.idle {
+		return max(m.interval, m.idleInterval)
+	}
+	return m.interval
+}
+
+// handleBlur slows the refreshes down; the tick due at the normal interval
+// is replaced by one due an idle interval after the last refresh
+func (m Monitor) handleBlur() (Monitor, tea.Cmd) {
+	if m.idleInterval == 0 || m.idle {
+		return m, nil
+	}
+	m.idle = true
+	m.nextRefresh = m.lastUpdate.Add(m.refreshInterval())
+	if m.paused || m.waiting() || m.preloading() {
+		return m, nil
+	}
+	return m, m.tick()
+}
+
+// handleFocus leaves the low-power mode, refreshing right away since the
+// readings may be up to an idle interval old, and restarts the countdown
+// if it stopped. The refresh still credits the time in state up to the
+// idle interval.
+func (m Monitor) handleFocus() (Monitor, tea.Cmd) {
+	if !m.idle {
+		return m, nil
+	}
+	var cmds []tea.Cmd
+	if m.countdownStopped {
+		m.countdownStopped = false
+		cmds = append(cmds, m.countdown())
+	}
+	if m.paused || m.waiting() || m.preloading() {
+		m.idle = false
+		m.nextRefresh = m.lastUpdate.Add(m.interval)
+		return m, tea.Batch(cmds...)
+	}
+	m = m.Refresh()
+	m.idle = false
+	m.nextRefresh = m.lastUpdate.Add(m.interval)
+	return m, tea.Batch(append(cmds, m.tick(), m.emitSnapshot())...)
+}
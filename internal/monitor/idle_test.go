@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestIdleSlowsRefreshesUntilFocus(t *testing.T) {
+	m, clock := newClockedMonitor(2 * time.Second)
+	WithIdleInterval(30 * time.Second)(&m)
+	start := clock.now
+	m.width, m.height = 80, 24
+
+	m, _ = m.Update(tea.BlurMsg{})
+	m = clock.advance(m, 65*time.Second)
+	if n := len(m.BatteryHistory()); n != 2 {
+		t.Fatalf("expected refreshes at 30s and 60s while idle, got %d", n)
+	}
+	if !m.nextRefresh.Equal(start.Add(90 * time.Second)) {
+		t.Errorf("expected the next refresh at 90s, got %v", m.nextRefresh.Sub(start))
+	}
+	if view := m.View(); !strings.Contains(view, "| idle") || strings.Contains(view, "next in") {
+		t.Errorf("expected the idle hint instead of the countdown:\n%s", view)
+	}
+	// Only the refresh tick wakes the idle monitor
+	if len(clock.timers) != 1 || !m.countdownStopped {
+		t.Errorf("expected the countdown redraws to stop, %d timers left", len(clock.timers))
+	}
+
+	m, _ = m.Update(tea.FocusMsg{})
+	if n := len(m.BatteryHistory()); n != 3 || !m.lastUpdate.Equal(start.Add(65*time.Second)) {
+		t.Fatalf("expected a refresh on focus, got %d at %v", n, m.lastUpdate.Sub(start))
+	}
+	m = clock.advance(m, 4*time.Second)
+	if n := len(m.BatteryHistory()); n != 5 {
+		t.Errorf("expected the normal interval back, got %d refreshes", n)
+	}
+	if view := m.View(); !strings.Contains(view, "next in") {
+		t.Errorf("expected the countdown back:\n%s", view)
+	}
+}
+
+func TestIdleDisabled(t *testing.T) {
+	m, clock := newClockedMonitor(2 * time.Second)
+	m, _ = m.Update(tea.BlurMsg{})
+	m = clock.advance(m, 10*time.Second)
+	if m.idle || len(m.BatteryHistory()) != 5 {
+		t.Errorf("expected focus changes ignored without an idle interval, got %d refreshes", len(m.BatteryHistory()))
+	}
+}
+
+func TestIdlePausedKeepsPaused(t *testing.T) {
+	m, clock := newClockedMonitor(2 * time.Second)
+	WithIdleInterval(30 * time.Second)(&m)
+	m = sendKeys(m, "p")
+	m, _ = m.Update(tea.BlurMsg{})
+	m, _ = m.Update(tea.FocusMsg{})
+	m = clock.advance(m, time.Minute)
+	if !m.paused || len(m.BatteryHistory()) != 0 {
+		t.Errorf("expected no refreshes while paused, got %d", len(m.BatteryHistory()))
+	}
+}
@@ -0,0 +1,352 @@
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// A single instance of the monitor holds the lock of the instance directory
+// (AcquireInstanceLock) and alone reads sysfs for the others, writes the
+// history and binds sockets. It streams its snapshots on a UNIX socket next
+// to the lock (InstanceServer); a second instance can show them
+// (DialInstance, WithInstanceClient) instead of reading sysfs itself.
+
+// ErrInstanceRunning is returned by AcquireInstanceLock when another
+// instance holds the lock
+var ErrInstanceRunning = errors.New("another sysfs-monitor-tui instance is running")
+
+const (
+	// instanceDialTimeout is how long DialInstance waits for the socket of
+	// an instance that took the lock but isn't listening yet
+	instanceDialTimeout = 2 * time.Second
+
+	// instanceWriteTimeout drops clients that stop reading
+	instanceWriteTimeout = 5 * time.Second
+
+	// instanceReadTimeout is how long ReadInstanceSnapshot waits for the
+	// first snapshot of an instance that hasn't refreshed yet
+	instanceReadTimeout = 250 * time.Millisecond
+
+	// instanceStaleIntervals is how many refresh intervals old the
+	// snapshot ReadInstanceSnapshot returns may be
+	instanceStaleIntervals = 3
+)
+
+// ErrInstanceStale is returned by ReadInstanceSnapshot when the running
+// instance's last refresh is more than three intervals old
+var ErrInstanceStale = errors.New("the running instance's readings are stale")
+
+// DefaultInstanceDir returns the directory of the lock and the socket:
+// sysfs-monitor-tui under XDG_RUNTIME_DIR, or a per-user directory in the
+// temporary directory without one
+func DefaultInstanceDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "sysfs-monitor-tui")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("sysfs-monitor-tui-%d", os.Getuid()))
+}
+
+func instanceLockPath(dir string) string {
+	return filepath.Join(dir, "instance.lock")
+}
+
+// InstanceSocketPath returns the socket the lock holder of dir serves
+func InstanceSocketPath(dir string) string {
+	return filepath.Join(dir, "instance.sock")
+}
+
+// InstanceLock is the held single-instance lock
+type InstanceLock struct {
+	dir  string
+	f    *os.File
+	once sync.Once
+}
+
+// SocketPath returns the socket the holder serves its snapshots on
+func (l *InstanceLock) SocketPath() string {
+	return InstanceSocketPath(l.dir)
+}
+
+// Release drops the lock. It may be called more than once, and on a nil
+// lock.
+func (l *InstanceLock) Release() {
+	if l == nil {
+		return
+	}
+	l.once.Do(func() { l.f.Close() })
+}
+
+// InstanceServer streams snapshots to the instances connected to its
+// socket, one JSON object per line: the latest on connecting, then one per
+// refresh. Publish never blocks; a client that doesn't keep up only gets
+// the latest snapshot, and one that stops reading is dropped.
+type InstanceServer struct {
+	latest func() (Snapshot, bool)
+	path   string
+
+	mu      sync.Mutex
+	ln      net.Listener
+	clients map[chan []byte]bool
+	closed  bool
+}
+
+// NewInstanceServer creates a server of the snapshots latest returns.
+// Listen starts it.
+func NewInstanceServer(latest func() (Snapshot, bool)) *InstanceServer {
+	return &InstanceServer{latest: latest, clients: make(map[chan []byte]bool)}
+}
+
+// Listen binds the socket at path and accepts clients in the background
+// until Close. A socket left behind by a crashed holder is replaced, which
+// is only safe while holding the lock.
+func (s *InstanceServer) Listen(path string) error {
+	os.Remove(path)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.ln, s.path = ln, path
+	s.mu.Unlock()
+	go s.accept(ln)
+	return nil
+}
+
+func (s *InstanceServer) accept(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		queue := make(chan []byte, 1)
+		if snap, ok := s.latest(); ok {
+			if data, err := json.Marshal(snap); err == nil {
+				queue <- append(data, '\n')
+			}
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.clients[queue] = true
+		s.mu.Unlock()
+		go s.send(conn, queue)
+	}
+}
+
+// send writes a client's queue until it fails or the server closes
+func (s *InstanceServer) send(conn net.Conn, queue chan []byte) {
+	defer conn.Close()
+	for data := range queue {
+		conn.SetWriteDeadline(time.Now().Add(instanceWriteTimeout))
+		if _, err := conn.Write(data); err != nil {
+			s.mu.Lock()
+			if s.clients[queue] {
+				delete(s.clients, queue)
+				close(queue)
+			}
+			s.mu.Unlock()
+			return
+		}
+	}
+}
+
+// Publish queues a refresh's snapshot for every client, replacing one not
+// sent yet
+func (s *InstanceServer) Publish(snap Snapshot) {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for queue := range s.clients {
+		select {
+		case <-queue:
+		default:
+		}
+		queue <- data
+	}
+}
+
+// Close stops serving, disconnects the clients and removes the socket
+func (s *InstanceServer) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	if s.ln != nil {
+		s.ln.Close()
+		os.Remove(s.path)
+	}
+	for queue := range s.clients {
+		delete(s.clients, queue)
+		close(queue)
+	}
+}
+
+// InstanceClient receives the snapshots of the running instance
+type InstanceClient struct {
+	conn net.Conn
+
+	mu     sync.Mutex
+	latest Snapshot
+	err    error
+}
+
+// DialInstance connects to the instance serving path, waiting briefly for
+// one that holds the lock but doesn't listen yet
+func DialInstance(path string) (*InstanceClient, error) {
+	deadline := time.Now().Add(instanceDialTimeout)
+	for {
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			c := &InstanceClient{conn: conn}
+			go c.receive()
+			return c, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (c *InstanceClient) receive() {
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var snap Snapshot
+		err := json.Unmarshal(scanner.Bytes(), &snap)
+		if err == nil {
+			_, err = checkSchema("the running instance", snap.SchemaVersion)
+		}
+		c.mu.Lock()
+		if err == nil {
+			c.latest = snap
+		}
+		c.err = err
+		c.mu.Unlock()
+	}
+	err := scanner.Err()
+	if err == nil {
+		err = errors.New("the instance exited")
+	}
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+}
+
+// Snapshot returns the last snapshot received, zero before the first, and
+// the error ending or garbling the stream
+func (c *InstanceClient) Snapshot() (Snapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.latest, c.err
+}
+
+// Close disconnects from the instance
+func (c *InstanceClient) Close() error {
+	return c.conn.Close()
+}
+
+// WithInstanceClient shows the readings of the running instance c is
+// connected to instead of reading sysfs: its temperatures as it arranged
+// them, its battery and its groups, refreshed on this monitor's interval.
+// Thresholds, offsets and profiles are the instance's; mutes and the views
+// are this monitor's.
+func WithInstanceClient(c *InstanceClient) Option {
+	return func(m *Monitor) {
+		m.instance = c
+	}
+}
+
+// updateInstance is updateSensors for an instance client: the readings of
+// the last snapshot received
+func (m Monitor) updateInstance() Monitor {
+	now := m.clock.Now()
+	snap, err := m.instance.Snapshot()
+	if err != nil {
+		m.status = "Running instance: " + err.Error()
+	}
+	if snap.Time.IsZero() {
+		return m
+	}
+	m.setBattery(snap.Battery, snap.BatteryTime)
+	m.temperatureSensors = snap.Temperatures
+	m.temperaturesRead = snap.TemperaturesTime
+	m.virtualization = snap.Virtualization
+	groups := make([]SensorGroup, len(snap.Groups))
+	for i, group := range snap.Groups {
+		groups[i].Name = group.Name
+		for _, reading := range group.Readings {
+			groups[i].Sensors = append(groups[i].Sensors, instanceSensor{reading})
+		}
+	}
+	m.extraGroups = groups
+	m.refreshGroups(now)
+	return m
+}
+
+// instanceSensor is a group reading of the running instance
+type instanceSensor struct {
+	reading SensorReading
+}
+
+func (s instanceSensor) Name() string   { return s.reading.Name }
+func (s instanceSensor) Value() string  { return s.reading.Value }
+func (s instanceSensor) Warning() bool  { return s.reading.State == StateWarning }
+func (s instanceSensor) Critical() bool { return s.reading.State == StateCritical }
+func (s instanceSensor) Refresh() error { return nil }
+func (s instanceSensor) Kind() Kind     { return s.reading.Kind }
+
+func (s instanceSensor) Measurement() (float64, bool) {
+	if s.reading.Measurement == nil {
+		return 0, false
+	}
+	return *s.reading.Measurement, true
+}
+
+// ReadInstanceSnapshot returns the latest snapshot of the instance serving
+// path without waiting for one that isn't listening, for shell prompts that
+// can't afford reading sysfs. A snapshot taken more than three of its
+// refresh intervals before now is returned with ErrInstanceStale.
+func ReadInstanceSnapshot(path string, now time.Time) (Snapshot, error) {
+	conn, err := net.DialTimeout("unix", path, instanceReadTimeout)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(instanceReadTimeout))
+	line, err := bufio.NewReaderSize(conn, 64<<10).ReadBytes('\n')
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(line, &snap); err != nil {
+		return Snapshot{}, err
+	}
+	if _, err := checkSchema("the running instance", snap.SchemaVersion); err != nil {
+		return Snapshot{}, err
+	}
+	interval := snap.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if age := now.Sub(snap.Time); age > instanceStaleIntervals*interval {
+		return snap, fmt.Errorf("%w: last refreshed %s ago", ErrInstanceStale, formatElapsed(age))
+	}
+	return snap, nil
+}
@@ -0,0 +1,42 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// AcquireInstanceLock takes the single-instance lock of dir (see
+// DefaultInstanceDir), creating the directory if needed. The lock is an
+// flock on dir/instance.lock, which also records the holder's pid: the
+// kernel drops it with the descriptor however the process ends, panics and
+// kills included, so a crashed instance never leaves it held. When another
+// process holds it, the error wraps ErrInstanceRunning and names its pid.
+func AcquireInstanceLock(dir string) (*InstanceLock, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	path := instanceLockPath(dir)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		data, _ := os.ReadFile(path)
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			if pid := strings.TrimSpace(string(data)); pid != "" {
+				return nil, fmt.Errorf("%w (pid %s)", ErrInstanceRunning, pid)
+			}
+			return nil, ErrInstanceRunning
+		}
+		return nil, fmt.Errorf("lock %s: %w", path, err)
+	}
+	if err := f.Truncate(0); err == nil {
+		f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &InstanceLock{dir: dir, f: f}, nil
+}
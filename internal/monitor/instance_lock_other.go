@@ -0,0 +1,9 @@
+//go:build !linux
+
+package monitor
+
+import "errors"
+
+func AcquireInstanceLock(dir string) (*InstanceLock, error) {
+	return nil, errors.New("the single-instance lock is only supported on linux")
+}
@@ -0,0 +1,116 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstanceLock(t *testing.T) {
+	dir := t.TempDir()
+	lock, err := AcquireInstanceLock(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = AcquireInstanceLock(dir)
+	if !errors.Is(err, ErrInstanceRunning) || !strings.Contains(err.Error(), fmt.Sprintf("(pid %d)", os.Getpid())) {
+		t.Errorf("expected the lock held by this process, got %v", err)
+	}
+
+	lock.Release()
+	lock.Release()
+	again, err := AcquireInstanceLock(dir)
+	if err != nil {
+		t.Fatalf("expected the released lock to be free, got %v", err)
+	}
+	again.Release()
+}
+
+// waitForSnapshot polls the client until it received a snapshot matching ok
+func waitForSnapshot(t *testing.T, c *InstanceClient, ok func(Snapshot, error) bool) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if ok(c.Snapshot()) {
+			return
+		}
+	}
+	snap, err := c.Snapshot()
+	t.Fatalf("timed out waiting for the instance, last %v %v", snap.Time, err)
+}
+
+func TestInstanceClientShowsServerReadings(t *testing.T) {
+	source, clock := newClockedMonitor(2 * time.Second)
+	source = source.Refresh()
+	latest := source.Snapshot()
+	server := NewInstanceServer(func() (Snapshot, bool) { return latest, true })
+	path := InstanceSocketPath(t.TempDir())
+	if err := server.Listen(path); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	client, err := DialInstance(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForSnapshot(t, client, func(snap Snapshot, _ error) bool { return snap.Time.Equal(latest.Time) })
+
+	m := NewMonitor(WithClock(&fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}), WithInstanceClient(client))
+	m = m.Refresh()
+	got := m.Snapshot()
+	if len(got.Temperatures) != len(latest.Temperatures) || got.Temperatures[0].Value != latest.Temperatures[0].Value {
+		t.Errorf("expected the instance's temperatures, got %+v", got.Temperatures)
+	}
+	if got.Battery.Capacity != latest.Battery.Capacity || len(got.Groups) != 1 || got.Groups[0].Readings[1].Value != latest.Groups[0].Readings[1].Value {
+		t.Errorf("expected the instance's battery and groups, got %+v %+v", got.Battery, got.Groups)
+	}
+
+	// Every refresh of the instance follows
+	clock.now = clock.now.Add(2 * time.Second)
+	source = source.Refresh()
+	server.Publish(source.Snapshot())
+	waitForSnapshot(t, client, func(snap Snapshot, _ error) bool { return snap.Time.After(latest.Time) })
+
+	// Losing it keeps the last readings, with the reason
+	server.Close()
+	waitForSnapshot(t, client, func(_ Snapshot, err error) bool { return err != nil })
+	if m = m.Refresh(); !strings.HasPrefix(m.status, "Running instance: ") || len(m.temperatureSensors) == 0 {
+		t.Errorf("expected the last readings and the error, got %q", m.status)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the socket removed, got %v", err)
+	}
+	m.Close()
+}
+
+func TestReadInstanceSnapshot(t *testing.T) {
+	source, clock := newClockedMonitor(2 * time.Second)
+	source = source.Refresh()
+	latest := source.Snapshot()
+	server := NewInstanceServer(func() (Snapshot, bool) { return latest, true })
+	path := InstanceSocketPath(t.TempDir())
+	if _, err := ReadInstanceSnapshot(path, clock.now); err == nil {
+		t.Error("expected an error without an instance")
+	}
+	if err := server.Listen(path); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	snap, err := ReadInstanceSnapshot(path, clock.now.Add(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !snap.Time.Equal(latest.Time) || snap.Interval != 2*time.Second || Summary(snap, 0, false) != Summary(latest, 0, false) {
+		t.Errorf("expected the instance's snapshot, got %v every %v", snap.Time, snap.Interval)
+	}
+
+	// Three intervals without a refresh: the instance may be hung
+	_, err = ReadInstanceSnapshot(path, clock.now.Add(7*time.Second))
+	if !errors.Is(err, ErrInstanceStale) || !strings.HasSuffix(err.Error(), "last refreshed 7s ago") {
+		t.Errorf("expected stale readings, got %v", err)
+	}
+}
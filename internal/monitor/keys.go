@@ -0,0 +1,195 @@
+package monitor
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// row identifies a selectable sensor: a temperature (group == -1) or the
+// index-th sensor of an extra group
+type row struct {
+	group, index int
+}
+
+// rows lists the selectable sensors in display order
+func (m Monitor) rows() []row {
+	var rows []row
+	_, hidden := chipSummaries(m.temperatureSensors, m.config.SummarizeChips)
+	for i := range m.temperatureSensors {
+		if !hidden[i] {
+			rows = append(rows, row{group: -1, index: i})
+		}
+	}
+	for g, group := range m.extraGroups {
+		if m.collapsed[group.Name] {
+			continue
+		}
+		for i, sensor := range group.Sensors {
+			if !m.isDenied(m.sensorRefresh[group.Name+"/"+sensor.Name()].err) {
+				rows = append(rows, row{group: g, index: i})
+			}
+		}
+	}
+	return rows
+}
+
+// selectedRow returns the row under the cursor, clamped to the current list
+func (m Monitor) selectedRow() (row, bool) {
+	rows := m.rows()
+	if !m.selecting || len(rows) == 0 {
+		return row{}, false
+	}
+	return rows[min(m.cursor, len(rows)-1)], true
+}
+
+func (m Monitor) isSelected(r row) bool {
+	selected, ok := m.selectedRow()
+	return ok && selected == r
+}
+
+// handleKey processes navigation, the detail view and threshold editing
+func (m Monitor) handleKey(msg tea.KeyMsg) (Monitor, tea.Cmd) {
+	if m.edit != nil {
+		return m.handleEditKey(msg), nil
+	}
+	m.status = ""
+	switch msg.String() {
+	case "up", "k":
+		if m.selecting && m.cursor > 0 {
+			m.cursor = min(m.cursor, len(m.rows())) - 1
+		}
+		m.selecting = true
+	case "down", "j":
+		if m.selecting && m.cursor < len(m.rows())-1 {
+			m.cursor++
+		}
+		m.selecting = true
+	case "enter":
+		if _, ok := m.selectedRow(); ok {
+			m.detail = true
+		}
+	case "a":
+		m.showAlerts = !m.showAlerts
+	case "g":
+		m.showBatteryGraph = !m.showBatteryGraph
+	case "x":
+		m.status = m.resetSessionStats()
+	case "X":
+		m.status = m.clearSelected()
+	case "p":
+		return m.togglePause()
+	case "esc":
+		if m.showAlerts {
+			m.showAlerts = false
+		} else if m.showBatteryGraph {
+			m.showBatteryGraph = false
+		} else if m.detail {
+			m.detail = false
+		} else {
+			m.selecting = false
+		}
+	case "e":
+		if sensor, ok := m.selectedSensor(); ok && m.detail {
+			high, critical := m.baseThresholds(sensor)
+			m.edit = &thresholdEdit{inputs: [2]string{editInput(high, m.unit), editInput(critical, m.unit)}}
+		}
+	case "m":
+		return m.toggleMute()
+	case "s":
+		if m.detail {
+			m.status = m.saveConfig()
+		}
+	case "[", "]":
+		step := 1
+		if msg.String() == "[" {
+			step = -1
+		}
+		m.status = m.adjustSelected(step)
+	case "r":
+		m.status = m.reloadScripts()
+	case "R":
+		return m.reloadConfig(m.clock.Now())
+	case "u":
+		m.unit = (m.unit + 1) % TempUnit(len(tempUnitNames))
+		return m.uiStateChanged()
+	case "b":
+		// Byte units come from the config file; toggles last for the session
+		m.byteUnits = (m.byteUnits + 1) % ByteUnits(len(byteUnitsNames))
+	case "B":
+		m.bitRates = !m.bitRates
+	case "o":
+		m.sortMode = (m.sortMode + 1) % SortMode(len(sortModeNames))
+		m.temperatureSensors = m.sortTemperatures(m.temperatureSensors)
+		return m.uiStateChanged()
+	case "z":
+		m.expandZones = !m.expandZones
+		m.arrangeTemperatures()
+		return m.uiStateChanged()
+	case "v":
+		m.viewMode = (m.viewMode + 1) % ViewMode(len(viewModeNames))
+		return m.uiStateChanged()
+	case "c":
+		if r, ok := m.selectedRow(); ok && r.group >= 0 {
+			if m.collapsed == nil {
+				m.collapsed = make(map[string]bool)
+			}
+			m.collapsed[m.extraGroups[r.group].Name] = true
+			return m.uiStateChanged()
+		}
+	case "C":
+		if len(m.collapsed) > 0 {
+			m.collapsed = nil
+			return m.uiStateChanged()
+		}
+	}
+	return m, nil
+}
+
+// adjustSelected changes the setting behind the selected sensor, if it is
+// Adjustable and control is enabled, returning a status message.
+func (m Monitor) adjustSelected(step int) string {
+	r, ok := m.selectedRow()
+	if !ok || r.group < 0 {
+		return ""
+	}
+	adjustable, ok := m.extraGroups[r.group].Sensors[r.index].(Adjustable)
+	if !ok {
+		return ""
+	}
+	if !m.controlEnabled {
+		return "Control is disabled (start with --enable-control)"
+	}
+	if err := adjustable.Adjust(step); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// hardwareClearer is implemented by Clearable sensors telling whether Clear
+// writes to the hardware. Clearing needs control enabled unless it resets
+// the monitor's own latch alone; sensors without the method are assumed to
+// write.
+type hardwareClearer interface {
+	clearsHardware() bool
+}
+
+// clearSelected resets the latch of the selected sensor, if it is Clearable
+// and control is enabled or the latch is the monitor's own, returning a
+// status message
+func (m Monitor) clearSelected() string {
+	r, ok := m.selectedRow()
+	if !ok || r.group < 0 {
+		return ""
+	}
+	clearable, ok := m.extraGroups[r.group].Sensors[r.index].(Clearable)
+	if !ok {
+		return ""
+	}
+	hardware, ok := clearable.(hardwareClearer)
+	if !m.controlEnabled && (!ok || hardware.clearsHardware()) {
+		return "Control is disabled (start with --enable-control)"
+	}
+	if err := clearable.Clear(); err != nil {
+		return err.Error()
+	}
+	return "Cleared " + clearable.Name()
+}
@@ -0,0 +1,147 @@
+package monitor
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Kind is what a sensor measures, deciding its unit, its default precision
+// and its metric name
+type Kind string
+
+const (
+	KindTemperature Kind = "temperature" // degrees Celsius
+	KindFan         Kind = "fan"         // revolutions per minute
+	KindPower       Kind = "power"       // watts
+	KindVoltage     Kind = "voltage"     // volts
+	KindCurrent     Kind = "current"     // amperes
+	KindPercentage  Kind = "percentage"  // percent
+	KindRate        Kind = "rate"        // bytes per second
+	KindInfo        Kind = "info"        // anything else, such as a name or a state
+)
+
+// kinds lists every Kind, for validating filters
+var kinds = []Kind{KindTemperature, KindFan, KindPower, KindVoltage, KindCurrent, KindPercentage, KindRate, KindInfo}
+
+// Kinded is implemented by sensors that know what they measure. Sensors
+// without it are KindInfo.
+type Kinded interface {
+	Sensor
+	Kind() Kind
+}
+
+// Measured is implemented by kinded sensors with a numeric reading, in the
+// unit of their kind. ok is false while there is no number to report.
+type Measured interface {
+	Kinded
+	Measurement() (value float64, ok bool)
+}
+
+// SensorKind returns the kind of a sensor, KindInfo if it doesn't tell
+func SensorKind(s Sensor) Kind {
+	if k, ok := s.(Kinded); ok && k.Kind() != "" {
+		return k.Kind()
+	}
+	return KindInfo
+}
+
+// formatMeasurement renders a reading with the unit and default precision
+// of its kind, e.g. "45.0°C", "1200 RPM" or "12.60V"
+func formatMeasurement(kind Kind, value float64) string {
+	switch kind {
+	case KindTemperature:
+		return fmt.Sprintf("%.1f°C", value)
+	case KindFan:
+		return fmt.Sprintf("%.0f RPM", value)
+	case KindPower:
+		return fmt.Sprintf("%.2fW", value)
+	case KindVoltage:
+		return fmt.Sprintf("%.2fV", value)
+	case KindCurrent:
+		return fmt.Sprintf("%.2fA", value)
+	case KindPercentage:
+		return fmt.Sprintf("%.0f%%", value)
+	case KindRate:
+		return formatRate(value, IECBytes, false)
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// valueUnits are the unit suffixes parseMeasurement recognizes, tried in
+// order
+var valueUnits = []struct {
+	suffix string
+	kind   Kind
+}{
+	{"°C", KindTemperature},
+	{"RPM", KindFan},
+	{"rpm", KindFan},
+	{"B/s", KindRate},
+	{"%", KindPercentage},
+	{"W", KindPower},
+	{"V", KindVoltage},
+	{"A", KindCurrent},
+}
+
+// parseMeasurement reads the kind and number of a displayed value such as
+// "1200 RPM" or "45.5°C", for sensors that only report strings (scripts).
+// Values it doesn't recognize are KindInfo. Rates are only recognized in
+// plain bytes per second, since prefixed ones have lost their precision.
+func parseMeasurement(value string) (Kind, float64, bool) {
+	value = strings.TrimSpace(value)
+	for _, unit := range valueUnits {
+		number, ok := strings.CutSuffix(value, unit.suffix)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		if err != nil {
+			return KindInfo, 0, false
+		}
+		return unit.kind, n, true
+	}
+	return KindInfo, 0, false
+}
+
+// parseExclude checks the filters of the config's exclude list, which take
+// the form "kind=<kind>", returning the excluded kinds
+func parseExclude(filters []string) (map[Kind]bool, error) {
+	excluded := make(map[Kind]bool, len(filters))
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok || strings.TrimSpace(key) != "kind" {
+			return nil, fmt.Errorf("exclude %q: expected kind=<kind>", filter)
+		}
+		kind := Kind(strings.TrimSpace(value))
+		if !slices.Contains(kinds, kind) {
+			return nil, fmt.Errorf("exclude %q: unknown kind %q", filter, kind)
+		}
+		excluded[kind] = true
+	}
+	return excluded, nil
+}
+
+// excludeSensors drops the sensors whose kind the config excludes from
+// groups, and the groups left without sensors
+func (m Monitor) excludeSensors(groups []SensorGroup) []SensorGroup {
+	excluded, _ := parseExclude(m.config.Exclude)
+	if len(excluded) == 0 {
+		return groups
+	}
+	var kept []SensorGroup
+	for _, group := range groups {
+		var sensors []Sensor
+		for _, sensor := range group.Sensors {
+			if !excluded[SensorKind(sensor)] {
+				sensors = append(sensors, sensor)
+			}
+		}
+		if len(sensors) > 0 || len(group.Sensors) == 0 {
+			group.Sensors = sensors
+			kept = append(kept, group)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,73 @@
+package monitor
+
+import "testing"
+
+func TestParseMeasurement(t *testing.T) {
+	tests := []struct {
+		value string
+		kind  Kind
+		n     float64
+		ok    bool
+	}{
+		{"1200 RPM", KindFan, 1200, true},
+		{"45.5°C", KindTemperature, 45.5, true},
+		{"12.60V", KindVoltage, 12.6, true},
+		{"1.25A", KindCurrent, 1.25, true},
+		{"7.5 W", KindPower, 7.5, true},
+		{"40%", KindPercentage, 40, true},
+		{"512 B/s", KindRate, 512, true},
+		{"1.2 MiB/s", KindInfo, 0, false},
+		{"LOW", KindInfo, 0, false},
+		{"balanced", KindInfo, 0, false},
+	}
+	for _, tt := range tests {
+		kind, n, ok := parseMeasurement(tt.value)
+		if kind != tt.kind || n != tt.n || ok != tt.ok {
+			t.Errorf("parseMeasurement(%q) = %s, %v, %v; expected %s, %v, %v", tt.value, kind, n, ok, tt.kind, tt.n, tt.ok)
+		}
+	}
+}
+
+func TestSensorKinds(t *testing.T) {
+	temp := TemperatureSensor{Value: 45}
+	sensors := map[Kind]Sensor{
+		KindTemperature: TemperatureSensorAdapter{&temp},
+		KindPercentage:  BatterySensorAdapter{BatteryStatus: &BatteryStatus{Capacity: 50}},
+		KindRate:        &netRateSensor{rate: 10},
+		KindFan:         &scriptSensor{value: "900 RPM"},
+		KindInfo:        NewGenericSensor("untyped", nil),
+	}
+	for want, sensor := range sensors {
+		if got := SensorKind(sensor); got != want {
+			t.Errorf("%T: expected kind %s, got %s", sensor, want, got)
+		}
+	}
+	fan := NewGenericSensor("fan1", func() (string, bool, bool, error) {
+		return formatMeasurement(KindFan, 1234.4), false, false, nil
+	}).SetKind(KindFan)
+	fan.Refresh()
+	if n, ok := fan.Measurement(); !ok || n != 1234 || fan.Value() != "1234 RPM" {
+		t.Errorf("expected a measurement of 1234 from %q, got %v, %v", fan.Value(), n, ok)
+	}
+}
+
+func TestExcludeKinds(t *testing.T) {
+	if _, err := parseExclude([]string{"kind=volts"}); err == nil {
+		t.Errorf("expected an unknown kind to be rejected")
+	}
+	if _, err := parseExclude([]string{"name=fan1"}); err == nil {
+		t.Errorf("expected an unknown filter to be rejected")
+	}
+
+	m := NewMonitor(WithConfig("", Config{Exclude: []string{"kind=voltage", " kind = info"}}))
+	volts := NewGenericSensor("vcore", nil).SetKind(KindVoltage)
+	fan := NewGenericSensor("fan1", nil).SetKind(KindFan)
+	groups := m.excludeSensors([]SensorGroup{
+		{Name: "Board", Sensors: []Sensor{volts, fan}},
+		{Name: "Info", Sensors: []Sensor{NewGenericSensor("vendor", nil)}},
+		{Name: "Empty"},
+	})
+	if len(groups) != 2 || groups[0].Name != "Board" || len(groups[0].Sensors) != 1 || groups[0].Sensors[0] != fan || groups[1].Name != "Empty" {
+		t.Errorf("expected only the fan and the group that was already empty, got %+v", groups)
+	}
+}
@@ -0,0 +1,123 @@
+package monitor
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxLayoutEntries bounds the layout cache; past it the cache starts over,
+// e.g. after a long session of moving the selection between refreshes
+const maxLayoutEntries = 1 << 14
+
+// layoutCache keeps the width-dependent work of the full view between
+// renders: the display width of lines, the fitted group names and the
+// column split of each section per terminal width. Resizing a terminal
+// sends a burst of sizes, each rendered with the same readings, so every
+// width after the first costs little and a width seen before nothing.
+//
+// Entries belong to one revision of the readings, the time of the refresh,
+// and are dropped when it changes. Within a revision they are keyed by
+// content, so a render never gets a stale layout. The cache is shared by
+// the Monitor's copies and only used by one program at a time, but a lock
+// keeps embedders rendering from several goroutines safe.
+type layoutCache struct {
+	mu       sync.Mutex
+	revision time.Time
+	widths   map[string]int
+	names    map[nameKey]string
+	flows    map[flowKey][]string
+}
+
+// nameKey is a name fitted to a name column
+type nameKey struct {
+	name  string
+	width int
+}
+
+// flowKey is a section's lines, by hash, flowed into a width
+type flowKey struct {
+	lines uint64
+	width int
+}
+
+func newLayoutCache() *layoutCache {
+	return &layoutCache{}
+}
+
+// at prepares the cache for rendering the readings of a revision. A nil
+// cache stays nil: renders without one measure everything.
+func (c *layoutCache) at(revision time.Time) *layoutCache {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.revision.Equal(revision) || len(c.widths)+len(c.names)+len(c.flows) > maxLayoutEntries {
+		c.revision = revision
+		c.widths = make(map[string]int)
+		c.names = make(map[nameKey]string)
+		c.flows = make(map[flowKey][]string)
+	}
+	return c
+}
+
+// width returns the display width of s, like lipgloss.Width
+func (c *layoutCache) width(s string) int {
+	if c == nil {
+		return lipgloss.Width(s)
+	}
+	c.mu.Lock()
+	w, ok := c.widths[s]
+	c.mu.Unlock()
+	if !ok {
+		w = lipgloss.Width(s)
+		c.mu.Lock()
+		c.widths[s] = w
+		c.mu.Unlock()
+	}
+	return w
+}
+
+// fitName truncates and pads a name to the name column of a group
+func (c *layoutCache) fitName(name string, width int) string {
+	if c == nil {
+		return padRight(truncateWidth(name, width), width)
+	}
+	key := nameKey{name, width}
+	c.mu.Lock()
+	fitted, ok := c.names[key]
+	c.mu.Unlock()
+	if !ok {
+		fitted = padRight(truncateWidth(name, width), width)
+		c.mu.Lock()
+		c.names[key] = fitted
+		c.mu.Unlock()
+	}
+	return fitted
+}
+
+// flow is flowColumns, remembering the split of each section per width
+func (c *layoutCache) flow(lines []string, width int) []string {
+	if c == nil {
+		return flowColumns(lines, width, lipgloss.Width)
+	}
+	h := fnv.New64a()
+	for _, line := range lines {
+		h.Write([]byte(line))
+		h.Write([]byte{0})
+	}
+	key := flowKey{h.Sum64(), width}
+	c.mu.Lock()
+	flowed, ok := c.flows[key]
+	c.mu.Unlock()
+	if !ok {
+		flowed = flowColumns(lines, width, c.width)
+		c.mu.Lock()
+		c.flows[key] = flowed
+		c.mu.Unlock()
+	}
+	return flowed
+}
@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newCrowdedMonitor returns a monitor with n readings, half temperatures
+// and half in a group
+func newCrowdedMonitor(n int) Monitor {
+	m := NewMonitor(WithHostname("bench"))
+	group := SensorGroup{Name: "Crowd"}
+	for i := 0; i < n/2; i++ {
+		m.temperatureSensors = append(m.temperatureSensors, TemperatureSensor{
+			Name: fmt.Sprintf("Core %d", i), Value: 40 + float64(i%50), High: 85, Critical: 100,
+			Path: fmt.Sprintf("/sys/class/hwmon/hwmon%d/temp%d_input", i/8, i%8+1),
+		})
+		group.Sensors = append(group.Sensors, newStaticSensor(fmt.Sprintf("sensor with a long name %d", i), i%7 == 0, false))
+	}
+	m.extraGroups = []SensorGroup{group}
+	return m
+}
+
+func TestLayoutCacheMatchesUncachedRender(t *testing.T) {
+	m := newCrowdedMonitor(200)
+	uncached := m
+	uncached.layout = nil
+	render := func(m Monitor, width int) string {
+		m, _ = m.Update(tea.WindowSizeMsg{Width: width, Height: 60})
+		return m.View()
+	}
+	// Widths in both directions, so the second pass comes from the cache
+	for pass := 0; pass < 2; pass++ {
+		for width := 40; width <= 240; width += 25 {
+			if got, want := render(m, width), render(uncached, width); got != want {
+				t.Fatalf("pass %d, width %d: cached render differs:\n%s\nexpected:\n%s", pass, width, got, want)
+			}
+		}
+	}
+
+	// A refresh is a new revision: the readings change, and so does the layout
+	m.temperatureSensors[0].Name = "A much longer sensor name than the others"
+	m.lastUpdate = m.lastUpdate.Add(time.Second)
+	uncached.temperatureSensors = m.temperatureSensors
+	uncached.lastUpdate = m.lastUpdate
+	if got, want := render(m, 140), render(uncached, 140); got != want {
+		t.Errorf("expected the new readings laid out, got:\n%s", got)
+	}
+	if n := len(m.layout.flows); n > 2 {
+		t.Errorf("expected the old revision dropped, %d flows kept", n)
+	}
+}
+
+func BenchmarkResize(b *testing.B) {
+	m := newCrowdedMonitor(200)
+	for i := 0; i < b.N; i++ {
+		for width := 80; width < 130; width++ {
+			m, _ = m.Update(tea.WindowSizeMsg{Width: width, Height: 60})
+			m.View()
+		}
+	}
+}
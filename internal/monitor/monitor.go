@@ -1,30 +1,339 @@
 package monitor
 
 import (
+	"errors"
 	"fmt"
-	"strings"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"sort"
+	"text/template"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
-const compactHeightThreshold = 10
+const (
+	compactHeightThreshold = 10
+
+	// titleHeight is the number of lines the title takes in the full view,
+	// given to the readings when it is hidden
+	titleHeight = 3
+
+	// DefaultTitle heads the full view
+	DefaultTitle = "System Status Monitor"
+
+	// DefaultInterval is the time between sensor refreshes
+	DefaultInterval = 2 * time.Second
+
+	// DefaultMinTemperature is the lowest reading accepted as real; below it
+	// readings are bogus, like the -273.2°C of disabled thermal zones
+	DefaultMinTemperature = -100.0
+
+	// DefaultUnderpoweredTicks is how many consecutive refreshes the battery
+	// must discharge with the adapter online before it is reported as
+	// underpowered; short CPU spikes routinely cause a tick or two.
+	DefaultUnderpoweredTicks = 3
+
+	// countdownInterval is how often the footer countdown is redrawn; it
+	// never triggers sensor reads
+	countdownInterval = time.Second
+)
 
 type Monitor struct {
 	temperatureSensors []TemperatureSensor
 	batteryStatus      BatteryStatus
 	extraGroups        []SensorGroup
+	tempReader         *TemperatureReader
+	batteryWatcher     *batteryWatcher
+	config             Config
+	configPath         string
+	clock              Clock
 	lastUpdate         time.Time
-	width, height      int
+	// When the temperatures, the battery and each group were last read (see
+	// reading_times.go)
+	temperaturesRead time.Time
+	batteryRead      time.Time
+	groupsRead       map[string]time.Time
+	nextRefresh      time.Time
+	interval         time.Duration
+	paused           bool
+	width, height    int
+	title            string
+	// Footer templates, nil for the defaults (see footer.go)
+	footer        *template.Template
+	compactFooter *template.Template
+
+	// Sensor selection, detail view and threshold editing
+	selecting bool
+	cursor    int
+	detail    bool
+	edit      *thresholdEdit
+	status    string
+
+	// unsavedOverrides is set while thresholds applied in the editor
+	// aren't saved to the config file
+	unsavedOverrides bool
+
+	controlEnabled bool
+	discovered     bool
+	virtualization string
+	hostname       string
+
+	// Providers skipped by discovery and the errors of those that failed
+	// (see providers.go)
+	disabledProviders map[string]bool
+	discoveryErrors   map[string]error
+
+	// Groups rebuilt on every refresh (see dynamic_groups.go): network
+	// interfaces and sensor scripts, and the SIGHUP channel reloading them
+	network   *networkCollector
+	scriptDir string
+	scripts   *scriptRunner
+	hangup    chan os.Signal
+
+	// Reading states of the last refresh, recorded transitions and the
+	// optional event stream (see events.go)
+	states map[string]State
+	// Time in each state by the same keys (see state_time.go)
+	stateTime map[string]StateDurations
+	// When each reading last changed value (see value_changes.go)
+	valueChanges map[string]valueChange
+	// Readings holding their value, by the same keys, the refresh count of
+	// the check and of the last change of any reading, and of a reading of
+	// each hwmon chip (see frozen.go)
+	frozenReadings          map[string]frozenReading
+	frozenTick, frozenMoved int
+	frozenChipMoved         map[string]int
+	history                 []Event
+	// bus publishes the recorded events; the alert history takes them
+	// from historySub, the --events stream of WithEventWriter from its
+	// own subscription (see bus.go)
+	bus         *EventBus
+	historySub  *Subscription
+	eventsTo    io.Writer
+	eventWriter *eventWriter
+	showAlerts  bool
+	// accessible replaces the views with the linear one of accessible.go
+	accessible bool
+
+	// Synthetic readings replacing sysfs (see demo.go)
+	demo *demoSource
+	// The running instance's readings replacing sysfs (see instance.go)
+	instance *InstanceClient
+
+	// How long the first refresh waits for sensors to appear, until when,
+	// and the sysfs root looked at (see wait.go)
+	sensorWait time.Duration
+	waitUntil  time.Time
+	waitRoot   string
+
+	// How long NewMonitor waits for the first refresh, and the refresh
+	// still running past it (see preload.go)
+	preloadTimeout time.Duration
+	preload        chan Monitor
+
+	// Low-power mode: the interval while the terminal doesn't have focus,
+	// whether it has lost it, and whether the countdown redraws stopped
+	// meanwhile (see idle.go)
+	idleInterval     time.Duration
+	idle             bool
+	countdownStopped bool
+
+	// History file for `sysfs-check report` (see history.go)
+	historyFile *historyFile
+
+	// Width-dependent layout work kept between renders (see layout.go)
+	layout *layoutCache
+
+	// Battery capacity samples of the session (see battery_graph.go) and
+	// the peak power since the last reset (see battery_power.go)
+	batteryHistory   []BatterySample
+	showBatteryGraph bool
+	powerPeak        float64
+
+	// Active threshold profile and history entries queued by the refresh
+	// (see profiles.go)
+	profile *Profile
+	pending []Event
+
+	// Toast above the footer for battery status changes and config reloads
+	// (see toast.go)
+	toast      string
+	toastUntil time.Time
+
+	// Extra groups whose refresh keeps failing and the last refresh of
+	// each group sensor, by "group/name" (see group_refresh.go)
+	groupRefresh  map[string]*groupRefresh
+	sensorRefresh map[string]sensorRefresh
+
+	// Whether permission failures are summarized instead of listed, and
+	// those of the last temperature read (see degraded.go)
+	degraded          bool
+	temperatureDenied []error
+
+	// Temperatures before thermal zone clusters are collapsed, and whether
+	// they are shown that way (see thermal_clusters.go)
+	zoneSensors []TemperatureSensor
+	expandZones bool
+
+	// Temperatures of the last refresh by value file path, and those found
+	// since, with the refreshes left of their "new" tag (see
+	// sensor_changes.go)
+	knownSensors map[string]string
+	newSensors   map[string]int
+
+	// Last fresh raw reading of each temperature, by path, standing in for
+	// transient read failures (see stale.go)
+	freshTemperatures map[string]freshTemperature
+
+	// Temperature corrections by name or glob (see offsets.go)
+	offsets map[string]float64
+	// Readings below this are dropped as bogus
+	minTemperature float64
+
+	batteryThresholds     BatteryThresholds
+	notChargingThresholds *BatteryThresholds
+
+	// The time on battery since the last unplug, nil on AC, and the last
+	// resume from pause, which isn't a suspend (see power_events.go).
+	// refreshed is set by the first refresh (see suspend.go).
+	onBattery *BatterySpan
+	resumedAt time.Time
+	refreshed bool
+	// energy is the energy the battery delivered and took in this session
+	// (see battery_energy.go)
+	energy EnergyTotals
+
+	// The monotonic time of the last refresh, and the last jump of the wall
+	// clock with the refreshes left to note it (see clock_jump.go)
+	monoUpdate    time.Duration
+	clockJump     time.Duration
+	clockJumpLeft int
+
+	// Consecutive refreshes discharging on AC, and how many make a warning
+	underpoweredCount int
+	underpoweredTicks int
+
+	// Persisted UI preferences (see uistate.go)
+	theme     Theme
+	unit      TempUnit
+	numbers   NumberFormat
+	byteUnits ByteUnits
+	bitRates  bool
+	sortMode  SortMode
+	viewMode  ViewMode
+	collapsed map[string]bool
+	muted     map[string]bool // by mute key, see mute.go
+	statePath string
+	stateSeq  int
+}
+
+// Option configures a Monitor
+type Option func(*Monitor)
+
+// WithInterval sets the time between sensor refreshes
+func WithInterval(d time.Duration) Option {
+	return func(m *Monitor) {
+		if d > 0 {
+			m.interval = d
+		}
+	}
+}
+
+// WithTitle replaces the title of the full view; an empty title hides the
+// title line, leaving its lines to the readings
+func WithTitle(title string) Option {
+	return func(m *Monitor) {
+		m.title = title
+	}
+}
+
+// WithHeldFiles makes the monitor keep up to maxHeld temperature value files
+// open between refreshes instead of re-opening every attribute each tick.
+func WithHeldFiles(maxHeld int) Option {
+	return func(m *Monitor) {
+		m.tempReader = NewTemperatureReader(maxHeld)
+	}
 }
 
 type TemperatureSensor struct {
-	Name     string
+	Name string
+	// Key tells apart thermal zones sharing a type, e.g. "acpitz@zone2";
+	// empty when Name is unique (see zone_keys.go)
+	Key      string  `json:",omitempty"`
 	Value    float64 // in Celsius
 	High     float64 // high threshold
 	Critical float64 // critical threshold
 	Path     string  // sysfs path
+
+	// Emergency is the threshold above Critical and LowCritical the
+	// too-cold one, nil when the chip doesn't expose them: an lcrit of
+	// 0°C is a real threshold
+	Emergency   *float64 `json:",omitempty"`
+	LowCritical *float64 `json:",omitempty"`
+
+	// Raw is the integer the value file held, millidegrees for the sysfs
+	// readers, empty for sensors that aren't read from a file or while
+	// stale
+	Raw string `json:",omitempty"`
+
+	// Zones is the number of thermal zones a cluster reading is the hottest
+	// of, 0 for a single sensor (see thermal_clusters.go)
+	Zones int `json:",omitempty"`
+
+	// Stale is set when the last read failed transiently and Value is the
+	// previous reading (see stale.go)
+	Stale bool `json:",omitempty"`
+
+	// Alarm is the state forced by the chip's latched alarm flags at the
+	// last read, StateOK when none is set (see sysfs_alarms.go)
+	Alarm State `json:",omitempty"`
+	// alarms are the alarm files read with the value
+	alarms []alarmFile
+
+	// Asleep is set when the sensor's device is runtime suspended and was
+	// left unread so it can stay that way; Value is 0 and the thresholds
+	// are the defaults (see chipAsleep)
+	Asleep bool `json:",omitempty"`
+
+	// Cooling lists the cooling devices a thermal zone drives
+	Cooling []CoolingBinding `json:",omitempty"`
+
+	// Muted is set when the sensor's alerts are muted (see mute.go)
+	Muted bool `json:",omitempty"`
+
+	// Frozen is set when the reading has held the exact same value for
+	// long while others changed (see frozen.go)
+	Frozen bool `json:",omitempty"`
+
+	// New is set for a few refreshes after rediscovery found the sensor
+	// (see sensor_changes.go)
+	New bool `json:",omitempty"`
+
+	// chip is the hwmon chip's name, empty for thermal zones; the full
+	// view summarizes the channels of each chip (see chip_summary.go)
+	chip string
+}
+
+// State returns the alert state used to color the reading. Readings at or
+// below LowCritical are critical as well; muted readings are always OK.
+func (t TemperatureSensor) State() State {
+	if t.Muted || t.Asleep {
+		return StateOK
+	}
+	if t.Value >= t.Critical || t.belowLowCritical() {
+		return StateCritical
+	}
+	if t.Value >= t.High {
+		return max(StateWarning, t.Alarm)
+	}
+	return t.Alarm
+}
+
+func (t TemperatureSensor) belowLowCritical() bool {
+	return t.LowCritical != nil && t.Value <= *t.LowCritical
 }
 
 type BatteryStatus struct {
@@ -36,18 +345,280 @@ type BatteryStatus struct {
 	Health        string  // Health status
 	Temperature   float64 // Celsius
 	Energy        float64 // watt-hours
+	EnergyFull    float64 // watt-hours when full, 0 if not exposed
 	CapacityLevel string  // capacity level (Full, Normal, etc.)
+	ACOnline      bool    // a mains or USB power supply is online
+	ACVoltage     float64 // volts reported by the online adapter, 0 if not exposed
+	ACCurrent     float64 // amperes reported by the online adapter, 0 if not exposed
+
+	VoltageMinDesign float64 // lowest voltage the pack is designed for, 0 if not exposed
+	ChargeLimit      int     // percentage charging stops at, 0 without a charge limit
+
+	CapacitySuspect bool // raw capacity was outside 0–100 and has been corrected
+	RawCapacity     int  // capacity as reported by sysfs, set when suspect
+}
+
+// Present reports whether any battery information was read
+func (b BatteryStatus) Present() bool {
+	return b.Capacity > 0 || b.Status != ""
+}
+
+// ACDescription describes the adapter state with its power draw when
+// available, e.g. "online 19.80V × 3.20A = 63.4W"
+func (b BatteryStatus) ACDescription() string {
+	if !b.ACOnline {
+		return "offline"
+	}
+	switch {
+	case b.ACVoltage > 0 && b.ACCurrent > 0:
+		return fmt.Sprintf("online %.2fV × %.2fA = %.1fW", b.ACVoltage, b.ACCurrent, b.ACVoltage*b.ACCurrent)
+	case b.ACVoltage > 0:
+		return fmt.Sprintf("online %.2fV", b.ACVoltage)
+	case b.ACCurrent > 0:
+		return fmt.Sprintf("online %.2fA", b.ACCurrent)
+	}
+	return "online"
 }
 
-func NewMonitor() Monitor {
-	return Monitor{
+// BatteryThresholds are the capacity percentages below which the battery is
+// in warning or critical state
+type BatteryThresholds struct {
+	Warning  int `json:"warning"`
+	Critical int `json:"critical"`
+}
+
+// DefaultBatteryThresholds is used for the Battery section coloring
+var DefaultBatteryThresholds = BatteryThresholds{Warning: 50, Critical: 20}
+
+// dischargingOnAC reports a discharging battery while an adapter is online
+func (b BatteryStatus) dischargingOnAC() bool {
+	return b.ACOnline && b.Status == "Discharging"
+}
+
+// CapacityState returns the alert state for the battery's capacity. A
+// charging or full battery never warns. While the AC adapter is online but
+// the battery is "Not charging" (held by a charge limit), notCharging applies
+// when non-nil; otherwise t applies.
+func (b BatteryStatus) CapacityState(t BatteryThresholds, notCharging *BatteryThresholds) State {
+	switch b.Status {
+	case "Charging", "Full":
+		return StateOK
+	}
+	t = b.thresholds(t, notCharging)
+	if b.Capacity < t.Critical {
+		return StateCritical
+	}
+	if b.Capacity < t.Warning {
+		return StateWarning
+	}
+	return StateOK
+}
+
+// thresholds returns the capacity thresholds in effect for the status
+func (b BatteryStatus) thresholds(t BatteryThresholds, notCharging *BatteryThresholds) BatteryThresholds {
+	if b.Status == "Not charging" && b.ACOnline && notCharging != nil {
+		return *notCharging
+	}
+	return t
+}
+
+// State combines the capacity state with the health mapping and the
+// deep-discharge risk, which is critical whatever the capacity
+func (b BatteryStatus) State(t BatteryThresholds, notCharging *BatteryThresholds) State {
+	state := max(b.CapacityState(t, notCharging), BatteryHealthState(b.Health))
+	if b.DeepDischargeRisk() {
+		state = StateCritical
+	}
+	return state
+}
+
+// deepDischargeMargin is how close to voltage_min_design, as a fraction of
+// it, the voltage may sag while discharging before the battery is at risk
+const deepDischargeMargin = 0.05
+
+// DeepDischargeRisk reports whether the battery is discharging within 5% of
+// its design minimum voltage. Capacity estimates of worn batteries are often
+// wrong, while voltage sag is the real danger signal.
+func (b BatteryStatus) DeepDischargeRisk() bool {
+	return b.Status == "Discharging" && b.VoltageMinDesign > 0 && b.Voltage > 0 &&
+		b.Voltage <= b.VoltageMinDesign*(1+deepDischargeMargin)
+}
+
+func NewMonitor(opts ...Option) Monitor {
+	m := Monitor{
 		temperatureSensors: []TemperatureSensor{},
 		batteryStatus:      BatteryStatus{},
 		extraGroups:        []SensorGroup{},
-		lastUpdate:         time.Now(),
+		clock:              realClock{},
+		interval:           DefaultInterval,
+		batteryThresholds:  DefaultBatteryThresholds,
+		underpoweredTicks:  DefaultUnderpoweredTicks,
+		minTemperature:     DefaultMinTemperature,
+		theme:              DefaultTheme,
+		title:              DefaultTitle,
+		layout:             newLayoutCache(),
+		degraded:           os.Geteuid() != 0,
+	}
+	m.hostname, _ = os.Hostname()
+	for _, opt := range opts {
+		opt(&m)
+	}
+	if m.bus == nil {
+		m.bus = NewEventBus()
+	}
+	m.historySub = m.bus.subscribe(nil)
+	if m.eventsTo != nil {
+		m.eventWriter = newEventWriter(m.bus, m.eventsTo)
+	}
+	m.lastUpdate = m.clock.Now()
+	m.monoUpdate, _ = m.monotonic()
+	m.nextRefresh = m.lastUpdate.Add(m.interval)
+	if m.sensorWait > 0 && m.demo == nil && m.instance == nil {
+		m.waitUntil = m.lastUpdate.Add(m.sensorWait)
+	}
+	if m.preloadTimeout > 0 && !m.waiting() {
+		m = m.preloadFirstRefresh()
+	}
+	return m
+}
+
+// WithBatteryWatch refreshes the battery immediately when the kernel reports
+// a power supply change, instead of waiting for the next tick. If the uevent
+// socket can't be opened the monitor silently falls back to polling.
+func WithBatteryWatch() Option {
+	return func(m *Monitor) {
+		if w, err := newBatteryWatcher(); err == nil {
+			m.batteryWatcher = w
+		}
+	}
+}
+
+// WithBatteryThresholds replaces DefaultBatteryThresholds, the capacity
+// thresholds of the battery
+func WithBatteryThresholds(t BatteryThresholds) Option {
+	return func(m *Monitor) {
+		m.batteryThresholds = t
+	}
+}
+
+// WithNotChargingThresholds sets the capacity thresholds used while the AC
+// adapter is online but the battery is held at a charge limit ("Not
+// charging"). By default the regular thresholds apply.
+func WithNotChargingThresholds(t BatteryThresholds) Option {
+	return func(m *Monitor) {
+		m.notChargingThresholds = &t
+	}
+}
+
+// WithByteUnits sets the prefixes for sizes and rates and whether rates are
+// shown in bits per second
+func WithByteUnits(units ByteUnits, bitRates bool) Option {
+	return func(m *Monitor) {
+		m.byteUnits = units
+		m.bitRates = bitRates
+	}
+}
+
+// WithHostname overrides the host name labeling machine-readable output
+// (snapshots, events, metrics) and shown in the title
+func WithHostname(name string) Option {
+	return func(m *Monitor) {
+		m.hostname = name
 	}
 }
 
+// WithMinTemperature sets the lowest reading, in Celsius, accepted as real.
+// Lower readings are dropped as bogus.
+func WithMinTemperature(celsius float64) Option {
+	return func(m *Monitor) {
+		m.minTemperature = celsius
+	}
+}
+
+// WithUnderpoweredTicks sets how many consecutive refreshes the battery must
+// discharge while the adapter is online before the adapter is reported as
+// underpowered. Non-positive values keep DefaultUnderpoweredTicks.
+func WithUnderpoweredTicks(n int) Option {
+	return func(m *Monitor) {
+		if n > 0 {
+			m.underpoweredTicks = n
+		}
+	}
+}
+
+// WithConfig applies a loaded config file. path is where threshold overrides
+// edited in the TUI are saved; an empty path disables saving.
+func WithConfig(path string, cfg Config) Option {
+	return func(m *Monitor) {
+		m.configPath = path
+		m.config = cfg
+		if cfg.MinValidTemperature != nil {
+			m.minTemperature = *cfg.MinValidTemperature
+		}
+		if cfg.Hostname != "" {
+			m.hostname = cfg.Hostname
+		}
+		if len(cfg.Offsets) > 0 {
+			m.offsets = mergeOffsets(m.offsets, cfg.Offsets)
+		}
+		if cfg.NotChargingThresholds != nil {
+			m.notChargingThresholds = cfg.NotChargingThresholds
+		}
+		if d := cfg.RefreshInterval(); d > 0 {
+			m.interval = d
+		}
+		if cfg.ByteUnits != "" {
+			m.byteUnits = ByteUnits(indexOf(byteUnitsNames, cfg.ByteUnits))
+		}
+		if cfg.NetworkRates != "" {
+			m.bitRates = cfg.NetworkRates == "bits"
+		}
+		if cfg.UnderpoweredTicks > 0 {
+			m.underpoweredTicks = cfg.UnderpoweredTicks
+		}
+		WithoutProviders(cfg.DisabledProviders...)(m)
+		if theme, ok := themes[cfg.Theme]; ok {
+			m.theme = theme
+		}
+		if cfg.Footer != "" || cfg.CompactFooter != "" {
+			m.footer, m.compactFooter = footerTemplates(cfg)
+		}
+	}
+}
+
+// WithControl allows keybindings that write to sysfs, such as brightness
+// adjustment. Without it the monitor never writes.
+func WithControl() Option {
+	return func(m *Monitor) {
+		m.controlEnabled = true
+	}
+}
+
+// Close releases resources held by the monitor, such as open sysfs files.
+func (m Monitor) Close() error {
+	var errs []error
+	if m.tempReader != nil {
+		errs = append(errs, m.tempReader.Close())
+	}
+	if m.batteryWatcher != nil {
+		errs = append(errs, m.batteryWatcher.close())
+	}
+	if m.hangup != nil {
+		signal.Stop(m.hangup)
+	}
+	if m.historyFile != nil {
+		errs = append(errs, m.historyFile.close())
+	}
+	if m.instance != nil {
+		errs = append(errs, m.instance.Close())
+	}
+	m.historySub.Unsubscribe()
+	if m.eventWriter != nil {
+		m.eventWriter.close()
+	}
+	return errors.Join(errs...)
+}
+
 // RegisterSensorGroup adds a new group of sensors to the monitor.
 // This enables easy extension with new types of system monitoring.
 func (m *Monitor) RegisterSensorGroup(group SensorGroup) {
@@ -55,245 +626,321 @@ func (m *Monitor) RegisterSensorGroup(group SensorGroup) {
 }
 
 func (m Monitor) Init() tea.Cmd {
+	return tea.Batch(m.start(), m.countdown(), m.watchBattery(), m.watchHangup())
+}
+
+// start schedules the first refresh, after waiting for sensors when asked.
+// A first refresh done by NewMonitor is published right away.
+func (m Monitor) start() tea.Cmd {
+	switch {
+	case m.waiting():
+		return m.pollSensors(0)
+	case m.preloading():
+		return m.awaitPreload()
+	case m.preloadTimeout > 0:
+		return tea.Batch(m.tick(), m.emitSnapshot())
+	}
 	return m.tick()
 }
 
 func (m Monitor) Update(msg tea.Msg) (Monitor, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
+		// Only the size is kept: a resize burst lays out once per render,
+		// and the layout cache makes widths seen before free
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
 	case tickMsg:
-		m = m.updateSensors()
-		m.lastUpdate = time.Now()
-		return m, m.tick()
+		return m.handleTick(msg)
+	case sensorWaitMsg:
+		return m.handleSensorWait()
+	case preloadMsg:
+		return m.handlePreload(msg)
+	case countdownMsg:
+		// Nothing to update; receiving the message redraws the footer.
+		// The footer has no countdown while idle, so the redraws stop
+		// until the focus comes back.
+		if m.idle {
+			m.countdownStopped = true
+			return m, nil
+		}
+		return m, m.countdown()
+	case tea.BlurMsg:
+		return m.handleBlur()
+	case tea.FocusMsg:
+		return m.handleFocus()
+	case tea.KeyMsg:
+		if m.preloading() {
+			return m, nil
+		}
+		return m.handleKey(msg)
+	case saveUIStateMsg:
+		if msg.seq == m.stateSeq {
+			m.SaveUIState()
+		}
+		return m, nil
+	case batteryEventMsg:
+		if m.preloading() {
+			return m, m.watchBattery()
+		}
+		m.setBattery(ReadBatteryStatus(), m.clock.Now())
+		// Events only clear the warning; raising it is left to the ticks
+		if !m.batteryStatus.dischargingOnAC() {
+			m.underpoweredCount = 0
+		}
+		return m, m.watchBattery()
+	case hangupMsg:
+		if m.preloading() {
+			return m, m.watchHangup()
+		}
+		m, cmd := m.reloadConfig(m.clock.Now())
+		m.status = m.reloadScripts()
+		return m, tea.Batch(cmd, m.watchHangup())
 	}
 	return m, nil
 }
 
+// compactHeight is the terminal height below which the auto view mode is
+// compact: the full view needs fewer lines without its title
+func (m Monitor) compactHeight() int {
+	if m.title == "" {
+		return compactHeightThreshold - titleHeight
+	}
+	return compactHeightThreshold
+}
+
 func (m Monitor) View() string {
 	if m.width == 0 || m.height == 0 {
 		return "Initializing..."
 	}
+	if m.waiting() {
+		return m.waitingView()
+	}
+	if m.preloading() {
+		return "Reading sensors…"
+	}
+	if m.accessible {
+		return m.AccessibleView()
+	}
 
 	// Use compact view for small panes
-	if m.height < compactHeightThreshold {
+	if m.viewMode == ViewCompact || (m.viewMode == ViewAuto && m.height < m.compactHeight()) {
 		return m.compactView()
 	}
 
-	var sb strings.Builder
+	if m.detail {
+		return m.detailView()
+	}
 
-	// Title
-	titleStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("63")).
-		PaddingBottom(1)
-	sb.WriteString(titleStyle.Render("System Status Monitor"))
-	sb.WriteString("\n\n")
+	if m.showAlerts {
+		return m.alertsView()
+	}
 
-	// Two-column layout: temperatures on left, battery on right
-	var leftCol, rightCol strings.Builder
+	if m.showBatteryGraph {
+		return renderBatteryGraph(m.batteryHistory, m.energy, m.width, m.height, m.theme, 3*m.interval)
+	}
 
-	// Temperatures column
-	leftCol.WriteString(lipgloss.NewStyle().Bold(true).Render("Temperatures"))
-	leftCol.WriteString("\n")
-	if len(m.temperatureSensors) == 0 {
-		leftCol.WriteString("  No temperature sensors found\n")
-	} else {
-		for _, sensor := range m.temperatureSensors {
-			color := "42" // green
-			if sensor.Value >= sensor.Critical {
-				color = "9" // red
-			} else if sensor.Value >= sensor.High {
-				color = "214" // orange
-			}
-			style := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
-			tempStr := style.Render(fmt.Sprintf("%6.1f°C", sensor.Value))
-			fmt.Fprintf(&leftCol, "  %-8s  %s\n", tempStr, sensor.Path)
+	return RenderFull(m.Snapshot(), m.width, m.height, m.theme, m.viewState(m.clock.Now()))
+}
+
+// compactView renders a minimal display suitable for small panes (≤3 lines)
+func (m Monitor) compactView() string {
+	return RenderCompact(m.Snapshot(), m.width, m.theme, m.viewState(m.clock.Now()))
+}
+
+// dropBogus removes readings below the minimum valid temperature. Sub-zero
+// readings above it (outdoor probes, cold boots) are kept.
+func (m Monitor) dropBogus(sensors []TemperatureSensor) []TemperatureSensor {
+	var kept []TemperatureSensor
+	for _, sensor := range sensors {
+		if sensor.Value >= m.minTemperature {
+			kept = append(kept, sensor)
 		}
 	}
+	return kept
+}
+
+// sortTemperatures orders the temperature list by the sort mode. The slice is
+// copied so earlier snapshots keep their order.
+func (m Monitor) sortTemperatures(sensors []TemperatureSensor) []TemperatureSensor {
+	if m.sortMode != SortValue {
+		return sensors
+	}
+	sorted := append([]TemperatureSensor(nil), sensors...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value > sorted[j].Value
+	})
+	return sorted
+}
+
+// sensorValue returns the display value of a sensor, formatting byte-valued
+// sensors with the current byte units
+func (m Monitor) sensorValue(sensor Sensor) string {
+	bv, ok := sensor.(ByteValued)
+	if !ok {
+		return sensor.Value()
+	}
+	n, rate := bv.Bytes()
+	if rate {
+		return formatRate(n, m.byteUnits, m.bitRates)
+	}
+	return formatBytes(n, m.byteUnits)
+}
+
+// AdapterUnderpowered reports whether the battery has been discharging with
+// the adapter online for the configured number of consecutive refreshes,
+// meaning the charger can't keep up with the load.
+func (m Monitor) AdapterUnderpowered() bool {
+	return m.underpoweredCount >= m.underpoweredTicks
+}
 
-	// Battery column
-	rightCol.WriteString(lipgloss.NewStyle().Bold(true).Render("Battery"))
-	rightCol.WriteString("\n")
-	bat := m.batteryStatus
-	if bat.Capacity == 0 && bat.Status == "" {
-		rightCol.WriteString("  No battery information\n")
+// trackUnderpowered counts refreshes spent discharging on AC; called once per
+// tick so the count debounces over ticks rather than battery events
+func (m *Monitor) trackUnderpowered() {
+	if m.batteryStatus.dischargingOnAC() {
+		m.underpoweredCount++
 	} else {
-		capacityColor := "42"
-		if bat.Capacity < 20 {
-			capacityColor = "9"
-		} else if bat.Capacity < 50 {
-			capacityColor = "214"
-		}
-		capacityStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(capacityColor))
-		fmt.Fprintf(&rightCol, "  Capacity: %s\n", capacityStyle.Render(fmt.Sprintf("%d%%", bat.Capacity)))
-		fmt.Fprintf(&rightCol, "  Status: %s\n", bat.Status)
-		if bat.Voltage > 0 {
-			fmt.Fprintf(&rightCol, "  Voltage: %.2fV\n", bat.Voltage)
-		}
-		if bat.Current != 0 {
-			fmt.Fprintf(&rightCol, "  Current: %.2fA\n", bat.Current)
-		}
-		if bat.Power > 0 {
-			fmt.Fprintf(&rightCol, "  Power: %.2fW\n", bat.Power)
-		}
-		if bat.Health != "" {
-			fmt.Fprintf(&rightCol, "  Health: %s\n", bat.Health)
-		}
-		if bat.Temperature > 0 {
-			fmt.Fprintf(&rightCol, "  Temperature: %.1f°C\n", bat.Temperature)
-		}
-		if bat.Energy > 0 {
-			fmt.Fprintf(&rightCol, "  Energy: %.2f Wh\n", bat.Energy)
-		}
-		if bat.CapacityLevel != "" {
-			fmt.Fprintf(&rightCol, "  Capacity Level: %s\n", bat.CapacityLevel)
-		}
+		m.underpoweredCount = 0
 	}
+}
 
-	// Combine columns side by side with spacing
-	leftStr := leftCol.String()
-	rightStr := rightCol.String()
-	combined := lipgloss.JoinHorizontal(lipgloss.Top, leftStr, "    ", rightStr)
-	sb.WriteString(combined)
-	sb.WriteString("\n")
-
-	// Extra sensor groups
-	for _, group := range m.extraGroups {
-		sb.WriteString("\n")
-		sb.WriteString(lipgloss.NewStyle().Bold(true).Render(group.Name))
-		sb.WriteString("\n")
-		if len(group.Sensors) == 0 {
-			sb.WriteString("  No sensors\n")
-		} else {
-			for _, sensor := range group.Sensors {
-				color := "42" // green
-				if sensor.Critical() {
-					color = "9" // red
-				} else if sensor.Warning() {
-					color = "214" // orange
-				}
-				style := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
-				fmt.Fprintf(&sb, "  %-20s: %s\n", sensor.Name(), style.Render(sensor.Value()))
-			}
-		}
+// batteryState is the battery state including the underpowered warning
+func (m Monitor) batteryState() State {
+	state := m.batteryStatus.State(m.currentBatteryThresholds(), m.notChargingThresholds)
+	if m.AdapterUnderpowered() {
+		state = max(state, StateWarning)
 	}
+	return state
+}
 
-	// Footer
-	sb.WriteString("\n")
-	footerStyle := lipgloss.NewStyle().Faint(true)
-	sb.WriteString(footerStyle.Render(fmt.Sprintf("Last updated: %s | Press 'q' to quit", m.lastUpdate.Format("15:04:05"))))
+// batteryCapacityState returns the state used to color the capacity
+func (m Monitor) batteryCapacityState() State {
+	return m.batteryStatus.CapacityState(m.currentBatteryThresholds(), m.notChargingThresholds)
+}
 
-	return sb.String()
+// untilRefresh formats the time left until the next refresh
+func (m Monitor) untilRefresh(now time.Time) string {
+	return untilRefresh(m.nextRefresh, now)
 }
 
-// compactView renders a minimal display suitable for small panes (≤3 lines)
-func (m Monitor) compactView() string {
-	var lines []string
-
-	// Combine temperature and battery on first line if both present
-	var firstLine strings.Builder
-	// Temperature - show multiple temperatures in a row
-	if len(m.temperatureSensors) > 0 {
-		firstLine.WriteString("🌡 ")
-		for i, sensor := range m.temperatureSensors {
-			if i > 0 {
-				firstLine.WriteString("   ")
-			}
-			color := "42" // green
-			if sensor.Value >= sensor.Critical {
-				color = "9" // red
-			} else if sensor.Value >= sensor.High {
-				color = "214" // orange
-			}
-			style := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
-			firstLine.WriteString(style.Render(fmt.Sprintf("%.1f°C", sensor.Value)))
-		}
+// batteryEventMsg is sent when the kernel reports a power supply change
+type batteryEventMsg struct{}
+
+func (m Monitor) watchBattery() tea.Cmd {
+	if m.batteryWatcher == nil {
+		return nil
 	}
-	// Battery
-	bat := m.batteryStatus
-	if bat.Capacity > 0 || bat.Status != "" {
-		if firstLine.Len() > 0 {
-			firstLine.WriteString(" | ")
-		}
-		capacityColor := "42"
-		if bat.Capacity < 20 {
-			capacityColor = "9"
-		} else if bat.Capacity < 50 {
-			capacityColor = "214"
-		}
-		capacityStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(capacityColor))
-		fmt.Fprintf(&firstLine, "🔋 %s %s", capacityStyle.Render(fmt.Sprintf("%d%%", bat.Capacity)), bat.Status)
-		if bat.Voltage > 0 {
-			fmt.Fprintf(&firstLine, " %.2fV", bat.Voltage)
+	w := m.batteryWatcher
+	return func() tea.Msg {
+		if !w.wait() {
+			return nil
 		}
+		return batteryEventMsg{}
 	}
-	if firstLine.Len() > 0 {
-		lines = append(lines, firstLine.String())
-	}
-
-	// Extra groups summary (second line)
-	if len(m.extraGroups) > 0 {
-		totalSensors := 0
-		warningCount := 0
-		criticalCount := 0
-		for _, group := range m.extraGroups {
-			totalSensors += len(group.Sensors)
-			for _, sensor := range group.Sensors {
-				if sensor.Critical() {
-					criticalCount++
-				} else if sensor.Warning() {
-					warningCount++
-				}
-			}
+}
+
+func (m Monitor) updateSensors() Monitor {
+	if m.demo != nil {
+		return m.updateDemo()
+	}
+	if m.instance != nil {
+		return m.updateInstance()
+	}
+
+	// Discover built-in groups on the first refresh
+	if !m.discovered {
+		m.discovered = true
+		if !sysfsSupported {
+			m.status = fmt.Sprintf("No sysfs on %s: run with --demo for synthetic readings", runtime.GOOS)
 		}
-		color := "42" // green
-		if criticalCount > 0 {
-			color = "9" // red
-		} else if warningCount > 0 {
-			color = "214" // orange
+		m.virtualization = DetectVirtualization()
+		m.discoverGroups(sysfsRoot)
+		m.addFanCheck()
+		if m.providerEnabled("network") {
+			m.network = newNetworkCollector(sysfsRoot, m.networkSettings())
 		}
-		style := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
-		summary := fmt.Sprintf("Extra: %d groups, %d sensors", len(m.extraGroups), totalSensors)
-		if warningCount > 0 || criticalCount > 0 {
-			summary += fmt.Sprintf(" (%d warning, %d critical)", warningCount, criticalCount)
+		if m.scriptDir != "" {
+			var settings ScriptsConfig
+			if m.config.Scripts != nil {
+				settings = *m.config.Scripts
+			}
+			m.scripts = newScriptRunner(m.scriptDir, settings, m.interval)
 		}
-		lines = append(lines, style.Render(summary))
 	}
 
-	// Footer with update time (always last line)
-	footerStyle := lipgloss.NewStyle().Faint(true)
-	lines = append(lines, footerStyle.Render(fmt.Sprintf("Updated: %s", m.lastUpdate.Format("15:04:05"))))
-
-	// Ensure we don't exceed 3 lines
-	maxLines := 3
-	if len(lines) > maxLines {
-		lines = lines[:maxLines]
+	// Update built-in sensors. The battery goes first since it decides
+	// power-based profiles, which adjust temperature thresholds.
+	now := m.clock.Now()
+	if m.providerEnabled("battery") {
+		m.setBattery(ReadBatteryStatus(), now)
+		m.trackUnderpowered()
+	}
+	m.selectProfile(now)
+	switch {
+	case !m.providerEnabled("thermal"):
+		m.temperatureSensors = []TemperatureSensor{}
+	case m.tempReader != nil:
+		m.tempReader.wake = m.config.WakeOnRead
+		m.temperatureSensors = m.tempReader.Refresh()
+		m.temperatureDenied = m.tempReader.denied
+	default:
+		var err error
+		m.temperatureSensors, err = discoverTemperatures(sysfsRoot, m.config.WakeOnRead)
+		m.temperatureDenied = permissionErrors(err)
+	}
+	m.holdStale(now)
+	m.adjustTemperatures()
+	if m.providerEnabled("thermal") {
+		m.temperaturesRead = now
 	}
-	return strings.Join(lines, "\n")
+
+	m.refreshGroups(now)
+	m.checkFanResponse()
+	m.refreshDynamicGroups(now)
+	return m
 }
 
-// tickMsg is a message sent periodically to update sensor readings
-type tickMsg time.Time
+// updateDemo is updateSensors for the demo dataset: one step of synthetic
+// readings, with the same thresholds, profiles and sorting as real ones
+func (m Monitor) updateDemo() Monitor {
+	if !m.discovered {
+		m.discovered = true
+		m.extraGroups = append(m.extraGroups, m.excludeSensors(m.demo.groups())...)
+	}
+	now := m.clock.Now()
+	m.demo.step()
+	m.setBattery(m.demo.battery(), now)
+	m.trackUnderpowered()
+	m.selectProfile(now)
+	m.temperatureSensors = m.demo.temperatures()
+	m.adjustTemperatures()
+	m.temperaturesRead = now
+	m.refreshGroups(now)
+	return m
+}
 
-func (m Monitor) tick() tea.Cmd {
-	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+// adjustTemperatures applies the configured corrections to freshly read
+// temperatures and sorts them
+func (m *Monitor) adjustTemperatures() {
+	m.zoneSensors = m.dropBogus(m.temperatureSensors)
+	m.zoneSensors = m.applyOffsets(m.zoneSensors)
+	m.trackSensorSet(m.clock.Now())
+	m.arrangeTemperatures()
 }
 
-func (m Monitor) updateSensors() Monitor {
-	// Update built-in sensors
-	m.temperatureSensors = ReadTemperatures()
-	m.batteryStatus = ReadBatteryStatus()
-
-	// Refresh extra sensor groups
-	for _, group := range m.extraGroups {
-		for _, sensor := range group.Sensors {
-			_ = sensor.Refresh() // Ignore errors for now
-		}
+// arrangeTemperatures builds the displayed temperatures from the corrected
+// readings: zone clusters are collapsed unless expanded, then thresholds are
+// applied and the list sorted
+func (m *Monitor) arrangeTemperatures() {
+	sensors := m.zoneSensors
+	if !m.expandZones {
+		sensors = collapseZones(sensors)
 	}
-	return m
+	sensors = m.applyOverrides(sensors)
+	sensors = m.applyProfile(sensors)
+	sensors = m.markNew(sensors)
+	sensors = m.markFrozen(sensors)
+	sensors = m.applyMutes(sensors)
+	m.temperatureSensors = m.sortTemperatures(sensors)
 }
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestCompactView(t *testing.T) {
@@ -112,6 +115,74 @@ func TestCompactViewWithExtraGroups(t *testing.T) {
 	}
 }
 
+func TestCompactViewNamesWorstExtraSensor(t *testing.T) {
+	m := NewMonitor()
+	m.RegisterSensorGroup(SensorGroup{Name: "Fans", Sensors: []Sensor{
+		newStaticSensor("fan2", true, false),
+		newStaticSensor("fan1", false, true),
+		newStaticSensor("fan3", false, false),
+	}})
+	output := m.compactView()
+	// The alerts line names critical sensors only
+	if !strings.Contains(output, "✖ fan1") || strings.Contains(output, "more)") {
+		t.Errorf("expected the critical sensor named in compact view:\n%s", output)
+	}
+	if strings.Contains(output, "Extra:") {
+		t.Error("expected the worst sensor instead of the count summary")
+	}
+
+	// Without criticals, the worst warning is named and the others counted
+	m = NewMonitor()
+	m.RegisterSensorGroup(SensorGroup{Name: "Fans", Sensors: []Sensor{
+		newStaticSensor("fan1", false, false),
+		newStaticSensor("fan2", true, false),
+		newStaticSensor("fan3", true, false),
+	}})
+	if output := m.compactView(); !strings.Contains(output, "⚠ fan2") || !strings.Contains(output, "(+1 more)") {
+		t.Errorf("expected the first warning named in compact view:\n%s", output)
+	}
+}
+
+func TestFitWorstSensor(t *testing.T) {
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{0, "⚠ nvme0n1 Composite 71°C (+2 more)"},
+		{30, "⚠ nvme0n1 Composite 71°C"},
+		{16, "⚠ nvme0n1… 71°C"},
+		{8, "⚠ 71°C"},
+	}
+	for _, tt := range tests {
+		if got := fitWorstSensor("⚠", "nvme0n1 Composite", "71°C", " (+2 more)", tt.width); got != tt.want {
+			t.Errorf("width %d: expected %q, got %q", tt.width, tt.want, got)
+		}
+	}
+}
+
+func TestFitWorstSensorWideNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		// "⚠ " + name + " 41°C": the CJK name is 18 cells wide
+		{"磁盘温度传感器读数", 25, "⚠ 磁盘温度传感器读数 41°C"},
+		// 14 cells left: 6 characters and the ellipsis, never half a character
+		{"磁盘温度传感器读数", 21, "⚠ 磁盘温度传感… 41°C"},
+		{"🔥 hotspot", 13, "⚠ 🔥 ho… 41°C"},
+	}
+	for _, tt := range tests {
+		got := fitWorstSensor("⚠", tt.name, "41°C", "", tt.width)
+		if got != tt.want {
+			t.Errorf("%s at width %d: expected %q, got %q", tt.name, tt.width, tt.want, got)
+		}
+		if w := lipgloss.Width(got); w > tt.width {
+			t.Errorf("%s at width %d: line is %d cells wide", tt.name, tt.width, w)
+		}
+	}
+}
+
 func TestViewUsesCompactWhenHeightSmall(t *testing.T) {
 	m := NewMonitor()
 	// Set up some data
@@ -158,3 +229,30 @@ func TestViewUsesFullWhenHeightLarge(t *testing.T) {
 		t.Error("Full view should include 'Battery' header")
 	}
 }
+
+func TestFooterCountdown(t *testing.T) {
+	m := NewMonitor(WithInterval(10 * time.Second))
+	now := m.lastUpdate
+	if got := m.untilRefresh(now.Add(2500 * time.Millisecond)); got != "8s" {
+		t.Errorf("expected 8s left, got %s", got)
+	}
+	if got := m.untilRefresh(now.Add(time.Minute)); got != "0s" {
+		t.Errorf("expected overdue refresh to show 0s, got %s", got)
+	}
+
+	m.width, m.height = 80, 24
+	if !strings.Contains(m.View(), "next in") {
+		t.Error("full view footer should show the countdown")
+	}
+	m.height = compactHeightThreshold - 1
+	if strings.Contains(m.View(), "next in") {
+		t.Error("compact view should not show the countdown")
+	}
+
+	// The countdown sub-tick must not read sensors
+	m.temperatureSensors = []TemperatureSensor{{Name: "fake", Value: 1}}
+	m, _ = m.Update(countdownMsg{})
+	if len(m.temperatureSensors) != 1 || m.temperatureSensors[0].Name != "fake" {
+		t.Error("countdown tick should not refresh sensors")
+	}
+}
@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Muting keeps a sensor on screen while ignoring its alerts, for sensors
+// with meaningless thresholds such as a WiFi module sitting at 75°C with a
+// critical of 80°C. A muted sensor is always OK: it isn't colored, counted,
+// recorded as an event or named on the compact alerts line.
+
+// muteKey names a sensor in the mute lists: a temperature by its key, a
+// group sensor as "Group/name"
+func muteKey(group, name string) string {
+	if group == "" {
+		return name
+	}
+	return group + "/" + name
+}
+
+// mutedByConfig reports whether a pattern of the config's mute list matches
+// the sensor's key or, for temperatures, its name or sysfs path
+func (m Monitor) mutedByConfig(key string, also ...string) bool {
+	return slices.ContainsFunc(m.config.Mute, func(pattern string) bool {
+		return match(pattern, key) || slices.ContainsFunc(also, func(s string) bool { return match(pattern, s) })
+	})
+}
+
+// isMuted reports whether the sensor's alerts are muted, with m in the UI
+// state or by the config
+func (m Monitor) isMuted(key string, also ...string) bool {
+	return m.muted[key] || m.mutedByConfig(key, also...)
+}
+
+// applyMutes marks the muted temperatures. The slice is copied so earlier
+// snapshots keep their values.
+func (m Monitor) applyMutes(sensors []TemperatureSensor) []TemperatureSensor {
+	if len(m.muted) == 0 && len(m.config.Mute) == 0 {
+		return sensors
+	}
+	result := append([]TemperatureSensor(nil), sensors...)
+	for i := range result {
+		result[i].Muted = m.isMuted(result[i].key(), result[i].Name, result[i].Path)
+	}
+	return result
+}
+
+// groupSensorState returns the alert state of a group sensor, OK when muted
+func (m Monitor) groupSensorState(group string, sensor Sensor) State {
+	if m.isMuted(muteKey(group, sensor.Name())) {
+		return StateOK
+	}
+	return sensorState(sensor)
+}
+
+// toggleMute mutes or unmutes the alerts of the sensor shown in the detail
+// view. Sensors muted by the config stay muted.
+func (m Monitor) toggleMute() (Monitor, tea.Cmd) {
+	r, ok := m.selectedRow()
+	if !ok || !m.detail {
+		return m, nil
+	}
+	key, also := m.rowMuteKey(r)
+	if m.mutedByConfig(key, also...) {
+		m.status = "Muted by the config file"
+		return m, nil
+	}
+	if m.muted == nil {
+		m.muted = make(map[string]bool)
+	}
+	if m.muted[key] {
+		delete(m.muted, key)
+		m.status = "Alerts unmuted"
+	} else {
+		m.muted[key] = true
+		m.status = "Alerts muted"
+	}
+	m.arrangeTemperatures()
+	return m.uiStateChanged()
+}
+
+// rowMuteKey returns the mute key of a row and, for temperatures, the name
+// and sysfs path patterns may match as well
+func (m Monitor) rowMuteKey(r row) (key string, also []string) {
+	if r.group < 0 {
+		sensor := m.temperatureSensors[r.index]
+		return sensor.key(), []string{sensor.Name, sensor.Path}
+	}
+	group := m.extraGroups[r.group]
+	return muteKey(group.Name, group.Sensors[r.index].Name()), nil
+}
+
+// validateMute checks the patterns of the config's mute list
+func validateMute(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("mute %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// readingStyle colors a reading by its state, or not at all when muted
+func readingStyle(theme Theme, state State, muted bool) lipgloss.Style {
+	if muted {
+		return lipgloss.NewStyle()
+	}
+	return theme.stateStyle(state)
+}
+
+// muteHelp is the detail view's help for m
+func muteHelp(muted bool) string {
+	if muted {
+		return "m: unmute alerts"
+	}
+	return "m: mute alerts"
+}
+
+// mutedTag marks a muted reading so screenshots don't pass it off as OK
+func mutedTag(muted bool) string {
+	if !muted {
+		return ""
+	}
+	return lipgloss.NewStyle().Faint(true).Render(" muted")
+}
@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestMuteFromDetailView(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	m := NewMonitor(WithUIState(statePath, false))
+	m.width, m.height = 100, 30
+	m.zoneSensors = []TemperatureSensor{
+		{Name: "iwlwifi_1", Value: 75, High: 70, Critical: 80, Path: "/sys/class/hwmon/hwmon4/temp1_input"},
+		{Name: "Package id 0", Value: 50, High: 80, Critical: 100, Path: "/sys/class/hwmon/hwmon2/temp1_input"},
+	}
+	m.arrangeTemperatures()
+	if worst, _ := m.WorstState(); worst != StateWarning {
+		t.Fatalf("expected the WiFi module to warn before muting, got %v", worst)
+	}
+
+	m = sendKeys(m, "j", "enter", "m")
+	if !m.temperatureSensors[0].Muted || m.status != "Alerts muted" {
+		t.Fatalf("expected the selected sensor muted, got %+v (%q)", m.temperatureSensors[0], m.status)
+	}
+	if worst, counts := m.WorstState(); worst != StateOK || counts.Warning != 0 {
+		t.Errorf("expected a muted sensor out of the counts, got %v %+v", worst, counts)
+	}
+	if events := m.transitions(time.Now()); len(events) != 0 {
+		t.Errorf("expected no events for a muted sensor, got %+v", events)
+	}
+	if headroom, _ := m.Snapshot().Headroom(); headroom.Sensor != "Package id 0" {
+		t.Errorf("expected the headroom of the unmuted sensor, got %+v", headroom)
+	}
+	if got := m.UIState().Muted; len(got) != 1 || got[0] != "iwlwifi_1" {
+		t.Errorf("expected the mute in the UI state, got %v", got)
+	}
+
+	// Still shown, tagged, after a refresh re-reads the thresholds
+	m.arrangeTemperatures()
+	m = sendKeys(m, "esc")
+	if out := ansi.Strip(m.View()); !strings.Contains(out, "75.0°C  /sys/class/hwmon/hwmon4/temp1_input muted") {
+		t.Errorf("expected the muted sensor shown with its tag:\n%s", out)
+	}
+
+	m.SaveUIState()
+	restored := NewMonitor(WithUIState(statePath, false))
+	restored.zoneSensors = m.zoneSensors
+	restored.arrangeTemperatures()
+	if !restored.temperatureSensors[0].Muted {
+		t.Error("expected the mute restored from the UI state")
+	}
+
+	m = sendKeys(m, "enter", "m")
+	if m.temperatureSensors[0].Muted || m.temperatureSensors[0].State() != StateWarning {
+		t.Errorf("expected m to unmute, got %+v", m.temperatureSensors[0])
+	}
+}
+
+func TestMuteByConfig(t *testing.T) {
+	m := NewMonitor(WithConfig("", Config{Mute: []string{"Network/wwan*", "/sys/class/hwmon/hwmon4/*"}}))
+	m.width, m.height = 100, 30
+	m.zoneSensors = []TemperatureSensor{{Name: "iwlwifi_1", Value: 85, High: 70, Critical: 80, Path: "/sys/class/hwmon/hwmon4/temp1_input"}}
+	m.arrangeTemperatures()
+	m.RegisterSensorGroup(SensorGroup{Name: "Network", Sensors: []Sensor{
+		newStaticSensor("wwan0 link", false, true),
+		newStaticSensor("eth0 link", true, false),
+	}})
+
+	snap := m.Snapshot()
+	if !snap.Temperatures[0].Muted {
+		t.Error("expected the temperature muted by its path")
+	}
+	readings := snap.Groups[0].Readings
+	if !readings[0].Muted || readings[0].State != StateOK || readings[1].Muted || readings[1].State != StateWarning {
+		t.Errorf("expected only wwan0 muted, got %+v", readings)
+	}
+	if snap.Worst != StateWarning || snap.Counts.Critical != 0 {
+		t.Errorf("expected the muted criticals left out, got %v %+v", snap.Worst, snap.Counts)
+	}
+	if criticals := criticalReadings(snap, ViewState{}); len(criticals) != 0 {
+		t.Errorf("expected no muted sensor on the compact alerts line, got %+v", criticals)
+	}
+
+	m = sendKeys(m, "j", "enter", "m")
+	if m.status != "Muted by the config file" || len(m.muted) != 0 {
+		t.Errorf("expected config mutes to stay, got %q %v", m.status, m.muted)
+	}
+}
+
+func TestLoadConfigRejectsBadMutePattern(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"mute": ["Network/[wwan"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "mute") {
+		t.Errorf("expected a mute pattern error, got %v", err)
+	}
+}
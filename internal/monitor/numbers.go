@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// NumberFormat selects how displayed numbers are written. Readings are
+// formatted with a decimal point everywhere; the renderers localize their
+// display strings, so machine-readable outputs (JSON events, history,
+// Prometheus) and config parsing always use dots.
+type NumberFormat int
+
+const (
+	DecimalPoint NumberFormat = iota // 64.5°C
+	DecimalComma                     // 64,5 °C, with a space before units
+)
+
+// decimalCommaLanguages are the languages writing decimals with a comma
+var decimalCommaLanguages = map[string]bool{
+	"az": true, "be": true, "bg": true, "ca": true, "cs": true, "da": true,
+	"de": true, "el": true, "es": true, "et": true, "eu": true, "fi": true,
+	"fr": true, "gl": true, "hr": true, "hu": true, "id": true, "it": true,
+	"kk": true, "lt": true, "lv": true, "nb": true, "nl": true, "nn": true,
+	"no": true, "pl": true, "pt": true, "ro": true, "ru": true, "sk": true,
+	"sl": true, "sr": true, "sv": true, "tr": true, "uk": true, "vi": true,
+}
+
+// decimalPointRegions are the exceptions of decimalCommaLanguages
+var decimalPointRegions = map[string]bool{
+	"de_CH": true, "it_CH": true, "es_MX": true, "es_US": true,
+}
+
+// ParseNumberFormat returns the number format of a POSIX locale name such as
+// "de_DE.UTF-8"; "C", "POSIX", empty and unknown names use a decimal point
+func ParseNumberFormat(locale string) NumberFormat {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	language, _, _ := strings.Cut(locale, "_")
+	if decimalCommaLanguages[language] && !decimalPointRegions[locale] {
+		return DecimalComma
+	}
+	return DecimalPoint
+}
+
+// NumberFormatFromEnv returns the number format of the locale numbers are
+// formatted in: LC_ALL, LC_NUMERIC or LANG, the first one set
+func NumberFormatFromEnv() NumberFormat {
+	for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			return ParseNumberFormat(locale)
+		}
+	}
+	return DecimalPoint
+}
+
+// WithNumberFormat sets how displayed numbers are written
+func WithNumberFormat(f NumberFormat) Option {
+	return func(m *Monitor) {
+		m.numbers = f
+	}
+}
+
+var (
+	decimalPointPattern = regexp.MustCompile(`(\d)\.(\d)`)
+	// unitPattern matches a number directly followed by a unit, with the
+	// character after the unit so "12V" matches but "12Vcore" doesn't
+	unitPattern = regexp.MustCompile(`(\d)(°[CF]|K|%|Wh|W|V|A)([^\pL\d]|$)`)
+)
+
+// localize rewrites the numbers of a display string in the format:
+// "64.5°C" becomes "64,5 °C". Strings in DecimalPoint are returned as is.
+func (f NumberFormat) localize(s string) string {
+	if f != DecimalComma {
+		return s
+	}
+	s = decimalPointPattern.ReplaceAllString(s, "$1,$2")
+	return unitPattern.ReplaceAllString(s, "$1 $2$3")
+}
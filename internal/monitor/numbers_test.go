@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestParseNumberFormat(t *testing.T) {
+	tests := map[string]NumberFormat{
+		"":            DecimalPoint,
+		"C":           DecimalPoint,
+		"C.UTF-8":     DecimalPoint,
+		"en_US.UTF-8": DecimalPoint,
+		"de_DE.UTF-8": DecimalComma,
+		"de_CH.UTF-8": DecimalPoint,
+		"fr_FR@euro":  DecimalComma,
+		"pt_BR":       DecimalComma,
+	}
+	for locale, want := range tests {
+		if got := ParseNumberFormat(locale); got != want {
+			t.Errorf("ParseNumberFormat(%q) = %v, expected %v", locale, got, want)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	tests := []struct {
+		in, point, comma string
+	}{
+		{"64.5°C", "64.5°C", "64,5 °C"},
+		{"-3.0°F", "-3.0°F", "-3,0 °F"},
+		{"12.60W", "12.60W", "12,60 W"},
+		{"Power: 12.1 W (now 15.9, peak 18.4)", "Power: 12.1 W (now 15.9, peak 18.4)", "Power: 12,1 W (now 15,9, peak 18,4)"},
+		{"85%", "85%", "85 %"},
+		{"Energy: 31.92 Wh", "Energy: 31.92 Wh", "Energy: 31,92 Wh"},
+		{"1200 RPM", "1200 RPM", "1200 RPM"},
+		{"1.2 MiB/s", "1.2 MiB/s", "1,2 MiB/s"},
+		// Names and paths keep their digits
+		{"Vcore 1.20V", "Vcore 1.20V", "Vcore 1,20 V"},
+		{"hwmon0/temp1_input", "hwmon0/temp1_input", "hwmon0/temp1_input"},
+		{"12Vcore", "12Vcore", "12Vcore"},
+	}
+	for _, tt := range tests {
+		if got := DecimalPoint.localize(tt.in); got != tt.point {
+			t.Errorf("DecimalPoint.localize(%q) = %q", tt.in, got)
+		}
+		if got := DecimalComma.localize(tt.in); got != tt.comma {
+			t.Errorf("DecimalComma.localize(%q) = %q, expected %q", tt.in, got, tt.comma)
+		}
+	}
+}
+
+func TestRenderNumberFormats(t *testing.T) {
+	snap := Snapshot{
+		Temperatures: []TemperatureSensor{{Name: "CPU", Value: 64.5, High: 80, Critical: 100, Path: "/sys/class/hwmon/hwmon0/temp1_input"}},
+		Battery:      BatteryStatus{Capacity: 85, Status: "Discharging", Voltage: 12.6, Power: 9.5},
+		Groups: []GroupSnapshot{{Name: "Power", Readings: []SensorReading{
+			{Name: "package", Value: "12.60W", State: StateOK},
+		}}},
+	}
+	for _, tt := range []struct {
+		numbers NumberFormat
+		full    []string
+		compact string
+	}{
+		{DecimalPoint, []string{"  64.5°C", "Capacity: 85%", "Voltage: 12.60V", "Power: 9.50W", "package: 12.60W"}, "🌡 64.5°C | 🔋 85% Discharging 12.60V"},
+		{DecimalComma, []string{"  64,5 °C", "Capacity: 85 %", "Voltage: 12,60 V", "Power: 9,50 W", "package: 12,60 W"}, "🌡 64,5 °C | 🔋 85 % Discharging 12,60 V"},
+	} {
+		view := ViewState{Numbers: tt.numbers}
+		full := ansi.Strip(RenderFull(snap, 120, 30, DefaultTheme, view))
+		for _, want := range tt.full {
+			if !strings.Contains(full, want) {
+				t.Errorf("format %v: expected %q in:\n%s", tt.numbers, want, full)
+			}
+		}
+		if !strings.Contains(full, "hwmon0/temp1_input") {
+			t.Errorf("format %v: expected the path untouched:\n%s", tt.numbers, full)
+		}
+		compact := ansi.Strip(RenderCompact(snap, 80, DefaultTheme, view))
+		if first := strings.Split(compact, "\n")[0]; first != tt.compact {
+			t.Errorf("format %v: expected compact %q, got %q", tt.numbers, tt.compact, first)
+		}
+	}
+
+	// Machine-readable output keeps dots
+	var sb strings.Builder
+	if err := WritePrometheus(&sb, snap); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sb.String(), `sysfs_monitor_temperature_celsius{sensor="CPU"} 64.5`) {
+		t.Errorf("expected dots in the exposition, got:\n%s", sb.String())
+	}
+}
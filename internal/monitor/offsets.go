@@ -0,0 +1,63 @@
+package monitor
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+)
+
+// WithTemperatureOffsets adds corrections to temperature readings, keyed by
+// sensor name or a glob matched against the name or the value file path,
+// e.g. {"Tctl": -10, "/sys/class/hwmon/hwmon3/temp2_input": -8}. Offsets
+// from the config file are applied as well.
+func WithTemperatureOffsets(offsets map[string]float64) Option {
+	return func(m *Monitor) {
+		m.offsets = mergeOffsets(m.offsets, offsets)
+	}
+}
+
+// mergeOffsets returns a copy of base with extra added, so monitors never
+// share a map
+func mergeOffsets(base, extra map[string]float64) map[string]float64 {
+	merged := maps.Clone(base)
+	if merged == nil {
+		merged = make(map[string]float64, len(extra))
+	}
+	maps.Copy(merged, extra)
+	return merged
+}
+
+// offsetFor returns the correction for a sensor. An exact key or name wins;
+// otherwise the first matching pattern in sorted order applies.
+func (m Monitor) offsetFor(sensor TemperatureSensor) (float64, bool) {
+	if offset, ok := lookupSensor(m.offsets, sensor); ok {
+		return offset, true
+	}
+	for _, pattern := range slices.Sorted(maps.Keys(m.offsets)) {
+		if matchSensor(pattern, sensor) {
+			return m.offsets[pattern], true
+		}
+	}
+	return 0, false
+}
+
+func match(pattern, name string) bool {
+	ok, _ := filepath.Match(pattern, name)
+	return ok
+}
+
+// applyOffsets corrects readings before thresholds are evaluated, so the
+// view, alerts, events and snapshots all see the corrected value. The slice
+// is copied so earlier snapshots keep their values.
+func (m Monitor) applyOffsets(sensors []TemperatureSensor) []TemperatureSensor {
+	if len(m.offsets) == 0 {
+		return sensors
+	}
+	result := append([]TemperatureSensor(nil), sensors...)
+	for i := range result {
+		if offset, ok := m.offsetFor(result[i]); ok && !result[i].Asleep {
+			result[i].Value += offset
+		}
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyOffsets(t *testing.T) {
+	m := NewMonitor(
+		WithConfig("", Config{Offsets: map[string]float64{"Tctl": -10}}),
+		WithTemperatureOffsets(map[string]float64{
+			"/sys/class/hwmon/hwmon3/temp*_input": -8,
+			"Core *":                              1,
+		}),
+	)
+	raw := []TemperatureSensor{
+		{Name: "Tctl", Value: 90, High: 85, Critical: 95, Path: "/sys/class/hwmon/hwmon1/temp1_input"},
+		{Name: "SYSTIN", Value: 45, High: 80, Critical: 95, Path: "/sys/class/hwmon/hwmon3/temp2_input"},
+		{Name: "Core 0", Value: 50, Path: "/sys/class/hwmon/hwmon4/temp2_input"},
+		{Name: "acpitz", Value: 30, Path: "/sys/class/thermal/thermal_zone0/temp"},
+	}
+	got := m.applyOffsets(raw)
+	for i, want := range []float64{80, 37, 51, 30} {
+		if got[i].Value != want {
+			t.Errorf("%s: expected %.1f, got %.1f", got[i].Name, want, got[i].Value)
+		}
+	}
+	if raw[0].Value != 90 {
+		t.Error("applyOffsets must not modify its input")
+	}
+	// Thresholds are evaluated against the corrected value
+	if got[0].State() != StateOK {
+		t.Errorf("expected corrected Tctl to be OK, got %v", got[0].State())
+	}
+}
+
+func TestDetailViewShowsOffset(t *testing.T) {
+	m := newDetailMonitor()
+	m.offsets = map[string]float64{"CPU": -10}
+	m.temperatureSensors = m.applyOffsets(m.temperatureSensors)
+	m = sendKeys(m, "down", "enter")
+	if view := m.View(); !strings.Contains(view, "55.0°C (raw 65.0°C, offset -10.0°C)") {
+		t.Errorf("expected raw and corrected values in detail view:\n%s", view)
+	}
+}
@@ -0,0 +1,24 @@
+package monitor
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// sysfsSupported reports whether the platform has a sysfs to read
+const sysfsSupported = true
+
+// clockBoottime is CLOCK_BOOTTIME, the monotonic clock that keeps counting
+// while suspended
+const clockBoottime = 7
+
+// bootTime reads CLOCK_BOOTTIME, so a suspend isn't mistaken for a jump of
+// the wall clock
+func bootTime() (time.Duration, bool) {
+	var ts syscall.Timespec
+	if _, _, errno := syscall.Syscall(syscall.SYS_CLOCK_GETTIME, clockBoottime, uintptr(unsafe.Pointer(&ts)), 0); errno != 0 {
+		return 0, false
+	}
+	return time.Duration(ts.Nano()), true
+}
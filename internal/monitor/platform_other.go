@@ -0,0 +1,15 @@
+//go:build !linux
+
+package monitor
+
+import "time"
+
+// sysfsSupported reports whether the platform has a sysfs to read. Elsewhere
+// every reader finds nothing, and only the demo dataset shows readings.
+const sysfsSupported = false
+
+// bootTime is only read on Linux; elsewhere the monotonic clock counts from
+// the start
+func bootTime() (time.Duration, bool) {
+	return 0, false
+}
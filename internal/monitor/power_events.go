@@ -0,0 +1,148 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// Power events: the charger being plugged in or unplugged, the battery
+// becoming full and its other status changes, such as a charge limit
+// stopping the charge, are recorded in the alert history with the capacity
+// at that moment, and the Battery section counts the time on battery since
+// the last unplug.
+
+// Power event names
+const (
+	PowerPlugged   = "plugged"
+	PowerUnplugged = "unplugged"
+	PowerFull      = "full"
+	// PowerStatus is any other change of the battery's status
+	PowerStatus = "status"
+)
+
+// PowerChange is recorded in the alert history on a power event; Sensor is
+// then "Power"
+type PowerChange struct {
+	// Event is PowerPlugged, PowerUnplugged, PowerFull or PowerStatus
+	Event    string `json:"event"`
+	Capacity int    `json:"capacity"`
+	// From and To are the battery statuses of a PowerStatus event, e.g.
+	// "Charging" and "Not charging"
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Unplugged is the span a plug-in ends, nil when the monitor didn't
+	// see the charger unplugged
+	Unplugged *BatterySpan `json:"unplugged,omitempty"`
+}
+
+// BatterySpan is a time on battery, from the unplug or from the first
+// reading when the monitor started on battery
+type BatterySpan struct {
+	Since time.Time `json:"since"`
+	// Capacity is the capacity at Since
+	Capacity int `json:"capacity"`
+	// Partial is set when the monitor started on battery, so the span
+	// began before Since
+	Partial bool `json:"partial,omitempty"`
+	// Suspended is the time spent suspended since, counted from gaps
+	// between battery readings
+	Suspended time.Duration `json:"suspended,omitzero"`
+}
+
+// describe tells about the change at t, e.g. "unplugged at 09:14 (100%) —
+// plugged at 13:02 (31%), 3h48m on battery"
+func (c PowerChange) describe(t time.Time) string {
+	if c.Event == PowerStatus {
+		return fmt.Sprintf("%s → %s at %s (%d%%)", c.From, c.To, t.Format("15:04"), c.Capacity)
+	}
+	at := fmt.Sprintf("%s at %s (%d%%)", c.Event, t.Format("15:04"), c.Capacity)
+	span := c.Unplugged
+	if span == nil {
+		return at
+	}
+	if span.Partial {
+		return fmt.Sprintf("%s, %s", at, span.describe(t))
+	}
+	return fmt.Sprintf("%s at %s (%d%%) — %s, %s", PowerUnplugged, span.Since.Format("15:04"), span.Capacity, at, span.describe(t))
+}
+
+// describe tells how long the span lasted until t, e.g. "3h48m on battery
+// (52m suspended)"
+func (s BatterySpan) describe(t time.Time) string {
+	d := formatElapsed(t.Sub(s.Since))
+	if s.Partial {
+		d = "≥" + d
+	}
+	if s.Suspended > 0 {
+		return fmt.Sprintf("%s on battery (%s suspended)", d, formatElapsed(s.Suspended))
+	}
+	return d + " on battery"
+}
+
+// counter is the Battery section's live count at now, e.g. "3h48m since
+// 09:14 (100%)"
+func (s BatterySpan) counter(now time.Time) string {
+	d := formatElapsed(now.Sub(s.Since))
+	if s.Partial {
+		d = "≥" + d
+	}
+	text := fmt.Sprintf("%s since %s (%d%%)", d, s.Since.Format("15:04"), s.Capacity)
+	if s.Suspended > 0 {
+		text += fmt.Sprintf(", %s suspended", formatElapsed(s.Suspended))
+	}
+	return text
+}
+
+// trackPower records the power events between two battery readings and
+// keeps the span on battery. A gap of more than three refresh intervals
+// between readings counts as suspended, unless the monitor was paused in
+// between.
+func (m *Monitor) trackPower(prev, cur BatteryStatus, prevRead, now time.Time) {
+	if !cur.Present() {
+		return
+	}
+	if m.onBattery != nil && !prevRead.IsZero() {
+		if gap := now.Sub(prevRead); gap > 3*m.refreshInterval() && !m.resumedAt.After(prevRead) {
+			// Replaced, not changed: snapshots share the span
+			span := *m.onBattery
+			span.Suspended += gap
+			m.onBattery = &span
+		}
+	}
+	if !prev.Present() {
+		// Started on battery: count from now, marked partial
+		if !cur.ACOnline {
+			m.onBattery = &BatterySpan{Since: now, Capacity: cur.Capacity, Partial: true}
+		}
+		return
+	}
+
+	var event string
+	change := PowerChange{Capacity: cur.Capacity}
+	switch {
+	case prev.ACOnline && !cur.ACOnline:
+		event = PowerUnplugged
+		m.onBattery = &BatterySpan{Since: now, Capacity: cur.Capacity}
+	case !prev.ACOnline && cur.ACOnline:
+		event = PowerPlugged
+		change.Unplugged, m.onBattery = m.onBattery, nil
+	case prev.Status != "Full" && cur.Status == "Full":
+		event = PowerFull
+	case prev.Status != "" && cur.Status != prev.Status:
+		// Toasted too (see toast.go), but only the events reach the
+		// stream and other subscribers
+		event = PowerStatus
+		change.From, change.To = prev.Status, cur.Status
+	default:
+		return
+	}
+	change.Event = event
+	m.pending = append(m.pending, Event{
+		Time:   now,
+		Host:   m.hostname,
+		Type:   EventPowerChanged,
+		Sensor: "Power",
+		Value:  change.describe(now),
+		Power:  &change,
+	})
+}
@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPowerEvents(t *testing.T) {
+	m := NewMonitor(WithClock(&fakeClock{}))
+	m.width, m.height = 80, 30
+	unplug := time.Date(2026, 3, 2, 9, 14, 0, 0, time.UTC)
+	read := func(at time.Time, capacity int, status string, ac bool) {
+		m.setBattery(BatteryStatus{Capacity: capacity, Status: status, ACOnline: ac}, at)
+		m.record(m.pending)
+		m.pending = nil
+	}
+
+	read(unplug.Add(-2*time.Second), 100, "Full", true)
+	if m.onBattery != nil || len(m.history) != 0 {
+		t.Fatalf("expected nothing on AC at startup, got %+v %v", m.onBattery, m.history)
+	}
+	read(unplug, 100, "Discharging", false)
+	read(unplug.Add(time.Hour), 80, "Discharging", false)
+	// Paused for an hour, then suspended for an hour
+	m.resumedAt = unplug.Add(2 * time.Hour)
+	read(unplug.Add(2*time.Hour), 60, "Discharging", false)
+	read(unplug.Add(3*time.Hour), 50, "Discharging", false)
+
+	snap := m.Snapshot()
+	if span := snap.OnBattery; span == nil || span.Suspended != 2*time.Hour || span.Capacity != 100 {
+		t.Fatalf("expected the two hours of gaps without the pause as suspended, got %+v", span)
+	}
+	pane := batteryPane(snap, DefaultTheme, ViewState{}, unplug.Add(3*time.Hour+48*time.Minute))
+	if want := "On battery: 3h48m since 09:14 (100%), 2h0m suspended"; !strings.Contains(pane, want) {
+		t.Errorf("expected %q in the battery section:\n%s", want, pane)
+	}
+
+	// Suspended again until plugged in
+	read(unplug.Add(3*time.Hour+48*time.Minute), 31, "Charging", true)
+	read(unplug.Add(5*time.Hour), 100, "Full", true)
+	if m.Snapshot().OnBattery != nil {
+		t.Errorf("expected the counter reset on plug-in")
+	}
+	// A charge limit holding the battery is a status change on AC
+	read(unplug.Add(6*time.Hour), 95, "Not charging", true)
+	var values []string
+	for _, event := range m.history {
+		if event.Sensor != "Power" || event.Power == nil {
+			t.Fatalf("expected only power events, got %+v", event)
+		}
+		values = append(values, event.Value.(string))
+	}
+	want := []string{
+		"unplugged at 09:14 (100%)",
+		"unplugged at 09:14 (100%) — plugged at 13:02 (31%), 3h48m on battery (2h48m suspended)",
+		"full at 14:14 (100%)",
+		"Full → Not charging at 15:14 (95%)",
+	}
+	if strings.Join(values, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected the power events:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(values, "\n"))
+	}
+
+	m.showAlerts = true
+	if view := m.alertsView(); !strings.Contains(view, "13:02:00 Power                    unplugged at 09:14") {
+		t.Errorf("expected the plug-in in the alert history:\n%s", view)
+	}
+}
+
+func TestPowerEventsStartedOnBattery(t *testing.T) {
+	m := NewMonitor(WithClock(&fakeClock{}))
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	m.setBattery(BatteryStatus{Capacity: 70, Status: "Discharging"}, start)
+	if span := m.onBattery; span == nil || !span.Partial {
+		t.Fatalf("expected a partial span from the first reading, got %+v", span)
+	}
+	m.setBattery(BatteryStatus{Capacity: 70, Status: "Charging", ACOnline: true}, start.Add(4*time.Second))
+	if len(m.pending) != 1 || m.pending[0].Value != "plugged at 09:00 (70%), ≥4s on battery" {
+		t.Errorf("expected the plug-in to tell the time is a lower bound, got %+v", m.pending)
+	}
+}
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultPreloadTimeout bounds how long NewMonitor waits for the first
+// refresh with WithSynchronousFirstRefresh
+const DefaultPreloadTimeout = 2 * time.Second
+
+// WithSynchronousFirstRefresh makes NewMonitor discover the sensors and
+// refresh once before returning, so the first frame shows readings instead
+// of an empty monitor until the first tick. A refresh taking longer than
+// DefaultPreloadTimeout, such as one stuck on a hung sysfs file, is left to
+// finish in the background: the monitor starts without readings, shows
+// "Reading sensors…" and schedules its ticks once it is done. Waiting for
+// sensors with WithSensorWait takes precedence.
+func WithSynchronousFirstRefresh() Option {
+	return func(m *Monitor) {
+		m.preloadTimeout = DefaultPreloadTimeout
+	}
+}
+
+// preloadMsg delivers a first refresh that outlasted the preload timeout.
+// from tells the monitors of a program apart, e.g. several widgets.
+type preloadMsg struct {
+	mon  Monitor
+	from chan Monitor
+}
+
+// preloadFirstRefresh runs the first refresh for NewMonitor. The timeout is
+// in real time, since it bounds the program's startup.
+func (m Monitor) preloadFirstRefresh() Monitor {
+	done := make(chan Monitor, 1)
+	go func(m Monitor) {
+		done <- m.Refresh()
+	}(m)
+	timer := time.NewTimer(m.preloadTimeout)
+	defer timer.Stop()
+	select {
+	case refreshed := <-done:
+		return refreshed
+	case <-timer.C:
+		m.preload = done
+		return m
+	}
+}
+
+// preloading reports whether the first refresh is still running in the
+// background. Nothing else may refresh or read sensors meanwhile, since it
+// shares the readers.
+func (m Monitor) preloading() bool {
+	return m.preload != nil
+}
+
+// awaitPreload delivers the first refresh once it is done
+func (m Monitor) awaitPreload() tea.Cmd {
+	done := m.preload
+	return func() tea.Msg {
+		return preloadMsg{mon: <-done, from: done}
+	}
+}
+
+// handlePreload takes over the readings of a late first refresh, keeping
+// the terminal size received meanwhile, and starts the ticks
+func (m Monitor) handlePreload(msg preloadMsg) (Monitor, tea.Cmd) {
+	if !m.preloading() || msg.from != m.preload {
+		return m, nil
+	}
+	refreshed := msg.mon
+	refreshed.width, refreshed.height = m.width, m.height
+	refreshed.preload = nil
+	return refreshed, tea.Batch(refreshed.tick(), refreshed.emitSnapshot())
+}
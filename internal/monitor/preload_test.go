@@ -0,0 +1,92 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSynchronousFirstRefresh(t *testing.T) {
+	m := NewMonitor(WithDemo(DefaultDemoSeed), WithSynchronousFirstRefresh())
+	if len(m.temperatureSensors) == 0 || !m.discovered {
+		t.Fatal("expected NewMonitor to return with readings")
+	}
+	if m.preloading() {
+		t.Fatal("expected the refresh done within the timeout")
+	}
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	if view := m.View(); !strings.Contains(view, "demo/temp1") {
+		t.Errorf("expected the first frame to show readings, got:\n%s", view)
+	}
+	// The preloaded snapshot is published with the first tick scheduled
+	if cmds := runBatch(m.start()); len(cmds) != 2 {
+		t.Errorf("expected a tick and the snapshot, got %d commands", len(cmds))
+	}
+
+	if lazy := NewMonitor(WithDemo(DefaultDemoSeed)); lazy.discovered {
+		t.Error("expected no refresh before the first tick by default")
+	}
+}
+
+// runBatch returns the commands of a tea.Batch without running them
+func runBatch(cmd tea.Cmd) []tea.Cmd {
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		return batch
+	}
+	return []tea.Cmd{cmd}
+}
+
+func TestSynchronousFirstRefreshTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	slow := NewGenericSensor("hung", func() (string, bool, bool, error) {
+		<-release
+		return "ok", false, false, nil
+	})
+	start := time.Now()
+	m := NewMonitor(WithDemo(DefaultDemoSeed), WithSynchronousFirstRefresh(), func(m *Monitor) {
+		m.preloadTimeout = 20 * time.Millisecond
+		m.extraGroups = []SensorGroup{{Name: "Slow", Sensors: []Sensor{slow}}}
+	})
+	if time.Since(start) > time.Second {
+		t.Fatal("expected the timeout to bound NewMonitor")
+	}
+	if !m.preloading() || m.discovered {
+		t.Fatal("expected the monitor to start without readings")
+	}
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	if view := m.View(); view != "Reading sensors…" {
+		t.Errorf("expected the refresh still running, got %q", view)
+	}
+	// Ticks and keys wait for the refresh, which uses the same readers
+	if m, cmd := m.Update(tickMsg{due: m.nextRefresh}); cmd != nil || m.discovered {
+		t.Error("expected no tick while the first refresh runs")
+	}
+	if m, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}); m.showAlerts {
+		t.Error("expected keys ignored while the first refresh runs")
+	}
+
+	close(release)
+	msg := m.start()()
+	// Another monitor of the program, e.g. a widget, leaves it alone
+	other := NewMonitor(WithDemo(DefaultDemoSeed))
+	if other, _ = other.Update(msg); other.discovered {
+		t.Error("expected the refresh taken only by its monitor")
+	}
+	m, cmd := m.Update(msg)
+	if m.preloading() || !m.discovered || len(m.temperatureSensors) == 0 || cmd == nil {
+		t.Fatal("expected the late refresh taken over and the ticks started")
+	}
+	if m.width != 100 {
+		t.Errorf("expected the terminal size kept, got width %d", m.width)
+	}
+}
+
+func TestSynchronousFirstRefreshAfterSensorWait(t *testing.T) {
+	root := t.TempDir()
+	m := NewMonitor(WithSensorWait(time.Minute), WithSynchronousFirstRefresh(), func(m *Monitor) { m.waitRoot = root })
+	if m.discovered || m.preloading() || !m.waiting() {
+		t.Error("expected waiting for sensors to replace the preload")
+	}
+}
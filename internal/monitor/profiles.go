@@ -0,0 +1,156 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultProfileName is shown when no profile's rules match
+const defaultProfileName = "default"
+
+// Profile adjusts thresholds while its activation rules hold. Profiles are
+// evaluated in config order on every refresh and the first match applies.
+type Profile struct {
+	Name string `json:"name"`
+
+	// Hours is a local time range such as "22:00-07:00"; ranges may wrap
+	// past midnight. Empty means any time.
+	Hours string `json:"hours,omitempty"`
+	// Power is "battery" or "ac"; empty means either
+	Power string `json:"power,omitempty"`
+
+	// BatteryThresholds replace the capacity thresholds
+	BatteryThresholds *BatteryThresholds `json:"battery_thresholds,omitempty"`
+	// HighOffset is added to every temperature's High threshold, e.g. -10
+	// to warn earlier on battery
+	HighOffset float64 `json:"high_offset,omitempty"`
+}
+
+// ProfileChange is recorded in the alert history when the active profile
+// switches
+type ProfileChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// parseHours returns the start and end of an "HH:MM-HH:MM" range in minutes
+// after midnight
+func parseHours(s string) (start, end int, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("hours %q: expected HH:MM-HH:MM", s)
+	}
+	parse := func(hm string) (int, error) {
+		t, err := time.Parse("15:04", strings.TrimSpace(hm))
+		if err != nil {
+			return 0, fmt.Errorf("hours %q: %w", s, err)
+		}
+		return t.Hour()*60 + t.Minute(), nil
+	}
+	if start, err = parse(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parse(to); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+func (p Profile) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("profile without a name")
+	}
+	if p.Hours != "" {
+		if _, _, err := parseHours(p.Hours); err != nil {
+			return fmt.Errorf("profile %s: %w", p.Name, err)
+		}
+	}
+	if p.Power != "" && p.Power != "battery" && p.Power != "ac" {
+		return fmt.Errorf("profile %s: power must be \"battery\" or \"ac\", got %q", p.Name, p.Power)
+	}
+	return nil
+}
+
+// matches reports whether the profile's rules hold at now for the battery
+func (p Profile) matches(now time.Time, bat BatteryStatus) bool {
+	if p.Hours != "" {
+		start, end, err := parseHours(p.Hours)
+		if err != nil {
+			return false
+		}
+		minute := now.Hour()*60 + now.Minute()
+		if start <= end && (minute < start || minute >= end) {
+			return false
+		}
+		if start > end && minute < start && minute >= end {
+			return false
+		}
+	}
+	switch p.Power {
+	case "battery":
+		return bat.Present() && !bat.ACOnline
+	case "ac":
+		return bat.ACOnline
+	}
+	return true
+}
+
+// selectProfile activates the first matching profile, queueing a history
+// entry when the active profile changes
+func (m *Monitor) selectProfile(now time.Time) {
+	var active *Profile
+	for i := range m.config.Profiles {
+		if m.config.Profiles[i].matches(now, m.batteryStatus) {
+			active = &m.config.Profiles[i]
+			break
+		}
+	}
+	from, to := m.profileName(), defaultProfileName
+	if active != nil {
+		to = active.Name
+	}
+	m.profile = active
+	if from != to {
+		m.pending = append(m.pending, Event{
+			Time:    now,
+			Host:    m.hostname,
+			Type:    EventProfileChanged,
+			Sensor:  "Profile",
+			Value:   to,
+			Profile: &ProfileChange{From: from, To: to},
+		})
+	}
+}
+
+// profileName returns the active profile's name
+func (m Monitor) profileName() string {
+	if m.profile == nil {
+		return defaultProfileName
+	}
+	return m.profile.Name
+}
+
+// currentBatteryThresholds returns the capacity thresholds of the active
+// profile, or the configured ones
+func (m Monitor) currentBatteryThresholds() BatteryThresholds {
+	if m.profile != nil && m.profile.BatteryThresholds != nil {
+		return *m.profile.BatteryThresholds
+	}
+	return m.batteryThresholds
+}
+
+// applyProfile shifts High thresholds by the active profile's offset. The
+// slice is copied so earlier snapshots keep their values.
+func (m Monitor) applyProfile(sensors []TemperatureSensor) []TemperatureSensor {
+	if m.profile == nil || m.profile.HighOffset == 0 {
+		return sensors
+	}
+	result := append([]TemperatureSensor(nil), sensors...)
+	for i := range result {
+		if result[i].High > 0 {
+			result[i].High += m.profile.HighOffset
+		}
+	}
+	return result
+}
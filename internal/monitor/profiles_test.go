@@ -0,0 +1,87 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProfileMatches(t *testing.T) {
+	onBattery := BatteryStatus{Capacity: 60, Status: "Discharging"}
+	onAC := BatteryStatus{Capacity: 60, Status: "Charging", ACOnline: true}
+	at := func(hm string) time.Time {
+		tm, _ := time.Parse("15:04", hm)
+		return time.Date(2026, 1, 1, tm.Hour(), tm.Minute(), 0, 0, time.Local)
+	}
+	tests := []struct {
+		profile Profile
+		now     string
+		bat     BatteryStatus
+		want    bool
+	}{
+		{Profile{Hours: "22:00-07:00"}, "23:30", onAC, true},
+		{Profile{Hours: "22:00-07:00"}, "06:59", onAC, true},
+		{Profile{Hours: "22:00-07:00"}, "07:00", onAC, false},
+		{Profile{Hours: "22:00-07:00"}, "12:00", onAC, false},
+		{Profile{Hours: "09:00-17:00"}, "12:00", onAC, true},
+		{Profile{Hours: "09:00-17:00"}, "08:00", onAC, false},
+		{Profile{Power: "battery"}, "12:00", onBattery, true},
+		{Profile{Power: "battery"}, "12:00", onAC, false},
+		{Profile{Power: "battery"}, "12:00", BatteryStatus{}, false},
+		{Profile{Power: "ac"}, "12:00", onAC, true},
+		{Profile{Hours: "22:00-07:00", Power: "battery"}, "23:00", onAC, false},
+	}
+	for _, tt := range tests {
+		if got := tt.profile.matches(at(tt.now), tt.bat); got != tt.want {
+			t.Errorf("%+v at %s: expected %v, got %v", tt.profile, tt.now, tt.want, got)
+		}
+	}
+}
+
+func TestProfileSwitchAdjustsThresholds(t *testing.T) {
+	cfg := Config{Profiles: []Profile{{
+		Name:              "on-battery",
+		Power:             "battery",
+		BatteryThresholds: &BatteryThresholds{Warning: 70, Critical: 30},
+		HighOffset:        -10,
+	}}}
+	m := NewMonitor(WithConfig("", cfg))
+	m.width, m.height = 80, 24
+	now := time.Now()
+
+	m.batteryStatus = BatteryStatus{Capacity: 60, Status: "Discharging"}
+	m.selectProfile(now)
+	if m.profileName() != "on-battery" {
+		t.Fatalf("expected on-battery profile, got %q", m.profileName())
+	}
+	if m.batteryCapacityState() != StateWarning {
+		t.Error("expected the profile's 70% warning threshold to apply")
+	}
+	sensors := m.applyProfile([]TemperatureSensor{{Name: "CPU", Value: 75, High: 80, Critical: 100}})
+	if sensors[0].State() != StateWarning {
+		t.Error("expected the lowered High threshold to warn at 75°C")
+	}
+	if !strings.Contains(m.View(), "profile: on-battery") {
+		t.Error("expected the active profile in the footer")
+	}
+
+	m.batteryStatus.ACOnline = true
+	m.selectProfile(now)
+	m.record(m.pending)
+	history := m.AlertHistory()
+	if len(history) != 2 || history[0].Profile.To != "on-battery" || history[1].Profile.From != "on-battery" || history[1].Profile.To != "default" {
+		t.Errorf("expected both profile switches in the alert history, got %+v", history)
+	}
+}
+
+func TestLoadConfigRejectsBadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"profiles": [{"name": "night", "hours": "22-7"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("expected an error for malformed hours")
+	}
+}
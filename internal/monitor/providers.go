@@ -0,0 +1,221 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Provider discovers a family of sensors under a sysfs root (normally
+// "/sys"). Code embedding the monitor can register its own providers with
+// RegisterProvider before calling NewMonitor.
+type Provider interface {
+	Name() string
+	Discover(root string) ([]SensorGroup, error)
+}
+
+// providers is the registry, in registration order, which is also the order
+// discovered groups are shown in
+var providers []Provider
+
+// columnProviders feed the dedicated temperature and battery columns
+// instead of extra groups. Their Discover still returns groups, for code
+// using the registry directly; the monitor only honors them being disabled.
+var columnProviders = map[string]bool{
+	"thermal": true,
+	"battery": true,
+}
+
+func init() {
+	RegisterProvider(NewProvider("thermal", func(root string) ([]SensorGroup, error) {
+		return createSensorGroups(root, readTemperatures(root), BatteryStatus{}), nil
+	}))
+	RegisterProvider(NewProvider("battery", func(root string) ([]SensorGroup, error) {
+		return createSensorGroups(root, nil, readBatteryStatus(root)), nil
+	}))
+	RegisterProvider(NewProvider("backlight", func(root string) ([]SensorGroup, error) {
+		if backlights := readBacklights(root); len(backlights) > 0 {
+			return []SensorGroup{{Name: "Display", Sensors: backlights}}, nil
+		}
+		return nil, nil
+	}))
+	RegisterProvider(NewProvider("fans", func(root string) ([]SensorGroup, error) {
+		if fans := readFans(root); len(fans) > 0 {
+			return []SensorGroup{{Name: fansGroupName, Sensors: fans}}, nil
+		}
+		return nil, nil
+	}))
+	RegisterProvider(NewProvider("pwm", func(root string) ([]SensorGroup, error) {
+		return readPWMGroups(root), nil
+	}))
+	RegisterProvider(NewProvider("voltages", func(root string) ([]SensorGroup, error) {
+		if rails := readVoltages(root); len(rails) > 0 {
+			return []SensorGroup{{Name: voltageGroupName, Sensors: rails}}, nil
+		}
+		return nil, nil
+	}))
+	RegisterProvider(NewProvider("currents", func(root string) ([]SensorGroup, error) {
+		if currents := readCurrents(root); len(currents) > 0 {
+			return []SensorGroup{{Name: currentGroupName, Sensors: currents}}, nil
+		}
+		return nil, nil
+	}))
+	RegisterProvider(NewProvider("power", func(root string) ([]SensorGroup, error) {
+		return readPowerGroups(root), nil
+	}))
+	RegisterProvider(NewProvider("humidity", func(root string) ([]SensorGroup, error) {
+		if humidity := readHumidity(root); len(humidity) > 0 {
+			return []SensorGroup{{Name: humidityGroupName, Sensors: humidity}}, nil
+		}
+		return nil, nil
+	}))
+	RegisterProvider(NewProvider("chassis", func(root string) ([]SensorGroup, error) {
+		if alarms := readChassisAlarms(root); len(alarms) > 0 {
+			return []SensorGroup{{Name: chassisGroupName, Sensors: alarms}}, nil
+		}
+		return nil, nil
+	}))
+	RegisterProvider(NewProvider("platform_profile", func(root string) ([]SensorGroup, error) {
+		if profile := readPlatformProfile(root); profile != nil {
+			return []SensorGroup{{Name: "Platform", Sensors: []Sensor{profile}}}, nil
+		}
+		return nil, nil
+	}))
+}
+
+// RegisterProvider adds a provider to the registry. It panics if a provider
+// with the same name is already registered, like database/sql.Register.
+func RegisterProvider(p Provider) {
+	if slices.ContainsFunc(providers, func(q Provider) bool { return q.Name() == p.Name() }) {
+		panic("monitor: provider registered twice: " + p.Name())
+	}
+	providers = append(providers, p)
+}
+
+// ProviderNames returns the names of the registered providers
+func ProviderNames() []string {
+	names := make([]string, len(providers))
+	for i, p := range providers {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// funcProvider implements Provider with a discovery function
+type funcProvider struct {
+	name     string
+	discover func(root string) ([]SensorGroup, error)
+}
+
+// NewProvider returns a Provider calling discover
+func NewProvider(name string, discover func(root string) ([]SensorGroup, error)) Provider {
+	return funcProvider{name: name, discover: discover}
+}
+
+func (p funcProvider) Name() string {
+	return p.name
+}
+
+func (p funcProvider) Discover(root string) ([]SensorGroup, error) {
+	return p.discover(root)
+}
+
+// WithoutProviders disables the named providers. Disabling "thermal" or
+// "battery" hides the temperature or battery column.
+func WithoutProviders(names ...string) Option {
+	return func(m *Monitor) {
+		if m.disabledProviders == nil {
+			m.disabledProviders = make(map[string]bool)
+		}
+		for _, name := range names {
+			m.disabledProviders[name] = true
+		}
+	}
+}
+
+func (m Monitor) providerEnabled(name string) bool {
+	return !m.disabledProviders[name]
+}
+
+// discoverGroups runs the enabled providers under root and adds their
+// groups. A failing provider doesn't stop the others; its error is kept for
+// DiscoveryErrors and shown in the status line.
+func (m *Monitor) discoverGroups(root string) {
+	var failures []string
+	for _, p := range providers {
+		if columnProviders[p.Name()] || !m.providerEnabled(p.Name()) {
+			continue
+		}
+		groups, err := p.Discover(root)
+		if err != nil {
+			if m.discoveryErrors == nil {
+				m.discoveryErrors = make(map[string]error)
+			}
+			m.discoveryErrors[p.Name()] = err
+			// Degraded mode summarizes permission failures instead
+			if m.degraded {
+				_, rest := permissionGaps(root, []error{err})
+				err = errors.Join(rest...)
+			}
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", p.Name(), err))
+			}
+		}
+		if renderer, ok := p.(GroupRenderer); ok {
+			for i := range groups {
+				if groups[i].Renderer == nil {
+					groups[i].Renderer = renderer
+				}
+			}
+		}
+		m.extraGroups = append(m.extraGroups, m.excludeSensors(groups)...)
+	}
+	if len(failures) > 0 {
+		m.status = "Discovery failed: " + strings.Join(failures, "; ")
+	}
+}
+
+// DiscoveryErrors returns the error of each provider whose discovery failed,
+// keyed by provider name
+func (m Monitor) DiscoveryErrors() map[string]error {
+	return m.discoveryErrors
+}
+
+// ProviderCheck is what a provider discovered, each group refreshed once
+type ProviderCheck struct {
+	Provider string
+	Groups   []GroupSnapshot
+	Err      error // discovery error
+	// Denied are the read failures of the readings degraded mode hides
+	Denied []error
+}
+
+// CheckProviders discovers and refreshes the groups of the named providers
+// under /sys, or of every provider without names, in registry order. It
+// backs sysfs-check, which prints any provider's readings without knowing
+// it. The thermal and battery providers are accepted but left out: callers
+// print their richer readings themselves. Unknown names are an error.
+func CheckProviders(names ...string) ([]ProviderCheck, error) {
+	return checkProviders(sysfsRoot, names)
+}
+
+func checkProviders(root string, names []string) ([]ProviderCheck, error) {
+	for _, name := range names {
+		if !slices.Contains(ProviderNames(), name) {
+			return nil, fmt.Errorf("unknown provider %q (known: %s)", name, strings.Join(ProviderNames(), ", "))
+		}
+	}
+	var checks []ProviderCheck
+	for _, p := range providers {
+		if columnProviders[p.Name()] || len(names) > 0 && !slices.Contains(names, p.Name()) {
+			continue
+		}
+		groups, err := p.Discover(root)
+		m := NewMonitor()
+		m.extraGroups = groups
+		m.refreshGroups(m.clock.Now())
+		checks = append(checks, ProviderCheck{Provider: p.Name(), Groups: m.Snapshot().Groups, Err: err, Denied: m.deniedErrors()})
+	}
+	return checks, nil
+}
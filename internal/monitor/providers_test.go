@@ -0,0 +1,162 @@
+package monitor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func writeProviderFixture(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/backlight/intel_backlight/brightness":     "500\n",
+		"class/backlight/intel_backlight/max_brightness": "1000\n",
+		"firmware/acpi/platform_profile":                 "balanced\n",
+		"firmware/acpi/platform_profile_choices":         "low-power balanced performance\n",
+	})
+	return root
+}
+
+func groupNames(groups []SensorGroup) []string {
+	var names []string
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	return names
+}
+
+func TestDiscoverGroupsSkipsDisabledProviders(t *testing.T) {
+	root := writeProviderFixture(t)
+
+	m := NewMonitor()
+	m.discoverGroups(root)
+	if got := strings.Join(groupNames(m.extraGroups), ","); got != "Display,Platform" {
+		t.Errorf("expected Display and Platform groups, got %q", got)
+	}
+
+	m = NewMonitor(WithConfig("", Config{DisabledProviders: []string{"backlight"}}))
+	m.discoverGroups(root)
+	if got := strings.Join(groupNames(m.extraGroups), ","); got != "Platform" {
+		t.Errorf("expected only Platform with backlight disabled, got %q", got)
+	}
+}
+
+func TestDiscoverGroupsCollectsErrors(t *testing.T) {
+	saved := providers
+	t.Cleanup(func() { providers = saved })
+	providers = append([]Provider{NewProvider("broken", func(string) ([]SensorGroup, error) {
+		return nil, errors.New("no such device")
+	})}, saved...)
+
+	m := NewMonitor()
+	m.discoverGroups(writeProviderFixture(t))
+	if got := strings.Join(groupNames(m.extraGroups), ","); got != "Display,Platform" {
+		t.Errorf("a failing provider shouldn't stop the others, got %q", got)
+	}
+	if err := m.DiscoveryErrors()["broken"]; err == nil || err.Error() != "no such device" {
+		t.Errorf("expected the broken provider's error, got %v", err)
+	}
+	if !strings.Contains(m.status, "broken: no such device") {
+		t.Errorf("expected the error in the status line, got %q", m.status)
+	}
+}
+
+func TestRegisterProviderRejectsDuplicates(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic registering thermal twice")
+		}
+	}()
+	RegisterProvider(NewProvider("thermal", func(string) ([]SensorGroup, error) { return nil, nil }))
+}
+
+func TestColumnProvidersDiscoverGroups(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/thermal/thermal_zone0/type": "x86_pkg_temp\n",
+		"class/thermal/thermal_zone0/temp": "45000\n",
+	})
+	for _, p := range providers {
+		if p.Name() != "thermal" {
+			continue
+		}
+		groups, err := p.Discover(root)
+		if err != nil || len(groups) != 1 || groups[0].Name != "Temperatures" {
+			t.Fatalf("expected a Temperatures group, got %v, %v", groupNames(groups), err)
+		}
+		if v := groups[0].Sensors[0].Value(); v != "45.0°C" {
+			t.Errorf("expected 45.0°C, got %q", v)
+		}
+		return
+	}
+	t.Fatal("thermal provider not registered")
+}
+
+func TestCheckProviders(t *testing.T) {
+	saved := providers
+	t.Cleanup(func() { providers = saved })
+	providers = append(saved[:len(saved):len(saved)], NewProvider("fake", func(string) ([]SensorGroup, error) {
+		return []SensorGroup{{Name: "Fake", Sensors: []Sensor{
+			newStaticSensor("ok", false, false),
+			newStaticSensor("hot", true, false),
+			NewGenericSensor("gone", func() (string, bool, bool, error) { return "", false, false, errors.New("no such device") }),
+		}}}, errors.New("partly discovered")
+	}))
+
+	root := writeProviderFixture(t)
+	checks, err := checkProviders(root, []string{"thermal", "fake"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 1 || checks[0].Provider != "fake" || checks[0].Err == nil {
+		t.Fatalf("expected only the fake provider with its error, got %+v", checks)
+	}
+	readings := checks[0].Groups[0].Readings
+	if readings[1].State != StateWarning || readings[2].Err != "no such device" {
+		t.Errorf("expected refreshed readings with states and errors, got %+v", readings)
+	}
+
+	all, _ := checkProviders(root, nil)
+	var names []string
+	for _, check := range all {
+		names = append(names, check.Provider)
+	}
+	if got := strings.Join(names, ","); got != "backlight,fans,pwm,voltages,currents,power,humidity,chassis,platform_profile,fake" {
+		t.Errorf("expected every group provider in registry order, got %q", got)
+	}
+	if _, err := checkProviders(root, []string{"nope"}); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
+
+// tableProvider draws every group it discovers as a pool table
+type tableProvider struct {
+	Provider
+	poolTable
+}
+
+func TestProviderRendersItsGroups(t *testing.T) {
+	saved := providers
+	t.Cleanup(func() { providers = saved })
+	own := newPoolGroup()
+	plain := SensorGroup{Name: "Plain", Sensors: own.Sensors}
+	providers = []Provider{tableProvider{
+		Provider: NewProvider("zfs", func(string) ([]SensorGroup, error) {
+			return []SensorGroup{plain, own}, nil
+		}),
+		poolTable: poolTable{pools: own.Sensors[:1]},
+	}}
+
+	m := NewMonitor()
+	m.discoverGroups(t.TempDir())
+	if len(m.extraGroups) != 2 {
+		t.Fatalf("expected both groups, got %q", groupNames(m.extraGroups))
+	}
+	if got, ok := m.extraGroups[0].Renderer.(tableProvider); !ok || len(got.pools) != 1 {
+		t.Errorf("expected the provider to render a group without a renderer, got %T", m.extraGroups[0].Renderer)
+	}
+	if got, ok := m.extraGroups[1].Renderer.(poolTable); !ok || len(got.pools) != 2 {
+		t.Errorf("expected the group's own renderer kept, got %T", m.extraGroups[1].Renderer)
+	}
+}
@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Parts of the display refresh at their own rates: scripts run on their own
+// intervals, failing groups back off and battery events refresh the battery
+// between ticks. Each part remembers when its readings were taken, so the
+// view never passes older readings off as the last refresh's.
+
+// markGroupRead records that the named group's readings were taken at t
+func (m *Monitor) markGroupRead(name string, t time.Time) {
+	if m.groupsRead == nil {
+		m.groupsRead = make(map[string]time.Time)
+	}
+	m.groupsRead[name] = t
+}
+
+// readTimes returns the times the snapshot's parts were read, skipping those
+// never read and an absent battery
+func (s Snapshot) readTimes() []time.Time {
+	var times []time.Time
+	if !s.TemperaturesTime.IsZero() {
+		times = append(times, s.TemperaturesTime)
+	}
+	if !s.BatteryTime.IsZero() && s.Battery.Present() {
+		times = append(times, s.BatteryTime)
+	}
+	for _, group := range s.Groups {
+		if !group.Time.IsZero() {
+			times = append(times, group.Time)
+		}
+	}
+	return times
+}
+
+// Oldest returns when the oldest readings of the snapshot were taken, Time
+// when no part tells
+func (s Snapshot) Oldest() time.Time {
+	oldest := s.Time
+	for _, t := range s.readTimes() {
+		if t.Before(oldest) {
+			oldest = t
+		}
+	}
+	return oldest
+}
+
+// diverged reports whether a part of the snapshot lags its refresh by more
+// than interval, so the parts get their own age instead of a common time
+func (s Snapshot) diverged(interval time.Duration) bool {
+	return s.Time.Sub(s.Oldest()) > interval
+}
+
+// ageMarker returns a section header's faint " (read 42s ago)" when the snapshot
+// diverged and the section's readings, taken at t, are among the lagging
+// ones; "" otherwise
+func ageMarker(snap Snapshot, t time.Time, interval time.Duration, now time.Time) string {
+	if t.IsZero() || !snap.diverged(interval) || snap.Time.Sub(t) <= interval {
+		return ""
+	}
+	return " " + lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("(read %s ago)", now.Sub(t).Round(time.Second)))
+}
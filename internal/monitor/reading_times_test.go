@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestReadingTimesFollowGroupRates(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	start := clock.now
+	m := NewMonitor(WithClock(clock), WithInterval(2*time.Second), WithHostname("box1"))
+	m.discovered = true
+	m.disabledProviders = map[string]bool{"thermal": true, "battery": true}
+	m.width, m.height = 80, 24
+	failing := false
+	m.RegisterSensorGroup(SensorGroup{Name: "Fast", Sensors: []Sensor{
+		NewGenericSensor("load", func() (string, bool, bool, error) { return "1.0", false, false, nil }),
+	}})
+	m.RegisterSensorGroup(SensorGroup{Name: "Slow", Sensors: []Sensor{
+		NewGenericSensor("psi", func() (string, bool, bool, error) {
+			if failing {
+				return "", false, false, errors.New("busy")
+			}
+			return "0.5%", false, false, nil
+		}),
+	}})
+
+	m = m.Refresh()
+	snap := m.Snapshot()
+	for _, group := range snap.Groups {
+		if !group.Time.Equal(start) {
+			t.Errorf("expected %s read at the first refresh, got %v", group.Name, group.Time)
+		}
+	}
+
+	// Slow fails and backs off while Fast keeps refreshing
+	failing = true
+	clock.now = start.Add(2 * time.Second)
+	m = m.Refresh()
+	snap = m.Snapshot()
+	if got := snap.group("Slow").Time; !got.Equal(start) {
+		t.Errorf("expected the failing group to keep its read time, got %v", got)
+	}
+	if !snap.Oldest().Equal(start) {
+		t.Errorf("expected the oldest readings from the start, got %v", snap.Oldest())
+	}
+	// Within an interval: one common time, the oldest
+	out := ansi.Strip(m.View())
+	if !strings.Contains(out, "Last updated: 12:00:00") || strings.Contains(out, "ago)") {
+		t.Errorf("expected the oldest time in the footer and no age markers:\n%s", out)
+	}
+
+	clock.now = start.Add(4 * time.Second)
+	m = m.Refresh()
+	snap = m.Snapshot()
+	if got := snap.group("Fast").Time; !got.Equal(clock.now) {
+		t.Errorf("expected the healthy group read at the last refresh, got %v", got)
+	}
+	// Beyond an interval: the refresh time, and the lagging group's age
+	out = ansi.Strip(m.View())
+	if !strings.Contains(out, "Last updated: 12:00:04") || !strings.Contains(out, "retrying in 2s (read 4s ago)") {
+		t.Errorf("expected the refresh time and the Slow group's age:\n%s", out)
+	}
+	if strings.Contains(out, "Fast (read") {
+		t.Errorf("expected no age on the group read at the refresh:\n%s", out)
+	}
+
+	var sb strings.Builder
+	if err := WritePrometheus(&sb, snap); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`sysfs_monitor_last_read_timestamp_seconds{host="box1",section="Fast"} 1.772366404e+09`,
+		`sysfs_monitor_last_read_timestamp_seconds{host="box1",section="Slow"} 1.7723664e+09`,
+	} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("expected %q in:\n%s", want, sb.String())
+		}
+	}
+}
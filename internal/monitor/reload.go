@@ -0,0 +1,152 @@
+package monitor
+
+import (
+	"maps"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reloadConfig re-reads the config file and applies the settings that
+// changed, leaving readings, history and discovered groups alone. Settings
+// the file doesn't change keep their current values, so command line flags
+// stay in effect. An invalid file is reported in a toast and ignored. The
+// command reschedules the next refresh when the interval changed.
+func (m Monitor) reloadConfig(now time.Time) (Monitor, tea.Cmd) {
+	if m.configPath == "" {
+		return m, nil
+	}
+	cfg, err := LoadConfig(m.configPath)
+	if err != nil {
+		m.toast = "Config not reloaded: " + err.Error()
+		m.toastUntil = now.Add(toastDuration)
+		return m, nil
+	}
+	old := m.config
+	changed := m.applyConfigChanges(old, cfg)
+	m.config = cfg
+	var cmd tea.Cmd
+	if old.Interval != cfg.Interval {
+		interval := cfg.RefreshInterval()
+		if interval == 0 {
+			interval = DefaultInterval
+		}
+		m, cmd = m.SetInterval(interval)
+	}
+	switch {
+	case len(changed) == 0:
+		m.toast = "Config reloaded, nothing changed"
+	default:
+		m.toast = "Config reloaded: " + strings.Join(changed, ", ")
+	}
+	if m.unsavedOverrides {
+		// The file's overrides replaced the editor's
+		m.toast += "; unsaved threshold edits discarded"
+		m.unsavedOverrides = false
+		m.arrangeTemperatures()
+	}
+	m.toastUntil = now.Add(toastDuration)
+	return m, cmd
+}
+
+// applyConfigChanges applies the fields that differ between the old and new
+// config, returning their config names
+func (m *Monitor) applyConfigChanges(old, cfg Config) []string {
+	var changed []string
+	differs := func(name string, a, b any) bool {
+		if reflect.DeepEqual(a, b) {
+			return false
+		}
+		changed = append(changed, name)
+		return true
+	}
+
+	if differs("hostname", old.Hostname, cfg.Hostname) {
+		m.hostname = cfg.Hostname
+		if m.hostname == "" {
+			m.hostname, _ = os.Hostname()
+		}
+	}
+	// Overrides, profiles, the stale timeout, wake_on_read and frozen_ticks are read from m.config on every refresh
+	differs("overrides", old.Overrides, cfg.Overrides)
+	differs("profiles", old.Profiles, cfg.Profiles)
+	differs("stale_timeout", old.StaleTimeout, cfg.StaleTimeout)
+	differs("wake_on_read", old.WakeOnRead, cfg.WakeOnRead)
+	differs("frozen_ticks", old.FrozenTicks, cfg.FrozenTicks)
+	// The interval is set by reloadConfig, which reschedules the tick
+	differs("interval", old.Interval, cfg.Interval)
+	// Exclude applies to dynamic groups from the next refresh and to
+	// discovered ones from the next start
+	differs("exclude", old.Exclude, cfg.Exclude)
+	// The fan check's ticks and pairs apply from the next refresh; turning
+	// it on or off takes a restart, like discovered groups
+	differs("fan_check", old.FanCheck, cfg.FanCheck)
+	if differs("mute", old.Mute, cfg.Mute) {
+		// Mark the temperatures now rather than on the next refresh
+		m.config.Mute = cfg.Mute
+		m.arrangeTemperatures()
+	}
+	if differs("min_valid_temperature", old.MinValidTemperature, cfg.MinValidTemperature) {
+		m.minTemperature = DefaultMinTemperature
+		if cfg.MinValidTemperature != nil {
+			m.minTemperature = *cfg.MinValidTemperature
+		}
+	}
+	if differs("offsets", old.Offsets, cfg.Offsets) {
+		// Offsets given as an option stay unless the file had the same key
+		offsets := maps.Clone(m.offsets)
+		for name := range old.Offsets {
+			delete(offsets, name)
+		}
+		m.offsets = mergeOffsets(offsets, cfg.Offsets)
+	}
+	if differs("not_charging_thresholds", old.NotChargingThresholds, cfg.NotChargingThresholds) {
+		m.notChargingThresholds = cfg.NotChargingThresholds
+	}
+	if differs("byte_units", old.ByteUnits, cfg.ByteUnits) {
+		m.byteUnits = ByteUnits(indexOf(byteUnitsNames, cfg.ByteUnits))
+	}
+	if differs("network_rates", old.NetworkRates, cfg.NetworkRates) {
+		m.bitRates = cfg.NetworkRates == "bits"
+	}
+	if differs("underpowered_ticks", old.UnderpoweredTicks, cfg.UnderpoweredTicks) {
+		m.underpoweredTicks = DefaultUnderpoweredTicks
+		if cfg.UnderpoweredTicks > 0 {
+			m.underpoweredTicks = cfg.UnderpoweredTicks
+		}
+	}
+	if differs("theme", old.Theme, cfg.Theme) {
+		m.theme = DefaultTheme
+		if theme, ok := themes[cfg.Theme]; ok {
+			m.theme = theme
+		}
+	}
+	footer := differs("footer", old.Footer, cfg.Footer)
+	if differs("compact_footer", old.CompactFooter, cfg.CompactFooter) || footer {
+		m.footer, m.compactFooter = footerTemplates(cfg)
+	}
+	if differs("disabled_providers", old.DisabledProviders, cfg.DisabledProviders) {
+		// Thermal and battery are checked on every refresh; other providers
+		// only ran at discovery, whose groups are kept until a restart
+		m.disabledProviders = nil
+		WithoutProviders(cfg.DisabledProviders...)(m)
+	}
+	if differs("network", old.Network, cfg.Network) && m.network != nil {
+		var settings NetworkConfig
+		if cfg.Network != nil {
+			settings = *cfg.Network
+		}
+		m.network = newNetworkCollector(m.network.root, settings)
+	}
+	if differs("scripts", old.Scripts, cfg.Scripts) && m.scripts != nil {
+		var settings ScriptsConfig
+		if cfg.Scripts != nil {
+			settings = *cfg.Scripts
+		}
+		m.scripts = newScriptRunner(m.scriptDir, settings, m.interval)
+	}
+	return changed
+}
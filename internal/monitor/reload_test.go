@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReloadConfigAppliesChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"hostname": "cfg", "offsets": {"Tctl": -10}}`)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMonitor(WithConfig(path, cfg), WithHostname("flag"), WithTemperatureOffsets(map[string]float64{"acpitz": 2}))
+
+	write(`{"hostname": "cfg", "offsets": {"Tccd1": -5}, "underpowered_ticks": 5}`)
+	now := time.Now()
+	m, _ = m.reloadConfig(now)
+	if toast := m.activeToast(now); toast != "Config reloaded: offsets, underpowered_ticks" {
+		t.Errorf("unexpected toast %q", toast)
+	}
+	if m.hostname != "flag" {
+		t.Errorf("an unchanged hostname should keep the flag value, got %q", m.hostname)
+	}
+	if _, ok := m.offsets["Tctl"]; ok || m.offsets["Tccd1"] != -5 || m.offsets["acpitz"] != 2 {
+		t.Errorf("unexpected offsets %v", m.offsets)
+	}
+	if m.underpoweredTicks != 5 {
+		t.Errorf("expected 5 underpowered ticks, got %d", m.underpoweredTicks)
+	}
+}
+
+func TestReloadConfigKeepsSettingsOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	m := NewMonitor(WithConfig(path, Config{UnderpoweredTicks: 4}))
+	if err := os.WriteFile(path, []byte(`{"profiles": [{"name": "x", "hours": "late"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	m, _ = m.reloadConfig(now)
+	if toast := m.activeToast(now); !strings.HasPrefix(toast, "Config not reloaded: ") {
+		t.Errorf("expected an error toast, got %q", toast)
+	}
+	if m.underpoweredTicks != 4 || m.config.UnderpoweredTicks != 4 {
+		t.Errorf("expected the old settings to stay, got %d ticks", m.underpoweredTicks)
+	}
+}
+
+func TestReloadConfigInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"interval": "5s"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, clock := newClockedMonitor(DefaultInterval)
+	WithConfig(path, cfg)(&m)
+	if m.interval != 5*time.Second {
+		t.Fatalf("expected the file's interval, got %v", m.interval)
+	}
+
+	// An edit not saved is replaced by the file's overrides, and said so
+	m.config.Overrides = map[string]ThresholdOverride{"CPU": {High: 70, Critical: 90}}
+	m.unsavedOverrides = true
+	if err := os.WriteFile(path, []byte(`{"interval": "10s"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, _ = m.reloadConfig(clock.Now())
+	if m.interval != 10*time.Second || !m.nextRefresh.Equal(m.lastUpdate.Add(10*time.Second)) {
+		t.Errorf("expected the next refresh rescheduled 10s after the last, got %v at %v", m.interval, m.nextRefresh)
+	}
+	if last := clock.timers[len(clock.timers)-1]; !last.at.Equal(m.nextRefresh) {
+		t.Errorf("expected a tick scheduled for %v, got %v", m.nextRefresh, last.at)
+	}
+	if toast := m.activeToast(clock.Now()); toast != "Config reloaded: overrides, interval; unsaved threshold edits discarded" {
+		t.Errorf("unexpected toast %q", toast)
+	}
+
+	for _, bad := range []string{`{"interval": "soon"}`, `{"interval": "0s"}`} {
+		if err := os.WriteFile(path, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil || !strings.HasPrefix(err.Error(), "interval: ") {
+			t.Errorf("%s: expected an interval error, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,781 @@
+package monitor
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// Theme holds the colors of the rendered views, as lipgloss color strings
+// (ANSI 256 numbers or "#rrggbb")
+type Theme struct {
+	Title    string
+	OK       string
+	Warning  string
+	Critical string
+}
+
+// DefaultTheme is the theme of the TUI
+var DefaultTheme = Theme{
+	Title:    "63",
+	OK:       "42",  // green
+	Warning:  "214", // orange
+	Critical: "9",   // red
+}
+
+// LightTheme has darker colors for terminals with a light background
+var LightTheme = Theme{
+	Title:    "55",
+	OK:       "28",  // dark green
+	Warning:  "130", // dark orange
+	Critical: "160", // dark red
+}
+
+// themes maps the config names of the themes
+var themes = map[string]Theme{
+	"dark":  DefaultTheme,
+	"light": LightTheme,
+}
+
+// WithTheme sets the colors of the TUI
+func WithTheme(theme Theme) Option {
+	return func(m *Monitor) {
+		m.theme = theme
+	}
+}
+
+// stateColor returns the color for an alert state
+func (t Theme) stateColor(state State) string {
+	switch state {
+	case StateCritical:
+		return t.Critical
+	case StateWarning:
+		return t.Warning
+	default:
+		return t.OK
+	}
+}
+
+func (t Theme) stateStyle(state State) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(t.stateColor(state)))
+}
+
+// Selection identifies a highlighted row: a temperature when Group is -1,
+// otherwise the Index-th reading of Snapshot.Groups[Group]
+type Selection struct {
+	Group, Index int
+}
+
+// ViewState is the interactive state drawn around a snapshot. The zero value
+// renders the readings alone, in Celsius.
+type ViewState struct {
+	Unit      TempUnit
+	Selection *Selection
+	// Collapsed groups show only their sensor count
+	Collapsed map[string]bool
+	// Overridden names the temperatures with user-defined thresholds, by
+	// key or name, marked with "*"
+	Overridden map[string]bool
+	// Status is the result of the last key action and Toast a transient
+	// notice, both shown above the footer
+	Status string
+	Toast  string
+	// Now times the countdowns; the snapshot time is used if zero
+	Now time.Time
+	// NextRefresh adds "next in Ns" to the footer when set; Paused replaces
+	// it with "paused" and Idle, the low-power mode, with "idle"
+	NextRefresh time.Time
+	Paused      bool
+	Idle        bool
+	// NetworkTotalOnly shows only the total network rates in the compact
+	// view
+	NetworkTotalOnly bool
+	// ASCII draws symbols as letters, for terminals without colors
+	// (NO_COLOR or a dumb terminal) where they can't be told apart
+	ASCII bool
+	// Numbers localizes the displayed readings
+	Numbers NumberFormat
+	// Interval is the time between refreshes: sections whose readings lag
+	// the last refresh by more than that show their age
+	Interval time.Duration
+	// SummarizeChips shows the hwmon chips with more than this many
+	// temperature channels as their min/avg/max row alone; 0 lists every
+	// channel under the row
+	SummarizeChips int
+	// Unchanged marks slow-moving group readings (percentages and text)
+	// with the time they have held their value
+	Unchanged bool
+	// Title replaces DefaultTitle; HideTitle drops the title line, moving
+	// the host name and the demo notice to the footer
+	Title     string
+	HideTitle bool
+	// Footer and CompactFooter replace the footer templates of the views
+	// (see footer.go); nil uses the defaults
+	Footer        *template.Template
+	CompactFooter *template.Template
+	// ClockJump is the last jump of the wall clock, set for a few
+	// refreshes after it
+	ClockJump time.Duration
+
+	// layout caches the width-dependent work between renders of the same
+	// readings (see layout.go); nil measures everything each time
+	layout *layoutCache
+}
+
+func (v ViewState) selected(group, index int) bool {
+	return v.Selection != nil && *v.Selection == Selection{Group: group, Index: index}
+}
+
+// RenderFull renders a snapshot in the full layout: temperatures and
+// battery side by side, the extra groups below and a footer. The layout
+// doesn't clip to width and height yet.
+func RenderFull(snap Snapshot, width, height int, theme Theme, view ViewState) string {
+	now := view.Now
+	if now.IsZero() {
+		now = snap.Time
+	}
+
+	var sb strings.Builder
+
+	// Title, with the host on the same line
+	host := snap.Hostname
+	if snap.Demo {
+		host = strings.TrimSpace(host + " (demo data)")
+	}
+	if !view.HideTitle {
+		titleStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(theme.Title)).
+			PaddingBottom(1)
+		title := cmp.Or(view.Title, DefaultTitle)
+		if snap.Hostname != "" {
+			title += " — " + snap.Hostname
+		}
+		if snap.Demo {
+			title += " (demo data)"
+		}
+		sb.WriteString(titleStyle.Render(title))
+		sb.WriteString("\n\n")
+	}
+	// Explain empty sections inside a VM instead of looking broken
+	if snap.Virtualization != "" && len(snap.Temperatures) == 0 && !snap.Battery.Present() {
+		sb.WriteString(lipgloss.NewStyle().Faint(true).Render(
+			fmt.Sprintf("Running in a virtual machine (%s) — hardware sensors are typically unavailable", snap.Virtualization)))
+		sb.WriteString("\n\n")
+	}
+
+	// Two-column layout: temperatures on left, battery on right
+	var leftCol strings.Builder
+	heading, tempLines := temperaturePane(snap, theme, view, now)
+	leftCol.WriteString(heading)
+
+	// Combine columns side by side with spacing; the temperatures flow into
+	// the width the battery leaves
+	rightStr := view.Numbers.localize(batteryPane(snap, theme, view, now))
+	for _, line := range view.layout.flow(tempLines, width-view.layout.width(rightStr)-4) {
+		leftCol.WriteString(line + "\n")
+	}
+	leftStr := leftCol.String()
+	combined := lipgloss.JoinHorizontal(lipgloss.Top, leftStr, "    ", rightStr)
+	sb.WriteString(combined)
+	sb.WriteString("\n")
+
+	// Extra sensor groups
+	for g, group := range snap.Groups {
+		sb.WriteString("\n")
+		sb.WriteString(lipgloss.NewStyle().Bold(true).Render(group.Name))
+		states := make([]State, len(group.Readings))
+		for i, reading := range group.Readings {
+			states[i] = reading.State
+		}
+		sb.WriteString(stateBadge(states, theme, view.ASCII))
+		if badge := groupBadge(group.Refresh, now); badge != "" {
+			sb.WriteString(" " + theme.stateStyle(StateWarning).Render(badge))
+		}
+		sb.WriteString(ageMarker(snap, group.Time, view.Interval, now))
+		sb.WriteString("\n")
+		if view.Collapsed[group.Name] {
+			fmt.Fprintf(&sb, "  %s\n", lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("(collapsed, %d sensors)", len(group.Readings))))
+		} else if group.Renderer != nil {
+			for _, line := range strings.Split(group.Renderer.RenderGroup(max(width-2, 0), theme), "\n") {
+				sb.WriteString("  " + line + "\n")
+			}
+		} else if len(group.Readings) == 0 {
+			sb.WriteString("  No sensors\n")
+		} else {
+			nameWidth := 0
+			for _, reading := range group.Readings {
+				if !reading.Denied {
+					nameWidth = max(nameWidth, view.layout.width(reading.Name))
+				}
+			}
+			nameWidth = min(nameWidth, maxNameWidth)
+			var lines []string
+			for i, reading := range group.Readings {
+				if reading.Denied {
+					continue
+				}
+				prefix := "  "
+				if view.selected(g, i) {
+					prefix = "> "
+				}
+				marker := ""
+				if reading.Err != "" {
+					marker = " " + theme.stateStyle(StateWarning).Render("!")
+				}
+				marker += mutedTag(reading.Muted) + frozenTag(theme, reading.Frozen) + unchangedMarker(reading, view, now)
+				name := view.layout.fitName(reading.Name, nameWidth)
+				lines = append(lines, fmt.Sprintf("%s%s: %s%s", prefix, name, readingStyle(theme, reading.State, reading.Muted).Render(view.Numbers.localize(reading.Value)), marker))
+			}
+			for _, line := range view.layout.flow(lines, width) {
+				sb.WriteString(line + "\n")
+			}
+		}
+	}
+
+	// One line per class of sensors hidden for lack of permission
+	if len(snap.PermissionGaps) > 0 {
+		sb.WriteString("\n")
+		for _, gap := range snap.PermissionGaps {
+			sb.WriteString(lipgloss.NewStyle().Faint(true).Render(gap.String()))
+			sb.WriteString("\n")
+		}
+	}
+
+	// Status message from the last key action
+	if view.Status != "" {
+		sb.WriteString("\n")
+		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Warning)).Render(view.Status))
+		sb.WriteString("\n")
+	}
+
+	// Toast; the countdown redraw removes it once expired
+	if view.Toast != "" {
+		sb.WriteString("\n")
+		sb.WriteString(lipgloss.NewStyle().Bold(true).Reverse(true).Render(" " + view.Toast + " "))
+		sb.WriteString("\n")
+	}
+
+	// Footer
+	sb.WriteString("\n")
+	footerStyle := lipgloss.NewStyle().Faint(true)
+	ctx := footerContext(snap, view, host, now)
+	sb.WriteString(footerStyle.Render(renderFooter(view.Footer, defaultFooter, ctx, width)))
+
+	return sb.String()
+}
+
+// stateBadge counts the warning and critical states of a section for its
+// header, e.g. " [2⚠ 1✖]" or " [2w 1c]" in ASCII, colored by the worst
+// state. It is empty when everything is OK, so trouble in a collapsed or
+// scrolled-off section still shows.
+func stateBadge(states []State, theme Theme, ascii bool) string {
+	var warnings, criticals int
+	for _, state := range states {
+		switch state {
+		case StateWarning:
+			warnings++
+		case StateCritical:
+			criticals++
+		}
+	}
+	if warnings == 0 && criticals == 0 {
+		return ""
+	}
+	warnSymbol, critSymbol := "⚠", "✖"
+	if ascii {
+		warnSymbol, critSymbol = "w", "c"
+	}
+	var parts []string
+	if warnings > 0 {
+		parts = append(parts, fmt.Sprintf("%d%s", warnings, warnSymbol))
+	}
+	worst := StateWarning
+	if criticals > 0 {
+		parts = append(parts, fmt.Sprintf("%d%s", criticals, critSymbol))
+		worst = StateCritical
+	}
+	return " " + theme.stateStyle(worst).Render("["+strings.Join(parts, " ")+"]")
+}
+
+// temperaturePane draws the Temperatures section: the heading, with the
+// headroom or the lack of sensors, and one line per sensor, which the
+// caller lays out. RenderFull and TemperatureWidget share it.
+func temperaturePane(snap Snapshot, theme Theme, view ViewState, now time.Time) (string, []string) {
+	var heading strings.Builder
+	heading.WriteString(lipgloss.NewStyle().Bold(true).Render("Temperatures"))
+	tempStates := make([]State, len(snap.Temperatures))
+	for i, sensor := range snap.Temperatures {
+		tempStates[i] = sensor.State()
+	}
+	heading.WriteString(stateBadge(tempStates, theme, view.ASCII))
+	heading.WriteString(ageMarker(snap, snap.TemperaturesTime, view.Interval, now))
+	heading.WriteString("\n")
+	if headroom, ok := snap.Headroom(); ok {
+		fmt.Fprintf(&heading, "  Headroom: %s\n", theme.stateStyle(headroom.State()).Render(
+			fmt.Sprintf("%s (%s)", view.Numbers.localize(formatTempDelta(headroom.Degrees, view.Unit)), headroom.Sensor)))
+	}
+	var tempLines []string
+	if len(snap.Temperatures) == 0 {
+		heading.WriteString("  No temperature sensors found\n")
+	} else {
+		summaries, hidden := chipSummaries(snap.Temperatures, view.SummarizeChips)
+		firsts := make(map[int]chipSummary, len(summaries))
+		for _, s := range summaries {
+			firsts[s.first] = s
+		}
+		for i, sensor := range snap.Temperatures {
+			if s, ok := firsts[i]; ok {
+				tempLines = append(tempLines, s.line(theme, view, hidden[i]))
+			}
+			if hidden[i] {
+				continue
+			}
+			tempStr := readingStyle(theme, sensor.State(), sensor.Muted).Render(view.Numbers.localize(formatTemp(sensor.Value, view.Unit, 6)))
+			if sensor.Asleep {
+				tempStr = lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("%8s", asleepText))
+			}
+			prefix := "  "
+			if view.selected(-1, i) {
+				prefix = "> "
+			}
+			marker := ""
+			if _, ok := lookupSensor(view.Overridden, sensor); ok {
+				marker = lipgloss.NewStyle().Faint(true).Render(" *")
+			}
+			if sensor.Zones > 1 {
+				marker += lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf(" (hottest of %d)", sensor.Zones))
+			}
+			if sensor.Stale {
+				marker += " " + theme.stateStyle(StateWarning).Render("!")
+			}
+			marker += newTag(sensor.New)
+			marker += mutedTag(sensor.Muted)
+			marker += frozenTag(theme, sensor.Frozen)
+			tempLines = append(tempLines, fmt.Sprintf("%s%s  %s%s", prefix, padRight(tempStr, 8), sensor.Path, marker))
+		}
+	}
+	return heading.String(), tempLines
+}
+
+// asleepText stands in for the value of a temperature whose device is
+// runtime suspended
+const asleepText = "asleep"
+
+// batteryPane draws the Battery section, not yet localized. RenderFull and
+// BatteryWidget share it.
+func batteryPane(snap Snapshot, theme Theme, view ViewState, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render("Battery"))
+	sb.WriteString(ageMarker(snap, snap.BatteryTime, view.Interval, now))
+	sb.WriteString("\n")
+	bat := snap.Battery
+	if bat.Capacity == 0 && bat.Status == "" {
+		sb.WriteString("  No battery information\n")
+	} else {
+		capacityStyle := theme.stateStyle(snap.BatteryCapacityState)
+		fmt.Fprintf(&sb, "  Capacity: %s", capacityStyle.Render(fmt.Sprintf("%d%%", bat.Capacity)))
+		if bat.CapacitySuspect {
+			fmt.Fprintf(&sb, " %s", lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("(suspect: raw %d)", bat.RawCapacity)))
+		}
+		sb.WriteString("\n")
+		fmt.Fprintf(&sb, "  Status: %s\n", bat.Status)
+		fmt.Fprintf(&sb, "  AC: %s\n", bat.ACDescription())
+		if span := snap.OnBattery; span != nil {
+			fmt.Fprintf(&sb, "  On battery: %s\n", span.counter(now))
+		}
+		if snap.AdapterUnderpowered {
+			fmt.Fprintf(&sb, "  %s\n", theme.stateStyle(StateWarning).Render("⚠ Adapter underpowered: discharging on AC"))
+		}
+		if bat.DeepDischargeRisk() {
+			fmt.Fprintf(&sb, "  %s\n", theme.stateStyle(StateCritical).Render("⚠ Deep discharge risk: voltage near design minimum"))
+		}
+		switch {
+		case bat.Voltage > 0 && bat.VoltageMinDesign > 0:
+			voltage := fmt.Sprintf("%.2f V (min %.2f)", bat.Voltage, bat.VoltageMinDesign)
+			if bat.DeepDischargeRisk() {
+				voltage = theme.stateStyle(StateCritical).Render(voltage)
+			}
+			fmt.Fprintf(&sb, "  Voltage: %s\n", voltage)
+		case bat.Voltage > 0:
+			fmt.Fprintf(&sb, "  Voltage: %.2fV\n", bat.Voltage)
+		}
+		if bat.Current != 0 {
+			fmt.Fprintf(&sb, "  Current: %.2fA\n", bat.Current)
+		}
+		if power := snap.BatteryPower; power.Average > 0 {
+			fmt.Fprintf(&sb, "  Power: %.1f W %s\n", power.Average,
+				lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("(now %.1f, peak %.1f)", bat.Power, power.Peak)))
+		} else if bat.Power > 0 {
+			fmt.Fprintf(&sb, "  Power: %.2fW\n", bat.Power)
+		}
+		if full := snap.BatteryFull; full != nil {
+			fmt.Fprintf(&sb, "  Full: %s\n", full)
+		}
+		if bat.Health != "" {
+			healthStyle := lipgloss.NewStyle()
+			if state := BatteryHealthState(bat.Health); state != StateOK {
+				healthStyle = theme.stateStyle(state)
+			}
+			fmt.Fprintf(&sb, "  Health: %s\n", healthStyle.Render(bat.Health))
+		}
+		if bat.Temperature > 0 {
+			fmt.Fprintf(&sb, "  Temperature: %s\n", formatTemp(bat.Temperature, view.Unit, 0))
+		}
+		if bat.Energy > 0 {
+			fmt.Fprintf(&sb, "  Energy: %.2f Wh\n", bat.Energy)
+		}
+		if bat.CapacityLevel != "" {
+			fmt.Fprintf(&sb, "  Capacity Level: %s\n", bat.CapacityLevel)
+		}
+	}
+	return sb.String()
+}
+
+// maxNameWidth caps the name column of a sensor group; longer names are
+// truncated
+const maxNameWidth = 28
+
+// Sections with at least 2*minFlowRows entries flow into up to
+// maxFlowColumns columns, separated by columnGap, when the width allows
+const (
+	minFlowRows    = 4
+	maxFlowColumns = 3
+	columnGap      = 4
+)
+
+// flowColumns lays out the lines of a section top to bottom, then left to
+// right, in as many columns as fit in width without any column getting
+// fewer than minFlowRows lines. With one column the lines are returned as
+// they are, so narrow terminals keep one entry per line. measure gives the
+// display width of a line.
+func flowColumns(lines []string, width int, measure func(string) int) []string {
+	cellWidth := 0
+	for _, line := range lines {
+		cellWidth = max(cellWidth, measure(line))
+	}
+	cols := min(maxFlowColumns, (width+columnGap)/(cellWidth+columnGap), len(lines)/minFlowRows)
+	if cols <= 1 {
+		return lines
+	}
+	rows := (len(lines) + cols - 1) / cols
+	flowed := make([]string, rows)
+	for r := range flowed {
+		var row strings.Builder
+		for c := 0; c < cols; c++ {
+			i := c*rows + r
+			if i >= len(lines) {
+				break
+			}
+			if c > 0 {
+				row.WriteString(strings.Repeat(" ", columnGap))
+			}
+			if next := i + rows; next < len(lines) && c+1 < cols {
+				row.WriteString(lines[i] + strings.Repeat(" ", cellWidth-measure(lines[i])))
+			} else {
+				row.WriteString(lines[i])
+			}
+		}
+		flowed[r] = row.String()
+	}
+	return flowed
+}
+
+// RenderCompact renders a snapshot in at most 3 lines for small panes:
+// temperatures and battery, the worst extra sensor or a group summary, and
+// the update time. Only the worst-sensor line is fitted to width.
+func RenderCompact(snap Snapshot, width int, theme Theme, view ViewState) string {
+	var lines []string
+	if first := summaryLine(snap, width, theme, view); first != "" {
+		lines = append(lines, first)
+	}
+
+	// Second line: the critical sensors when there are any, since which ones
+	// matters most; otherwise the extra groups summary, the worst sensor
+	// when any is failing, or the counts
+	if criticals := criticalReadings(snap, view); len(criticals) > 0 {
+		lines = append(lines, theme.stateStyle(StateCritical).Render(fitCriticals(criticals, width)))
+	} else if len(snap.Groups) > 0 {
+		totalSensors := 0
+		alerting := 0
+		var worst *SensorReading
+		worstState := StateOK
+		for _, group := range snap.Groups {
+			totalSensors += len(group.Readings)
+			for i, reading := range group.Readings {
+				if reading.State != StateOK {
+					alerting++
+				}
+				if reading.State > worstState {
+					worst, worstState = &group.Readings[i], reading.State
+				}
+			}
+		}
+		style := theme.stateStyle(worstState)
+		if worst != nil {
+			icon := "⚠"
+			if worstState == StateCritical {
+				icon = "✖"
+			}
+			more := ""
+			if alerting > 1 {
+				more = fmt.Sprintf(" (+%d more)", alerting-1)
+			}
+			lines = append(lines, style.Render(fitWorstSensor(icon, worst.Name, view.Numbers.localize(worst.Value), more, width)))
+		} else if network := snap.group(networkGroupName); network != nil && len(network.Readings) > 0 {
+			summary := view.Numbers.localize(networkSummary(network.Readings, view.NetworkTotalOnly))
+			if width > 0 {
+				summary = truncateWidth(summary, width)
+			}
+			lines = append(lines, style.Render(summary))
+		} else {
+			lines = append(lines, style.Render(fmt.Sprintf("Extra: %d groups, %d sensors", len(snap.Groups), totalSensors)))
+		}
+	}
+
+	// Footer with update time (always last line)
+	footerStyle := lipgloss.NewStyle().Faint(true)
+	ctx := footerContext(snap, view, "", cmp.Or(view.Now, snap.Time))
+	lines = append(lines, footerStyle.Render(renderFooter(view.CompactFooter, defaultCompactFooter, ctx, width)))
+
+	// Ensure we don't exceed 3 lines
+	maxLines := 3
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Summary is the first line of the compact view, the temperatures and the
+// battery, e.g. "🌡 58.3°C   55.1°C | 🔋 81% Discharging 12.32V", in Celsius
+// with decimal points. Temperatures that don't fit maxWidth (0 for no
+// limit) are left out coolest first. Unstyled, it holds no escape sequences,
+// for scripts; `sysfs-check --brief` prints it.
+func Summary(snap Snapshot, maxWidth int, styled bool) string {
+	line := summaryLine(snap, maxWidth, DefaultTheme, ViewState{})
+	if !styled {
+		return ansi.Strip(line)
+	}
+	return line
+}
+
+// summaryLine combines the temperatures and the battery. The battery
+// segment is built first so temperatures get the remaining width.
+func summaryLine(snap Snapshot, width int, theme Theme, view ViewState) string {
+	batteryStr := view.Numbers.localize(compactBattery(snap, theme))
+	budget := 0
+	if width > 0 {
+		budget = width
+		if batteryStr != "" {
+			budget = max(width-lipgloss.Width(batteryStr)-len(" | "), 0)
+		}
+	}
+	line := compactTemperatures(snap.Temperatures, view.Unit, view.Numbers, theme, budget, width > 0)
+	if batteryStr != "" {
+		if line != "" {
+			line += " | "
+		}
+		line += batteryStr
+	}
+	return line
+}
+
+// compactBattery is the battery segment of the compact view, not yet
+// localized, or "" without a battery. RenderCompact and BatteryWidget share
+// it.
+func compactBattery(snap Snapshot, theme Theme) string {
+	bat := snap.Battery
+	if bat.Capacity == 0 && bat.Status == "" {
+		return ""
+	}
+	var battery strings.Builder
+	capacityStyle := theme.stateStyle(snap.BatteryCapacityState)
+	fmt.Fprintf(&battery, "🔋 %s %s", capacityStyle.Render(fmt.Sprintf("%d%%", bat.Capacity)), bat.Status)
+	if bat.Voltage > 0 {
+		fmt.Fprintf(&battery, " %.2fV", bat.Voltage)
+	}
+	if state := BatteryHealthState(bat.Health); state != StateOK {
+		fmt.Fprintf(&battery, " %s", theme.stateStyle(state).Render(bat.Health))
+	}
+	if snap.AdapterUnderpowered {
+		fmt.Fprintf(&battery, " %s", theme.stateStyle(StateWarning).Render("⚠ underpowered"))
+	}
+	return battery.String()
+}
+
+// compactTemperatures renders the temperatures of the compact first line.
+// When limited and they don't all fit in budget cells, only the hottest that
+// fit are kept, in their usual order, followed by "+N" for the rest in the
+// color of the worst hidden reading; if not even that fits, nothing is shown.
+func compactTemperatures(sensors []TemperatureSensor, unit TempUnit, numbers NumberFormat, theme Theme, budget int, limited bool) string {
+	if len(sensors) == 0 {
+		return ""
+	}
+	const prefix, separator = "🌡 ", "   "
+	entries := make([]string, len(sensors))
+	for i, sensor := range sensors {
+		entries[i] = readingStyle(theme, sensor.State(), sensor.Muted).Render(numbers.localize(formatTemp(sensor.Value, unit, 0)))
+		if sensor.Asleep {
+			entries[i] = lipgloss.NewStyle().Faint(true).Render(asleepText)
+		}
+	}
+	if !limited {
+		return prefix + strings.Join(entries, separator)
+	}
+
+	hottest := make([]int, len(sensors))
+	for i := range hottest {
+		hottest[i] = i
+	}
+	sort.SliceStable(hottest, func(a, b int) bool { return sensors[hottest[a]].Value > sensors[hottest[b]].Value })
+	for n := len(sensors); n >= 0; n-- {
+		used := lipgloss.Width(prefix)
+		shown := make([]bool, len(sensors))
+		for k, i := range hottest[:n] {
+			if k > 0 {
+				used += len(separator)
+			}
+			used += lipgloss.Width(entries[i])
+			shown[i] = true
+		}
+		more := ""
+		if hidden := len(sensors) - n; hidden > 0 {
+			worst := StateOK
+			for _, i := range hottest[n:] {
+				worst = max(worst, sensors[i].State())
+			}
+			more = theme.stateStyle(worst).Render(fmt.Sprintf("+%d", hidden))
+			if n > 0 {
+				more = " " + more
+			}
+		}
+		if used+lipgloss.Width(more) > budget {
+			continue
+		}
+		var parts []string
+		for i, entry := range entries {
+			if shown[i] {
+				parts = append(parts, entry)
+			}
+		}
+		return prefix + strings.Join(parts, separator) + more
+	}
+	return ""
+}
+
+// criticalReading is a critical sensor named on the compact alerts line
+type criticalReading struct {
+	name, value string
+}
+
+// criticalReadings lists the critical temperatures, then the critical group
+// sensors, in display order
+func criticalReadings(snap Snapshot, view ViewState) []criticalReading {
+	var criticals []criticalReading
+	for _, sensor := range snap.Temperatures {
+		if sensor.State() == StateCritical {
+			criticals = append(criticals, criticalReading{sensor.Name, view.Numbers.localize(formatTemp(sensor.Value, view.Unit, 0))})
+		}
+	}
+	for _, group := range snap.Groups {
+		for _, reading := range group.Readings {
+			if reading.State == StateCritical {
+				criticals = append(criticals, criticalReading{reading.Name, view.Numbers.localize(reading.Value)})
+			}
+		}
+	}
+	return criticals
+}
+
+// fitCriticals formats "✖ name value, name value" within width (0 for no
+// limit), keeping as many sensors as fit and counting the rest
+func fitCriticals(criticals []criticalReading, width int) string {
+	entries := make([]string, len(criticals))
+	for i, c := range criticals {
+		entries[i] = c.name + " " + c.value
+	}
+	more := func(hidden int) string {
+		if hidden == 0 {
+			return ""
+		}
+		return fmt.Sprintf(" (+%d more)", hidden)
+	}
+	for n := len(entries); n > 0; n-- {
+		line := "✖ " + strings.Join(entries[:n], ", ") + more(len(entries)-n)
+		if width <= 0 || lipgloss.Width(line) <= width {
+			return line
+		}
+	}
+	return fitWorstSensor("✖", criticals[0].name, criticals[0].value, more(len(entries)-1), width)
+}
+
+// fitWorstSensor formats "icon name value suffix" within width (0 for no
+// limit). The suffix goes first, then the name is shortened, and only then
+// dropped; the value is always kept.
+func fitWorstSensor(icon, name, value, suffix string, width int) string {
+	line := fmt.Sprintf("%s %s %s%s", icon, name, value, suffix)
+	if width <= 0 || lipgloss.Width(line) <= width {
+		return line
+	}
+	avail := width - lipgloss.Width(fmt.Sprintf("%s  %s", icon, value))
+	switch {
+	case avail >= lipgloss.Width(name):
+		return fmt.Sprintf("%s %s %s", icon, name, value)
+	case avail >= 2:
+		return fmt.Sprintf("%s %s %s", icon, truncateWidth(name, avail), value)
+	}
+	return fmt.Sprintf("%s %s", icon, value)
+}
+
+// untilRefresh formats the time left until next, rounded up to whole
+// seconds
+func untilRefresh(next, now time.Time) string {
+	left := next.Sub(now)
+	if left < 0 {
+		left = 0
+	}
+	return fmt.Sprintf("%ds", int((left+time.Second-1)/time.Second))
+}
+
+// viewState collects the interactive state the renderers draw
+func (m Monitor) viewState(now time.Time) ViewState {
+	view := ViewState{
+		Unit:             m.unit,
+		Collapsed:        m.collapsed,
+		Status:           m.status,
+		Toast:            m.activeToast(now),
+		Now:              now,
+		NextRefresh:      m.nextRefresh,
+		Paused:           m.paused,
+		Idle:             m.idle,
+		NetworkTotalOnly: m.networkSettings().CompactTotalOnly,
+		ASCII:            lipgloss.ColorProfile() == termenv.Ascii,
+		Numbers:          m.numbers,
+		Interval:         m.refreshInterval(),
+		Title:            m.title,
+		HideTitle:        m.title == "",
+		Unchanged:        m.config.UnchangedTimes,
+		SummarizeChips:   m.config.SummarizeChips,
+		Footer:           m.footer,
+		CompactFooter:    m.compactFooter,
+		ClockJump:        m.clockChanged(),
+		layout:           m.layout.at(m.lastUpdate),
+	}
+	if r, ok := m.selectedRow(); ok {
+		view.Selection = &Selection{Group: r.group, Index: r.index}
+	}
+	if len(m.config.Overrides) > 0 {
+		view.Overridden = make(map[string]bool, len(m.config.Overrides))
+		for name := range m.config.Overrides {
+			view.Overridden[name] = true
+		}
+	}
+	return view
+}
@@ -0,0 +1,161 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// renderSizes are the terminal sizes of the golden files; heights below
+// compactHeightThreshold use the compact layout, as in auto view mode
+var renderSizes = []struct{ width, height int }{
+	{100, 30},
+	{60, 20},
+	{80, 5},
+	{24, 3},
+}
+
+var renderThemes = map[string]Theme{
+	"dark":  DefaultTheme,
+	"light": LightTheme,
+}
+
+var renderTime = time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+func temps(values ...float64) []TemperatureSensor {
+	var sensors []TemperatureSensor
+	for i, v := range values {
+		sensors = append(sensors, TemperatureSensor{
+			Name:     fmt.Sprintf("temp%d", i+1),
+			Value:    v,
+			High:     80,
+			Critical: 100,
+			Path:     fmt.Sprintf("/sys/class/hwmon/hwmon%d/temp1_input", i),
+		})
+	}
+	return sensors
+}
+
+func readings(state State, names ...string) []SensorReading {
+	var rs []SensorReading
+	for _, name := range names {
+		rs = append(rs, SensorReading{Name: name, Value: "1200 RPM", State: state})
+	}
+	return rs
+}
+
+// renderFixtures are the snapshots rendered into testdata/render
+var renderFixtures = map[string]Snapshot{
+	"no-battery": {
+		Hostname:     "desktop",
+		Time:         renderTime,
+		Temperatures: temps(45, 52.5, 38),
+	},
+	"low-battery": {
+		Hostname:             "laptop",
+		Time:                 renderTime,
+		Temperatures:         temps(61),
+		Battery:              BatteryStatus{Capacity: 8, Status: "Discharging", Voltage: 10.9, Current: 1.2, Power: 13.1, Health: "Good"},
+		BatteryCapacityState: StateCritical,
+		Profile:              "on-battery",
+	},
+	"many-sensors": {
+		Hostname:     "workstation",
+		Time:         renderTime,
+		Temperatures: temps(41, 43, 45, 47, 49, 51, 53, 55, 57, 59, 81, 83),
+		Battery:      BatteryStatus{Capacity: 96, Status: "Charging", ACOnline: true, ACVoltage: 20, ACCurrent: 3.25, Voltage: 12.6},
+		Groups: []GroupSnapshot{
+			{Name: "Fans", Readings: readings(StateOK, "cpu_fan", "sys_fan1", "sys_fan2", "sys_fan3", "pump", "gpu_fan")},
+			{Name: "Network", Readings: []SensorReading{
+				{Name: "eth0 rx", Value: "1.2 MiB/s"},
+				{Name: "eth0 tx", Value: "56.0 KiB/s"},
+				{Name: "wlan0 rx", Value: "0 B/s"},
+				{Name: "wlan0 tx", Value: "0 B/s"},
+			}},
+			{Name: "Display", Readings: []SensorReading{{Name: "Backlight intel_backlight", Value: "40%"}}},
+		},
+	},
+	"critical-everywhere": {
+		Hostname:             "server",
+		Time:                 renderTime,
+		Temperatures:         temps(105, 110, 99),
+		Battery:              BatteryStatus{Capacity: 3, Status: "Discharging", ACOnline: true, Health: "Overheat", Temperature: 61},
+		BatteryCapacityState: StateCritical,
+		AdapterUnderpowered:  true,
+		Groups: []GroupSnapshot{
+			{Name: "Fans", Readings: readings(StateCritical, "fan1", "fan2"), Refresh: GroupRefreshState{
+				Failures: 3, RetryAt: renderTime.Add(16 * time.Second), Err: "no such device",
+			}},
+			{Name: "Self", Readings: []SensorReading{{Name: "Resident memory", Value: "512.0 MiB", State: StateWarning}}},
+		},
+	},
+	// The --demo dataset, which the golden keeps deterministic
+	"demo": demoSnapshot(DefaultDemoSeed, 30),
+	"unicode-names": {
+		Hostname: "ноутбук",
+		Time:     renderTime,
+		Temperatures: []TemperatureSensor{
+			{Name: "温度センサー", Value: 48, High: 80, Critical: 100, Path: "/sys/class/hwmon/hwmon0/温度_input"},
+			{Name: "🔥 hotspot", Value: 88, High: 80, Critical: 100, Path: "/sys/class/hwmon/hwmon1/🔥_input"},
+		},
+		Battery: BatteryStatus{Capacity: 55, Status: "Not charging", ACOnline: true},
+		Groups: []GroupSnapshot{
+			{Name: "ディスク", Readings: []SensorReading{
+				{Name: "Señal Ñandú", Value: "ok"},
+				{Name: "磁盘温度传感器读数", Value: "41°C", State: StateWarning},
+				{Name: "🌀 风扇", Value: "900 RPM", State: StateCritical},
+			}},
+		},
+	},
+}
+
+// TestRenderGolden renders each fixture at every size and theme and compares
+// with testdata/render/<fixture>-<theme>.golden (run with -update to
+// regenerate). Colors are forced to 256 so the themes differ.
+func TestRenderGolden(t *testing.T) {
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() { lipgloss.SetColorProfile(prev) })
+
+	for name, snap := range renderFixtures {
+		for themeName, theme := range renderThemes {
+			t.Run(name+"-"+themeName, func(t *testing.T) {
+				var sb strings.Builder
+				for _, size := range renderSizes {
+					fmt.Fprintf(&sb, "=== %dx%d ===\n", size.width, size.height)
+					if size.height < compactHeightThreshold {
+						sb.WriteString(RenderCompact(snap, size.width, theme, ViewState{}))
+					} else {
+						sb.WriteString(RenderFull(snap, size.width, size.height, theme, ViewState{}))
+					}
+					sb.WriteString("\n")
+				}
+				got := sb.String()
+
+				golden := filepath.Join("testdata", "render", name+"-"+themeName+".golden")
+				if *update {
+					if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+						t.Fatal(err)
+					}
+					if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("reading golden file (run with -update to create it): %v", err)
+				}
+				if got != string(want) {
+					t.Errorf("rendering differs from %s (run with -update if intended)\ngot:\n%s\nwant:\n%s", golden, got, want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderFullWithoutViewState(t *testing.T) {
+	snap := Snapshot{
+		Hostname:     "box1",
+		Time:         time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		Temperatures: []TemperatureSensor{{Name: "CPU", Value: 55, High: 80, Critical: 100, Path: "thermal_zone0"}},
+		Groups: []GroupSnapshot{{
+			Name:     "Fans",
+			Readings: []SensorReading{{Name: "fan1", Value: "1200 RPM"}},
+			Refresh:  GroupRefreshState{Failures: 1, RetryAt: time.Date(2026, 3, 1, 12, 30, 4, 0, time.UTC), Err: "gone"},
+		}},
+	}
+	out := RenderFull(snap, 80, 24, DefaultTheme, ViewState{})
+	for _, want := range []string{"System Status Monitor — box1", "55.0°C", "thermal_zone0", "fan1", "1200 RPM", "retrying in 4s", "Last updated: 12:30:00 | Press 'q' to quit"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "next in") || strings.Contains(out, "> ") {
+		t.Errorf("expected no countdown or selection without view state:\n%s", out)
+	}
+
+	out = RenderFull(snap, 80, 24, DefaultTheme, ViewState{Unit: Fahrenheit, Selection: &Selection{Group: 0, Index: 0}})
+	if !strings.Contains(out, "131.0°F") || !strings.Contains(out, "> fan1") {
+		t.Errorf("expected Fahrenheit and the selected fan:\n%s", out)
+	}
+}
+
+func TestViewMatchesRenderers(t *testing.T) {
+	m := NewMonitor(WithHostname("box1"))
+	m.width, m.height = 80, 24
+	m.temperatureSensors = []TemperatureSensor{{Name: "CPU", Value: 85, High: 80, Critical: 100, Path: "thermal_zone0"}}
+	m.nextRefresh = m.lastUpdate
+	if got, want := m.View(), RenderFull(m.Snapshot(), 80, 24, DefaultTheme, m.viewState(time.Now())); got != want {
+		t.Errorf("View differs from RenderFull:\n%s\n---\n%s", got, want)
+	}
+	m.height = 5
+	if got, want := m.View(), RenderCompact(m.Snapshot(), 80, DefaultTheme, m.viewState(time.Now())); got != want {
+		t.Errorf("View differs from RenderCompact:\n%s\n---\n%s", got, want)
+	}
+}
+
+func TestRenderCompactCapsTemperatures(t *testing.T) {
+	values := make([]float64, 15)
+	for i := range values {
+		values[i] = 40 + float64(i)
+	}
+	values[3] = 105 // critical, kept as one of the hottest
+	snap := Snapshot{
+		Temperatures: temps(values...),
+		Battery:      BatteryStatus{Capacity: 85, Status: "Charging", Voltage: 3.7},
+	}
+
+	first := strings.Split(RenderCompact(snap, 80, DefaultTheme, ViewState{}), "\n")[0]
+	if w := lipgloss.Width(first); w > 80 {
+		t.Errorf("expected the first line to fit 80 cells, got %d: %q", w, first)
+	}
+	plain := ansi.Strip(first)
+	if !strings.Contains(plain, "105.0°C") || strings.Contains(plain, "40.0°C") || !strings.HasSuffix(plain, "| 🔋 85% Charging 3.70V") {
+		t.Errorf("expected the hottest temperatures and the battery, got %q", plain)
+	}
+	if !strings.Contains(plain, " +") {
+		t.Errorf("expected a count of hidden temperatures, got %q", plain)
+	}
+
+	// Temperatures go before the battery does
+	first = ansi.Strip(strings.Split(RenderCompact(snap, 30, DefaultTheme, ViewState{}), "\n")[0])
+	if first != "🌡 +15 | 🔋 85% Charging 3.70V" {
+		t.Errorf("expected only the count of temperatures at 30 columns, got %q", first)
+	}
+	first = ansi.Strip(strings.Split(RenderCompact(snap, 24, DefaultTheme, ViewState{}), "\n")[0])
+	if first != "🔋 85% Charging 3.70V" {
+		t.Errorf("expected only the battery at 24 columns, got %q", first)
+	}
+
+	// Everything is shown when the width is unknown
+	first = ansi.Strip(strings.Split(RenderCompact(snap, 0, DefaultTheme, ViewState{}), "\n")[0])
+	if strings.Count(first, "°C") != 15 {
+		t.Errorf("expected all 15 temperatures without a width, got %q", first)
+	}
+}
+
+func TestRenderCompactCriticals(t *testing.T) {
+	fans := []GroupSnapshot{{Name: "Fans", Readings: []SensorReading{
+		{Name: "fan1", Value: "1200 RPM", State: StateOK},
+		{Name: "fan2", Value: "0 RPM", State: StateWarning},
+	}}}
+	second := func(snap Snapshot, width int) string {
+		lines := strings.Split(ansi.Strip(RenderCompact(snap, width, DefaultTheme, ViewState{})), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("expected 3 lines, got %q", lines)
+		}
+		return lines[1]
+	}
+
+	// No critical sensor: the extras summary keeps the line
+	snap := Snapshot{Temperatures: temps(50, 60), Groups: fans}
+	if got := second(snap, 80); got != "⚠ fan2 0 RPM" {
+		t.Errorf("expected the worst group sensor, got %q", got)
+	}
+
+	// One: it takes the line over the warning
+	snap.Temperatures = temps(50, 101)
+	if got := second(snap, 80); got != "✖ temp2 101.0°C" {
+		t.Errorf("expected the critical temperature, got %q", got)
+	}
+
+	// Many: temperatures first, then groups, as many as fit
+	snap.Temperatures = temps(105, 50, 101)
+	snap.Groups[0].Readings[0].State = StateCritical
+	if got := second(snap, 80); got != "✖ temp1 105.0°C, temp3 101.0°C, fan1 1200 RPM" {
+		t.Errorf("expected every critical sensor, got %q", got)
+	}
+	if got := second(snap, 40); got != "✖ temp1 105.0°C, temp3 101.0°C (+1 more)" {
+		t.Errorf("expected the sensors that fit and a count, got %q", got)
+	}
+	if got := second(snap, 20); got != "✖ temp1 105.0°C" {
+		t.Errorf("expected the first critical sensor alone, got %q", got)
+	}
+}
+
+func TestRenderFullFlowsGroups(t *testing.T) {
+	var readings []SensorReading
+	for i := 0; i < 12; i++ {
+		readings = append(readings, SensorReading{Name: fmt.Sprintf("core%d", i), Value: "1800 MHz", State: StateOK})
+	}
+	readings = append(readings, SensorReading{Name: "a name far longer than the name column allows", Value: "on", State: StateOK})
+	snap := Snapshot{
+		Time:   time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		Groups: []GroupSnapshot{{Name: "CPU", Readings: readings}},
+	}
+
+	wide := ansi.Strip(RenderFull(snap, 200, 40, DefaultTheme, ViewState{}))
+	if !strings.Contains(wide, "  core0   ") || !strings.Contains(wide, "core5") {
+		t.Fatalf("expected the group rendered, got:\n%s", wide)
+	}
+	var row string
+	for _, line := range strings.Split(wide, "\n") {
+		if strings.HasPrefix(line, "  core0 ") {
+			row = line
+		}
+	}
+	if !strings.Contains(row, "core5") || !strings.Contains(row, "core10") {
+		t.Errorf("expected three columns at 200 cells, got %q", row)
+	}
+	if want := "a name far longer than the… : on"; !strings.Contains(wide, want) {
+		t.Errorf("expected the long name truncated to %q, got:\n%s", want, wide)
+	}
+
+	narrow := ansi.Strip(RenderFull(snap, 60, 40, DefaultTheme, ViewState{}))
+	if !strings.Contains(narrow, "  core0                       : 1800 MHz\n  core1 ") {
+		t.Errorf("expected one sensor per line at 60 cells, got:\n%s", narrow)
+	}
+}
+
+func TestFlowColumns(t *testing.T) {
+	lines := strings.Split("abcdefghijkl", "")
+	if got := flowColumns(lines, 80, lipgloss.Width); strings.Join(got, "|") != "a    e    i|b    f    j|c    g    k|d    h    l" {
+		t.Errorf("expected three columns filled top to bottom, got %q", got)
+	}
+	if got := flowColumns(lines[:9], 80, lipgloss.Width); len(got) != 5 || got[4] != "e" {
+		t.Errorf("expected two columns of at least %d lines, got %q", minFlowRows, got)
+	}
+	if got := flowColumns(lines, 6, lipgloss.Width); len(got) != 6 {
+		t.Errorf("expected two columns in 6 cells, got %q", got)
+	}
+	if got := flowColumns(lines[:7], 80, lipgloss.Width); len(got) != 7 {
+		t.Errorf("expected short sections to stay in one column, got %q", got)
+	}
+}
+
+func TestRenderFullStateBadges(t *testing.T) {
+	snap := Snapshot{
+		Time:         time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		Temperatures: []TemperatureSensor{{Name: "CPU", Value: 50, High: 80, Critical: 100}},
+		Groups: []GroupSnapshot{{Name: "Fans", Readings: []SensorReading{
+			{Name: "fan1", State: StateWarning},
+			{Name: "fan2", State: StateWarning},
+			{Name: "fan3", State: StateCritical},
+		}}},
+	}
+	view := ViewState{Collapsed: map[string]bool{"Fans": true}}
+	out := ansi.Strip(RenderFull(snap, 80, 24, DefaultTheme, view))
+	if !strings.Contains(out, "Fans [2⚠ 1✖]\n") {
+		t.Errorf("expected a badge on the collapsed group, got:\n%s", out)
+	}
+	if strings.Contains(out, "Temperatures [") {
+		t.Errorf("expected no badge when every temperature is OK, got:\n%s", out)
+	}
+
+	view.ASCII = true
+	if out := ansi.Strip(RenderFull(snap, 80, 24, DefaultTheme, view)); !strings.Contains(out, "Fans [2w 1c]\n") {
+		t.Errorf("expected letters in ASCII mode, got:\n%s", out)
+	}
+}
+
+func TestRenderFullDeepDischarge(t *testing.T) {
+	snap := Snapshot{
+		Time:    time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		Battery: BatteryStatus{Capacity: 45, Status: "Discharging", Voltage: 3.14, VoltageMinDesign: 3},
+	}
+	out := ansi.Strip(RenderFull(snap, 100, 30, DefaultTheme, ViewState{}))
+	if !strings.Contains(out, "Voltage: 3.14 V (min 3.00)") || !strings.Contains(out, "Deep discharge risk") {
+		t.Errorf("expected the voltage against its design minimum and a warning, got:\n%s", out)
+	}
+}
+
+func TestRenderFullTitle(t *testing.T) {
+	snap := Snapshot{
+		Hostname:     "box1",
+		Time:         time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC),
+		Temperatures: []TemperatureSensor{{Name: "CPU", Value: 55, High: 80, Critical: 100, Path: "thermal_zone0"}},
+	}
+	out := RenderFull(snap, 80, 24, DefaultTheme, ViewState{Title: "rack 3"})
+	if !strings.Contains(out, "rack 3 — box1") || strings.Contains(out, DefaultTitle) {
+		t.Errorf("expected the custom title with the host on one line:\n%s", out)
+	}
+
+	hidden := RenderFull(snap, 80, 24, DefaultTheme, ViewState{HideTitle: true})
+	if strings.Contains(hidden, DefaultTitle) || !strings.HasPrefix(ansi.Strip(hidden), "Temperatures") {
+		t.Errorf("expected no title line:\n%s", hidden)
+	}
+	if !strings.Contains(hidden, "box1 | Last updated: 12:30:00") {
+		t.Errorf("expected the host in the footer:\n%s", hidden)
+	}
+	if got, want := strings.Count(hidden, "\n"), strings.Count(out, "\n")-titleHeight; got != want {
+		t.Errorf("expected the title's %d lines reclaimed, got %d lines instead of %d", titleHeight, got, want)
+	}
+}
+
+func TestViewWithoutTitleStaysFullLonger(t *testing.T) {
+	m := NewMonitor(WithTitle(""))
+	m.temperatureSensors = []TemperatureSensor{{Name: "CPU", Value: 65, High: 80, Critical: 100, Path: "thermal_zone0"}}
+	m.width, m.height = 80, compactHeightThreshold-titleHeight
+	if out := m.View(); !strings.Contains(out, "Temperatures") {
+		t.Errorf("expected the full view without a title at height %d:\n%s", m.height, out)
+	}
+	m.height--
+	if out := m.View(); strings.Contains(out, "Temperatures") {
+		t.Errorf("expected the compact view at height %d:\n%s", m.height, out)
+	}
+}
+
+func TestAsleepTemperatureDisplay(t *testing.T) {
+	lcrit := 5.0
+	snap := Snapshot{Temperatures: []TemperatureSensor{
+		{Name: "edge", High: 80, Critical: 100, LowCritical: &lcrit, Path: "hwmon0/temp1_input", Asleep: true},
+	}}
+	if state := snap.Temperatures[0].State(); state != StateOK {
+		t.Errorf("expected an asleep sensor to be OK, got %v", state)
+	}
+	if _, ok := snap.Headroom(); ok {
+		t.Error("expected no headroom from an asleep sensor")
+	}
+	full := ansi.Strip(RenderFull(snap, 80, 24, DefaultTheme, ViewState{}))
+	if !strings.Contains(full, "asleep  hwmon0/temp1_input") || strings.Contains(full, "0.0°C") {
+		t.Errorf("expected the row to say asleep instead of a value:\n%s", full)
+	}
+	if compact := ansi.Strip(RenderCompact(snap, 80, DefaultTheme, ViewState{})); !strings.Contains(compact, "asleep") {
+		t.Errorf("expected the compact view to say asleep:\n%s", compact)
+	}
+}
+
+// poolTable is a GroupRenderer drawing ZFS pools, fed by GenericSensors
+// reporting each pool's health, as a table
+type poolTable struct {
+	pools []Sensor
+}
+
+func (p poolTable) RenderGroup(width int, theme Theme) string {
+	lines := []string{padRight("POOL", 8) + padRight("HEALTH", 10) + "STATE"}
+	for _, pool := range p.pools {
+		state := sensorState(pool)
+		lines = append(lines, padRight(pool.Name(), 8)+padRight(theme.stateStyle(state).Render(pool.Value()), 10)+state.String())
+	}
+	for i, line := range lines {
+		if width > 0 {
+			lines[i] = truncateWidth(line, width)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func newPoolGroup() SensorGroup {
+	pool := func(name, health string) Sensor {
+		sensor := NewGenericSensor(name, func() (string, bool, bool, error) {
+			return health, health != "ONLINE", health == "FAULTED", nil
+		})
+		sensor.Refresh()
+		return sensor
+	}
+	pools := []Sensor{pool("tank", "ONLINE"), pool("backup", "DEGRADED")}
+	return SensorGroup{Name: "ZFS", Sensors: pools, Renderer: poolTable{pools: pools}}
+}
+
+func ExampleGroupRenderer() {
+	group := newPoolGroup()
+	fmt.Println(ansi.Strip(group.Renderer.RenderGroup(40, DefaultTheme)))
+	// Output:
+	// POOL    HEALTH    STATE
+	// tank    ONLINE    ok
+	// backup  DEGRADED  warning
+}
+
+func TestRenderFullUsesGroupRenderer(t *testing.T) {
+	m := NewMonitor()
+	m.RegisterSensorGroup(newPoolGroup())
+	m.refreshGroups(m.clock.Now())
+	snap := m.Snapshot()
+
+	full := ansi.Strip(RenderFull(snap, 80, 24, DefaultTheme, ViewState{}))
+	if !strings.Contains(full, "ZFS [1⚠]\n  POOL    HEALTH    STATE\n  tank ") {
+		t.Errorf("expected the table indented under the badged header:\n%s", full)
+	}
+	if strings.Contains(full, "backup: DEGRADED") {
+		t.Errorf("expected the table instead of the default lines:\n%s", full)
+	}
+
+	// The renderer gets the width left by the indent
+	for _, line := range strings.Split(ansi.Strip(RenderFull(snap, 14, 24, DefaultTheme, ViewState{})), "\n") {
+		if strings.HasPrefix(line, "  backup") && ansi.StringWidth(line) > 14 {
+			t.Errorf("expected the table within 14 cells, got %q", line)
+		}
+	}
+
+	collapsed := ansi.Strip(RenderFull(snap, 80, 24, DefaultTheme, ViewState{Collapsed: map[string]bool{"ZFS": true}}))
+	if strings.Contains(collapsed, "POOL") || !strings.Contains(collapsed, "(collapsed, 2 sensors)") {
+		t.Errorf("expected a collapsed group to hide the table:\n%s", collapsed)
+	}
+
+	if compact := ansi.Strip(RenderCompact(snap, 200, DefaultTheme, ViewState{})); strings.Contains(compact, "POOL") {
+		t.Errorf("expected the compact view to ignore the renderer:\n%s", compact)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	snap := demoSnapshot(1, 5)
+	for width, want := range map[int]string{
+		0:  "🌡 54.5°C   55.3°C   59.5°C   42.2°C   60.2°C | 🔋 68% Discharging 12.12V",
+		50: "🌡 59.5°C   60.2°C +3 | 🔋 68% Discharging 12.12V",
+	} {
+		if got := Summary(snap, width, false); got != want {
+			t.Errorf("width %d: expected %q, got %q", width, want, got)
+		}
+	}
+	// The compact view opens with the same line
+	compact := RenderCompact(snap, 50, DefaultTheme, ViewState{})
+	if first, _, _ := strings.Cut(ansi.Strip(compact), "\n"); first != Summary(snap, 50, false) {
+		t.Errorf("expected the compact view to open with the summary, got %q", first)
+	}
+}
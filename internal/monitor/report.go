@@ -0,0 +1,184 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// historyGap is the longest pause between two history records still counted
+// as monitored time; longer ones are suspends or the monitor not running
+const historyGap = 5 * time.Minute
+
+// Report summarizes a span of the history file
+type Report struct {
+	SchemaVersion int            `json:"schema_version"`
+	From          time.Time      `json:"from"`
+	To            time.Time      `json:"to"`
+	Records       int            `json:"records"`
+	Sensors       []SensorReport `json:"sensors"`
+	Battery       *BatteryReport `json:"battery,omitempty"`
+}
+
+// SensorReport summarizes one temperature, in Celsius
+type SensorReport struct {
+	Name string  `json:"name"`
+	Min  float64 `json:"min"`
+	Avg  float64 `json:"avg"`
+	Max  float64 `json:"max"`
+	// OKSeconds, WarningSeconds and CriticalSeconds are the monitored time
+	// spent in each state
+	OKSeconds       float64 `json:"ok_seconds"`
+	WarningSeconds  float64 `json:"warning_seconds"`
+	CriticalSeconds float64 `json:"critical_seconds"`
+	// Crossings counts the readings that went to a worse state
+	Crossings int `json:"crossings"`
+}
+
+// BatteryReport summarizes the battery over the span
+type BatteryReport struct {
+	MinCapacity int `json:"min_capacity"` // the deepest discharge
+	MaxCapacity int `json:"max_capacity"`
+	// ChargeSessions counts the times charging started
+	ChargeSessions int `json:"charge_sessions"`
+	// FullCycles is the capacity discharged in total, in full batteries
+	FullCycles float64 `json:"full_cycles"`
+	// DrainPerHour is the average capacity lost per hour while discharging
+	DrainPerHour float64 `json:"drain_percent_per_hour"`
+	// DischargePower is the average power drawn while discharging, in watts
+	DischargePower float64 `json:"discharge_watts,omitempty"`
+	// Energy is the energy delivered and taken in over monitored time,
+	// nil when the records carry no energy
+	Energy *EnergyTotals `json:"energy,omitempty"`
+}
+
+// NewReport summarizes history records, oldest first
+func NewReport(records []HistoryRecord) Report {
+	report := Report{SchemaVersion: SchemaVersion, Records: len(records)}
+	if len(records) == 0 {
+		return report
+	}
+	report.From, report.To = records[0].Time, records[len(records)-1].Time
+
+	type totals struct {
+		SensorReport
+		sum   float64
+		count int
+	}
+	sensors := make(map[string]*totals)
+	var battery *BatteryReport
+	var discharging time.Duration
+	var drained, drainedDischarging, powerSum float64
+	var powerCount int
+	var energy EnergyTotals
+	hasEnergy := false
+
+	for i, record := range records {
+		for name, reading := range record.Temperatures {
+			t, ok := sensors[name]
+			if !ok {
+				t = &totals{SensorReport: SensorReport{Name: name, Min: reading.Value, Max: reading.Value}}
+				sensors[name] = t
+			}
+			t.Min = min(t.Min, reading.Value)
+			t.Max = max(t.Max, reading.Value)
+			t.sum += reading.Value
+			t.count++
+		}
+		if bat := record.Battery; bat != nil {
+			if battery == nil {
+				battery = &BatteryReport{MinCapacity: bat.Capacity, MaxCapacity: bat.Capacity}
+			}
+			battery.MinCapacity = min(battery.MinCapacity, bat.Capacity)
+			battery.MaxCapacity = max(battery.MaxCapacity, bat.Capacity)
+			hasEnergy = hasEnergy || bat.Energy > 0
+		}
+		if i == 0 {
+			continue
+		}
+
+		prev := records[i-1]
+		dt := record.Time.Sub(prev.Time)
+		monitored := dt > 0 && dt <= historyGap
+		for name, reading := range prev.Temperatures {
+			t := sensors[name]
+			if monitored {
+				switch reading.State {
+				case StateWarning:
+					t.WarningSeconds += dt.Seconds()
+				case StateCritical:
+					t.CriticalSeconds += dt.Seconds()
+				default:
+					t.OKSeconds += dt.Seconds()
+				}
+			}
+			if next, ok := record.Temperatures[name]; ok && next.State > reading.State {
+				t.Crossings++
+			}
+		}
+		if prevBat, bat := prev.Battery, record.Battery; prevBat != nil && bat != nil {
+			if bat.Status == "Charging" && prevBat.Status != "Charging" {
+				battery.ChargeSessions++
+			}
+			// Capacity lost while suspended still counts as discharged
+			if drop := prevBat.Capacity - bat.Capacity; drop > 0 && prevBat.Status != "Charging" {
+				drained += float64(drop)
+				if monitored && prevBat.Status == "Discharging" {
+					drainedDischarging += float64(drop)
+				}
+			}
+			energy.add(prevBat.status(), bat.status(), !monitored)
+			if monitored && prevBat.Status == "Discharging" {
+				discharging += dt
+				if prevBat.Power > 0 {
+					powerSum += prevBat.Power
+					powerCount++
+				}
+			}
+		}
+	}
+
+	for _, t := range sensors {
+		t.Avg = t.sum / float64(t.count)
+		report.Sensors = append(report.Sensors, t.SensorReport)
+	}
+	sort.Slice(report.Sensors, func(i, j int) bool { return report.Sensors[i].Name < report.Sensors[j].Name })
+
+	if battery != nil {
+		battery.FullCycles = drained / 100
+		if hours := discharging.Hours(); hours > 0 {
+			battery.DrainPerHour = drainedDischarging / hours
+		}
+		if powerCount > 0 {
+			battery.DischargePower = powerSum / float64(powerCount)
+		}
+		if hasEnergy {
+			battery.Energy = &energy
+		}
+		report.Battery = battery
+	}
+	return report
+}
+
+// ParseSince turns a --since argument into a start time: a duration back
+// from now ("24h", "90m", or days such as "7d") or an RFC 3339 timestamp
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("negative duration %q", s)
+		}
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a duration (24h, 7d) nor an RFC 3339 time", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,168 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "history.jsonl")
+	m := NewMonitor(WithHistoryFile(path))
+	m.hostname = "laptop"
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	m.temperatureSensors = []TemperatureSensor{{Name: "CPU", Value: 85, High: 80, Critical: 100}}
+	m.batteryStatus = BatteryStatus{Capacity: 50, Status: "Discharging"}
+	m.recordHistory(start)
+	m.temperatureSensors[0].Value = 60
+	m.recordHistory(start.Add(time.Minute))
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A line cut short by a crash is skipped
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2026-03-01T12:02:00Z","temp`)
+	f.Close()
+
+	records, err := ReadHistory(path, start.Add(30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected the record after the start time, got %d", len(records))
+	}
+	r := records[0]
+	if r.Host != "laptop" || r.Temperatures["CPU"] != (HistoryReading{Value: 60, State: StateOK}) || r.Battery.Capacity != 50 {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if records, _ := ReadHistory(path, start); records[0].Temperatures["CPU"].State != StateWarning {
+		t.Errorf("expected the warning state to round-trip, got %+v", records[0])
+	}
+}
+
+func TestHistoryRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	h := &historyFile{path: path}
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	record := func(i int) HistoryRecord {
+		return HistoryRecord{SchemaVersion: SchemaVersion, Time: start.Add(time.Duration(i) * time.Minute), Host: "laptop"}
+	}
+	line, _ := json.Marshal(record(0))
+	// Three records fit under the limit, the fourth starts a new file
+	limit := int64(3 * (len(line) + 1))
+	for i := range 8 {
+		if err := h.write(record(i), limit); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.close()
+
+	// Records 0-2 went with the first rotation, 3-5 are in path.1
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("expected 2 records since the last rotation, got %d", lines)
+	}
+	var times []string
+	err = ScanHistory(path, start.Add(4*time.Minute), func(r HistoryRecord) error {
+		times = append(times, r.Time.Format("15:04"))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(times, ","); got != "12:04,12:05,12:06,12:07" {
+		t.Errorf("expected the records since 12:04 across both files in order, got %s", got)
+	}
+
+	// fn's error stops the scan
+	stop := errors.New("enough")
+	calls := 0
+	err = ScanHistory(path, time.Time{}, func(HistoryRecord) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("expected the scan to stop at fn's error, got %v after %d calls", err, calls)
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	temp := func(v float64, s State) map[string]HistoryReading {
+		return map[string]HistoryReading{"CPU": {Value: v, State: s}}
+	}
+	bat := func(capacity int, status string, power float64) *HistoryBattery {
+		return &HistoryBattery{Capacity: capacity, Status: status, Power: power}
+	}
+	records := []HistoryRecord{
+		{Time: start, Temperatures: temp(50, StateOK), Battery: bat(80, "Discharging", 10)},
+		{Time: start.Add(time.Minute), Temperatures: temp(85, StateWarning), Battery: bat(79, "Discharging", 12)},
+		{Time: start.Add(2 * time.Minute), Temperatures: temp(105, StateCritical), Battery: bat(78, "Discharging", 0)},
+		// Suspended for an hour: not monitored time, but the drop counts
+		{Time: start.Add(62 * time.Minute), Temperatures: temp(40, StateOK), Battery: bat(70, "Charging", 0)},
+		{Time: start.Add(63 * time.Minute), Temperatures: temp(42, StateOK), Battery: bat(72, "Full", 0)},
+		{Time: start.Add(64 * time.Minute), Temperatures: temp(44, StateOK), Battery: bat(72, "Charging", 0)},
+	}
+	r := NewReport(records)
+
+	if r.Records != 6 || !r.From.Equal(start) || !r.To.Equal(start.Add(64*time.Minute)) {
+		t.Errorf("unexpected span %v..%v (%d records)", r.From, r.To, r.Records)
+	}
+	if len(r.Sensors) != 1 {
+		t.Fatalf("expected one sensor, got %+v", r.Sensors)
+	}
+	s := r.Sensors[0]
+	if s.Min != 40 || s.Max != 105 || s.Avg != 61 {
+		t.Errorf("unexpected min/avg/max %v/%v/%v", s.Min, s.Avg, s.Max)
+	}
+	if s.OKSeconds != 180 || s.WarningSeconds != 60 || s.CriticalSeconds != 0 || s.Crossings != 2 {
+		t.Errorf("expected 180s OK, 60s warning, no monitored critical time and 2 crossings, got %+v", s)
+	}
+
+	b := r.Battery
+	if b == nil {
+		t.Fatal("expected a battery report")
+	}
+	if b.MinCapacity != 70 || b.MaxCapacity != 80 || b.ChargeSessions != 2 {
+		t.Errorf("unexpected battery report %+v", b)
+	}
+	if b.FullCycles != 0.1 || b.DrainPerHour != 60 || b.DischargePower != 11 {
+		t.Errorf("expected 0.1 cycles, 60%%/h and 11 W, got %+v", b)
+	}
+
+	if empty := NewReport(nil); empty.Records != 0 || empty.Battery != nil {
+		t.Errorf("expected an empty report, got %+v", empty)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"24h":                  now.Add(-24 * time.Hour),
+		"90m":                  now.Add(-90 * time.Minute),
+		"7d":                   time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		"2026-03-05T08:00:00Z": time.Date(2026, 3, 5, 8, 0, 0, 0, time.UTC),
+	}
+	for in, want := range tests {
+		got, err := ParseSince(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseSince(%q) = %v, %v; expected %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "yesterday", "-1h", "2026-03-05"} {
+		if _, err := ParseSince(in, now); err == nil {
+			t.Errorf("expected an error for %q", in)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package monitor
+
+import "fmt"
+
+// SchemaVersion is the version of the machine-readable outputs: the event
+// stream, the history file, the snapshots sent to instance clients, the
+// JSON report and the Prometheus metrics, each of which carries it as
+// schema_version. It goes up when a field is renamed, removed or changes
+// meaning, not when one is added. Readers refuse newer versions and migrate
+// older ones.
+const SchemaVersion = 1
+
+// SchemaError is a record written with a newer schema than this build reads
+type SchemaError struct {
+	// What names the output, e.g. "history file"
+	What    string
+	Version int
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s has schema version %d, newer than the supported %d; upgrade sysfs-monitor-tui to read it", e.What, e.Version, SchemaVersion)
+}
+
+// checkSchema returns the schema version of a record, reading the missing
+// field of records written before it existed as version 1, or a
+// SchemaError for a version newer than SchemaVersion
+func checkSchema(what string, version int) (int, error) {
+	if version > SchemaVersion {
+		return version, &SchemaError{What: what, Version: version}
+	}
+	return max(version, 1), nil
+}
+
+// historyMigrations bring history records of an older schema, by version,
+// up to the next one; ReadHistory applies them in turn. Version 1 is the
+// first, so there are none yet.
+var historyMigrations = map[int]func(*HistoryRecord){}
+
+// migrateHistory brings a record of the given version up to SchemaVersion
+func migrateHistory(record *HistoryRecord, version int) {
+	for ; version < SchemaVersion; version++ {
+		if migrate := historyMigrations[version]; migrate != nil {
+			migrate(record)
+		}
+	}
+	record.SchemaVersion = SchemaVersion
+}
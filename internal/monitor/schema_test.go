@@ -0,0 +1,94 @@
+package monitor
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// historyV1 is a history file as written before records carried their
+// schema version
+const historyV1 = `{"time":"2026-03-01T12:00:00Z","host":"laptop","temperatures":{"CPU":{"value":85,"state":"warning"}},"battery":{"capacity":50,"status":"Discharging","ac_online":false,"power":9.5}}
+{"time":"2026-03-01T12:01:00Z","host":"laptop","temperatures":{"CPU":{"value":60,"state":"ok"}}}
+`
+
+func TestReadHistoryV1RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	v1 := filepath.Join(dir, "v1.jsonl")
+	if err := os.WriteFile(v1, []byte(historyV1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	records, err := ReadHistory(v1, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].SchemaVersion != SchemaVersion || records[0].Temperatures["CPU"].State != StateWarning || records[0].Battery.Power != 9.5 {
+		t.Fatalf("unexpected records %+v", records)
+	}
+
+	// Written back by the current writer, they read the same
+	current := &historyFile{path: filepath.Join(dir, "current.jsonl")}
+	for _, record := range records {
+		if err := current.write(record, DefaultHistoryMaxMB<<20); err != nil {
+			t.Fatal(err)
+		}
+	}
+	current.close()
+	data, _ := os.ReadFile(current.path)
+	if !strings.HasPrefix(string(data), `{"schema_version":1,"time":`) {
+		t.Errorf("expected the schema version first in each record, got %s", data)
+	}
+	again, err := ReadHistory(current.path, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, records) {
+		t.Errorf("records changed on the round trip:\n%+v\n%+v", records, again)
+	}
+}
+
+func TestReadHistoryRefusesNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	newer := historyV1 + `{"schema_version":2,"time":"2026-03-01T12:02:00Z","readings":[]}` + "\n"
+	if err := os.WriteFile(path, []byte(newer), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ReadHistory(path, time.Time{})
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || schemaErr.Version != 2 {
+		t.Fatalf("expected a schema error for version 2, got %v", err)
+	}
+	if want := "schema version 2, newer than the supported 1"; !strings.Contains(err.Error(), want) {
+		t.Errorf("expected %q in %q", want, err)
+	}
+}
+
+func TestInstanceClientRefusesNewerSchema(t *testing.T) {
+	server, conn := net.Pipe()
+	c := &InstanceClient{conn: conn}
+	go c.receive()
+	go server.Write([]byte(`{"SchemaVersion":3,"Hostname":"box1"}` + "\n"))
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		snap, err := c.Snapshot()
+		if err != nil {
+			var schemaErr *SchemaError
+			if !errors.As(err, &schemaErr) || snap.Hostname != "" {
+				t.Errorf("expected the snapshot refused with a schema error, got %+v, %v", snap, err)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no error from a newer instance")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	server.Close()
+	c.Close()
+}
@@ -0,0 +1,353 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultScriptTimeout bounds a script run
+	DefaultScriptTimeout = 5 * time.Second
+
+	// DefaultScriptConcurrency is how many scripts may run at once
+	DefaultScriptConcurrency = 4
+)
+
+// ScriptsConfig configures the sensor scripts in the sensors.d directory
+type ScriptsConfig struct {
+	// Timeout bounds each run, e.g. "5s"
+	Timeout string `json:"timeout,omitempty"`
+	// MaxConcurrent caps how many scripts run at once
+	MaxConcurrent int `json:"max_concurrent,omitempty"`
+	// Intervals sets how often a script runs, keyed by file name, e.g.
+	// {"gpu.sh": "30s"}. Scripts not listed run on every refresh.
+	Intervals map[string]string `json:"intervals,omitempty"`
+}
+
+func (c ScriptsConfig) validate() error {
+	if c.Timeout != "" {
+		if _, err := time.ParseDuration(c.Timeout); err != nil {
+			return fmt.Errorf("scripts timeout: %w", err)
+		}
+	}
+	for name, interval := range c.Intervals {
+		if _, err := time.ParseDuration(interval); err != nil {
+			return fmt.Errorf("scripts interval of %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// DefaultScriptDir returns $XDG_CONFIG_HOME/sysfs-monitor-tui/sensors.d
+func DefaultScriptDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "sysfs-monitor-tui", "sensors.d")
+}
+
+// WithScriptDir runs the executables in dir as sensor scripts. Each prints
+// lines of the form "name|value|state|text", where state is ok, warning or
+// critical and text is optional; the lines of a script become the sensors of
+// a group named after it. A missing directory is not an error.
+func WithScriptDir(dir string) Option {
+	return func(m *Monitor) {
+		m.scriptDir = dir
+	}
+}
+
+// scriptRunner runs the scripts of a directory in the background. It is
+// shared by copies of the Monitor; results are picked up on each refresh.
+type scriptRunner struct {
+	dir      string
+	timeout  time.Duration
+	interval time.Duration
+	settings ScriptsConfig
+	sem      chan struct{}
+	wg       sync.WaitGroup
+
+	mu      sync.Mutex
+	scripts []*script
+}
+
+// script is one executable of the directory and its last result
+type script struct {
+	name     string
+	path     string
+	interval time.Duration
+	nextRun  time.Time
+	running  bool
+	ran      bool
+	sensors  []Sensor
+	// started is when the running or last run started; readAt is when the
+	// sensors' run started
+	started time.Time
+	readAt  time.Time
+}
+
+func newScriptRunner(dir string, settings ScriptsConfig, interval time.Duration) *scriptRunner {
+	r := &scriptRunner{
+		dir:      dir,
+		timeout:  DefaultScriptTimeout,
+		interval: interval,
+		settings: settings,
+		sem:      make(chan struct{}, DefaultScriptConcurrency),
+	}
+	if d, err := time.ParseDuration(settings.Timeout); err == nil && d > 0 {
+		r.timeout = d
+	}
+	if settings.MaxConcurrent > 0 {
+		r.sem = make(chan struct{}, settings.MaxConcurrent)
+	}
+	r.scan()
+	return r
+}
+
+// scan (re)reads the directory, keeping the last result of scripts that are
+// still there, and returns how many scripts were found
+func (r *scriptRunner) scan() int {
+	entries, _ := os.ReadDir(r.dir)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var scripts []*script
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode()&0o111 == 0 {
+			continue
+		}
+		name := entry.Name()
+		i := slices.IndexFunc(r.scripts, func(s *script) bool { return s.name == name })
+		if i >= 0 {
+			scripts = append(scripts, r.scripts[i])
+			continue
+		}
+		s := &script{name: name, path: filepath.Join(r.dir, name), interval: r.interval}
+		if d, err := time.ParseDuration(r.settings.Intervals[name]); err == nil && d > 0 {
+			s.interval = d
+		}
+		scripts = append(scripts, s)
+	}
+	r.scripts = scripts
+	return len(scripts)
+}
+
+// run starts the scripts that are due and not already running
+func (r *scriptRunner) run(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.scripts {
+		if s.running || now.Before(s.nextRun) {
+			continue
+		}
+		s.running = true
+		s.started = now
+		s.nextRun = now.Add(s.interval)
+		r.wg.Add(1)
+		go r.exec(s)
+	}
+}
+
+func (r *scriptRunner) exec(s *script) {
+	defer r.wg.Done()
+	r.sem <- struct{}{}
+	sensors := runScript(s.path, r.timeout)
+	<-r.sem
+
+	r.mu.Lock()
+	s.sensors = sensors
+	s.readAt = s.started
+	s.running = false
+	s.ran = true
+	r.mu.Unlock()
+}
+
+// wait blocks until the running scripts finish
+func (r *scriptRunner) wait() {
+	r.wg.Wait()
+}
+
+// groups returns a group per script that has finished at least one run;
+// a script whose group name is taken gets an error instead (see groupName)
+func (r *scriptRunner) groups(taken map[string]bool) []SensorGroup {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var groups []SensorGroup
+	for _, s := range r.scripts {
+		if !s.ran {
+			continue
+		}
+		if name, ok := r.groupName(s, taken); ok {
+			groups = append(groups, SensorGroup{Name: name, Sensors: s.sensors})
+		} else {
+			err := fmt.Errorf("group name %q is taken by a built-in group, rename the script", scriptGroupName(s.name))
+			groups = append(groups, SensorGroup{Name: name, Sensors: []Sensor{scriptError(err)}})
+		}
+	}
+	return groups
+}
+
+// readTimes returns when the sensors of each script group were read, keyed
+// by group name
+func (r *scriptRunner) readTimes(taken map[string]bool) map[string]time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	times := make(map[string]time.Time, len(r.scripts))
+	for _, s := range r.scripts {
+		if s.ran {
+			name, _ := r.groupName(s, taken)
+			times[name] = s.readAt
+		}
+	}
+	return times
+}
+
+// groupName names a script's group after its file. A name taken by
+// another group is refused: ok is false and the name is the file's path
+// in the directory, e.g. "sensors.d/Display.sh", for the script's error.
+func (r *scriptRunner) groupName(s *script, taken map[string]bool) (name string, ok bool) {
+	name = scriptGroupName(s.name)
+	if taken[name] {
+		return filepath.Join(filepath.Base(r.dir), s.name), false
+	}
+	return name, true
+}
+
+// scriptGroupName names a script's group after its file, without extension
+func scriptGroupName(file string) string {
+	if name := strings.TrimSuffix(file, filepath.Ext(file)); name != "" {
+		return name
+	}
+	return file
+}
+
+// runScript runs a script and parses its output. A failure yields a single
+// "Error" sensor so the group stays visible.
+func runScript(path string, timeout time.Duration) []Sensor {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path)
+	// Don't wait for children still holding the output open
+	cmd.WaitDelay = time.Second
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		return []Sensor{scriptError(fmt.Errorf("timed out after %s", timeout))}
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if line, _, _ := bytes.Cut(bytes.TrimSpace(exitErr.Stderr), []byte("\n")); len(line) > 0 {
+				err = fmt.Errorf("%w: %s", err, line)
+			}
+		}
+		return []Sensor{scriptError(err)}
+	}
+	sensors, err := parseScriptOutput(out)
+	if err != nil {
+		return []Sensor{scriptError(err)}
+	}
+	return sensors
+}
+
+// parseScriptOutput turns "name|value|state|text" lines into sensors.
+// Blank lines are skipped; any other malformed line fails the whole run.
+func parseScriptOutput(out []byte) ([]Sensor, error) {
+	var sensors []Sensor
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "|", 4)
+		if len(fields) < 2 || strings.TrimSpace(fields[0]) == "" {
+			return nil, fmt.Errorf("line %d: expected name|value|state|text", n)
+		}
+		s := &scriptSensor{name: strings.TrimSpace(fields[0]), value: strings.TrimSpace(fields[1])}
+		if len(fields) > 2 {
+			switch state := strings.TrimSpace(fields[2]); state {
+			case "", "ok":
+			case "warning":
+				s.state = StateWarning
+			case "critical":
+				s.state = StateCritical
+			default:
+				return nil, fmt.Errorf("line %d: unknown state %q", n, state)
+			}
+		}
+		if len(fields) > 3 {
+			s.text = strings.TrimSpace(fields[3])
+		}
+		sensors = append(sensors, s)
+	}
+	if len(sensors) == 0 {
+		return nil, errors.New("no sensor lines")
+	}
+	return sensors, nil
+}
+
+func scriptError(err error) Sensor {
+	return &scriptSensor{name: "Error", value: err.Error(), state: StateWarning}
+}
+
+// scriptSensor is one line of a script's output
+type scriptSensor struct {
+	name  string
+	value string
+	state State
+	text  string
+}
+
+func (s *scriptSensor) Name() string {
+	return s.name
+}
+
+// Value shows the reading followed by the script's text, if any
+func (s *scriptSensor) Value() string {
+	if s.text != "" {
+		return s.value + " (" + s.text + ")"
+	}
+	return s.value
+}
+
+// Kind is told by the unit of the value, e.g. "1200 RPM" is a fan
+func (s *scriptSensor) Kind() Kind {
+	kind, _, _ := parseMeasurement(s.value)
+	return kind
+}
+
+func (s *scriptSensor) Measurement() (float64, bool) {
+	_, n, ok := parseMeasurement(s.value)
+	return n, ok
+}
+
+func (s *scriptSensor) Warning() bool {
+	return s.state == StateWarning
+}
+
+func (s *scriptSensor) Critical() bool {
+	return s.state == StateCritical
+}
+
+// Refresh does nothing; the runner replaces the sensors after each run
+func (s *scriptSensor) Refresh() error {
+	return nil
+}
+
+// reloadScripts rescans the script directory, returning a status message
+func (m Monitor) reloadScripts() string {
+	if m.scripts == nil {
+		return ""
+	}
+	return fmt.Sprintf("Reloaded %d sensor scripts", m.scripts.scan())
+}
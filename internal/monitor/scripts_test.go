@@ -0,0 +1,151 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeScript(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseScriptOutput(t *testing.T) {
+	sensors, err := parseScriptOutput([]byte("fan|1200 RPM|ok\n\npump|0 RPM|critical|stalled\nmode|quiet\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sensors) != 3 {
+		t.Fatalf("expected 3 sensors, got %d", len(sensors))
+	}
+	if s := sensors[1]; s.Name() != "pump" || s.Value() != "0 RPM (stalled)" || !s.Critical() {
+		t.Errorf("unexpected pump sensor: %s = %s", s.Name(), s.Value())
+	}
+	if s := sensors[2]; s.Value() != "quiet" || s.Warning() || s.Critical() {
+		t.Errorf("expected an OK mode sensor without state field, got %s", s.Value())
+	}
+
+	for _, out := range []string{"", "novalue\n", "fan|1|hot\n"} {
+		if _, err := parseScriptOutput([]byte(out)); err == nil {
+			t.Errorf("expected an error for %q", out)
+		}
+	}
+}
+
+func TestScriptGroups(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "gpu.sh", "echo 'GPU|61°C|ok'\necho 'VRAM|7.1 GiB|warning|almost full'\n")
+	writeScript(t, dir, "broken.sh", "echo 'no device' >&2\nexit 3\n")
+	writeScript(t, dir, "slow.sh", "sleep 5\n")
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("not a script"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMonitor()
+	m.scripts = newScriptRunner(dir, ScriptsConfig{Timeout: "200ms"}, time.Minute)
+	m.RegisterSensorGroup(SensorGroup{Name: "Display"})
+	m.refreshDynamicGroups(time.Now())
+	m.scripts.wait()
+	m.refreshDynamicGroups(time.Now())
+
+	var names []string
+	for _, g := range m.extraGroups {
+		names = append(names, g.Name)
+	}
+	if got := strings.Join(names, ","); got != "Display,broken,gpu,slow" {
+		t.Fatalf("expected Display and a group per script, got %q", got)
+	}
+	if gpu := m.extraGroups[2]; len(gpu.Sensors) != 2 || !gpu.Sensors[1].Warning() {
+		t.Errorf("expected two gpu sensors with a VRAM warning, got %d", len(gpu.Sensors))
+	}
+	if s := m.extraGroups[1].Sensors; len(s) != 1 || s[0].Name() != "Error" || s[0].Value() != "exit status 3: no device" {
+		t.Errorf("expected the broken script's error, got %s", s[0].Value())
+	}
+	if s := m.extraGroups[3].Sensors; len(s) != 1 || !strings.HasPrefix(s[0].Value(), "timed out") {
+		t.Errorf("expected the slow script to time out, got %s", s[0].Value())
+	}
+
+	// Refreshing again replaces the groups instead of appending them
+	m.refreshDynamicGroups(time.Now())
+	if len(m.extraGroups) != 4 {
+		t.Errorf("expected 4 groups after another refresh, got %d", len(m.extraGroups))
+	}
+
+	// A reload drops removed scripts
+	if err := os.Remove(filepath.Join(dir, "slow.sh")); err != nil {
+		t.Fatal(err)
+	}
+	if status := m.reloadScripts(); status != "Reloaded 2 sensor scripts" {
+		t.Errorf("unexpected reload status %q", status)
+	}
+	m.refreshDynamicGroups(time.Now())
+	if len(m.extraGroups) != 3 {
+		t.Errorf("expected the slow group to be gone, got %d groups", len(m.extraGroups))
+	}
+}
+
+func TestScriptGroupNamedLikeBuiltIn(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sensors.d")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeScript(t, dir, "Display.sh", "echo 'panel|on'\n")
+
+	m := NewMonitor()
+	m.scripts = newScriptRunner(dir, ScriptsConfig{}, time.Minute)
+	m.RegisterSensorGroup(SensorGroup{Name: "Display", Sensors: []Sensor{newStaticSensor("Brightness", false, false)}})
+	m.refreshDynamicGroups(time.Now())
+	m.scripts.wait()
+
+	// The built-in group stays, however often the groups are refreshed
+	for range 2 {
+		m.refreshDynamicGroups(time.Now())
+		if len(m.extraGroups) != 2 || m.extraGroups[0].Name != "Display" || m.extraGroups[0].Sensors[0].Name() != "Brightness" {
+			t.Fatalf("expected the built-in Display group kept, got %+v", m.extraGroups)
+		}
+	}
+	refused := m.extraGroups[1]
+	if refused.Name != "sensors.d/Display.sh" || len(refused.Sensors) != 1 || !strings.Contains(refused.Sensors[0].Value(), `group name "Display" is taken`) {
+		t.Errorf("expected the script refused with an error, got %s: %v", refused.Name, refused.Sensors)
+	}
+}
+
+func TestScriptIntervals(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(t.TempDir(), "runs")
+	writeScript(t, dir, "count.sh", "echo x >> "+counter+"\necho 'runs|1'\n")
+
+	r := newScriptRunner(dir, ScriptsConfig{Intervals: map[string]string{"count.sh": "10s"}}, time.Second)
+	now := time.Now()
+	for _, offset := range []time.Duration{0, 5 * time.Second, 11 * time.Second} {
+		r.run(now.Add(offset))
+		r.wait()
+	}
+	data, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs := strings.Count(string(data), "x"); runs != 2 {
+		t.Errorf("expected 2 runs with a 10s interval, got %d", runs)
+	}
+}
+
+func TestScriptReadTimes(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "gpu.sh", "echo 'temp|50°C'\n")
+
+	r := newScriptRunner(dir, ScriptsConfig{Intervals: map[string]string{"gpu.sh": "10s"}}, time.Second)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, offset := range []time.Duration{0, 5 * time.Second} {
+		r.run(now.Add(offset))
+		r.wait()
+	}
+	if got := r.readTimes(nil)["gpu"]; !got.Equal(now) {
+		t.Errorf("expected the readings of the run started at %v, got %v", now, got)
+	}
+}
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	procRoot = "/proc"
+
+	// DefaultSelfRSSLimit is the resident memory above which the Self group
+	// warns
+	DefaultSelfRSSLimit = 100 << 20
+
+	// selfFDLimit is the open descriptor count above which the Self group
+	// warns; the held temperature files stay well below it
+	selfFDLimit = 256
+)
+
+// WithSelfSensors adds a "Self" group reporting the monitor's own resident
+// memory, open file descriptors and goroutines, to catch leaks. RSS above
+// rssLimit bytes (DefaultSelfRSSLimit if zero) is a warning.
+func WithSelfSensors(rssLimit uint64) Option {
+	return func(m *Monitor) {
+		m.RegisterSensorGroup(selfSensorGroup(procRoot, rssLimit))
+	}
+}
+
+// selfSensorGroup builds the Self group from the /proc tree under root. It
+// is also an example of a custom SensorGroup: a Sensor type of its own for
+// the byte-valued RSS, and GenericSensors for the simple counters.
+func selfSensorGroup(root string, rssLimit uint64) SensorGroup {
+	if rssLimit == 0 {
+		rssLimit = DefaultSelfRSSLimit
+	}
+	fdDir := filepath.Join(root, "self", "fd")
+	return SensorGroup{
+		Name: "Self",
+		Sensors: []Sensor{
+			&selfRSSSensor{path: filepath.Join(root, "self", "status"), limit: rssLimit},
+			NewGenericSensor("Open files", func() (string, bool, bool, error) {
+				entries, err := os.ReadDir(fdDir)
+				if err != nil {
+					return "", false, false, err
+				}
+				// Reading the directory holds one descriptor itself
+				count := len(entries) - 1
+				return strconv.Itoa(count), count > selfFDLimit, false, nil
+			}),
+			NewGenericSensor("Goroutines", func() (string, bool, bool, error) {
+				return strconv.Itoa(runtime.NumGoroutine()), false, false, nil
+			}),
+		},
+	}
+}
+
+// selfRSSSensor reports VmRSS from /proc/self/status
+type selfRSSSensor struct {
+	path  string
+	limit uint64
+	rss   uint64
+}
+
+func (s *selfRSSSensor) Name() string {
+	return "Memory (RSS)"
+}
+
+func (s *selfRSSSensor) Value() string {
+	return formatBytes(float64(s.rss), IECBytes)
+}
+
+func (s *selfRSSSensor) Bytes() (float64, bool) {
+	return float64(s.rss), false
+}
+
+// Kind is KindInfo: sizes have no kind of their own
+func (s *selfRSSSensor) Kind() Kind {
+	return KindInfo
+}
+
+func (s *selfRSSSensor) Warning() bool {
+	return s.rss > s.limit
+}
+
+func (s *selfRSSSensor) Critical() bool {
+	return false
+}
+
+func (s *selfRSSSensor) Refresh() error {
+	rss, err := readVmRSS(s.path)
+	if err != nil {
+		return err
+	}
+	s.rss = rss
+	return nil
+}
+
+// readVmRSS parses the "VmRSS:  1234 kB" line of a status file into bytes
+func readVmRSS(path string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "VmRSS:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return kb << 10, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("%s: no VmRSS line", path)
+}
@@ -0,0 +1,46 @@
+package monitor
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestSelfSensorGroup(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"self/status":  "Name:\tsysfs-monitor\nVmPeak:\t  40000 kB\nVmRSS:\t   12288 kB\nThreads:\t8\n",
+		"self/fd/0":    "",
+		"self/fd/1":    "",
+		"self/fd/2":    "",
+		"self/fd/3":    "",
+		"self/fd/.dir": "", // stands in for the descriptor ReadDir holds
+	})
+
+	group := selfSensorGroup(root, 10<<20)
+	for _, sensor := range group.Sensors {
+		if err := sensor.Refresh(); err != nil {
+			t.Fatalf("%s: %v", sensor.Name(), err)
+		}
+	}
+	m := NewMonitor()
+	rss, fds, goroutines := group.Sensors[0], group.Sensors[1], group.Sensors[2]
+	if got := m.sensorValue(rss); got != "12.0 MiB" || !rss.Warning() {
+		t.Errorf("expected 12.0 MiB RSS over the 10 MiB cap, got %q warning=%v", got, rss.Warning())
+	}
+	if fds.Value() != "4" || fds.Warning() {
+		t.Errorf("expected 4 open files without warning, got %q warning=%v", fds.Value(), fds.Warning())
+	}
+	if n, err := strconv.Atoi(goroutines.Value()); err != nil || n < 1 {
+		t.Errorf("expected a goroutine count, got %q", goroutines.Value())
+	}
+}
+
+func TestSelfSensorMissingProc(t *testing.T) {
+	group := selfSensorGroup(t.TempDir(), 0)
+	if err := group.Sensors[0].Refresh(); err == nil {
+		t.Error("expected an error without /proc/self/status")
+	}
+	if group.Sensors[0].(*selfRSSSensor).limit != DefaultSelfRSSLimit {
+		t.Error("expected the default RSS limit")
+	}
+}
@@ -1,5 +1,10 @@
 package monitor
 
+import (
+	"fmt"
+	"time"
+)
+
 // Sensor represents a generic system sensor that can be monitored
 type Sensor interface {
 	// Name returns a human-readable identifier
@@ -14,19 +19,137 @@ type Sensor interface {
 	Refresh() error
 }
 
+// Adjustable is implemented by sensors whose underlying setting can be changed
+// from the TUI. Adjust moves the setting one step up (step > 0) or down.
+type Adjustable interface {
+	Sensor
+	Adjust(step int) error
+}
+
+// Clearable is implemented by sensors holding a latched flag that can be
+// reset from the TUI, such as a chassis intrusion. Clear resets the latch.
+type Clearable interface {
+	Sensor
+	Clear() error
+}
+
+// ByteValued is implemented by sensors reporting a size or a rate in bytes
+// per second. The monitor formats them with the configured byte units instead
+// of using Value, so every byte-valued reading follows the same preference.
+type ByteValued interface {
+	Sensor
+	Bytes() (n float64, rate bool)
+}
+
+// CounterSensor is implemented by sensors displaying a rate computed from a
+// monotonic counter, such as an interface's transferred bytes, the only
+// built-in one; embedders' sensors may implement it too. Exporters publish
+// the raw cumulative value and leave rates to the time series database,
+// while the TUI keeps showing the rate.
+type CounterSensor interface {
+	Sensor
+	// Counter returns the cumulative value and its unit in plural form,
+	// e.g. "bytes" or "events"
+	Counter() (value float64, unit string)
+}
+
+// ErrorReporter is implemented by sensors that remember how their last
+// refresh went. The monitor prefers it over the error Refresh returns, so
+// sensors refreshed elsewhere still report accurately.
+type ErrorReporter interface {
+	// LastError returns the error of the last refresh, nil if it succeeded
+	LastError() error
+	// LastSuccess returns when a refresh last succeeded, zero if never
+	LastSuccess() time.Time
+}
+
+// Alarmed is implemented by sensors backed by hwmon alarm flags (see
+// sysfs_alarms.go), whose Warning and Critical already include them
+type Alarmed interface {
+	Sensor
+	// Alarm returns the state the flags forced at the last refresh,
+	// StateOK when none was set
+	Alarm() State
+}
+
+// State is the alert level of a reading
+type State int
+
+const (
+	StateOK State = iota
+	StateWarning
+	StateCritical
+)
+
+func (s State) String() string {
+	switch s {
+	case StateWarning:
+		return "warning"
+	case StateCritical:
+		return "critical"
+	default:
+		return "ok"
+	}
+}
+
+// MarshalText encodes the state by name, as in exported events
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a state name written by MarshalText
+func (s *State) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "ok":
+		*s = StateOK
+	case "warning":
+		*s = StateWarning
+	case "critical":
+		*s = StateCritical
+	default:
+		return fmt.Errorf("unknown state %q", text)
+	}
+	return nil
+}
+
 // SensorGroup represents a collection of sensors under a category
 type SensorGroup struct {
 	Name    string
 	Sensors []Sensor
+	// Renderer draws the group's body in the full view instead of the
+	// default lines; nil for the default, or the discovering provider's
+	// when it implements GroupRenderer
+	Renderer GroupRenderer
+	// dynamic marks the groups replaced on every refresh (see
+	// dynamic_groups.go)
+	dynamic bool
 }
 
-// GenericSensor is a simple implementation of Sensor for basic key-value pairs
+// GroupRenderer draws the body of a group in the full view, e.g. as a table,
+// in place of the "name: value" lines. RenderGroup returns lines of at most
+// width cells (0 when the terminal's width isn't known yet), colored with
+// theme and without a trailing newline; the view indents them under the
+// group's header, which keeps its badges and collapsing. The compact view,
+// the detail view and every other consumer still go through the group's
+// Sensors, and the selection marker isn't drawn inside a custom body.
+type GroupRenderer interface {
+	RenderGroup(width int, theme Theme) string
+}
+
+// GenericSensor is a simple implementation of Sensor for basic key-value pairs.
+// A failed refresh keeps the previous reading and is reported by LastError.
+// Successes are stamped with the monitor's clock when the monitor refreshes
+// the sensor, with the wall clock otherwise.
 type GenericSensor struct {
-	name      string
-	value     string
-	warning   bool
-	critical  bool
-	refreshFn func() (string, bool, bool, error)
+	name        string
+	value       string
+	warning     bool
+	critical    bool
+	refreshFn   func() (string, bool, bool, error)
+	lastErr     error
+	lastSuccess time.Time
+	kind        Kind
+	clock       Clock
 }
 
 func NewGenericSensor(name string, refreshFn func() (string, bool, bool, error)) *GenericSensor {
@@ -55,6 +178,7 @@ func (g *GenericSensor) Critical() bool {
 func (g *GenericSensor) Refresh() error {
 	if g.refreshFn != nil {
 		value, warning, critical, err := g.refreshFn()
+		g.lastErr = err
 		if err != nil {
 			return err
 		}
@@ -62,5 +186,46 @@ func (g *GenericSensor) Refresh() error {
 		g.warning = warning
 		g.critical = critical
 	}
+	g.lastSuccess = g.now()
 	return nil
 }
+
+// useClock stamps the following refreshes with c
+func (g *GenericSensor) useClock(c Clock) {
+	g.clock = c
+}
+
+func (g *GenericSensor) now() time.Time {
+	if g.clock == nil {
+		return time.Now()
+	}
+	return g.clock.Now()
+}
+
+// SetKind declares what the sensor measures, KindInfo by default. The value
+// is then expected to end in the kind's unit, as formatMeasurement writes it.
+func (g *GenericSensor) SetKind(kind Kind) *GenericSensor {
+	g.kind = kind
+	return g
+}
+
+func (g *GenericSensor) Kind() Kind {
+	if g.kind == "" {
+		return KindInfo
+	}
+	return g.kind
+}
+
+// Measurement parses the value when it is in the unit of the sensor's kind
+func (g *GenericSensor) Measurement() (float64, bool) {
+	kind, n, ok := parseMeasurement(g.value)
+	return n, ok && kind == g.Kind()
+}
+
+func (g *GenericSensor) LastError() error {
+	return g.lastErr
+}
+
+func (g *GenericSensor) LastSuccess() time.Time {
+	return g.lastSuccess
+}
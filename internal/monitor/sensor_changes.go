@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newSensorTicks is how many refreshes a temperature found by rediscovery
+// is tagged "new"
+const newSensorTicks = 5
+
+// SensorChange is recorded in the alert history when rediscovery changes
+// the set of temperatures, listing the sensors by name
+type SensorChange struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// String describes the change, e.g. "+nvme Composite, -acpitz"
+func (c SensorChange) String() string {
+	var parts []string
+	for _, name := range c.Added {
+		parts = append(parts, "+"+name)
+	}
+	for _, name := range c.Removed {
+		parts = append(parts, "-"+name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// trackSensorSet compares the temperatures just read with those of the
+// previous refresh, keyed by value file path so a relabeled sensor is
+// neither added nor removed. Changes queue an event for the added sensors
+// and one for the removed ones, and a toast with the config's
+// sensor_change_toast; added sensors are tagged "new" for newSensorTicks
+// refreshes. The first refresh only records the set.
+func (m *Monitor) trackSensorSet(now time.Time) {
+	current := make(map[string]string, len(m.zoneSensors))
+	for _, sensor := range m.zoneSensors {
+		current[valueFilePath(sensor)] = sensor.Name
+	}
+	known := m.knownSensors
+	m.knownSensors = current
+	if known == nil {
+		return
+	}
+
+	fresh := make(map[string]int, len(m.newSensors))
+	for path, left := range m.newSensors {
+		if _, ok := current[path]; ok && left > 1 {
+			fresh[path] = left - 1
+		}
+	}
+	var change SensorChange
+	for _, path := range slices.Sorted(maps.Keys(current)) {
+		if _, ok := known[path]; !ok {
+			change.Added = append(change.Added, current[path])
+			fresh[path] = newSensorTicks
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(known)) {
+		if _, ok := current[path]; !ok {
+			change.Removed = append(change.Removed, known[path])
+		}
+	}
+	m.newSensors = fresh
+	if len(change.Added) == 0 && len(change.Removed) == 0 {
+		return
+	}
+	if len(change.Added) > 0 {
+		m.pending = append(m.pending, m.sensorChangeEvent(now, EventSensorAdded, SensorChange{Added: change.Added}))
+	}
+	if len(change.Removed) > 0 {
+		m.pending = append(m.pending, m.sensorChangeEvent(now, EventSensorRemoved, SensorChange{Removed: change.Removed}))
+	}
+	if m.config.SensorChangeToast {
+		m.toast = "Sensors changed: " + change.String()
+		m.toastUntil = now.Add(toastDuration)
+	}
+}
+
+// sensorChangeEvent is the event of sensors added or removed
+func (m Monitor) sensorChangeEvent(now time.Time, kind EventType, change SensorChange) Event {
+	return Event{
+		Time:    now,
+		Host:    m.hostname,
+		Type:    kind,
+		Sensor:  "Sensors",
+		Value:   change.String(),
+		Sensors: &change,
+	}
+}
+
+// markNew tags the temperatures found by a recent rediscovery
+func (m Monitor) markNew(sensors []TemperatureSensor) []TemperatureSensor {
+	if len(m.newSensors) == 0 {
+		return sensors
+	}
+	result := append([]TemperatureSensor(nil), sensors...)
+	for i := range result {
+		_, result[i].New = m.newSensors[valueFilePath(result[i])]
+	}
+	return result
+}
+
+// newTag is the faint marker of a temperature found by rediscovery
+func newTag(isNew bool) string {
+	if !isNew {
+		return ""
+	}
+	return lipgloss.NewStyle().Faint(true).Render(" new")
+}
+
+// sensorChangeLine is the alert history line of a sensor set change
+func sensorChangeLine(event Event) string {
+	return fmt.Sprintf("  %s %s %s\n", event.Time.Format("15:04:05"), padRight("Sensors", 24), event.Sensors)
+}
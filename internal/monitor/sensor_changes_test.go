@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRediscoveryRecordsSensorChanges(t *testing.T) {
+	m := NewMonitor(WithConfig("", Config{SensorChangeToast: true}))
+	read := func(sensors ...TemperatureSensor) {
+		m.temperatureSensors = sensors
+		m.adjustTemperatures()
+	}
+	cpu := TemperatureSensor{Name: "Package id 0", Value: 50, High: 85, Critical: 100, Path: "/sys/class/hwmon/hwmon1/temp1_input"}
+	wifi := TemperatureSensor{Name: "iwlwifi_1", Value: 40, High: 85, Critical: 100, Path: "/sys/class/hwmon/hwmon5/temp1_input"}
+	nvme := TemperatureSensor{Name: "Composite", Value: 38, High: 70, Critical: 80, Path: "/sys/class/hwmon/hwmon3/temp1_input"}
+
+	read(cpu, wifi)
+	if len(m.pending) != 0 || m.temperatureSensors[0].New {
+		t.Fatal("expected the first sensor set recorded without an event")
+	}
+
+	// The CPU is relabeled, the Wi-Fi card goes away and a drive appears
+	relabeled := cpu
+	relabeled.Name = "CPU"
+	read(relabeled, nvme)
+	if len(m.pending) != 2 || m.pending[0].Type != EventSensorAdded || m.pending[1].Type != EventSensorRemoved {
+		t.Fatalf("expected an added and a removed event queued, got %+v", m.pending)
+	}
+	added, removed := m.pending[0].Sensors, m.pending[1].Sensors
+	if strings.Join(added.Added, ",") != "Composite" || strings.Join(removed.Removed, ",") != "iwlwifi_1" {
+		t.Errorf("expected +Composite -iwlwifi_1 keyed by path, got %+v %+v", added, removed)
+	}
+	if toast := m.activeToast(m.clock.Now()); toast != "Sensors changed: +Composite, -iwlwifi_1" {
+		t.Errorf("expected a toast, got %q", toast)
+	}
+	m.record(m.pending)
+	m.pending = nil
+	if view := m.alertsView(); !strings.Contains(view, "+Composite") || !strings.Contains(view, "-iwlwifi_1") {
+		t.Errorf("expected the change in the alert history, got:\n%s", view)
+	}
+
+	isNew := func(name string) bool {
+		for _, sensor := range m.temperatureSensors {
+			if sensor.Name == name {
+				return sensor.New
+			}
+		}
+		t.Fatalf("no sensor %q", name)
+		return false
+	}
+	if !isNew("Composite") || isNew("CPU") {
+		t.Error("expected only the added sensor tagged new")
+	}
+	snap := m.Snapshot()
+	if full := RenderFull(snap, 100, 40, DefaultTheme, ViewState{}); !strings.Contains(full, "hwmon3/temp1_input new") {
+		t.Errorf("expected a new tag on the row, got:\n%s", full)
+	}
+	for i := 1; i < newSensorTicks; i++ {
+		read(relabeled, nvme)
+	}
+	if !isNew("Composite") {
+		t.Errorf("expected the tag kept for %d refreshes", newSensorTicks)
+	}
+	read(relabeled, nvme)
+	if isNew("Composite") || len(m.pending) != 0 {
+		t.Error("expected the tag gone and no further changes")
+	}
+}
@@ -0,0 +1,238 @@
+package monitor
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// StateCounts holds the number of readings in each alert state
+type StateCounts struct {
+	OK       int
+	Warning  int
+	Critical int
+}
+
+func (c *StateCounts) add(state State) {
+	switch state {
+	case StateCritical:
+		c.Critical++
+	case StateWarning:
+		c.Warning++
+	default:
+		c.OK++
+	}
+}
+
+// SensorReading is a point-in-time copy of a Sensor
+type SensorReading struct {
+	Name  string
+	Value string
+	State State
+	// Counter is the cumulative value of a CounterSensor, in CounterUnit;
+	// CounterUnit is empty for other sensors
+	Counter     float64
+	CounterUnit string
+	// Kind is what the sensor measures; Measurement is its numeric reading
+	// in the kind's unit, nil if it has none
+	Kind        Kind
+	Measurement *float64
+	// Err is the error of the last refresh, empty if it succeeded;
+	// LastSuccess is when a refresh last succeeded
+	Err         string
+	LastSuccess time.Time
+	// Muted is set when the sensor's alerts are muted; State is then OK
+	Muted bool
+	// Frozen is set when the value has held bit-identical for frozen_ticks
+	// refreshes while others changed
+	Frozen bool `json:",omitempty"`
+	// Changed is when the value last changed beyond jitter, zero before
+	// the first refresh
+	Changed time.Time
+	// Denied is set in degraded mode when the last refresh failed for lack
+	// of permission; the views leave the reading out and count it in
+	// Snapshot.PermissionGaps instead
+	Denied bool `json:",omitempty"`
+}
+
+// GroupSnapshot is a point-in-time copy of a SensorGroup
+type GroupSnapshot struct {
+	Name     string
+	Readings []SensorReading
+	Refresh  GroupRefreshState
+	// Time is when the readings were taken, zero if never; it lags the
+	// snapshot's Time while the group backs off or runs on its own interval
+	Time time.Time
+	// Renderer is the group's GroupRenderer, nil for the default lines
+	Renderer GroupRenderer `json:"-"`
+}
+
+// Snapshot is an immutable copy of everything the monitor displays, suitable
+// for handing to parent models and other consumers.
+type Snapshot struct {
+	// SchemaVersion is the SchemaVersion of the snapshot's JSON, as sent to
+	// instance clients. It is encoded by its name like the other fields
+	// rather than as schema_version.
+	SchemaVersion int
+	Hostname      string
+	// Time is the last refresh. TemperaturesTime, BatteryTime and each
+	// group's Time are when their readings were taken, which may differ:
+	// see Oldest
+	Time time.Time
+	// Interval is the time between refreshes, slowed down while idle;
+	// instance clients tell stale readings by it
+	Interval         time.Duration `json:",omitzero"`
+	TemperaturesTime time.Time
+	BatteryTime      time.Time
+	Temperatures     []TemperatureSensor
+	Battery          BatteryStatus
+	// BatteryPower smooths Battery.Power over the session history; zero
+	// without history
+	BatteryPower BatteryPower
+	// BatteryFull estimates when a charging battery reaches its charge
+	// limit; nil when not charging or the rate is too low to tell
+	BatteryFull *ChargeEstimate `json:",omitempty"`
+	// BatteryCapacityState is the state of the capacity alone under the
+	// active thresholds, which colors the percentage
+	BatteryCapacityState State
+	// AdapterUnderpowered is set while the battery discharges on AC
+	AdapterUnderpowered bool
+	// OnBattery is the time on battery since the charger was unplugged,
+	// nil on AC
+	OnBattery *BatterySpan `json:",omitempty"`
+	// BatteryEnergy is the energy the battery delivered and took in this
+	// session, counted from energy_now
+	BatteryEnergy EnergyTotals `json:",omitzero"`
+	Groups        []GroupSnapshot
+	Worst         State
+	Counts        StateCounts
+	// Virtualization names the hypervisor when running in a VM
+	Virtualization string
+	// Demo is set when the readings are the synthetic demo dataset
+	Demo bool
+	// Profile is the active threshold profile, empty when none are
+	// configured
+	Profile string
+	// TimeInState is the time each reading spent in each state since the
+	// start or the last reset, by name as in the mute lists
+	TimeInState map[string]StateDurations
+	// PermissionGaps summarizes the sensors degraded mode hides, by sysfs
+	// class
+	PermissionGaps []PermissionGap `json:",omitempty"`
+}
+
+// group returns the named group, or nil
+func (s Snapshot) group(name string) *GroupSnapshot {
+	for i := range s.Groups {
+		if s.Groups[i].Name == name {
+			return &s.Groups[i]
+		}
+	}
+	return nil
+}
+
+// SnapshotMsg is emitted after every refresh so parent models can react to
+// the current readings without polling the Monitor.
+type SnapshotMsg Snapshot
+
+// sensorState returns the alert state of a generic Sensor
+func sensorState(sensor Sensor) State {
+	if sensor.Critical() {
+		return StateCritical
+	}
+	if sensor.Warning() {
+		return StateWarning
+	}
+	return StateOK
+}
+
+// WorstState returns the most severe state across the built-in temperatures
+// and battery and every registered sensor group, together with the number of
+// readings in each state. It covers exactly the readings the UI displays.
+func (m Monitor) WorstState() (State, StateCounts) {
+	var counts StateCounts
+	worst := StateOK
+	record := func(state State) {
+		counts.add(state)
+		worst = max(worst, state)
+	}
+	for _, sensor := range m.temperatureSensors {
+		record(sensor.State())
+	}
+	if m.batteryStatus.Present() {
+		record(m.batteryState())
+	}
+	for _, group := range m.extraGroups {
+		for _, sensor := range group.Sensors {
+			record(m.groupSensorState(group.Name, sensor))
+		}
+	}
+	return worst, counts
+}
+
+// Snapshot returns a copy of the current readings
+func (m Monitor) Snapshot() Snapshot {
+	snap := Snapshot{
+		SchemaVersion:        SchemaVersion,
+		Hostname:             m.hostname,
+		Time:                 m.lastUpdate,
+		Interval:             m.refreshInterval(),
+		TemperaturesTime:     m.temperaturesRead,
+		BatteryTime:          m.batteryRead,
+		Temperatures:         append([]TemperatureSensor(nil), m.temperatureSensors...),
+		Battery:              m.batteryStatus,
+		BatteryPower:         m.batteryPower(),
+		BatteryFull:          m.chargeEstimate(),
+		BatteryCapacityState: m.batteryCapacityState(),
+		AdapterUnderpowered:  m.AdapterUnderpowered(),
+		OnBattery:            m.onBattery,
+		BatteryEnergy:        m.energy,
+		Virtualization:       m.virtualization,
+		Demo:                 m.demo != nil,
+		TimeInState:          m.timeInState(),
+	}
+	if len(m.config.Profiles) > 0 {
+		snap.Profile = m.profileName()
+	}
+	for _, group := range m.extraGroups {
+		gs := GroupSnapshot{Name: group.Name, Refresh: m.GroupRefreshState(group.Name), Time: m.groupsRead[group.Name], Renderer: group.Renderer}
+		for _, sensor := range group.Sensors {
+			reading := SensorReading{
+				Name:   sensor.Name(),
+				Value:  m.sensorValue(sensor),
+				State:  m.groupSensorState(group.Name, sensor),
+				Kind:   SensorKind(sensor),
+				Muted:  m.isMuted(muteKey(group.Name, sensor.Name())),
+				Frozen: m.isFrozen(groupStateKey(group.Name, sensor.Name())),
+			}
+			reading.Changed, _ = m.lastChange(groupStateKey(group.Name, sensor.Name()))
+			if measured, ok := sensor.(Measured); ok {
+				if n, ok := measured.Measurement(); ok {
+					reading.Measurement = &n
+				}
+			}
+			if counter, ok := sensor.(CounterSensor); ok {
+				reading.Counter, reading.CounterUnit = counter.Counter()
+			}
+			if refresh, ok := m.sensorRefresh[group.Name+"/"+sensor.Name()]; ok {
+				reading.LastSuccess = refresh.lastSuccess
+				if refresh.err != nil {
+					reading.Err = refresh.err.Error()
+				}
+				reading.Denied = m.isDenied(refresh.err)
+			}
+			gs.Readings = append(gs.Readings, reading)
+		}
+		snap.Groups = append(snap.Groups, gs)
+	}
+	snap.PermissionGaps, _ = permissionGaps(sysfsRoot, m.deniedErrors())
+	snap.Worst, snap.Counts = m.WorstState()
+	return snap
+}
+
+func (m Monitor) emitSnapshot() tea.Cmd {
+	snap := m.Snapshot()
+	return func() tea.Msg {
+		return SnapshotMsg(snap)
+	}
+}
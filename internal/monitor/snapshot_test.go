@@ -0,0 +1,179 @@
+package monitor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWorstStateMixed(t *testing.T) {
+	m := NewMonitor()
+	m.temperatureSensors = []TemperatureSensor{
+		{Name: "CPU", Value: 65.0, High: 80.0, Critical: 100.0},
+		{Name: "GPU", Value: 85.0, High: 80.0, Critical: 100.0},
+	}
+	m.batteryStatus = BatteryStatus{Capacity: 60, Status: "Discharging"}
+	m.RegisterSensorGroup(SensorGroup{
+		Name: "Custom",
+		Sensors: []Sensor{
+			newStaticSensor("ok", false, false),
+			newStaticSensor("warn", true, false),
+		},
+	})
+
+	worst, counts := m.WorstState()
+	if worst != StateWarning {
+		t.Errorf("expected worst state warning, got %v", worst)
+	}
+	if counts != (StateCounts{OK: 3, Warning: 2, Critical: 0}) {
+		t.Errorf("unexpected counts: %+v", counts)
+	}
+
+	// A critical extra sensor dominates
+	m.RegisterSensorGroup(SensorGroup{
+		Name:    "Other",
+		Sensors: []Sensor{newStaticSensor("crit", false, true)},
+	})
+	worst, counts = m.WorstState()
+	if worst != StateCritical || counts.Critical != 1 {
+		t.Errorf("expected one critical, got %v %+v", worst, counts)
+	}
+}
+
+// TestCriticalGroupSensorReachesEveryAggregate guards the aggregates
+// against only looking at the built-in temperatures and battery: a critical
+// custom sensor must show wherever the monitor's overall state does
+func TestCriticalGroupSensorReachesEveryAggregate(t *testing.T) {
+	var events bytes.Buffer
+	m := NewMonitor(WithDemo(DefaultDemoSeed), WithHostname("box"), WithEventWriter(&events))
+	m.RegisterSensorGroup(SensorGroup{Name: "Custom", Sensors: []Sensor{newStaticSensor("probe", false, true)}})
+	m = m.Refresh()
+
+	if worst, counts := m.WorstState(); worst != StateCritical || counts.Critical != 1 {
+		t.Errorf("WorstState: expected one critical, got %v %+v", worst, counts)
+	}
+	snap := m.Snapshot()
+	if snap.Worst != StateCritical || snap.Counts.Critical != 1 {
+		t.Errorf("Snapshot: expected one critical, got %v %+v", snap.Worst, snap.Counts)
+	}
+	if compact := RenderCompact(snap, 80, DefaultTheme, ViewState{}); !strings.Contains(compact, "✖ probe") {
+		t.Errorf("compact view: expected the sensor on the alerts line, got:\n%s", compact)
+	}
+	var metrics bytes.Buffer
+	if err := WritePrometheus(&metrics, snap); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(metrics.String(), `sysfs_monitor_worst_state{host="box"} 2`) {
+		t.Errorf("Prometheus: expected a critical worst state, got:\n%s", metrics.String())
+	}
+	properties := map[string]any{}
+	for _, p := range monitorProperties(snap) {
+		properties[p.name] = p.value
+	}
+	if properties["Worst"] != "critical" || properties["Critical"] != uint32(1) {
+		t.Errorf("D-Bus: expected one critical, got %v", properties)
+	}
+	m.Close()
+	if !strings.Contains(events.String(), `"sensor":"probe","group":"Custom","from":"ok","to":"critical"`) {
+		t.Errorf("event stream: expected the transition, got:\n%s", events.String())
+	}
+	if history := m.AlertHistory(); len(history) == 0 || history[len(history)-1].Sensor != "probe" {
+		t.Errorf("alert history: expected the transition, got %+v", history)
+	}
+}
+
+func TestWorstStateBattery(t *testing.T) {
+	m := NewMonitor()
+	if worst, counts := m.WorstState(); worst != StateOK || counts != (StateCounts{}) {
+		t.Errorf("expected empty OK state without sensors, got %v %+v", worst, counts)
+	}
+
+	m.batteryStatus = BatteryStatus{Capacity: 15, Status: "Discharging"}
+	if worst, _ := m.WorstState(); worst != StateCritical {
+		t.Errorf("expected low battery to be critical, got %v", worst)
+	}
+
+	m.batteryStatus = BatteryStatus{Capacity: 90, Status: "Discharging", Health: "Warm"}
+	if worst, _ := m.WorstState(); worst != StateWarning {
+		t.Errorf("expected Warm health to be a warning, got %v", worst)
+	}
+}
+
+func TestAdapterUnderpoweredDebounce(t *testing.T) {
+	m := NewMonitor(WithUnderpoweredTicks(2))
+	onAC := BatteryStatus{Capacity: 90, Status: "Discharging", ACOnline: true}
+
+	m.batteryStatus = onAC
+	m.trackUnderpowered()
+	if m.AdapterUnderpowered() {
+		t.Fatal("expected a single tick discharging on AC to be ignored")
+	}
+
+	// A tick charging again resets the count
+	m.batteryStatus = BatteryStatus{Capacity: 90, Status: "Charging", ACOnline: true}
+	m.trackUnderpowered()
+	m.batteryStatus = onAC
+	m.trackUnderpowered()
+	if m.AdapterUnderpowered() {
+		t.Fatal("expected the count to reset after charging")
+	}
+
+	m.trackUnderpowered()
+	if !m.AdapterUnderpowered() {
+		t.Fatal("expected underpowered after 2 consecutive ticks")
+	}
+	if worst, _ := m.WorstState(); worst != StateWarning {
+		t.Errorf("expected underpowered adapter to be a warning, got %v", worst)
+	}
+	if !m.Snapshot().AdapterUnderpowered {
+		t.Error("expected snapshot to report the underpowered adapter")
+	}
+
+	// Discharging on battery alone is normal
+	m.batteryStatus = BatteryStatus{Capacity: 90, Status: "Discharging"}
+	m.trackUnderpowered()
+	if m.AdapterUnderpowered() {
+		t.Error("expected no warning without AC")
+	}
+}
+
+func TestSnapshotCopiesReadings(t *testing.T) {
+	m := NewMonitor()
+	m.temperatureSensors = []TemperatureSensor{{Name: "CPU", Value: 101.0, High: 80.0, Critical: 100.0}}
+	m.RegisterSensorGroup(SensorGroup{
+		Name:    "Custom",
+		Sensors: []Sensor{newStaticSensor("warn", true, false)},
+	})
+
+	snap := m.Snapshot()
+	if snap.Worst != StateCritical || snap.Counts.Critical != 1 || snap.Counts.Warning != 1 {
+		t.Errorf("unexpected snapshot aggregate: %v %+v", snap.Worst, snap.Counts)
+	}
+	if len(snap.Groups) != 1 || snap.Groups[0].Readings[0].State != StateWarning {
+		t.Errorf("unexpected snapshot groups: %+v", snap.Groups)
+	}
+
+	m.temperatureSensors[0].Value = 20.0
+	if snap.Temperatures[0].Value != 101.0 {
+		t.Error("snapshot should not alias the monitor's readings")
+	}
+}
+
+// newStaticSensor returns a refreshed GenericSensor with fixed state
+func newStaticSensor(name string, warning, critical bool) Sensor {
+	s := NewGenericSensor(name, func() (string, bool, bool, error) {
+		return name, warning, critical, nil
+	})
+	s.Refresh()
+	return s
+}
+
+func TestSnapshotHostname(t *testing.T) {
+	if got := NewMonitor(WithConfig("", Config{Hostname: "from-config"})).Snapshot().Hostname; got != "from-config" {
+		t.Errorf("expected config host name, got %q", got)
+	}
+	m := NewMonitor(WithConfig("", Config{Hostname: "from-config"}), WithHostname("from-flag"))
+	if got := m.Snapshot().Hostname; got != "from-flag" {
+		t.Errorf("expected the flag to win over the config, got %q", got)
+	}
+}
@@ -0,0 +1,45 @@
+package monitor
+
+import "time"
+
+// DefaultStaleTimeout is how long a temperature whose reads keep failing
+// transiently shows its last value before it's dropped
+const DefaultStaleTimeout = 30 * time.Second
+
+// freshTemperature is the last successful raw reading of a temperature
+type freshTemperature struct {
+	value float64
+	at    time.Time
+}
+
+// staleTimeout returns the configured stale timeout
+func (m Monitor) staleTimeout() time.Duration {
+	if d, err := time.ParseDuration(m.config.StaleTimeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultStaleTimeout
+}
+
+// holdStale fills in the previous value of temperatures whose read failed
+// transiently, so they keep their row instead of blinking out for a tick.
+// Stale readings are dropped once the last fresh one is older than the
+// stale timeout, or when there was none.
+func (m *Monitor) holdStale(now time.Time) {
+	fresh := make(map[string]freshTemperature, len(m.temperatureSensors))
+	kept := m.temperatureSensors[:0]
+	for _, sensor := range m.temperatureSensors {
+		if sensor.Stale {
+			last, ok := m.freshTemperatures[sensor.Path]
+			if !ok || now.Sub(last.at) > m.staleTimeout() {
+				continue
+			}
+			sensor.Value = last.value
+			fresh[sensor.Path] = last
+		} else if !sensor.Asleep {
+			fresh[sensor.Path] = freshTemperature{value: sensor.Value, at: now}
+		}
+		kept = append(kept, sensor)
+	}
+	m.temperatureSensors = kept
+	m.freshTemperatures = fresh
+}
@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// flakyFS makes the reads of value files fail with an errno, like an
+// SMBus sensor losing arbitration
+type flakyFS struct {
+	failing map[string]syscall.Errno
+}
+
+type flakyFile struct {
+	valueFile
+	fs   *flakyFS
+	path string
+}
+
+func (f flakyFile) ReadAt(p []byte, off int64) (int, error) {
+	if errno, ok := f.fs.failing[f.path]; ok {
+		return 0, &os.PathError{Op: "read", Path: f.path, Err: errno}
+	}
+	return f.valueFile.ReadAt(p, off)
+}
+
+func (fs *flakyFS) open(path string) (valueFile, error) {
+	f, err := openValueFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return flakyFile{valueFile: f, fs: fs, path: path}, nil
+}
+
+func TestTransientReadErrorsKeepSensors(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":        "nct6775\n",
+		"class/hwmon/hwmon0/temp1_input": "45000\n",
+		"class/hwmon/hwmon0/temp1_label": "SYSTIN\n",
+		"class/hwmon/hwmon0/temp2_input": "50000\n",
+		"class/hwmon/hwmon0/temp2_label": "CPUTIN\n",
+		"class/hwmon/hwmon0/temp3_input": "38000\n",
+		"class/hwmon/hwmon0/temp3_label": "AUXTIN\n",
+	})
+	fs := &flakyFS{failing: make(map[string]syscall.Errno)}
+	r := newTemperatureReader(root, 2)
+	r.open = fs.open
+	defer r.Close()
+
+	m := NewMonitor()
+	m.config.StaleTimeout = "10s"
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	refresh := func(at time.Duration) []TemperatureSensor {
+		m.temperatureSensors = r.Refresh()
+		m.holdStale(start.Add(at))
+		return m.temperatureSensors
+	}
+	refresh(0)
+
+	// CPUTIN is held open and AUXTIN re-read past the cap; both keep their
+	// row and previous value on a transient error
+	cpu := filepath.Join(root, "class/hwmon/hwmon0/temp2_input")
+	aux := filepath.Join(root, "class/hwmon/hwmon0/temp3_input")
+	fs.failing[cpu] = syscall.ENXIO
+	fs.failing[aux] = syscall.EAGAIN
+	got := refresh(2 * time.Second)
+	if len(got) != 3 {
+		t.Fatalf("expected the failing sensors to stay, got %+v", got)
+	}
+	if !got[1].Stale || got[1].Value != 50 || !got[2].Stale || got[2].Value != 38 || got[0].Stale {
+		t.Errorf("expected CPUTIN and AUXTIN stale with their last values, got %+v", got)
+	}
+
+	// A successful read clears the mark
+	delete(fs.failing, aux)
+	if got := refresh(4 * time.Second); got[2].Stale || len(got) != 3 {
+		t.Errorf("expected AUXTIN fresh again, got %+v", got)
+	}
+
+	// Past the stale timeout since the last fresh reading, CPUTIN goes
+	if got := refresh(12 * time.Second); len(got) != 2 || got[1].Name != "AUXTIN" {
+		t.Errorf("expected CPUTIN dropped after the stale timeout, got %+v", got)
+	}
+
+	// Permanent errors drop the sensor right away
+	delete(fs.failing, cpu)
+	fs.failing[aux] = syscall.ENOENT
+	if got := refresh(14 * time.Second); len(got) != 2 || got[1].Name != "CPUTIN" {
+		t.Errorf("expected AUXTIN dropped on ENOENT, got %+v", got)
+	}
+}
+
+func TestIsTransientReadError(t *testing.T) {
+	for _, errno := range []syscall.Errno{syscall.ENXIO, syscall.EAGAIN, syscall.EBUSY, syscall.ETIMEDOUT} {
+		if !isTransientReadError(&os.PathError{Op: "read", Err: errno}) {
+			t.Errorf("expected %v to be transient", errno)
+		}
+	}
+	for _, err := range []error{nil, os.ErrNotExist, &os.PathError{Op: "open", Err: syscall.ENOENT}, syscall.ENODEV} {
+		if isTransientReadError(err) {
+			t.Errorf("expected %v to be permanent", err)
+		}
+	}
+}
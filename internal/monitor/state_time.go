@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"fmt"
+	"maps"
+	"time"
+)
+
+// StateDurations is the time a reading spent in each state during the
+// session
+type StateDurations struct {
+	OK, Warning, Critical time.Duration
+}
+
+// String describes the time out of OK, e.g. "warning 14m32s, critical 0s"
+func (d StateDurations) String() string {
+	return fmt.Sprintf("warning %s, critical %s", d.Warning.Round(time.Second), d.Critical.Round(time.Second))
+}
+
+func (d *StateDurations) add(state State, dt time.Duration) {
+	switch state {
+	case StateWarning:
+		d.Warning += dt
+	case StateCritical:
+		d.Critical += dt
+	default:
+		d.OK += dt
+	}
+}
+
+// Keys of the readings in the state maps, which transitions compares
+const batteryStateKey = "battery"
+
+func temperatureStateKey(name string) string {
+	return "temp/" + name
+}
+
+func groupStateKey(group, name string) string {
+	return "group/" + group + "/" + name
+}
+
+// accrueStateTime credits each reading with the time since the previous
+// refresh in the state it had then. The time credited is at most one
+// interval (the idle one in the low-power mode), so pauses and suspends, which leave a longer gap, only count
+// the interval before them. Called by Refresh before transitions replaces
+// the states.
+func (m *Monitor) accrueStateTime(since time.Duration) {
+	if len(m.states) == 0 || since <= 0 {
+		return
+	}
+	dt := min(since, m.refreshInterval())
+	accrued := maps.Clone(m.stateTime)
+	if accrued == nil {
+		accrued = make(map[string]StateDurations, len(m.states))
+	}
+	for key, state := range m.states {
+		d := accrued[key]
+		d.add(state, dt)
+		accrued[key] = d
+	}
+	m.stateTime = accrued
+}
+
+// timeInState returns the session's time in each state of the readings on
+// display, by name as in the mute lists: a temperature by its name, the
+// battery as "Battery" and a group sensor as "Group/name"
+func (m Monitor) timeInState() map[string]StateDurations {
+	if len(m.stateTime) == 0 {
+		return nil
+	}
+	times := make(map[string]StateDurations)
+	set := func(name, key string) {
+		if d, ok := m.stateTime[key]; ok {
+			times[name] = d
+		}
+	}
+	for _, sensor := range m.temperatureSensors {
+		set(sensor.key(), temperatureStateKey(sensor.key()))
+	}
+	set("Battery", batteryStateKey)
+	for _, group := range m.extraGroups {
+		for _, sensor := range group.Sensors {
+			set(muteKey(group.Name, sensor.Name()), groupStateKey(group.Name, sensor.Name()))
+		}
+	}
+	return times
+}
+
+// resetSessionStats forgets the peaks and the time in each state recorded
+// so far, returning a status message
+func (m *Monitor) resetSessionStats() string {
+	m.resetSessionPeaks()
+	m.stateTime = nil
+	return "Session statistics reset"
+}
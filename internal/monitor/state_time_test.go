@@ -0,0 +1,64 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTimeInState(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	m := NewMonitor(WithClock(clock), WithDemo(DefaultDemoSeed), WithInterval(2*time.Second), func(m *Monitor) {
+		m.extraGroups = []SensorGroup{{Name: "Load", Sensors: []Sensor{newStaticSensor("Hot", true, false)}}}
+	})
+	m.Init()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	warning := func(m Monitor) time.Duration {
+		return m.Snapshot().TimeInState["Load/Hot"].Warning
+	}
+
+	// Refreshes at 2s, 4s, 6s and 8s: the first has nothing before it
+	m = clock.advance(m, 9*time.Second)
+	if got := warning(m); got != 6*time.Second {
+		t.Errorf("expected 6s in warning, got %v", got)
+	}
+	if d := m.Snapshot().TimeInState["Battery"]; d.OK+d.Warning+d.Critical != 6*time.Second {
+		t.Errorf("expected the battery timed too, got %+v", d)
+	}
+
+	// A pause only counts the interval before the refresh that ends it
+	m = sendKeys(m, "p")
+	m = clock.advance(m, time.Minute)
+	m = sendKeys(m, "p")
+	m = clock.advance(m, 0)
+	if got := warning(m); got != 8*time.Second {
+		t.Errorf("expected the pause left out, got %v", got)
+	}
+
+	// So does a suspend, which delays the next tick
+	for i := range clock.timers {
+		clock.timers[i].at = clock.timers[i].at.Add(time.Hour)
+	}
+	m = clock.advance(m, time.Hour+2*time.Second)
+	if got := warning(m); got != 10*time.Second {
+		t.Errorf("expected the suspend left out, got %v", got)
+	}
+
+	// The detail view of the sensor, the first row after the temperatures
+	m = sendKeys(m, strings.Split(strings.Repeat("j", len(m.temperatureSensors)+1), "")...)
+	m = sendKeys(m, "enter")
+	if view := m.View(); !strings.Contains(view, "Time:     warning 10s, critical 0s this session") {
+		t.Errorf("expected the time in state in the detail view, got:\n%s", view)
+	}
+
+	m = sendKeys(m, "esc", "x")
+	if m.status != "Session statistics reset" || m.Snapshot().TimeInState != nil {
+		t.Errorf("expected x to reset the time in state, got %q %v", m.status, m.Snapshot().TimeInState)
+	}
+	m = clock.advance(m, 2*time.Second)
+	if got := warning(m); got != 2*time.Second {
+		t.Errorf("expected counting to start over, got %v", got)
+	}
+}
@@ -0,0 +1,26 @@
+package monitor
+
+import "time"
+
+// A refresh coming more than three refresh intervals after the previous one,
+// on the monotonic clock that counts suspends, finds the machine slept in
+// between, as trackPower does for the battery. It is recorded as a
+// SuspendDetected event, unless the monitor was paused meanwhile.
+
+// trackSuspend queues a SuspendDetected event when the elapsed time since
+// the previous refresh, at prev, was a suspend. Called by Refresh; the
+// first refresh only starts the count.
+func (m *Monitor) trackSuspend(prev time.Time, elapsed time.Duration) {
+	refreshed := m.refreshed
+	m.refreshed = true
+	if !refreshed || elapsed <= 3*m.refreshInterval() || m.resumedAt.After(prev) {
+		return
+	}
+	m.pending = append(m.pending, Event{
+		Time:   m.lastUpdate,
+		Host:   m.hostname,
+		Type:   EventSuspendDetected,
+		Sensor: "System",
+		Value:  "resumed after " + formatElapsed(elapsed),
+	})
+}
@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSuspendDetected(t *testing.T) {
+	m, clock := newClockedMonitor(2 * time.Second)
+	suspends := func() []Event {
+		var events []Event
+		for _, event := range m.AlertHistory() {
+			if event.Type == EventSuspendDetected {
+				events = append(events, event)
+			}
+		}
+		return events
+	}
+
+	// Regular refreshes, then a gap of an hour
+	for range 3 {
+		clock.now = clock.now.Add(2 * time.Second)
+		m = m.Refresh()
+	}
+	if got := suspends(); len(got) != 0 {
+		t.Fatalf("expected no suspend between regular refreshes, got %+v", got)
+	}
+	clock.now = clock.now.Add(time.Hour)
+	m = m.Refresh()
+	got := suspends()
+	if len(got) != 1 || got[0].Value != "resumed after 1h0m" || !got[0].Time.Equal(clock.now) {
+		t.Fatalf("expected one suspend of an hour, got %+v", got)
+	}
+
+	// A pause isn't a suspend
+	m, _ = m.togglePause()
+	clock.now = clock.now.Add(time.Hour)
+	m, _ = m.togglePause()
+	m = m.Refresh()
+	if got := suspends(); len(got) != 1 {
+		t.Errorf("expected no suspend across a pause, got %+v", got)
+	}
+}
@@ -0,0 +1,78 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// alarmSuffixes are the latched alarm flags of an hwmon channel and the
+// state each forces while set. Chips raise them on excursions shorter than
+// the refresh interval, which the instantaneous value misses.
+var alarmSuffixes = []alarmSuffix{
+	{"_alarm", StateWarning},
+	{"_min_alarm", StateWarning},
+	{"_max_alarm", StateWarning},
+	{"_crit_alarm", StateCritical},
+	{"_lcrit_alarm", StateCritical},
+	{"_emergency_alarm", StateCritical},
+}
+
+// alarmSuffix is an alarm flag's suffix and the state it forces
+type alarmSuffix struct {
+	suffix string
+	state  State
+}
+
+// isAlarmSuffix reports whether a channel attribute with the suffix is an
+// alarm flag, such as "_crit_alarm"
+func isAlarmSuffix(suffix string) bool {
+	return slices.ContainsFunc(alarmSuffixes, func(a alarmSuffix) bool {
+		return a.suffix == suffix
+	})
+}
+
+// globalAlarmFile is the channel-less alarm bitmask of older chips. Its
+// bits are chip-specific, so a set bit warns on every channel of the chip.
+const globalAlarmFile = "alarms"
+
+// alarmLatchedText notes a set alarm flag in the detail views
+const alarmLatchedText = "hardware alarm latched"
+
+// alarmFile is an alarm flag and the state it forces
+type alarmFile struct {
+	path  string
+	state State
+}
+
+// channelAlarms returns the alarm files of an hwmon channel such as
+// "temp1" or "fan2", falling back to the chip's global alarm file when the
+// channel has none
+func channelAlarms(hwmonPath, channel string) []alarmFile {
+	var files []alarmFile
+	for _, alarm := range alarmSuffixes {
+		path := filepath.Join(hwmonPath, channel+alarm.suffix)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, alarmFile{path: path, state: alarm.state})
+		}
+	}
+	if len(files) == 0 {
+		path := filepath.Join(hwmonPath, globalAlarmFile)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, alarmFile{path: path, state: StateWarning})
+		}
+	}
+	return files
+}
+
+// readAlarms returns the worst state forced by the set flags, StateOK when
+// none is set or readable
+func readAlarms(files []alarmFile) State {
+	state := StateOK
+	for _, file := range files {
+		if flag, err := readSysfsInt(file.path); err == nil && flag != 0 {
+			state = max(state, file.state)
+		}
+	}
+	return state
+}
@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHwmonAlarmsForceStates(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		// Per-channel flags
+		"class/hwmon/hwmon0/name":             "nct6775\n",
+		"class/hwmon/hwmon0/temp1_input":      "45000\n",
+		"class/hwmon/hwmon0/temp1_max_alarm":  "0\n",
+		"class/hwmon/hwmon0/temp1_crit_alarm": "1\n",
+		"class/hwmon/hwmon0/temp2_input":      "45000\n",
+		"class/hwmon/hwmon0/temp2_alarm":      "1\n",
+		"class/hwmon/hwmon0/fan1_input":       "1200\n",
+		"class/hwmon/hwmon0/fan1_alarm":       "1\n",
+		// Only the chip-level bitmask
+		"class/hwmon/hwmon1/name":        "lm78\n",
+		"class/hwmon/hwmon1/temp1_input": "45000\n",
+		"class/hwmon/hwmon1/alarms":      "16\n",
+	})
+	sensors := readTemperatures(root)
+	if len(sensors) != 3 {
+		t.Fatalf("expected 3 sensors, got %+v", sensors)
+	}
+	for i, want := range []State{StateCritical, StateWarning, StateWarning} {
+		if got := sensors[i].State(); got != want {
+			t.Errorf("%s: expected %v at 45°C with its alarm set, got %v", sensors[i].Path, want, got)
+		}
+	}
+
+	fans := readFans(root)
+	if len(fans) != 1 {
+		t.Fatalf("expected 1 fan, got %d", len(fans))
+	}
+	if fan := fans[0].(Alarmed); !fan.Warning() || fan.Critical() || fan.Alarm() != StateWarning {
+		t.Errorf("expected the fan warning on its alarm, got alarm %v", fan.Alarm())
+	}
+
+	// The chip clears the flags once read; the next refresh follows
+	for _, file := range []string{"hwmon0/temp1_crit_alarm", "hwmon1/alarms"} {
+		if err := os.WriteFile(filepath.Join(root, "class/hwmon", file), []byte("0\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := newTemperatureReader(root, 4)
+	defer r.Close()
+	got := r.Refresh()
+	if got[0].State() != StateOK || got[1].State() != StateWarning || got[2].State() != StateOK {
+		t.Errorf("expected only temp2's alarm left, got %v %v %v", got[0].State(), got[1].State(), got[2].State())
+	}
+}
+
+func TestDetailNotesLatchedAlarm(t *testing.T) {
+	m := newDetailMonitor()
+	m = sendKeys(m, "down", "enter")
+	if view := m.View(); strings.Contains(view, alarmLatchedText) {
+		t.Errorf("expected no alarm note without a flag:\n%s", view)
+	}
+	m.temperatureSensors[0].Alarm = StateCritical
+	if view := m.View(); !strings.Contains(view, alarmLatchedText) {
+		t.Errorf("expected the alarm noted:\n%s", view)
+	}
+}
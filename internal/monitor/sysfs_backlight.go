@@ -0,0 +1,104 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	backlightClassPath = "class/backlight"
+
+	// brightnessStepPercent is the change per Adjust step
+	brightnessStepPercent = 5
+)
+
+// BacklightSensor reports a backlight's brightness as a percentage and can
+// adjust it by writing to the brightness file.
+type BacklightSensor struct {
+	path       string
+	brightness int
+	max        int
+}
+
+// ReadBacklights returns a refreshed sensor for each backlight device
+func ReadBacklights() []Sensor {
+	return readBacklights(sysfsRoot)
+}
+
+func readBacklights(root string) []Sensor {
+	var sensors []Sensor
+	paths, _ := filepath.Glob(filepath.Join(root, backlightClassPath, "*"))
+	for _, path := range paths {
+		b := &BacklightSensor{path: path}
+		if err := b.Refresh(); err == nil {
+			sensors = append(sensors, b)
+		}
+	}
+	return sensors
+}
+
+func (b *BacklightSensor) Name() string {
+	return "Backlight " + filepath.Base(b.path)
+}
+
+func (b *BacklightSensor) Value() string {
+	return formatMeasurement(KindPercentage, float64(b.percent()))
+}
+
+func (b *BacklightSensor) Kind() Kind {
+	return KindPercentage
+}
+
+func (b *BacklightSensor) Measurement() (float64, bool) {
+	return float64(b.percent()), b.max > 0
+}
+
+func (b *BacklightSensor) Warning() bool {
+	return false
+}
+
+func (b *BacklightSensor) Critical() bool {
+	return false
+}
+
+func (b *BacklightSensor) Refresh() error {
+	brightness, err := readSysfsInt(filepath.Join(b.path, "brightness"))
+	if err != nil {
+		return err
+	}
+	maxBrightness, err := readSysfsInt(filepath.Join(b.path, "max_brightness"))
+	if err != nil {
+		return err
+	}
+	if maxBrightness <= 0 {
+		return fmt.Errorf("%s: invalid max_brightness %d", b.path, maxBrightness)
+	}
+	b.brightness = int(brightness)
+	b.max = int(maxBrightness)
+	return nil
+}
+
+// Adjust changes brightness by 5% per step, clamped to [1, max_brightness]
+// so the panel is never switched off entirely.
+func (b *BacklightSensor) Adjust(step int) error {
+	delta := max(1, b.max*brightnessStepPercent/100) * step
+	target := max(1, min(b.max, b.brightness+delta))
+	path := filepath.Join(b.path, "brightness")
+	if err := os.WriteFile(path, []byte(strconv.Itoa(target)), 0); err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("no permission to write %s (needs a udev rule or root)", path)
+		}
+		return fmt.Errorf("writing %s: %v", path, err)
+	}
+	return b.Refresh()
+}
+
+func (b *BacklightSensor) percent() int {
+	if b.max == 0 {
+		return 0
+	}
+	return b.brightness * 100 / b.max
+}
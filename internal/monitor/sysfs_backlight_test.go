@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeBacklight(t *testing.T, brightness, maxBrightness string) string {
+	t.Helper()
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/backlight/intel_backlight/brightness":     brightness,
+		"class/backlight/intel_backlight/max_brightness": maxBrightness,
+	})
+	return root
+}
+
+func TestBacklightAdjustClamps(t *testing.T) {
+	root := writeBacklight(t, "1000\n", "1000\n")
+	sensors := readBacklights(root)
+	if len(sensors) != 1 {
+		t.Fatalf("expected 1 backlight, got %d", len(sensors))
+	}
+	b := sensors[0].(*BacklightSensor)
+	if b.Name() != "Backlight intel_backlight" || b.Value() != "100%" {
+		t.Errorf("unexpected sensor: %s = %s", b.Name(), b.Value())
+	}
+
+	if err := b.Adjust(1); err != nil {
+		t.Fatal(err)
+	}
+	if b.Value() != "100%" {
+		t.Errorf("expected brightness clamped at max, got %s", b.Value())
+	}
+	if err := b.Adjust(-1); err != nil {
+		t.Fatal(err)
+	}
+	if b.Value() != "95%" {
+		t.Errorf("expected 95%% after one step down, got %s", b.Value())
+	}
+
+	for i := 0; i < 30; i++ {
+		if err := b.Adjust(-1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(root, "class/backlight/intel_backlight/brightness"))
+	if string(data) != "1" {
+		t.Errorf("expected brightness clamped at 1, got %q", data)
+	}
+}
+
+func TestBacklightKeysRequireControl(t *testing.T) {
+	root := writeBacklight(t, "500\n", "1000\n")
+	m := NewMonitor()
+	m.width, m.height = 80, 24
+	m.RegisterSensorGroup(SensorGroup{Name: "Display", Sensors: readBacklights(root)})
+
+	m = sendKeys(m, "down", "]")
+	if !strings.Contains(m.status, "--enable-control") {
+		t.Errorf("expected control-disabled message, got %q", m.status)
+	}
+	if !strings.Contains(m.View(), "--enable-control") {
+		t.Error("status message should be shown in the view")
+	}
+	data, _ := os.ReadFile(filepath.Join(root, "class/backlight/intel_backlight/brightness"))
+	if string(data) != "500\n" {
+		t.Errorf("brightness must not change without control, got %q", data)
+	}
+
+	WithControl()(&m)
+	m = sendKeys(m, "]")
+	if m.status != "" {
+		t.Errorf("unexpected status: %q", m.status)
+	}
+	if got := m.extraGroups[0].Sensors[0].Value(); got != "55%" {
+		t.Errorf("expected 55%% after increase, got %s", got)
+	}
+}
+
+func TestBacklightPermissionError(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write read-only files")
+	}
+	root := writeBacklight(t, "500\n", "1000\n")
+	path := filepath.Join(root, "class/backlight/intel_backlight/brightness")
+	if err := os.Chmod(path, 0o444); err != nil {
+		t.Fatal(err)
+	}
+	b := readBacklights(root)[0].(*BacklightSensor)
+	err := b.Adjust(1)
+	if err == nil || !strings.Contains(err.Error(), "no permission") {
+		t.Errorf("expected a clear permission error, got %v", err)
+	}
+}
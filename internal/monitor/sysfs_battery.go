@@ -1,76 +1,156 @@
 package monitor
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 )
 
 const (
-	powerSupplyBasePath = "/sys/class/power_supply"
+	sysfsRoot            = "/sys"
+	powerSupplyClassPath = "class/power_supply"
 )
 
+// batteryHealthStates maps the standard power_supply health strings to alert
+// states. Values not listed here ("Good", "Unknown", ...) are neutral.
+var batteryHealthStates = map[string]State{
+	"Overheat":              StateCritical,
+	"Dead":                  StateCritical,
+	"Over voltage":          StateCritical,
+	"Unspecified failure":   StateCritical,
+	"Cold":                  StateCritical,
+	"Watchdog timer expire": StateCritical,
+	"Safety timer expire":   StateCritical,
+	"Warm":                  StateWarning,
+	"Cool":                  StateWarning,
+}
+
+// BatteryHealthState returns the alert state for a sysfs battery health string
+func BatteryHealthState(health string) State {
+	return batteryHealthStates[health]
+}
+
+// ReadBatteryStatus reads the first battery and the online adapter,
+// leaving out the attributes that fail to read
 func ReadBatteryStatus() BatteryStatus {
+	return readBatteryStatus(sysfsRoot)
+}
+
+// ReadBatteryStatusE is ReadBatteryStatus reporting why attributes were
+// left out: the error joins one error per unreadable power supply or
+// attribute. Attributes a driver doesn't provide aren't errors, and the
+// attributes that could be read are returned either way.
+func ReadBatteryStatusE() (BatteryStatus, error) {
+	return readBatteryStatusE(sysfsRoot)
+}
+
+func readBatteryStatus(root string) BatteryStatus {
+	status, _ := readBatteryStatusE(root)
+	return status
+}
+
+// attributeErrors collects the failures of a battery read. Missing
+// attributes are optional, and drivers answer ENODATA for those they can't
+// report at the moment, so neither counts.
+type attributeErrors []error
+
+func (e *attributeErrors) add(path string, err error) {
+	if err == nil || errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENODATA) {
+		return
+	}
+	if _, ok := err.(*fs.PathError); !ok {
+		err = fmt.Errorf("%s: %w", path, err)
+	}
+	*e = append(*e, err)
+}
+
+// read returns the trimmed contents of an attribute, "" if unreadable
+func (e *attributeErrors) read(path string) string {
+	data, err := os.ReadFile(path)
+	e.add(path, err)
+	return strings.TrimSpace(string(data))
+}
+
+// readInt returns an integer attribute and whether it could be read
+func (e *attributeErrors) readInt(path string) (int64, bool) {
+	n, err := readSysfsInt(path)
+	e.add(path, err)
+	return n, err == nil
+}
+
+func readBatteryStatusE(root string) (BatteryStatus, error) {
 	status := BatteryStatus{}
+	powerSupplyBasePath := filepath.Join(root, powerSupplyClassPath)
+	var errs attributeErrors
 
 	// Find battery directories by scanning all power supplies and checking type
 	var batteryPath string
 	entries, err := os.ReadDir(powerSupplyBasePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		// A kernel without power supplies has no class directory
+		if _, err := os.Stat(filepath.Join(root, "class")); err != nil {
+			return status, err
+		}
+		return status, nil
+	}
 	if err != nil {
-		return status
+		return status, err
 	}
 	for _, entry := range entries {
 		typePath := filepath.Join(powerSupplyBasePath, entry.Name(), "type")
 		data, err := os.ReadFile(typePath)
 		if err != nil {
+			errs.add(typePath, err)
 			continue
 		}
-		if strings.TrimSpace(string(data)) == "Battery" {
-			batteryPath = filepath.Join(powerSupplyBasePath, entry.Name())
-			break
+		switch strings.TrimSpace(string(data)) {
+		case "Battery":
+			if batteryPath == "" {
+				batteryPath = filepath.Join(powerSupplyBasePath, entry.Name())
+			}
+		case "Mains", "USB":
+			adapterPath := filepath.Join(powerSupplyBasePath, entry.Name())
+			if online, err := readSysfsInt(filepath.Join(adapterPath, "online")); err == nil && online > 0 && !status.ACOnline {
+				status.ACOnline = true
+				readACPower(&status, adapterPath)
+			}
 		}
 	}
 	if batteryPath == "" {
-		return status
+		return BatteryStatus{}, errors.Join(errs...)
 	}
 
 	// Read capacity
-	capacityPath := filepath.Join(batteryPath, "capacity")
-	if data, err := os.ReadFile(capacityPath); err == nil {
-		if cap, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil {
-			status.Capacity = cap
-		}
+	if capacity, ok := errs.readInt(filepath.Join(batteryPath, "capacity")); ok {
+		status.Capacity = int(capacity)
 	}
 
 	// Read status
-	statusPath := filepath.Join(batteryPath, "status")
-	if data, err := os.ReadFile(statusPath); err == nil {
-		status.Status = strings.TrimSpace(string(data))
-	}
+	status.Status = errs.read(filepath.Join(batteryPath, "status"))
 
 	// Read voltage (in microvolts)
-	voltagePath := filepath.Join(batteryPath, "voltage_now")
-	if data, err := os.ReadFile(voltagePath); err == nil {
-		if microvolts, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil {
-			status.Voltage = float64(microvolts) / 1_000_000.0
-		}
+	if microvolts, ok := errs.readInt(filepath.Join(batteryPath, "voltage_now")); ok {
+		status.Voltage = float64(microvolts) / 1_000_000.0
+	}
+
+	// Read the design minimum voltage (in microvolts)
+	if microvolts, ok := errs.readInt(filepath.Join(batteryPath, "voltage_min_design")); ok && microvolts > 0 {
+		status.VoltageMinDesign = float64(microvolts) / 1_000_000.0
 	}
 
 	// Read current (in microamperes)
-	currentPath := filepath.Join(batteryPath, "current_now")
-	if data, err := os.ReadFile(currentPath); err == nil {
-		if microamps, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil {
-			status.Current = float64(microamps) / 1_000_000.0
-		}
+	if microamps, ok := errs.readInt(filepath.Join(batteryPath, "current_now")); ok {
+		status.Current = float64(microamps) / 1_000_000.0
 	}
 
 	// Read power (in microwatts)
-	powerPath := filepath.Join(batteryPath, "power_now")
-	if data, err := os.ReadFile(powerPath); err == nil {
-		if microwatts, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil {
-			status.Power = float64(microwatts) / 1_000_000.0
-		}
+	if microwatts, ok := errs.readInt(filepath.Join(batteryPath, "power_now")); ok {
+		status.Power = float64(microwatts) / 1_000_000.0
 	}
 
 	// If power not available but voltage and current are, calculate power
@@ -79,38 +159,125 @@ func ReadBatteryStatus() BatteryStatus {
 	}
 
 	// Read health
-	healthPath := filepath.Join(batteryPath, "health")
-	if data, err := os.ReadFile(healthPath); err == nil {
-		status.Health = strings.TrimSpace(string(data))
-	}
+	status.Health = errs.read(filepath.Join(batteryPath, "health"))
 
 	// Read temperature (in tenths of degree Celsius)
-	tempPath := filepath.Join(batteryPath, "temp")
-	if data, err := os.ReadFile(tempPath); err == nil {
-		if temp, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil {
-			status.Temperature = float64(temp) / 10.0
-		}
+	if temp, ok := errs.readInt(filepath.Join(batteryPath, "temp")); ok {
+		status.Temperature = float64(temp) / 10.0
 	}
 
 	// Read energy (in micro-watt-hours)
-	energyPath := filepath.Join(batteryPath, "energy_now")
-	if data, err := os.ReadFile(energyPath); err == nil {
-		if microWh, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil {
-			status.Energy = float64(microWh) / 1_000_000.0
-		}
+	if microWh, ok := errs.readInt(filepath.Join(batteryPath, "energy_now")); ok {
+		status.Energy = float64(microWh) / 1_000_000.0
 	}
 
+	// Read the full energy (in micro-watt-hours), or estimate it from the
+	// full charge (in micro-ampere-hours) at the present voltage
+	if microWh, err := readSysfsInt(filepath.Join(batteryPath, "energy_full")); err == nil && microWh > 0 {
+		status.EnergyFull = float64(microWh) / 1_000_000.0
+	} else if microAh, err := readSysfsInt(filepath.Join(batteryPath, "charge_full")); err == nil && microAh > 0 {
+		status.EnergyFull = float64(microAh) / 1_000_000.0 * status.Voltage
+	}
+
+	// Read the charge limit, under its older name on some ThinkPads
+	status.ChargeLimit = readChargeLimit(batteryPath)
+
 	// Read capacity level
-	capacityLevelPath := filepath.Join(batteryPath, "capacity_level")
-	if data, err := os.ReadFile(capacityLevelPath); err == nil {
-		status.CapacityLevel = strings.TrimSpace(string(data))
+	status.CapacityLevel = errs.read(filepath.Join(batteryPath, "capacity_level"))
+
+	validateCapacity(&status, batteryPath)
+
+	return status, errors.Join(errs...)
+}
+
+// firstBattery returns the supply readBatteryStatus reads, if any
+func firstBattery(supplies []string) string {
+	for _, supply := range supplies {
+		if readTrimmed(filepath.Join(supply, "type")) == "Battery" {
+			return supply
+		}
 	}
+	return ""
+}
 
-	return status
+// batteryDevice returns the resolved device directory of the battery
+// readBatteryStatus reads, or "" without one
+func batteryDevice(root string) string {
+	supplies, _ := filepath.Glob(filepath.Join(root, powerSupplyClassPath, "*"))
+	battery := firstBattery(supplies)
+	if battery == "" {
+		return ""
+	}
+	dir, err := filepath.EvalSymlinks(battery)
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+// readACPower reads the voltage and current an online adapter reports, which
+// USB-C chargers expose for the negotiated PD contract. Either may be absent.
+func readACPower(status *BatteryStatus, adapterPath string) {
+	if microvolts, err := readSysfsInt(filepath.Join(adapterPath, "voltage_now")); err == nil && microvolts > 0 {
+		status.ACVoltage = float64(microvolts) / 1_000_000.0
+	}
+	if microamps, err := readSysfsInt(filepath.Join(adapterPath, "current_now")); err == nil && microamps > 0 {
+		status.ACCurrent = float64(microamps) / 1_000_000.0
+	}
+}
+
+// validateCapacity guards against firmware glitches reporting capacities
+// outside 0–100 (e.g. 255). The raw value is kept and the reading marked
+// suspect; capacity is recomputed from energy or charge counters when
+// possible, and clamped otherwise.
+func validateCapacity(status *BatteryStatus, batteryPath string) {
+	if status.Capacity >= 0 && status.Capacity <= 100 {
+		return
+	}
+	status.RawCapacity = status.Capacity
+	status.CapacitySuspect = true
+
+	for _, pair := range [][2]string{{"energy_now", "energy_full"}, {"charge_now", "charge_full"}} {
+		now, errNow := readSysfsInt(filepath.Join(batteryPath, pair[0]))
+		full, errFull := readSysfsInt(filepath.Join(batteryPath, pair[1]))
+		if errNow == nil && errFull == nil && full > 0 && now >= 0 && now <= full {
+			status.Capacity = int(now * 100 / full)
+			return
+		}
+	}
+
+	status.Capacity = max(0, min(100, status.Capacity))
+}
+
+// chargeLimitFiles hold the capacity charging stops at, newest name first
+var chargeLimitFiles = []string{"charge_control_end_threshold", "charge_stop_threshold"}
+
+// readChargeLimit returns the battery's charge stop threshold, 0 when it has
+// none or it is 100%
+func readChargeLimit(batteryPath string) int {
+	for _, name := range chargeLimitFiles {
+		if limit, err := readSysfsInt(filepath.Join(batteryPath, name)); err == nil {
+			if limit > 0 && limit < 100 {
+				return int(limit)
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// readSysfsInt reads a single integer attribute
+func readSysfsInt(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
 }
 
 // Helper function to check if battery exists
 func batteryExists() bool {
+	powerSupplyBasePath := filepath.Join(sysfsRoot, powerSupplyClassPath)
 	_, err := os.Stat(powerSupplyBasePath)
 	if os.IsNotExist(err) {
 		return false
@@ -0,0 +1,307 @@
+package monitor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBatteryHealthState(t *testing.T) {
+	tests := []struct {
+		health string
+		want   State
+	}{
+		{"Good", StateOK},
+		{"Unknown", StateOK},
+		{"", StateOK},
+		{"Warm", StateWarning},
+		{"Cool", StateWarning},
+		{"Overheat", StateCritical},
+		{"Dead", StateCritical},
+		{"Over voltage", StateCritical},
+		{"Unspecified failure", StateCritical},
+		{"Cold", StateCritical},
+		{"Watchdog timer expire", StateCritical},
+		{"Safety timer expire", StateCritical},
+	}
+	for _, tt := range tests {
+		if got := BatteryHealthState(tt.health); got != tt.want {
+			t.Errorf("BatteryHealthState(%q) = %v, want %v", tt.health, got, tt.want)
+		}
+	}
+}
+
+func TestBatterySensorAdapterHealth(t *testing.T) {
+	bat := BatteryStatus{Capacity: 90, Status: "Discharging", Health: "Overheat"}
+	adapter := BatterySensorAdapter{BatteryStatus: &bat}
+	if !adapter.Critical() {
+		t.Error("expected Overheat health to be critical despite high capacity")
+	}
+	bat.Health = "Warm"
+	if adapter.Critical() || !adapter.Warning() {
+		t.Error("expected Warm health to be warning only")
+	}
+	bat.Health = "Good"
+	if adapter.Critical() || adapter.Warning() {
+		t.Error("expected Good health at 90% to be OK")
+	}
+}
+
+func TestReadBatteryStatusCapacityValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		capacity  int
+		suspect   bool
+		rawReport int
+	}{
+		{"valid", map[string]string{"capacity": "85"}, 85, false, 0},
+		{"glitch recomputed from energy", map[string]string{"capacity": "255", "energy_now": "30000000", "energy_full": "50000000"}, 60, true, 255},
+		{"glitch recomputed from charge", map[string]string{"capacity": "255", "charge_now": "1000000", "charge_full": "4000000"}, 25, true, 255},
+		{"glitch clamped high", map[string]string{"capacity": "255"}, 100, true, 255},
+		{"glitch clamped low", map[string]string{"capacity": "-5"}, 0, true, -5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			files := map[string]string{
+				"class/power_supply/BAT0/type":   "Battery\n",
+				"class/power_supply/BAT0/status": "Discharging\n",
+			}
+			for name, content := range tt.files {
+				files["class/power_supply/BAT0/"+name] = content + "\n"
+			}
+			writeSysfs(t, root, files)
+
+			bat := readBatteryStatus(root)
+			if bat.Capacity != tt.capacity {
+				t.Errorf("expected capacity %d, got %d", tt.capacity, bat.Capacity)
+			}
+			if bat.CapacitySuspect != tt.suspect {
+				t.Errorf("expected suspect %v, got %v", tt.suspect, bat.CapacitySuspect)
+			}
+			if bat.RawCapacity != tt.rawReport {
+				t.Errorf("expected raw capacity %d, got %d", tt.rawReport, bat.RawCapacity)
+			}
+		})
+	}
+}
+
+func TestBatteryCapacityStateByStatus(t *testing.T) {
+	notCharging := &BatteryThresholds{Warning: 15, Critical: 5}
+	tests := []struct {
+		status      string
+		acOnline    bool
+		notCharging *BatteryThresholds
+		want        State
+	}{
+		{"Discharging", false, nil, StateWarning},
+		{"Charging", true, nil, StateOK},
+		{"Full", true, nil, StateOK},
+		{"Not charging", true, nil, StateWarning},
+		{"Not charging", true, notCharging, StateOK},
+		{"Not charging", false, notCharging, StateWarning},
+	}
+	for _, tt := range tests {
+		bat := BatteryStatus{Capacity: 45, Status: tt.status, ACOnline: tt.acOnline}
+		if got := bat.CapacityState(DefaultBatteryThresholds, tt.notCharging); got != tt.want {
+			t.Errorf("%s (ac %v, custom %v): got %v, want %v", tt.status, tt.acOnline, tt.notCharging != nil, got, tt.want)
+		}
+	}
+
+	// Health still applies while charging
+	bat := BatteryStatus{Capacity: 45, Status: "Charging", Health: "Overheat"}
+	if got := bat.State(DefaultBatteryThresholds, nil); got != StateCritical {
+		t.Errorf("expected Overheat to be critical while charging, got %v", got)
+	}
+}
+
+func TestBatterySensorAdapterStatus(t *testing.T) {
+	for _, status := range []string{"Charging", "Full", "Not charging", "Discharging"} {
+		bat := BatteryStatus{Capacity: 5, Status: status}
+		adapter := BatterySensorAdapter{BatteryStatus: &bat}
+		wantCritical := status == "Discharging" || status == "Not charging"
+		if adapter.Critical() != wantCritical {
+			t.Errorf("%s at 5%%: expected critical %v", status, wantCritical)
+		}
+	}
+}
+
+func TestBatterySensorAdapterMatchesReadBatteryStatus(t *testing.T) {
+	root := t.TempDir()
+	write := func(capacity, status, health string) {
+		writeSysfs(t, root, map[string]string{
+			"class/power_supply/BAT0/type":      "Battery\n",
+			"class/power_supply/BAT0/capacity":  capacity + "\n",
+			"class/power_supply/BAT0/status":    status + "\n",
+			"class/power_supply/BAT0/health":    health + "\n",
+			"class/power_supply/BAT0/power_now": "31000000\n",
+		})
+	}
+	write("85", "Charging", "Good")
+	groups := createSensorGroups(root, nil, readBatteryStatus(root))
+	if len(groups) != 1 {
+		t.Fatalf("expected the battery group, got %+v", groups)
+	}
+	adapter := groups[0].Sensors[0]
+
+	tests := []struct{ capacity, status, health, value string }{
+		{"85", "Charging", "Good", "85% ⚡ 31 W"},
+		{"45", "Discharging", "Good", "45% Discharging 31 W"},
+		{"15", "Discharging", "Good", "15% Discharging 31 W"},
+		{"15", "Charging", "Good", "15% ⚡ 31 W"},
+		{"90", "Full", "Overheat", "90% Full 31 W"},
+	}
+	for _, tt := range tests {
+		write(tt.capacity, tt.status, tt.health)
+		if err := adapter.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		want := readBatteryStatus(root).State(DefaultBatteryThresholds, nil)
+		if got := sensorState(adapter); got != want || adapter.Value() != tt.value {
+			t.Errorf("%s%% %s %s: adapter %v %q, expected %v %q", tt.capacity, tt.status, tt.health, got, adapter.Value(), want, tt.value)
+		}
+	}
+}
+
+func TestReadBatteryStatusACOnline(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/power_supply/AC/type":       "Mains\n",
+		"class/power_supply/AC/online":     "1\n",
+		"class/power_supply/BAT0/type":     "Battery\n",
+		"class/power_supply/BAT0/status":   "Not charging\n",
+		"class/power_supply/BAT0/capacity": "80\n",
+	})
+	if bat := readBatteryStatus(root); !bat.ACOnline {
+		t.Error("expected AC online")
+	}
+}
+
+func TestReadBatteryStatusACPower(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"both", map[string]string{"voltage_now": "19800000", "current_now": "3200000"}, "online 19.80V × 3.20A = 63.4W"},
+		{"voltage only", map[string]string{"voltage_now": "20000000"}, "online 20.00V"},
+		{"current only", map[string]string{"current_now": "1500000"}, "online 1.50A"},
+		{"neither", map[string]string{}, "online"},
+		{"offline", map[string]string{"online": "0", "voltage_now": "5000000"}, "offline"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			files := map[string]string{
+				"class/power_supply/BAT0/type":                          "Battery\n",
+				"class/power_supply/BAT0/status":                        "Charging\n",
+				"class/power_supply/BAT0/capacity":                      "50\n",
+				"class/power_supply/ucsi-source-psy-USBC000:001/type":   "USB\n",
+				"class/power_supply/ucsi-source-psy-USBC000:001/online": "1\n",
+			}
+			for name, content := range tt.files {
+				files["class/power_supply/ucsi-source-psy-USBC000:001/"+name] = content + "\n"
+			}
+			writeSysfs(t, root, files)
+			if got := readBatteryStatus(root).ACDescription(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDeepDischargeRisk(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/power_supply/BAT0/type":               "Battery\n",
+		"class/power_supply/BAT0/status":             "Discharging\n",
+		"class/power_supply/BAT0/capacity":           "45\n",
+		"class/power_supply/BAT0/voltage_now":        "3140000\n",
+		"class/power_supply/BAT0/voltage_min_design": "3000000\n",
+	})
+	bat := readBatteryStatus(root)
+	if bat.VoltageMinDesign != 3 {
+		t.Fatalf("expected a 3 V design minimum, got %v", bat.VoltageMinDesign)
+	}
+	// 45% is only a warning by capacity, but 3.14 V is within 5% of 3.00 V
+	if !bat.DeepDischargeRisk() || bat.State(DefaultBatteryThresholds, nil) != StateCritical {
+		t.Errorf("expected a critical deep-discharge risk at %.2f V", bat.Voltage)
+	}
+
+	bat.Voltage = 3.16
+	if bat.DeepDischargeRisk() {
+		t.Errorf("expected no risk more than 5%% above the minimum")
+	}
+	bat.Voltage, bat.Status = 3.05, "Charging"
+	if bat.DeepDischargeRisk() {
+		t.Errorf("expected no risk while charging")
+	}
+	bat.Status, bat.VoltageMinDesign, bat.Capacity = "Discharging", 0, 80
+	if bat.DeepDischargeRisk() || bat.State(DefaultBatteryThresholds, nil) != StateOK {
+		t.Errorf("expected batteries without the design file to go by capacity")
+	}
+}
+
+func TestReadBatteryStatusErrors(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/power_supply/BAT0/type":        "Battery\n",
+		"class/power_supply/BAT0/status":      "Discharging\n",
+		"class/power_supply/BAT0/capacity":    "45\n",
+		"class/power_supply/BAT0/voltage_now": "unknown\n",
+	})
+	bat, err := readBatteryStatusE(root)
+	if bat.Capacity != 45 || bat.Status != "Discharging" {
+		t.Errorf("expected the readable attributes, got %+v", bat)
+	}
+	// Attributes the driver doesn't provide, such as power_now, aren't errors
+	if err == nil || !strings.Contains(err.Error(), "voltage_now") || strings.Contains(err.Error(), "power_now") {
+		t.Errorf("expected only the voltage to fail, got %v", err)
+	}
+
+	if _, err := readBatteryStatusE(filepath.Join(root, "missing")); err == nil {
+		t.Error("expected an error without sysfs")
+	}
+	desktop := t.TempDir()
+	writeSysfs(t, desktop, map[string]string{"class/hwmon/hwmon0/name": "coretemp\n"})
+	if bat, err := readBatteryStatusE(desktop); err != nil || bat.Present() {
+		t.Errorf("expected no battery and no error without power supplies, got %+v, %v", bat, err)
+	}
+}
+
+func TestReadBatteryStatusChargeLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		energyFull float64
+		limit      int
+	}{
+		{"energy", map[string]string{"energy_full": "50000000", "charge_control_end_threshold": "80"}, 50, 80},
+		{"charge", map[string]string{"charge_full": "4000000", "charge_stop_threshold": "60"}, 48, 60},
+		{"no limit", map[string]string{"energy_full": "50000000", "charge_control_end_threshold": "100"}, 50, 0},
+		{"neither", map[string]string{}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			files := map[string]string{
+				"class/power_supply/BAT0/type":        "Battery\n",
+				"class/power_supply/BAT0/status":      "Charging\n",
+				"class/power_supply/BAT0/capacity":    "50\n",
+				"class/power_supply/BAT0/voltage_now": "12000000\n",
+			}
+			for name, content := range tt.files {
+				files["class/power_supply/BAT0/"+name] = content + "\n"
+			}
+			writeSysfs(t, root, files)
+			bat, err := readBatteryStatusE(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bat.EnergyFull != tt.energyFull || bat.ChargeLimit != tt.limit {
+				t.Errorf("expected %v Wh limited to %d%%, got %v Wh and %d%%", tt.energyFull, tt.limit, bat.EnergyFull, bat.ChargeLimit)
+			}
+		})
+	}
+}
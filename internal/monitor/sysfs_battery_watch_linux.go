@@ -0,0 +1,60 @@
+package monitor
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+)
+
+// batteryWatcher listens on the kernel uevent netlink socket for power_supply
+// changes (charger plugged/unplugged, battery status updates). sysfs attribute
+// files don't reliably raise inotify events, but the kernel broadcasts a
+// uevent whenever the power supply core notices a change.
+type batteryWatcher struct {
+	sock *os.File
+}
+
+func newBatteryWatcher() (*batteryWatcher, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC|syscall.SOCK_NONBLOCK, syscall.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, err
+	}
+	addr := &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: 1, Pid: 0}
+	if err := syscall.Bind(fd, addr); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	// A non-blocking fd is registered with the runtime poller, so Close
+	// unblocks a pending Read.
+	return &batteryWatcher{sock: os.NewFile(uintptr(fd), "uevent")}, nil
+}
+
+// wait blocks until a power_supply uevent arrives. It returns false once the
+// watcher has been closed or the socket fails.
+func (w *batteryWatcher) wait() bool {
+	buf := make([]byte, 8192)
+	for {
+		n, err := w.sock.Read(buf)
+		if err != nil {
+			return false
+		}
+		if isPowerSupplyUevent(buf[:n]) {
+			return true
+		}
+	}
+}
+
+func (w *batteryWatcher) close() error {
+	return w.sock.Close()
+}
+
+// isPowerSupplyUevent checks a NUL-separated uevent payload for
+// SUBSYSTEM=power_supply.
+func isPowerSupplyUevent(msg []byte) bool {
+	for _, field := range bytes.Split(msg, []byte{0}) {
+		if bytes.Equal(field, []byte("SUBSYSTEM=power_supply")) {
+			return true
+		}
+	}
+	return false
+}
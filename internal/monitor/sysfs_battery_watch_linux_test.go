@@ -0,0 +1,14 @@
+package monitor
+
+import "testing"
+
+func TestIsPowerSupplyUevent(t *testing.T) {
+	msg := []byte("change@/devices/LNXSYSTM:00/ACPI0003:00/power_supply/AC\x00ACTION=change\x00SUBSYSTEM=power_supply\x00POWER_SUPPLY_ONLINE=1\x00")
+	if !isPowerSupplyUevent(msg) {
+		t.Error("expected power_supply uevent to match")
+	}
+	other := []byte("add@/devices/virtual/net/tun0\x00ACTION=add\x00SUBSYSTEM=net\x00")
+	if isPowerSupplyUevent(other) {
+		t.Error("expected net uevent not to match")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package monitor
+
+import "errors"
+
+type batteryWatcher struct{}
+
+func newBatteryWatcher() (*batteryWatcher, error) {
+	return nil, errors.New("uevent watching is only supported on linux")
+}
+
+func (w *batteryWatcher) wait() bool {
+	return false
+}
+
+func (w *batteryWatcher) close() error {
+	return nil
+}
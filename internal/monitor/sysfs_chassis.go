@@ -0,0 +1,153 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// chassisGroupName is the group of the boolean alarms of hwmon chips
+const chassisGroupName = "Chassis"
+
+// ChassisAlarmSensor reports a boolean alarm of an hwmon chip: a chassis
+// intrusion (intrusion*_alarm) or a channel fault (*_fault, such as an
+// open thermal diode). Once read set it stays latched, and critical, until
+// cleared with Clear, even if the file reads 0 again. The kernel latches
+// intrusion itself, until 0 is written to the file; fault flags follow the
+// hardware and may come and go between refreshes, so the monitor latches
+// them instead, clearing only its own latch.
+type ChassisAlarmSensor struct {
+	path string
+	name string
+	// intrusion is set for intrusion*_alarm, which the kernel clears on
+	// writing 0
+	intrusion bool
+	// set is the flag as last read, latched whether it was ever set since
+	// the last Clear
+	set, latched bool
+}
+
+// ReadChassisAlarms returns a refreshed sensor for each intrusion and fault
+// flag of the hwmon chips
+func ReadChassisAlarms() []Sensor {
+	return readChassisAlarms(sysfsRoot)
+}
+
+func readChassisAlarms(root string) []Sensor {
+	var sensors []Sensor
+	battery := batteryDevice(root)
+	hwmonPaths, _ := filepath.Glob(filepath.Join(root, hwmonClassPath, "hwmon*"))
+	for _, hwmonPath := range hwmonPaths {
+		nameData, err := os.ReadFile(filepath.Join(hwmonPath, "name"))
+		if err != nil || duplicatesBattery(hwmonPath, battery) {
+			continue
+		}
+		chip := strings.TrimSpace(string(nameData))
+		intrusions, _ := filepath.Glob(filepath.Join(hwmonPath, "intrusion*_alarm"))
+		faults, _ := filepath.Glob(filepath.Join(hwmonPath, "*_fault"))
+		for _, path := range append(intrusions, faults...) {
+			base := filepath.Base(path)
+			alarm := &ChassisAlarmSensor{path: path, intrusion: strings.HasPrefix(base, "intrusion")}
+			if alarm.intrusion {
+				alarm.name = fmt.Sprintf("%s_%s", chip, strings.TrimSuffix(base, "_alarm"))
+			} else {
+				// Faults are named after their channel, "CPUTIN fault"
+				channel := strings.TrimSuffix(base, "_fault")
+				alarm.name = fmt.Sprintf("%s_%s fault", chip, channel)
+				if label, err := os.ReadFile(filepath.Join(hwmonPath, channel+"_label")); err == nil {
+					alarm.name = strings.TrimSpace(string(label)) + " fault"
+				}
+			}
+			if err := alarm.Refresh(); err != nil {
+				continue
+			}
+			sensors = append(sensors, alarm)
+		}
+	}
+	return sensors
+}
+
+func (a *ChassisAlarmSensor) Name() string {
+	return a.name
+}
+
+func (a *ChassisAlarmSensor) attribute() string {
+	return a.path
+}
+
+// Value is "on" while the flag reads set, "on (latched)" once it no longer
+// does, and "off"
+func (a *ChassisAlarmSensor) Value() string {
+	switch {
+	case a.set:
+		return "on"
+	case a.latched:
+		return "on (latched)"
+	}
+	return "off"
+}
+
+func (a *ChassisAlarmSensor) Kind() Kind {
+	return KindInfo
+}
+
+func (a *ChassisAlarmSensor) Warning() bool {
+	return false
+}
+
+func (a *ChassisAlarmSensor) Critical() bool {
+	return a.latched
+}
+
+func (a *ChassisAlarmSensor) Refresh() error {
+	flag, err := readSysfsInt(a.path)
+	if err != nil {
+		return err
+	}
+	a.set = flag != 0
+	a.latched = a.latched || a.set
+	return nil
+}
+
+// Clear resets the latch: an intrusion flag by writing 0 to the file, a
+// fault flag in the monitor alone, the kernel offering no way to clear
+// it. The file is then read again, and a flag still set is reported.
+func (a *ChassisAlarmSensor) Clear() error {
+	if a.intrusion {
+		if err := a.writeZero(); err != nil {
+			if errors.Is(err, os.ErrPermission) {
+				return fmt.Errorf("no permission to write %s (needs root)", a.path)
+			}
+			return fmt.Errorf("writing %s: %v", a.path, err)
+		}
+	}
+	a.latched = false
+	if err := a.Refresh(); err != nil {
+		return err
+	}
+	if a.set {
+		return fmt.Errorf("%s is still set", a.name)
+	}
+	return nil
+}
+
+// writeZero writes 0 to the flag's file, which must exist
+func (a *ChassisAlarmSensor) writeZero() error {
+	f, err := os.OpenFile(a.path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString("0")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// clearsHardware tells whether Clear writes to the chip, which only an
+// intrusion flag's does
+func (a *ChassisAlarmSensor) clearsHardware() bool {
+	return a.intrusion
+}
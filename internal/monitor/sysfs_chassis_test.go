@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChassisAlarmsLatch(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":             "nct6775\n",
+		"class/hwmon/hwmon0/intrusion0_alarm": "0\n",
+		"class/hwmon/hwmon0/temp1_input":      "45000\n",
+		"class/hwmon/hwmon0/temp1_label":      "CPUTIN\n",
+		"class/hwmon/hwmon0/temp1_fault":      "0\n",
+		"class/hwmon/hwmon1/name":             "coretemp\n",
+		"class/hwmon/hwmon1/temp1_input":      "50000\n",
+	})
+	write := func(rel, value string) {
+		if err := os.WriteFile(filepath.Join(root, "class/hwmon/hwmon0", rel), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alarms := readChassisAlarms(root)
+	if len(alarms) != 2 || alarms[0].Name() != "nct6775_intrusion0" || alarms[1].Name() != "CPUTIN fault" {
+		t.Fatalf("expected the intrusion and CPUTIN fault flags, got %d sensors", len(alarms))
+	}
+	intrusion, fault := alarms[0], alarms[1]
+	if intrusion.Value() != "off" || intrusion.Critical() {
+		t.Errorf("expected intrusion off, got %s", intrusion.Value())
+	}
+
+	// Set once, latched: a later read of 0 keeps the alarm critical
+	write("intrusion0_alarm", "1")
+	write("temp1_fault", "1")
+	intrusion.Refresh()
+	fault.Refresh()
+	if intrusion.Value() != "on" || !intrusion.Critical() {
+		t.Errorf("expected intrusion on and critical, got %s", intrusion.Value())
+	}
+	write("temp1_fault", "0")
+	fault.Refresh()
+	if fault.Value() != "on (latched)" || !fault.Critical() {
+		t.Errorf("expected the fault latched, got %s", fault.Value())
+	}
+
+	// Clearing writes 0 to the intrusion flag, as the kernel expects, and
+	// leaves the fault file alone
+	if err := intrusion.(Clearable).Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "class/hwmon/hwmon0/intrusion0_alarm")); string(data) != "0" {
+		t.Errorf("expected 0 written to intrusion0_alarm, got %q", data)
+	}
+	if intrusion.Critical() || intrusion.Value() != "off" {
+		t.Errorf("expected intrusion cleared, got %s", intrusion.Value())
+	}
+	if err := fault.(Clearable).Clear(); err != nil || fault.Critical() {
+		t.Errorf("expected the fault cleared, got %v, %s", err, fault.Value())
+	}
+
+	// A flag the hardware still sets comes back at once
+	write("temp1_fault", "1")
+	fault.Refresh()
+	if err := fault.(Clearable).Clear(); err == nil || !fault.Critical() {
+		t.Errorf("expected the fault still set, got %v, %s", err, fault.Value())
+	}
+
+	// Boards without the flags get no group
+	if alarms := readChassisAlarms(t.TempDir()); alarms != nil {
+		t.Errorf("expected no alarms, got %d", len(alarms))
+	}
+}
+
+func TestChassisClearRequiresControl(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":             "nct6775\n",
+		"class/hwmon/hwmon0/intrusion0_alarm": "1\n",
+	})
+	m := NewMonitor()
+	m.width, m.height = 80, 24
+	m.RegisterSensorGroup(SensorGroup{Name: chassisGroupName, Sensors: readChassisAlarms(root)})
+
+	m = sendKeys(m, "down", "X")
+	if !strings.Contains(m.status, "--enable-control") {
+		t.Errorf("expected control-disabled message, got %q", m.status)
+	}
+	if !m.extraGroups[0].Sensors[0].Critical() {
+		t.Error("the latch must not clear without control")
+	}
+
+	WithControl()(&m)
+	m = sendKeys(m, "X")
+	if m.status != "Cleared nct6775_intrusion0" || m.extraGroups[0].Sensors[0].Critical() {
+		t.Errorf("expected the latch cleared, got %q", m.status)
+	}
+}
+
+func TestChassisFaultClearsWithoutControl(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":        "nct6775\n",
+		"class/hwmon/hwmon0/temp1_fault": "1\n",
+	})
+	m := NewMonitor()
+	m.width, m.height = 80, 24
+	m.RegisterSensorGroup(SensorGroup{Name: chassisGroupName, Sensors: readChassisAlarms(root)})
+	if err := os.WriteFile(filepath.Join(root, "class/hwmon/hwmon0/temp1_fault"), []byte("0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fault's latch is the monitor's own, cleared without writing sysfs
+	m = sendKeys(m, "down", "X")
+	if m.status != "Cleared nct6775_temp1 fault" || m.extraGroups[0].Sensors[0].Critical() {
+		t.Errorf("expected the fault cleared without control, got %q", m.status)
+	}
+}
@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CoolingBinding links a thermal zone to a cooling device it drives, as
+// listed by the zone's cdevN symlinks and cdevN_trip_point files
+type CoolingBinding struct {
+	Device    string  // cooling device name, e.g. "cooling_device3"
+	Type      string  // cooling device type, e.g. "Fan" or "Processor"
+	Path      string  // resolved device directory, empty if the link dangles
+	TripPoint int     // index of the activating trip point, -1 if none
+	TripType  string  // type of that trip point, e.g. "active"
+	TripTemp  float64 // temperature of that trip point, in Celsius
+}
+
+// readCoolingBindings reads the cooling devices bound to a thermal zone,
+// ordered by cdev number. A dangling cdev link is kept without a Path, so
+// the detail view can show that the device went away.
+func readCoolingBindings(zonePath string) []CoolingBinding {
+	links, _ := filepath.Glob(filepath.Join(zonePath, "cdev*"))
+	var indexes []int
+	for _, link := range links {
+		if n, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(link), "cdev")); err == nil {
+			indexes = append(indexes, n)
+		}
+	}
+	slices.Sort(indexes)
+
+	var bindings []CoolingBinding
+	for _, n := range indexes {
+		link := filepath.Join(zonePath, fmt.Sprintf("cdev%d", n))
+		target, err := os.Readlink(link)
+		if err != nil {
+			continue
+		}
+		b := CoolingBinding{Device: filepath.Base(target), TripPoint: -1}
+		if dir, err := filepath.EvalSymlinks(link); err == nil {
+			b.Path = dir
+			b.Type = readTrimmed(filepath.Join(dir, "type"))
+		}
+		if trip, err := readSysfsInt(link + "_trip_point"); err == nil && trip >= 0 {
+			b.TripPoint = int(trip)
+			b.TripType = readTrimmed(filepath.Join(zonePath, fmt.Sprintf("trip_point_%d_type", trip)))
+			if milli, err := readSysfsInt(filepath.Join(zonePath, fmt.Sprintf("trip_point_%d_temp", trip))); err == nil {
+				b.TripTemp = float64(milli) / 1000.0
+			}
+		}
+		bindings = append(bindings, b)
+	}
+	return bindings
+}
+
+// coolingState reads a cooling device's current and maximum state. The
+// state changes as the governor reacts, so it is read when shown rather
+// than at discovery.
+func (b CoolingBinding) coolingState() (cur, max int64, err error) {
+	if b.Path == "" {
+		return 0, 0, os.ErrNotExist
+	}
+	if cur, err = readSysfsInt(filepath.Join(b.Path, "cur_state")); err != nil {
+		return 0, 0, err
+	}
+	if max, err = readSysfsInt(filepath.Join(b.Path, "max_state")); err != nil {
+		return 0, 0, err
+	}
+	return cur, max, nil
+}
@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeCoolingZone(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/thermal/thermal_zone0/type":              "acpitz\n",
+		"class/thermal/thermal_zone0/temp":              "58000\n",
+		"class/thermal/thermal_zone0/trip_point_0_temp": "55000\n",
+		"class/thermal/thermal_zone0/trip_point_0_type": "active\n",
+		"class/thermal/thermal_zone0/trip_point_1_temp": "95000\n",
+		"class/thermal/thermal_zone0/trip_point_1_type": "passive\n",
+		"class/thermal/thermal_zone0/cdev0_trip_point":  "0\n",
+		"class/thermal/thermal_zone0/cdev0_weight":      "0\n",
+		"class/thermal/thermal_zone0/cdev2_trip_point":  "1\n",
+		"class/thermal/thermal_zone0/cdev10_trip_point": "-1\n",
+		"class/thermal/cooling_device0/type":            "Fan\n",
+		"class/thermal/cooling_device0/cur_state":       "1\n",
+		"class/thermal/cooling_device0/max_state":       "5\n",
+		"class/thermal/cooling_device10/type":           "Processor\n",
+		"class/thermal/cooling_device10/cur_state":      "0\n",
+		"class/thermal/cooling_device10/max_state":      "10\n",
+	})
+	linkSysfs(t, root, "class/thermal/thermal_zone0/cdev0", "class/thermal/cooling_device0")
+	linkSysfs(t, root, "class/thermal/thermal_zone0/cdev10", "class/thermal/cooling_device10")
+	// The device behind cdev2 was unregistered
+	if err := os.Symlink("../cooling_device2", filepath.Join(root, "class/thermal/thermal_zone0/cdev2")); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestReadCoolingBindings(t *testing.T) {
+	root := writeCoolingZone(t)
+	sensors := readTemperatures(root)
+	if len(sensors) != 1 {
+		t.Fatalf("expected the zone despite the dangling link, got %d sensors", len(sensors))
+	}
+	bindings := sensors[0].Cooling
+	if len(bindings) != 3 {
+		t.Fatalf("expected 3 cooling devices, got %+v", bindings)
+	}
+	if b := bindings[0]; b.Device != "cooling_device0" || b.Type != "Fan" || b.TripPoint != 0 || b.TripType != "active" || b.TripTemp != 55 {
+		t.Errorf("unexpected fan binding %+v", b)
+	}
+	if b := bindings[1]; b.Device != "cooling_device2" || b.Path != "" {
+		t.Errorf("expected the dangling cdev2 second, got %+v", b)
+	}
+	if b := bindings[2]; b.Device != "cooling_device10" || b.TripPoint != -1 {
+		t.Errorf("expected cdev10 last without a trip point, got %+v", b)
+	}
+}
+
+func TestDetailViewListsCoolingDevices(t *testing.T) {
+	m := NewMonitor()
+	m.width, m.height = 100, 30
+	m.temperatureSensors = readTemperatures(writeCoolingZone(t))
+	m = sendKeys(m, "down", "enter")
+
+	view := m.View()
+	for _, want := range []string{
+		"Fan (cooling_device0): trip 0 active at 55.0°C, state 1/5",
+		"cooling_device2: device missing",
+		"Processor (cooling_device10): no trip point, state 0/10",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in the detail view:\n%s", want, view)
+		}
+	}
+}
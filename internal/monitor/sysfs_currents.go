@@ -0,0 +1,107 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// currentGroupName is the group of the current channels of hwmon chips
+const currentGroupName = "Currents"
+
+// CurrentSensor reports a current channel of an hwmon chip (curr*_input, in
+// milliamps), such as a VRM's output or a USB-C port controller. It warns
+// past the chip's curr*_max and is critical past curr*_crit, each when set,
+// or while the chip's alarm flags for the channel are.
+type CurrentSensor struct {
+	path string // the curr*_input file
+	name string
+	// Limits in milliamps, 0 when the chip doesn't set them
+	max, crit int64
+	milliamps int64
+	alarms    []alarmFile
+	alarm     State
+}
+
+// ReadCurrents returns a refreshed sensor for each current channel of the
+// hwmon chips
+func ReadCurrents() []Sensor {
+	return readCurrents(sysfsRoot)
+}
+
+func readCurrents(root string) []Sensor {
+	var sensors []Sensor
+	battery := batteryDevice(root)
+	hwmonPaths, _ := filepath.Glob(filepath.Join(root, hwmonClassPath, "hwmon*"))
+	for _, hwmonPath := range hwmonPaths {
+		nameData, err := os.ReadFile(filepath.Join(hwmonPath, "name"))
+		if err != nil || duplicatesBattery(hwmonPath, battery) {
+			continue
+		}
+		chip := strings.TrimSpace(string(nameData))
+		inputs, _ := filepath.Glob(filepath.Join(hwmonPath, "curr*_input"))
+		for _, input := range inputs {
+			base := strings.TrimSuffix(filepath.Base(input), "_input")
+			// Named like the temperatures of the chip
+			channel := &CurrentSensor{path: input, name: fmt.Sprintf("%s_%s", chip, base)}
+			if label, err := os.ReadFile(filepath.Join(hwmonPath, base+"_label")); err == nil {
+				channel.name = strings.TrimSpace(string(label))
+			}
+			if ma, err := readSysfsInt(filepath.Join(hwmonPath, base+"_max")); err == nil && ma > 0 {
+				channel.max = ma
+			}
+			if ma, err := readSysfsInt(filepath.Join(hwmonPath, base+"_crit")); err == nil && ma > 0 {
+				channel.crit = ma
+			}
+			channel.alarms = channelAlarms(hwmonPath, base)
+			if err := channel.Refresh(); err != nil {
+				continue
+			}
+			sensors = append(sensors, channel)
+		}
+	}
+	return sensors
+}
+
+func (c *CurrentSensor) Name() string {
+	return c.name
+}
+
+func (c *CurrentSensor) Value() string {
+	return formatMeasurement(KindCurrent, float64(c.milliamps)/1000)
+}
+
+func (c *CurrentSensor) Kind() Kind {
+	return KindCurrent
+}
+
+func (c *CurrentSensor) Measurement() (float64, bool) {
+	return float64(c.milliamps) / 1000, true
+}
+
+func (c *CurrentSensor) attribute() string {
+	return c.path
+}
+
+func (c *CurrentSensor) Warning() bool {
+	return c.max > 0 && c.milliamps > c.max || c.alarm == StateWarning
+}
+
+func (c *CurrentSensor) Critical() bool {
+	return c.crit > 0 && c.milliamps > c.crit || c.alarm == StateCritical
+}
+
+func (c *CurrentSensor) Alarm() State {
+	return c.alarm
+}
+
+func (c *CurrentSensor) Refresh() error {
+	ma, err := readSysfsInt(c.path)
+	if err != nil {
+		return err
+	}
+	c.milliamps = ma
+	c.alarm = readAlarms(c.alarms)
+	return nil
+}
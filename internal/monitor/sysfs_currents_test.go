@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCurrentChannels(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":        "ina3221\n",
+		"class/hwmon/hwmon0/curr1_input": "1250\n",
+		"class/hwmon/hwmon0/curr1_label": "VDD_CPU\n",
+		"class/hwmon/hwmon0/curr1_max":   "3000\n",
+		"class/hwmon/hwmon0/curr1_crit":  "4000\n",
+		"class/hwmon/hwmon0/curr2_input": "480\n",
+		"class/hwmon/hwmon1/name":        "BAT0\n",
+		"class/hwmon/hwmon1/curr1_input": "1840\n",
+		"class/power_supply/BAT0/type":   "Battery\n",
+		"class/power_supply/BAT0/status": "Discharging\n",
+	})
+	linkSysfs(t, root, "class/hwmon/hwmon1/device", "class/power_supply/BAT0")
+
+	currents := readCurrents(root)
+	var names []string
+	for _, c := range currents {
+		names = append(names, c.Name()+" "+c.Value())
+	}
+	// The battery's chip repeats the battery's current
+	if len(currents) != 2 || names[0] != "VDD_CPU 1.25A" || names[1] != "ina3221_curr2 0.48A" {
+		t.Fatalf("expected VDD_CPU and curr2, got %q", names)
+	}
+	if SensorKind(currents[0]) != KindCurrent {
+		t.Errorf("expected a current, got %s", SensorKind(currents[0]))
+	}
+
+	cpu := currents[0]
+	for _, tc := range []struct {
+		ma                string
+		warning, critical bool
+	}{
+		{"1250", false, false},
+		{"3200", true, false},
+		{"4100", true, true},
+	} {
+		path := filepath.Join(root, "class/hwmon/hwmon0/curr1_input")
+		if err := os.WriteFile(path, []byte(tc.ma+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cpu.Refresh()
+		if cpu.Warning() != tc.warning || cpu.Critical() != tc.critical {
+			t.Errorf("VDD_CPU at %s mA: expected warning %v critical %v", tc.ma, tc.warning, tc.critical)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fansGroupName is the group of the fans found on hwmon chips
+const fansGroupName = "Fans"
+
+// FanSensor reports the speed of an hwmon fan channel (fan*_input). It
+// warns below the chip's fan*_min, when one is set, and while the chip's
+// alarm flags for the fan are set. A fan reading 0 RPM after it was seen
+// spinning is critical: it stalled or lost its tachometer.
+type FanSensor struct {
+	path   string // the fan*_input file
+	name   string
+	min    int64
+	rpm    int64
+	alarms []alarmFile
+	alarm  State
+	// top is the highest speed read, telling a fan at full speed apart
+	// from one stuck lower
+	top int64
+	// spun is set once the fan was read turning; fans stopped since
+	// startup, like unconnected headers, stay OK
+	spun bool
+}
+
+// ReadFanSpeeds returns a refreshed sensor for each hwmon fan
+func ReadFanSpeeds() []Sensor {
+	return readFans(sysfsRoot)
+}
+
+func readFans(root string) []Sensor {
+	var sensors []Sensor
+	battery := batteryDevice(root)
+	hwmonPaths, _ := filepath.Glob(filepath.Join(root, hwmonClassPath, "hwmon*"))
+	for _, hwmonPath := range hwmonPaths {
+		nameData, err := os.ReadFile(filepath.Join(hwmonPath, "name"))
+		if err != nil || duplicatesBattery(hwmonPath, battery) {
+			continue
+		}
+		chip := strings.TrimSpace(string(nameData))
+		inputs, _ := filepath.Glob(filepath.Join(hwmonPath, "fan*_input"))
+		for _, input := range inputs {
+			base := strings.TrimSuffix(filepath.Base(input), "_input")
+			fan := &FanSensor{path: input, name: fmt.Sprintf("%s_%s", chip, base)}
+			if label, err := os.ReadFile(filepath.Join(hwmonPath, base+"_label")); err == nil {
+				fan.name = strings.TrimSpace(string(label))
+			}
+			if minimum, err := readSysfsInt(filepath.Join(hwmonPath, base+"_min")); err == nil && minimum > 0 {
+				fan.min = minimum
+			}
+			fan.alarms = channelAlarms(hwmonPath, base)
+			if err := fan.Refresh(); err == nil {
+				sensors = append(sensors, fan)
+			}
+		}
+	}
+	return sensors
+}
+
+func (f *FanSensor) Name() string {
+	return f.name
+}
+
+func (f *FanSensor) Value() string {
+	return formatMeasurement(KindFan, float64(f.rpm))
+}
+
+func (f *FanSensor) Kind() Kind {
+	return KindFan
+}
+
+func (f *FanSensor) Measurement() (float64, bool) {
+	return float64(f.rpm), true
+}
+
+func (f *FanSensor) attribute() string {
+	return f.path
+}
+
+func (f *FanSensor) Warning() bool {
+	return f.min > 0 && f.rpm < f.min || f.alarm == StateWarning
+}
+
+func (f *FanSensor) Critical() bool {
+	return f.alarm == StateCritical || f.stopped()
+}
+
+func (f *FanSensor) Alarm() State {
+	return f.alarm
+}
+
+func (f *FanSensor) Refresh() error {
+	rpm, err := readSysfsInt(f.path)
+	if err != nil {
+		return err
+	}
+	f.rpm, f.top = rpm, max(f.top, rpm)
+	f.spun = f.spun || rpm > 0
+	f.alarm = readAlarms(f.alarms)
+	return nil
+}
+
+// stopped reports whether the fan reads 0 RPM after it was seen spinning
+func (f *FanSensor) stopped() bool {
+	return f.spun && f.rpm == 0
+}
+
+// chip returns the hwmon directory of the fan
+func (f *FanSensor) chip() string {
+	return filepath.Dir(f.path)
+}
+
+// idle reports whether the fan is stopped, or no faster than at since
+// while below the highest speed it was read at: a fan holding its top
+// speed is doing what it can
+func (f *FanSensor) idle(since int64) bool {
+	return f.rpm == 0 || f.rpm <= since && f.rpm < f.top
+}
@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFanStates(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":       "nct6775\n",
+		"class/hwmon/hwmon0/fan1_input": "900\n",
+		"class/hwmon/hwmon0/fan1_label": "CPU fan\n",
+		"class/hwmon/hwmon0/fan1_min":   "600\n",
+		"class/hwmon/hwmon0/fan2_input": "0\n",
+		"class/hwmon/hwmon1/name":       "BAT0\n",
+		"class/hwmon/hwmon1/fan1_input": "0\n",
+		"class/power_supply/BAT0/type":  "Battery\n",
+	})
+	linkSysfs(t, root, "class/hwmon/hwmon1/device", "class/power_supply/BAT0")
+	// The battery's own chip is left to the battery section
+	fans := readFans(root)
+	if len(fans) != 2 || fans[0].Name() != "CPU fan" || fans[1].Name() != "nct6775_fan2" {
+		t.Fatalf("expected the labeled fan and the unconnected header, got %v", fans)
+	}
+	cpu, header := fans[0], fans[1]
+	if cpu.Warning() || cpu.Critical() || header.Warning() || header.Critical() {
+		t.Errorf("expected both fans OK, a fan never seen spinning included")
+	}
+
+	write := func(value string) {
+		if err := os.WriteFile(filepath.Join(root, "class/hwmon/hwmon0/fan1_input"), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cpu.Refresh()
+	}
+	write("450")
+	if !cpu.Warning() || cpu.Critical() {
+		t.Errorf("expected a warning below fan1_min, got %s", cpu.Value())
+	}
+	write("0")
+	if !cpu.Critical() {
+		t.Errorf("expected a stopped fan that spun before to be critical")
+	}
+	write("800")
+	if cpu.Warning() || cpu.Critical() {
+		t.Errorf("expected OK once spinning again, got %s", cpu.Value())
+	}
+}
@@ -0,0 +1,392 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxFindValueSize limits how much of an attribute FindAttributes prints
+const maxFindValueSize = 256
+
+// batteryAttributes are the battery files readBatteryStatus uses
+var batteryAttributes = map[string]string{
+	"type":               "supply type",
+	"capacity":           "battery capacity",
+	"status":             "battery status",
+	"voltage_now":        "battery voltage",
+	"voltage_min_design": "deep-discharge voltage",
+	"current_now":        "battery current",
+	"power_now":          "battery power",
+	"health":             "battery health",
+	"temp":               "battery temperature",
+	"energy_now":         "battery energy (also validates capacity)",
+	"energy_full":        "validates capacity",
+	"charge_now":         "validates capacity",
+	"charge_full":        "validates capacity",
+	"capacity_level":     "battery capacity level",
+}
+
+// adapterAttributes are the Mains/USB supply files readBatteryStatus uses
+var adapterAttributes = map[string]string{
+	"type":        "supply type",
+	"online":      "AC online state",
+	"voltage_now": "AC voltage",
+	"current_now": "AC current",
+}
+
+// hwmonFamily tells how the monitor reads the channels of an hwmon family
+// other than temperatures, such as fan1, fan2...
+type hwmonFamily struct {
+	// sensor is what a value file read by the family's collector is
+	sensor string
+	// read discovers the family's sensors, each a chipAttribute
+	read func(root string) []Sensor
+	// values are the suffixes of the value files, in order of preference
+	values []string
+	// attributes are the other files the collector reads, by suffix
+	attributes map[string]string
+	// alarms is set when the collector reads the channels' alarm flags
+	alarms bool
+}
+
+// hwmonFamilies are the families read by collectors, by channel prefix
+var hwmonFamilies = map[string]hwmonFamily{
+	"fan": {
+		sensor:     "fan sensor",
+		read:       readFans,
+		values:     []string{"_input"},
+		attributes: map[string]string{"_label": "label", "_min": "Low threshold"},
+		alarms:     true,
+	},
+	"in": {
+		sensor: "voltage sensor",
+		read:   readVoltages,
+		values: []string{"_input"},
+		attributes: map[string]string{
+			"_label": "label", "_min": "Low threshold", "_max": "High threshold",
+			"_lcrit": "Low critical threshold", "_crit": "Critical threshold",
+		},
+		alarms: true,
+	},
+	"curr": {
+		sensor: "current sensor",
+		read:   readCurrents,
+		values: []string{"_input"},
+		attributes: map[string]string{
+			"_label": "label", "_max": "High threshold", "_crit": "Critical threshold",
+		},
+		alarms: true,
+	},
+	"power": {
+		sensor: "power sensor",
+		read:   groupedSensors(readPowerGroups),
+		values: []string{"_average", "_input"},
+		attributes: map[string]string{
+			"_label": "label", "_cap": "High threshold", "_cap_max": "Critical threshold",
+		},
+		alarms: true,
+	},
+	"intrusion": {
+		sensor: "chassis alarm",
+		read:   readChassisAlarms,
+		values: []string{"_alarm"},
+	},
+	"humidity": {
+		sensor: "humidity sensor",
+		read:   readHumidity,
+		values: []string{"_input"},
+		attributes: map[string]string{
+			"_label": "label", "_min": "Low threshold", "_max": "High threshold",
+		},
+		alarms: true,
+	},
+	"pwm": {
+		sensor:     "PWM output",
+		read:       groupedSensors(readPWMGroups),
+		values:     []string{""},
+		attributes: map[string]string{"_enable": "control mode"},
+	},
+}
+
+// groupedSensors adapts a reader of per-chip groups to hwmonFamily.read
+func groupedSensors(read func(root string) []SensorGroup) func(root string) []Sensor {
+	return func(root string) []Sensor {
+		var sensors []Sensor
+		for _, group := range read(root) {
+			sensors = append(sensors, group.Sensors...)
+		}
+		return sensors
+	}
+}
+
+// AttributeMatch is a sysfs attribute found by FindAttributes
+type AttributeMatch struct {
+	Path   string // relative to the sysfs root
+	Value  string // trimmed content, or the read error
+	Status string // how discovery used the file, or why it was skipped
+}
+
+// FindAttributes lists the attribute files under the hwmon, thermal and
+// power_supply classes whose chip name, label or file name contains pattern
+// (case-insensitive) or matches it as a glob, explaining how the monitor's
+// discovery treats each of them.
+func FindAttributes(pattern string) []AttributeMatch {
+	return findAttributes(sysfsRoot, pattern)
+}
+
+func findAttributes(root, pattern string) []AttributeMatch {
+	// Index what discovery actually produced, by value file
+	discovered := make(map[string]string)
+	for _, sensor := range readTemperatures(root) {
+		discovered[valueFilePath(sensor)] = sensor.Name
+	}
+	for _, family := range hwmonFamilies {
+		for _, sensor := range family.read(root) {
+			if a, ok := sensor.(chipAttribute); ok {
+				discovered[a.attribute()] = sensor.Name()
+			}
+		}
+	}
+
+	var matches []AttributeMatch
+	visit := func(dir, chip string, classify func(file string) string) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || entry.Type()&os.ModeSymlink != 0 {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			label := readTrimmed(filepath.Join(dir, channelOf(entry.Name())+"_label"))
+			if !matchesPattern(pattern, chip, label, entry.Name()) {
+				continue
+			}
+			rel, _ := filepath.Rel(root, path)
+			matches = append(matches, AttributeMatch{Path: rel, Value: readForFind(path), Status: classify(entry.Name())})
+		}
+	}
+
+	zones, _ := filepath.Glob(filepath.Join(root, thermalClassPath, "thermal_zone*"))
+	for _, zone := range zones {
+		visit(zone, readTrimmed(filepath.Join(zone, "type")), func(file string) string {
+			return classifyThermal(zone, file, discovered)
+		})
+	}
+	chips, _ := filepath.Glob(filepath.Join(root, hwmonClassPath, "hwmon*"))
+	battery := batteryDevice(root)
+	for _, chip := range chips {
+		name := readTrimmed(filepath.Join(chip, "name"))
+		visit(chip, name, func(file string) string {
+			if duplicatesBattery(chip, battery) {
+				return "skipped: duplicates the power_supply battery"
+			}
+			return classifyHwmon(chip, name, file, discovered)
+		})
+	}
+	supplies, _ := filepath.Glob(filepath.Join(root, powerSupplyClassPath, "*"))
+	first := firstBattery(supplies)
+	for _, supply := range supplies {
+		visit(supply, filepath.Base(supply), func(file string) string {
+			return classifySupply(supply, first, file)
+		})
+	}
+	return matches
+}
+
+// channelOf returns the channel prefix of an hwmon attribute ("temp1" for
+// "temp1_input"), or the name itself
+func channelOf(file string) string {
+	channel, _, _ := strings.Cut(file, "_")
+	return channel
+}
+
+func matchesPattern(pattern string, candidates ...string) bool {
+	lower := strings.ToLower(pattern)
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		if strings.Contains(strings.ToLower(c), lower) || match(pattern, c) {
+			return true
+		}
+	}
+	return false
+}
+
+func readForFind(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	if len(data) > maxFindValueSize {
+		data = append(data[:maxFindValueSize], "..."...)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func classifyThermal(zone, file string, discovered map[string]string) string {
+	valuePath := filepath.Join(zone, "temp")
+	name, ok := discovered[valuePath]
+	switch file {
+	case "temp":
+		if ok {
+			return fmt.Sprintf("temperature sensor %q", name)
+		}
+		return unreadableValue(valuePath)
+	case "type":
+		return "zone name"
+	case "trip_point_0_temp":
+		return fmt.Sprintf("High threshold of %q", name)
+	case "trip_point_1_temp":
+		return fmt.Sprintf("Critical threshold of %q", name)
+	}
+	if strings.HasPrefix(file, "cdev") && strings.HasSuffix(file, "_trip_point") {
+		return fmt.Sprintf("cooling device trip point of %q", name)
+	}
+	return "skipped: not read by the monitor"
+}
+
+func classifyHwmon(chip, chipName, file string, discovered map[string]string) string {
+	switch file {
+	case "name":
+		return "chip name"
+	case globalAlarmFile:
+		if chipName == "" {
+			return "skipped: chip has no name file"
+		}
+		return "alarm flags of channels without their own"
+	}
+	if name, ok := discovered[filepath.Join(chip, file)]; ok && strings.HasSuffix(file, "_fault") {
+		return fmt.Sprintf("fault flag %q", name)
+	}
+	channel := channelOf(file)
+	if family, ok := hwmonFamilies[strings.TrimRight(channel, "0123456789")]; ok {
+		if chipName == "" {
+			return "skipped: chip has no name file"
+		}
+		return family.classify(chip, file, discovered)
+	}
+	if !strings.HasPrefix(file, "temp") {
+		if strings.HasSuffix(file, "_input") {
+			return "skipped: channel type not monitored"
+		}
+		return "skipped: not read by the monitor"
+	}
+	if chipName == "" {
+		return "skipped: chip has no name file"
+	}
+	valuePath := filepath.Join(chip, channel+"_input")
+	name, ok := discovered[valuePath]
+	suffix := strings.TrimPrefix(file, channel)
+	if isAlarmSuffix(suffix) {
+		if !ok {
+			return "skipped: channel not discovered"
+		}
+		return fmt.Sprintf("alarm flag of %q", name)
+	}
+	switch suffix {
+	case "_input":
+		if ok {
+			return fmt.Sprintf("temperature sensor %q", name)
+		}
+		return unreadableValue(valuePath)
+	case "_label":
+		return fmt.Sprintf("label of %q", name)
+	case "_max":
+		return fmt.Sprintf("High threshold of %q", name)
+	case "_crit":
+		return fmt.Sprintf("Critical threshold of %q", name)
+	}
+	if !strings.HasSuffix(file, "_input") {
+		return "skipped: no _input suffix"
+	}
+	return "skipped: not read by the monitor"
+}
+
+// classify explains how the family's collector treats an attribute of an
+// hwmon chip
+func (f hwmonFamily) classify(chip, file string, discovered map[string]string) string {
+	channel := channelOf(file)
+	suffix := strings.TrimPrefix(file, channel)
+	if name, ok := discovered[filepath.Join(chip, file)]; ok {
+		return fmt.Sprintf("%s %q", f.sensor, name)
+	}
+	if slices.Contains(f.values, suffix) {
+		if other, ok := f.channelName(chip, channel, discovered); ok {
+			return fmt.Sprintf("skipped: the value of %q is read from another file", other)
+		}
+		return unreadableAttribute(filepath.Join(chip, file))
+	}
+	use, ok := f.attributes[suffix]
+	if !ok && f.alarms && isAlarmSuffix(suffix) {
+		use, ok = "alarm flag", true
+	}
+	if !ok {
+		return "skipped: not read by the monitor"
+	}
+	name, found := f.channelName(chip, channel, discovered)
+	if !found {
+		return "skipped: channel not discovered"
+	}
+	return fmt.Sprintf("%s of %q", use, name)
+}
+
+// channelName returns the name of the sensor discovered for a channel
+func (f hwmonFamily) channelName(chip, channel string, discovered map[string]string) (string, bool) {
+	for _, suffix := range f.values {
+		if name, ok := discovered[filepath.Join(chip, channel+suffix)]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// unreadableAttribute explains why the value file of a channel other than
+// a temperature was not turned into a sensor
+func unreadableAttribute(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Sprintf("skipped: read failed (%v)", err)
+	}
+	if _, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err != nil {
+		return fmt.Sprintf("skipped: value %q is not an integer", strings.TrimSpace(string(data)))
+	}
+	return "skipped: filtered by discovery"
+}
+
+// unreadableValue explains why a value file was not turned into a sensor
+func unreadableValue(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Sprintf("skipped: read failed (%v)", err)
+	}
+	if _, err := parseMillidegrees(data); err != nil {
+		return fmt.Sprintf("skipped: value %q is not an integer", strings.TrimSpace(string(data)))
+	}
+	return "skipped: filtered by discovery"
+}
+
+func classifySupply(supply, battery, file string) string {
+	switch readTrimmed(filepath.Join(supply, "type")) {
+	case "Battery":
+		if supply != battery {
+			return "skipped: only the first battery is read"
+		}
+		if use, ok := batteryAttributes[file]; ok {
+			return use
+		}
+	case "Mains", "USB":
+		if use, ok := adapterAttributes[file]; ok {
+			return use
+		}
+	default:
+		return "skipped: supply is neither a battery nor an adapter"
+	}
+	return "skipped: not read by the monitor"
+}
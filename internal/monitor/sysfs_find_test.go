@@ -0,0 +1,146 @@
+package monitor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindAttributes(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":             "nct6798\n",
+		"class/hwmon/hwmon0/temp1_input":      "41000\n",
+		"class/hwmon/hwmon0/temp1_label":      "SYSTIN\n",
+		"class/hwmon/hwmon0/temp1_max":        "80000\n",
+		"class/hwmon/hwmon0/temp2_input":      "garbage\n",
+		"class/hwmon/hwmon0/temp1_alarm":      "0\n",
+		"class/hwmon/hwmon0/fan1_input":       "1200\n",
+		"class/hwmon/hwmon0/fan1_label":       "SYSFAN\n",
+		"class/hwmon/hwmon0/fan1_min":         "300\n",
+		"class/hwmon/hwmon0/fan1_alarm":       "0\n",
+		"class/hwmon/hwmon0/fan1_div":         "2\n",
+		"class/hwmon/hwmon0/fan2_input":       "garbage\n",
+		"class/hwmon/hwmon0/energy1_input":    "123\n",
+		"class/hwmon/hwmon0/in0_input":        "1104\n",
+		"class/hwmon/hwmon0/in0_label":        "Vcore\n",
+		"class/hwmon/hwmon0/in0_min":          "900\n",
+		"class/hwmon/hwmon0/in0_lcrit":        "800\n",
+		"class/hwmon/hwmon0/in0_min_alarm":    "0\n",
+		"class/hwmon/hwmon0/in0_beep":         "0\n",
+		"class/hwmon/hwmon0/curr1_input":      "4200\n",
+		"class/hwmon/hwmon0/curr1_max":        "10000\n",
+		"class/hwmon/hwmon0/curr1_crit_alarm": "0\n",
+		"class/hwmon/hwmon0/curr1_min":        "0\n",
+		"class/hwmon/hwmon0/power1_average":   "45000000\n",
+		"class/hwmon/hwmon0/power1_input":     "47000000\n",
+		"class/hwmon/hwmon0/power1_label":     "PPT\n",
+		"class/hwmon/hwmon0/power1_cap":       "65000000\n",
+		"class/hwmon/hwmon0/intrusion0_alarm": "0\n",
+		"class/hwmon/hwmon0/intrusion0_beep":  "1\n",
+		"class/hwmon/hwmon0/temp1_fault":      "0\n",
+		"class/hwmon/hwmon0/humidity1_input":  "45000\n",
+		"class/hwmon/hwmon0/humidity1_max":    "80000\n",
+		"class/hwmon/hwmon0/pwm1":             "128\n",
+		"class/hwmon/hwmon0/pwm1_enable":      "2\n",
+		"class/hwmon/hwmon0/pwm1_freq":        "25000\n",
+		"class/hwmon/hwmon0/alarms":           "0\n",
+		"class/power_supply/BAT0/type":        "Battery\n",
+		"class/power_supply/BAT0/capacity":    "80\n",
+		"class/power_supply/BAT0/model":       "x\n",
+	})
+
+	tests := []struct {
+		pattern string
+		want    map[string]string
+	}{
+		{"SYSTIN", map[string]string{
+			"class/hwmon/hwmon0/temp1_input": `temperature sensor "SYSTIN"`,
+			"class/hwmon/hwmon0/temp1_label": `label of "SYSTIN"`,
+			"class/hwmon/hwmon0/temp1_max":   `High threshold of "SYSTIN"`,
+			"class/hwmon/hwmon0/temp1_alarm": `alarm flag of "SYSTIN"`,
+			"class/hwmon/hwmon0/temp1_fault": `fault flag "SYSTIN fault"`,
+		}},
+		{"fan", map[string]string{
+			"class/hwmon/hwmon0/fan1_input": `fan sensor "SYSFAN"`,
+			"class/hwmon/hwmon0/fan1_label": `label of "SYSFAN"`,
+			"class/hwmon/hwmon0/fan1_min":   `Low threshold of "SYSFAN"`,
+			"class/hwmon/hwmon0/fan1_alarm": `alarm flag of "SYSFAN"`,
+			"class/hwmon/hwmon0/fan1_div":   "skipped: not read by the monitor",
+			"class/hwmon/hwmon0/fan2_input": `skipped: value "garbage" is not an integer`,
+		}},
+		{"energy*", map[string]string{
+			"class/hwmon/hwmon0/energy1_input": "skipped: channel type not monitored",
+		}},
+		{"in0*", map[string]string{
+			"class/hwmon/hwmon0/in0_input":     `voltage sensor "Vcore"`,
+			"class/hwmon/hwmon0/in0_label":     `label of "Vcore"`,
+			"class/hwmon/hwmon0/in0_min":       `Low threshold of "Vcore"`,
+			"class/hwmon/hwmon0/in0_lcrit":     `Low critical threshold of "Vcore"`,
+			"class/hwmon/hwmon0/in0_min_alarm": `alarm flag of "Vcore"`,
+			"class/hwmon/hwmon0/in0_beep":      "skipped: not read by the monitor",
+		}},
+		{"curr1*", map[string]string{
+			"class/hwmon/hwmon0/curr1_input":      `current sensor "nct6798_curr1"`,
+			"class/hwmon/hwmon0/curr1_max":        `High threshold of "nct6798_curr1"`,
+			"class/hwmon/hwmon0/curr1_crit_alarm": `alarm flag of "nct6798_curr1"`,
+			"class/hwmon/hwmon0/curr1_min":        "skipped: not read by the monitor",
+		}},
+		{"power1*", map[string]string{
+			"class/hwmon/hwmon0/power1_average": `power sensor "PPT"`,
+			"class/hwmon/hwmon0/power1_input":   `skipped: the value of "PPT" is read from another file`,
+			"class/hwmon/hwmon0/power1_label":   `label of "PPT"`,
+			"class/hwmon/hwmon0/power1_cap":     `High threshold of "PPT"`,
+		}},
+		{"intrusion0*", map[string]string{
+			"class/hwmon/hwmon0/intrusion0_alarm": `chassis alarm "nct6798_intrusion0"`,
+			"class/hwmon/hwmon0/intrusion0_beep":  "skipped: not read by the monitor",
+		}},
+		{"humidity1*", map[string]string{
+			"class/hwmon/hwmon0/humidity1_input": `humidity sensor "nct6798_humidity1"`,
+			"class/hwmon/hwmon0/humidity1_max":   `High threshold of "nct6798_humidity1"`,
+		}},
+		{"pwm1*", map[string]string{
+			"class/hwmon/hwmon0/pwm1":        `PWM output "pwm1"`,
+			"class/hwmon/hwmon0/pwm1_enable": `control mode of "pwm1"`,
+			"class/hwmon/hwmon0/pwm1_freq":   "skipped: not read by the monitor",
+		}},
+		{"alarms", map[string]string{
+			"class/hwmon/hwmon0/alarms": "alarm flags of channels without their own",
+		}},
+		{"temp2*", map[string]string{
+			"class/hwmon/hwmon0/temp2_input": `skipped: value "garbage" is not an integer`,
+		}},
+		{"capacity", map[string]string{
+			"class/power_supply/BAT0/capacity": "battery capacity",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			matches := findAttributes(root, tt.pattern)
+			if len(matches) != len(tt.want) {
+				t.Fatalf("expected %d matches, got %+v", len(tt.want), matches)
+			}
+			for _, m := range matches {
+				if want, ok := tt.want[m.Path]; !ok || m.Status != want {
+					t.Errorf("%s: expected %q, got %q", m.Path, want, m.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestFindAttributesBatteryHwmon(t *testing.T) {
+	skipped := 0
+	for _, m := range findAttributes(filepath.Join("testdata", "machines", "battery-hwmon"), "temp1_input") {
+		if strings.HasPrefix(m.Path, "class/hwmon/hwmon1/") {
+			if m.Status != "skipped: duplicates the power_supply battery" {
+				t.Errorf("%s: expected the battery chip to be skipped, got %q", m.Path, m.Status)
+			}
+			skipped++
+		}
+	}
+	if skipped != 1 {
+		t.Errorf("expected the battery chip's temp1_input to be listed once, got %d", skipped)
+	}
+}
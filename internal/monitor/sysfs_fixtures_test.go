@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// machineReadings is the golden representation of what the readers produce
+// for one captured machine. Paths are relative to the fixture root.
+type machineReadings struct {
+	Temperatures []TemperatureSensor
+	Battery      BatteryStatus
+	// Clusters is the collapsed temperature list, for machines where
+	// collapseZones merges any zones
+	Clusters []TemperatureSensor `json:",omitempty"`
+}
+
+// TestMachineFixtures runs the readers against sysfs trees captured from real
+// machines (see testdata/capture.go) and compares against expected.json.
+func TestMachineFixtures(t *testing.T) {
+	machines, err := filepath.Glob(filepath.Join("testdata", "machines", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(machines) == 0 {
+		t.Fatal("no machine fixtures found")
+	}
+	for _, root := range machines {
+		t.Run(filepath.Base(root), func(t *testing.T) {
+			got := machineReadings{
+				Temperatures: readTemperatures(root),
+				Battery:      readBatteryStatus(root),
+			}
+			for i := range got.Temperatures {
+				rel, err := filepath.Rel(root, got.Temperatures[i].Path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got.Temperatures[i].Path = filepath.ToSlash(rel)
+			}
+			if clusters := collapseZones(got.Temperatures); len(clusters) < len(got.Temperatures) {
+				got.Clusters = clusters
+			}
+			data, err := json.MarshalIndent(got, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			data = append(data, '\n')
+
+			golden := filepath.Join(root, "expected.json")
+			if *update {
+				if err := os.WriteFile(golden, data, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("reading golden file (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(data, want) {
+				t.Errorf("readings differ from %s (run with -update if intended)\ngot:\n%s\nwant:\n%s", golden, data, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// humidityGroupName is the group of the humidity sensors of hwmon chips
+const humidityGroupName = "Humidity"
+
+// HumiditySensor reports the relative humidity of an hwmon chip, such as
+// an SHT3x on an I2C bus (humidity*_input, in milli-percent). It warns
+// outside the chip's humidity*_min and humidity*_max bounds, each when
+// set, or while the chip's alarm flags for the channel are.
+type HumiditySensor struct {
+	path string // the humidity*_input file
+	name string
+	// Bounds in milli-percent, 0 when the chip doesn't set them
+	min, max     int64
+	millipercent int64
+	alarms       []alarmFile
+	alarm        State
+}
+
+// ReadHumidity returns a refreshed sensor for each humidity channel of the
+// hwmon chips
+func ReadHumidity() []Sensor {
+	return readHumidity(sysfsRoot)
+}
+
+func readHumidity(root string) []Sensor {
+	var sensors []Sensor
+	battery := batteryDevice(root)
+	hwmonPaths, _ := filepath.Glob(filepath.Join(root, hwmonClassPath, "hwmon*"))
+	for _, hwmonPath := range hwmonPaths {
+		nameData, err := os.ReadFile(filepath.Join(hwmonPath, "name"))
+		if err != nil || duplicatesBattery(hwmonPath, battery) {
+			continue
+		}
+		chip := strings.TrimSpace(string(nameData))
+		inputs, _ := filepath.Glob(filepath.Join(hwmonPath, "humidity*_input"))
+		for _, input := range inputs {
+			base := strings.TrimSuffix(filepath.Base(input), "_input")
+			// Named like the temperatures of the chip
+			channel := &HumiditySensor{path: input, name: fmt.Sprintf("%s_%s", chip, base)}
+			if label, err := os.ReadFile(filepath.Join(hwmonPath, base+"_label")); err == nil {
+				channel.name = strings.TrimSpace(string(label))
+			}
+			if pcm, err := readSysfsInt(filepath.Join(hwmonPath, base+"_min")); err == nil && pcm > 0 {
+				channel.min = pcm
+			}
+			if pcm, err := readSysfsInt(filepath.Join(hwmonPath, base+"_max")); err == nil && pcm > 0 {
+				channel.max = pcm
+			}
+			channel.alarms = channelAlarms(hwmonPath, base)
+			if err := channel.Refresh(); err != nil {
+				continue
+			}
+			sensors = append(sensors, channel)
+		}
+	}
+	return sensors
+}
+
+func (h *HumiditySensor) Name() string {
+	return h.name
+}
+
+func (h *HumiditySensor) attribute() string {
+	return h.path
+}
+
+func (h *HumiditySensor) Value() string {
+	return formatMeasurement(KindPercentage, float64(h.millipercent)/1000)
+}
+
+func (h *HumiditySensor) Kind() Kind {
+	return KindPercentage
+}
+
+func (h *HumiditySensor) Measurement() (float64, bool) {
+	return float64(h.millipercent) / 1000, true
+}
+
+func (h *HumiditySensor) Warning() bool {
+	return h.min > 0 && h.millipercent < h.min || h.max > 0 && h.millipercent > h.max || h.alarm == StateWarning
+}
+
+func (h *HumiditySensor) Critical() bool {
+	return h.alarm == StateCritical
+}
+
+func (h *HumiditySensor) Alarm() State {
+	return h.alarm
+}
+
+func (h *HumiditySensor) Refresh() error {
+	pcm, err := readSysfsInt(h.path)
+	if err != nil {
+		return err
+	}
+	h.millipercent = pcm
+	h.alarm = readAlarms(h.alarms)
+	return nil
+}
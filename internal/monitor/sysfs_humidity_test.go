@@ -0,0 +1,62 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHumidityFixture(t *testing.T) {
+	// The SHT31 of the ARM board, next to its temperature channel
+	sensors := readHumidity(filepath.Join("testdata", "machines", "arm-sbc"))
+	if len(sensors) != 1 {
+		t.Fatalf("expected the sht3x humidity, got %d sensors", len(sensors))
+	}
+	h := sensors[0]
+	if h.Name() != "sht3x_humidity1" || h.Value() != "46%" || SensorKind(h) != KindPercentage {
+		t.Errorf("expected sht3x_humidity1 at 46%%, got %s %s (%s)", h.Name(), h.Value(), SensorKind(h))
+	}
+	if h.Warning() || h.Critical() {
+		t.Error("expected 46% within the bounds")
+	}
+}
+
+func TestHumidityBounds(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":            "sht3x\n",
+		"class/hwmon/hwmon0/humidity1_input": "46250\n",
+		"class/hwmon/hwmon0/humidity1_label": "Enclosure\n",
+		"class/hwmon/hwmon0/humidity1_min":   "20000\n",
+		"class/hwmon/hwmon0/humidity1_max":   "80000\n",
+		"class/hwmon/hwmon1/name":            "cpu_thermal\n",
+		"class/hwmon/hwmon1/temp1_input":     "45000\n",
+		"class/hwmon/hwmon2/name":            "BAT0\n",
+		"class/hwmon/hwmon2/humidity1_input": "50000\n",
+		"class/power_supply/BAT0/type":       "Battery\n",
+	})
+	linkSysfs(t, root, "class/hwmon/hwmon2/device", "class/power_supply/BAT0")
+	// The battery's own chip is left to the battery section
+	sensors := readHumidity(root)
+	if len(sensors) != 1 || sensors[0].Name() != "Enclosure" {
+		t.Fatalf("expected the Enclosure humidity alone, got %d sensors", len(sensors))
+	}
+	h := sensors[0]
+	for _, tc := range []struct {
+		pcm     string
+		warning bool
+	}{
+		{"46250", false},
+		{"85000", true},
+		{"15000", true},
+	} {
+		path := filepath.Join(root, "class/hwmon/hwmon0/humidity1_input")
+		if err := os.WriteFile(path, []byte(tc.pcm+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		h.Refresh()
+		if h.Warning() != tc.warning || h.Critical() {
+			t.Errorf("Enclosure at %s pcm: expected warning %v", tc.pcm, tc.warning)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	netClassPath = "class/net"
+
+	// networkGroupName is the group of the interface rates
+	networkGroupName = "Network"
+
+	// networkTotalName is the pseudo-interface summing the selected ones
+	networkTotalName = "total"
+)
+
+// NetworkConfig selects the interfaces whose throughput is shown
+type NetworkConfig struct {
+	// Interfaces are globs of interface names, e.g. ["wl*", "en*"]. Empty
+	// selects every interface except loopback.
+	Interfaces []string `json:"interfaces,omitempty"`
+	// Total adds a "total" pseudo-interface summing the selected ones
+	Total bool `json:"total,omitempty"`
+	// CompactTotalOnly shows only the total in the compact view
+	CompactTotalOnly bool `json:"compact_total_only,omitempty"`
+}
+
+// netCounters are the cumulative byte counters of an interface
+type netCounters struct {
+	rx, tx float64
+}
+
+// networkCollector reads the selected interfaces' byte counters on every
+// refresh and turns them into rates. Interfaces are looked up on each
+// refresh, so ones appearing later (USB tethering, VPN tunnels) are picked
+// up once they match.
+type networkCollector struct {
+	root     string
+	settings NetworkConfig
+	prev     map[string]netCounters
+	prevTime time.Time
+}
+
+// reset forgets the previous counters, so the next rates are zero as on the
+// first refresh
+func (c *networkCollector) reset() {
+	c.prev = nil
+}
+
+// networkSettings returns the configured network settings, or the defaults
+func (m Monitor) networkSettings() NetworkConfig {
+	if m.config.Network != nil {
+		return *m.config.Network
+	}
+	return NetworkConfig{}
+}
+
+func newNetworkCollector(root string, settings NetworkConfig) *networkCollector {
+	return &networkCollector{root: root, settings: settings}
+}
+
+// selected reports whether an interface matches the configured globs
+func (c *networkCollector) selected(iface string) bool {
+	if len(c.settings.Interfaces) == 0 {
+		return iface != "lo"
+	}
+	for _, pattern := range c.settings.Interfaces {
+		if ok, _ := filepath.Match(pattern, iface); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// groups reads the counters and returns the Network group, with an rx and a
+// tx sensor per interface and the total last. Rates are zero on the first
+// refresh and after a counter reset.
+func (c *networkCollector) groups(now time.Time) []SensorGroup {
+	entries, err := os.ReadDir(filepath.Join(c.root, netClassPath))
+	if err != nil {
+		return nil
+	}
+	elapsed := now.Sub(c.prevTime).Seconds()
+	counters := make(map[string]netCounters)
+	var sensors []Sensor
+	var total netCounters
+	var totalRate [2]float64
+	for _, entry := range entries {
+		iface := entry.Name()
+		if !c.selected(iface) {
+			continue
+		}
+		stats := filepath.Join(c.root, netClassPath, iface, "statistics")
+		rx, errRx := readSysfsInt(filepath.Join(stats, "rx_bytes"))
+		tx, errTx := readSysfsInt(filepath.Join(stats, "tx_bytes"))
+		if errRx != nil || errTx != nil {
+			continue
+		}
+		cur := netCounters{rx: float64(rx), tx: float64(tx)}
+		counters[iface] = cur
+		var rxRate, txRate float64
+		if prev, ok := c.prev[iface]; ok && elapsed > 0 && cur.rx >= prev.rx && cur.tx >= prev.tx {
+			rxRate = (cur.rx - prev.rx) / elapsed
+			txRate = (cur.tx - prev.tx) / elapsed
+		}
+		sensors = append(sensors,
+			&netCounterSensor{netRateSensor{name: iface + " rx", rate: rxRate}, cur.rx},
+			&netCounterSensor{netRateSensor{name: iface + " tx", rate: txRate}, cur.tx})
+		total.rx += cur.rx
+		total.tx += cur.tx
+		totalRate[0] += rxRate
+		totalRate[1] += txRate
+	}
+	c.prev, c.prevTime = counters, now
+	if len(sensors) == 0 {
+		return nil
+	}
+	if c.settings.Total {
+		sensors = append(sensors,
+			&netRateSensor{name: networkTotalName + " rx", rate: totalRate[0]},
+			&netRateSensor{name: networkTotalName + " tx", rate: totalRate[1]})
+	}
+	return []SensorGroup{{Name: networkGroupName, Sensors: sensors}}
+}
+
+// netRateSensor is the receive or transmit rate of an interface, or of
+// the total of the selected ones
+type netRateSensor struct {
+	name string
+	rate float64 // bytes per second
+}
+
+// netCounterSensor is the rate of an interface with its byte counter. The
+// total has none: its sum drops when an interface goes away, which an
+// exported counter must never do.
+type netCounterSensor struct {
+	netRateSensor
+	total float64 // bytes since the interface came up
+}
+
+func (s *netRateSensor) Name() string {
+	return s.name
+}
+
+func (s *netRateSensor) Value() string {
+	return formatRate(s.rate, IECBytes, false)
+}
+
+func (s *netRateSensor) Warning() bool {
+	return false
+}
+
+func (s *netRateSensor) Critical() bool {
+	return false
+}
+
+// Refresh does nothing; the collector replaces the sensors on each refresh
+func (s *netRateSensor) Refresh() error {
+	return nil
+}
+
+func (s *netRateSensor) Bytes() (float64, bool) {
+	return s.rate, true
+}
+
+func (s *netCounterSensor) Counter() (float64, string) {
+	return s.total, "bytes"
+}
+
+func (s *netRateSensor) Kind() Kind {
+	return KindRate
+}
+
+func (s *netRateSensor) Measurement() (float64, bool) {
+	return s.rate, true
+}
+
+// networkSummary condenses the Network group's readings into one line,
+// e.g. "wlan0 ↓1.2 MiB/s ↑56.0 KiB/s", for the compact view. With totalOnly
+// only the total is shown, when there is one.
+func networkSummary(readings []SensorReading, totalOnly bool) string {
+	hasTotal := false
+	for _, r := range readings {
+		if strings.HasPrefix(r.Name, networkTotalName+" ") {
+			hasTotal = true
+		}
+	}
+	var parts []string
+	for i := 0; i+1 < len(readings); i += 2 {
+		iface := strings.TrimSuffix(readings[i].Name, " rx")
+		if totalOnly && hasTotal && iface != networkTotalName {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s ↓%s ↑%s", iface, readings[i].Value, readings[i+1].Value))
+	}
+	return strings.Join(parts, "  ")
+}
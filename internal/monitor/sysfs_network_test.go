@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeNetCounters(t *testing.T, root, iface, rx, tx string) {
+	t.Helper()
+	writeSysfs(t, root, map[string]string{
+		"class/net/" + iface + "/statistics/rx_bytes": rx,
+		"class/net/" + iface + "/statistics/tx_bytes": tx,
+	})
+}
+
+func sensorNames(groups []SensorGroup) string {
+	var names []string
+	for _, g := range groups {
+		for _, s := range g.Sensors {
+			names = append(names, s.Name())
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func TestNetworkSelection(t *testing.T) {
+	root := t.TempDir()
+	for _, iface := range []string{"eth0", "lo", "tun0", "wlan0"} {
+		writeNetCounters(t, root, iface, "0\n", "0\n")
+	}
+
+	all := newNetworkCollector(root, NetworkConfig{})
+	if got := sensorNames(all.groups(time.Now())); got != "eth0 rx,eth0 tx,tun0 rx,tun0 tx,wlan0 rx,wlan0 tx" {
+		t.Errorf("expected every interface but lo, got %q", got)
+	}
+
+	picked := newNetworkCollector(root, NetworkConfig{Interfaces: []string{"wl*", "en*"}, Total: true})
+	if got := sensorNames(picked.groups(time.Now())); got != "wlan0 rx,wlan0 tx,total rx,total tx" {
+		t.Errorf("expected wlan0 and the total, got %q", got)
+	}
+
+	none := newNetworkCollector(root, NetworkConfig{Interfaces: []string{"ppp*"}})
+	if groups := none.groups(time.Now()); groups != nil {
+		t.Errorf("expected no group without a matching interface, got %q", sensorNames(groups))
+	}
+}
+
+func TestNetworkRates(t *testing.T) {
+	root := t.TempDir()
+	writeNetCounters(t, root, "eth0", "1000\n", "500\n")
+	writeNetCounters(t, root, "wlan0", "2000\n", "0\n")
+	writeNetCounters(t, root, "tun0", "9000\n", "9000\n")
+
+	c := newNetworkCollector(root, NetworkConfig{Interfaces: []string{"eth*", "wl*"}, Total: true})
+	now := time.Now()
+	for _, s := range c.groups(now)[0].Sensors {
+		if rate, _ := s.(ByteValued).Bytes(); rate != 0 {
+			t.Errorf("expected no rate on the first refresh, got %s = %v", s.Name(), rate)
+		}
+	}
+
+	writeNetCounters(t, root, "eth0", "3000\n", "1500\n")
+	writeNetCounters(t, root, "wlan0", "6000\n", "0\n")
+	writeNetCounters(t, root, "tun0", "99000\n", "99000\n")
+	// A later interface is picked up once it matches
+	writeNetCounters(t, root, "wlan1", "100\n", "100\n")
+	sensors := c.groups(now.Add(2 * time.Second))[0].Sensors
+
+	rates := make(map[string]float64)
+	for _, s := range sensors {
+		rates[s.Name()], _ = s.(ByteValued).Bytes()
+	}
+	want := map[string]float64{
+		"eth0 rx": 1000, "eth0 tx": 500,
+		"wlan0 rx": 2000, "wlan0 tx": 0,
+		"wlan1 rx": 0, "wlan1 tx": 0,
+		"total rx": 3000, "total tx": 500,
+	}
+	if len(rates) != len(want) {
+		t.Fatalf("expected %d sensors, got %q", len(want), sensorNames([]SensorGroup{{Sensors: sensors}}))
+	}
+	for name, rate := range want {
+		if rates[name] != rate {
+			t.Errorf("expected %s at %v B/s, got %v", name, rate, rates[name])
+		}
+	}
+	if total, unit := sensors[0].(CounterSensor).Counter(); sensors[0].Name() != "eth0 rx" || total != 3000 || unit != "bytes" {
+		t.Errorf("expected the eth0 rx byte counter, got %s = %v %s", sensors[0].Name(), total, unit)
+	}
+	// The total drops when an interface goes away, so it is no counter
+	if _, ok := sensors[len(sensors)-2].(CounterSensor); ok {
+		t.Errorf("expected no counter for %s", sensors[len(sensors)-2].Name())
+	}
+
+	// A counter reset doesn't produce a negative rate
+	writeNetCounters(t, root, "eth0", "10\n", "10\n")
+	for _, s := range c.groups(now.Add(4 * time.Second))[0].Sensors {
+		if rate, _ := s.(ByteValued).Bytes(); rate < 0 {
+			t.Errorf("expected no negative rate after a reset, got %s = %v", s.Name(), rate)
+		}
+	}
+}
+
+func TestNetworkSummary(t *testing.T) {
+	rs := []SensorReading{
+		{Name: "eth0 rx", Value: "1.0 MiB/s"},
+		{Name: "eth0 tx", Value: "2.0 KiB/s"},
+		{Name: "total rx", Value: "1.0 MiB/s"},
+		{Name: "total tx", Value: "2.0 KiB/s"},
+	}
+	if got := networkSummary(rs, false); got != "eth0 ↓1.0 MiB/s ↑2.0 KiB/s  total ↓1.0 MiB/s ↑2.0 KiB/s" {
+		t.Errorf("unexpected summary %q", got)
+	}
+	if got := networkSummary(rs, true); got != "total ↓1.0 MiB/s ↑2.0 KiB/s" {
+		t.Errorf("expected only the total, got %q", got)
+	}
+	if got := networkSummary(rs[:2], true); got != "eth0 ↓1.0 MiB/s ↑2.0 KiB/s" {
+		t.Errorf("expected the interfaces without a total, got %q", got)
+	}
+}
@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const platformProfilePath = "firmware/acpi/platform_profile"
+
+// PlatformProfileSensor reports the ACPI platform profile (low-power,
+// balanced, performance, ...) and can cycle through the choices the firmware
+// advertises in platform_profile_choices.
+type PlatformProfileSensor struct {
+	path    string
+	profile string
+	choices []string
+}
+
+// ReadPlatformProfile returns the platform profile sensor, or nil when the
+// firmware doesn't expose one.
+func ReadPlatformProfile() Sensor {
+	return readPlatformProfile(sysfsRoot)
+}
+
+func readPlatformProfile(root string) Sensor {
+	p := &PlatformProfileSensor{path: filepath.Join(root, platformProfilePath)}
+	if err := p.Refresh(); err != nil {
+		return nil
+	}
+	return p
+}
+
+func (p *PlatformProfileSensor) Name() string {
+	return "Platform profile"
+}
+
+func (p *PlatformProfileSensor) Value() string {
+	return p.profile
+}
+
+func (p *PlatformProfileSensor) Kind() Kind {
+	return KindInfo
+}
+
+func (p *PlatformProfileSensor) Warning() bool {
+	return false
+}
+
+func (p *PlatformProfileSensor) Critical() bool {
+	return false
+}
+
+func (p *PlatformProfileSensor) Refresh() error {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+	p.profile = strings.TrimSpace(string(data))
+	if data, err := os.ReadFile(p.path + "_choices"); err == nil {
+		p.choices = strings.Fields(string(data))
+	}
+	return nil
+}
+
+// Adjust selects the next (step > 0) or previous profile from the advertised
+// choices, then re-reads the file to confirm the firmware accepted it.
+func (p *PlatformProfileSensor) Adjust(step int) error {
+	if len(p.choices) == 0 {
+		return fmt.Errorf("%s_choices lists no profiles", p.path)
+	}
+	current := 0
+	for i, choice := range p.choices {
+		if choice == p.profile {
+			current = i
+		}
+	}
+	next := (current + step%len(p.choices) + len(p.choices)) % len(p.choices)
+	target := p.choices[next]
+
+	if err := os.WriteFile(p.path, []byte(target), 0); err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("no permission to write %s (needs root)", p.path)
+		}
+		return fmt.Errorf("writing %s: %v", p.path, err)
+	}
+	if err := p.Refresh(); err != nil {
+		return err
+	}
+	if p.profile != target {
+		return fmt.Errorf("firmware kept profile %q instead of %q", p.profile, target)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package monitor
+
+import "testing"
+
+func TestPlatformProfileCycles(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"firmware/acpi/platform_profile":         "balanced\n",
+		"firmware/acpi/platform_profile_choices": "low-power balanced performance\n",
+	})
+	sensor := readPlatformProfile(root)
+	if sensor == nil {
+		t.Fatal("expected platform profile sensor")
+	}
+	p := sensor.(*PlatformProfileSensor)
+	if p.Value() != "balanced" {
+		t.Errorf("expected balanced, got %q", p.Value())
+	}
+
+	for _, want := range []string{"performance", "low-power", "balanced"} {
+		if err := p.Adjust(1); err != nil {
+			t.Fatal(err)
+		}
+		if p.Value() != want {
+			t.Errorf("expected %q, got %q", want, p.Value())
+		}
+	}
+	if err := p.Adjust(-1); err != nil {
+		t.Fatal(err)
+	}
+	if p.Value() != "low-power" {
+		t.Errorf("expected low-power after stepping back, got %q", p.Value())
+	}
+}
+
+func TestPlatformProfileMissing(t *testing.T) {
+	if readPlatformProfile(t.TempDir()) != nil {
+		t.Error("expected no sensor without platform_profile")
+	}
+}
@@ -0,0 +1,148 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PowerSensor reports a power channel of an hwmon chip, such as a GPU's
+// board power or a CPU's package power (power*_average, or power*_input
+// when the chip has no average, in microwatts). It warns past the chip's
+// power*_cap and is critical past power*_cap_max, each when set, or while
+// the chip's alarm flags for the channel are.
+type PowerSensor struct {
+	path string // the power*_average or power*_input file
+	name string
+	// Limits in microwatts, 0 when the chip doesn't set them
+	limit, maxLimit int64
+	microwatts      int64
+	alarms          []alarmFile
+	alarm           State
+}
+
+// ReadPowerGroups returns a group for each hwmon chip with power channels,
+// named after the chip, e.g. "amdgpu power"
+func ReadPowerGroups() []SensorGroup {
+	return readPowerGroups(sysfsRoot)
+}
+
+func readPowerGroups(root string) []SensorGroup {
+	return readChipGroups(root, "power", readPowerChannels)
+}
+
+// readChipGroups returns a group named after the chip and suffix for each
+// hwmon chip read returns sensors for, except the battery's
+func readChipGroups(root, suffix string, read func(hwmonPath string) []Sensor) []SensorGroup {
+	var groups []SensorGroup
+	var dirs []string
+	battery := batteryDevice(root)
+	hwmonPaths, _ := filepath.Glob(filepath.Join(root, hwmonClassPath, "hwmon*"))
+	for _, hwmonPath := range hwmonPaths {
+		nameData, err := os.ReadFile(filepath.Join(hwmonPath, "name"))
+		if err != nil || duplicatesBattery(hwmonPath, battery) {
+			continue
+		}
+		if sensors := read(hwmonPath); len(sensors) > 0 {
+			groups = append(groups, SensorGroup{Name: strings.TrimSpace(string(nameData)), Sensors: sensors})
+			dirs = append(dirs, filepath.Base(hwmonPath))
+		}
+	}
+
+	// Chips sharing a driver, such as two GPUs, are told apart by their
+	// hwmon directory as in the chip summaries
+	chips := make(map[string]int)
+	for _, group := range groups {
+		chips[group.Name]++
+	}
+	for i := range groups {
+		if chips[groups[i].Name] > 1 {
+			groups[i].Name += " (" + dirs[i] + ")"
+		}
+		groups[i].Name += " " + suffix
+	}
+	return groups
+}
+
+// readPowerChannels returns a refreshed sensor for each power channel of
+// an hwmon chip, named by power*_label or else the channel
+func readPowerChannels(hwmonPath string) []Sensor {
+	var bases []string
+	for _, pattern := range []string{"power*_average", "power*_input"} {
+		files, _ := filepath.Glob(filepath.Join(hwmonPath, pattern))
+		for _, file := range files {
+			base, _, _ := strings.Cut(filepath.Base(file), "_")
+			if !slices.Contains(bases, base) {
+				bases = append(bases, base)
+			}
+		}
+	}
+	slices.Sort(bases)
+
+	var sensors []Sensor
+	for _, base := range bases {
+		path := filepath.Join(hwmonPath, base+"_average")
+		if _, err := os.Stat(path); err != nil {
+			path = filepath.Join(hwmonPath, base+"_input")
+		}
+		channel := &PowerSensor{path: path, name: base}
+		if label, err := os.ReadFile(filepath.Join(hwmonPath, base+"_label")); err == nil {
+			channel.name = strings.TrimSpace(string(label))
+		}
+		if uw, err := readSysfsInt(filepath.Join(hwmonPath, base+"_cap")); err == nil && uw > 0 {
+			channel.limit = uw
+		}
+		if uw, err := readSysfsInt(filepath.Join(hwmonPath, base+"_cap_max")); err == nil && uw > 0 {
+			channel.maxLimit = uw
+		}
+		channel.alarms = channelAlarms(hwmonPath, base)
+		if err := channel.Refresh(); err != nil {
+			continue
+		}
+		sensors = append(sensors, channel)
+	}
+	return sensors
+}
+
+func (p *PowerSensor) Name() string {
+	return p.name
+}
+
+func (p *PowerSensor) Value() string {
+	return formatMeasurement(KindPower, float64(p.microwatts)/1e6)
+}
+
+func (p *PowerSensor) Kind() Kind {
+	return KindPower
+}
+
+func (p *PowerSensor) Measurement() (float64, bool) {
+	return float64(p.microwatts) / 1e6, true
+}
+
+func (p *PowerSensor) attribute() string {
+	return p.path
+}
+
+func (p *PowerSensor) Warning() bool {
+	return p.limit > 0 && p.microwatts > p.limit || p.alarm == StateWarning
+}
+
+func (p *PowerSensor) Critical() bool {
+	return p.maxLimit > 0 && p.microwatts > p.maxLimit || p.alarm == StateCritical
+}
+
+func (p *PowerSensor) Alarm() State {
+	return p.alarm
+}
+
+func (p *PowerSensor) Refresh() error {
+	uw, err := readSysfsInt(p.path)
+	if err != nil {
+		return err
+	}
+	p.microwatts = uw
+	p.alarm = readAlarms(p.alarms)
+	return nil
+}
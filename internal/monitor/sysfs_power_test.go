@@ -0,0 +1,68 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPowerGroups(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":           "k10temp\n",
+		"class/hwmon/hwmon0/temp1_input":    "45000\n",
+		"class/hwmon/hwmon1/name":           "amdgpu\n",
+		"class/hwmon/hwmon1/power1_average": "35000000\n",
+		"class/hwmon/hwmon1/power1_input":   "52000000\n",
+		"class/hwmon/hwmon1/power1_label":   "PPT\n",
+		"class/hwmon/hwmon1/power1_cap":     "120000000\n",
+		"class/hwmon/hwmon1/power1_cap_max": "150000000\n",
+		"class/hwmon/hwmon2/name":           "amdgpu\n",
+		"class/hwmon/hwmon2/power1_input":   "9500000\n",
+		"class/hwmon/hwmon3/name":           "BAT0\n",
+		"class/hwmon/hwmon3/power1_input":   "12000000\n",
+		"class/power_supply/BAT0/type":      "Battery\n",
+		"class/power_supply/BAT0/status":    "Discharging\n",
+	})
+	linkSysfs(t, root, "class/hwmon/hwmon3/device", "class/power_supply/BAT0")
+
+	// The average is preferred; chips without power channels and the
+	// battery's get no group
+	groups := readPowerGroups(root)
+	if len(groups) != 2 || groups[0].Name != "amdgpu (hwmon1) power" || groups[1].Name != "amdgpu (hwmon2) power" {
+		t.Fatalf("expected a group per GPU, got %+v", groups)
+	}
+	ppt, input := groups[0].Sensors[0], groups[1].Sensors[0]
+	if ppt.Name() != "PPT" || ppt.Value() != "35.00W" || input.Name() != "power1" || input.Value() != "9.50W" {
+		t.Errorf("expected PPT at 35.00W and power1 at 9.50W, got %s %s, %s %s", ppt.Name(), ppt.Value(), input.Name(), input.Value())
+	}
+	if SensorKind(ppt) != KindPower {
+		t.Errorf("expected a power, got %s", SensorKind(ppt))
+	}
+
+	for _, tc := range []struct {
+		uw                string
+		warning, critical bool
+	}{
+		{"35000000", false, false},
+		{"125000000", true, false},
+		{"155000000", true, true},
+	} {
+		path := filepath.Join(root, "class/hwmon/hwmon1/power1_average")
+		if err := os.WriteFile(path, []byte(tc.uw+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		ppt.Refresh()
+		if ppt.Warning() != tc.warning || ppt.Critical() != tc.critical {
+			t.Errorf("PPT at %s µW: expected warning %v critical %v", tc.uw, tc.warning, tc.critical)
+		}
+	}
+
+	// A single chip goes by its name alone
+	if err := os.RemoveAll(filepath.Join(root, "class/hwmon/hwmon2")); err != nil {
+		t.Fatal(err)
+	}
+	if groups := readPowerGroups(root); len(groups) != 1 || groups[0].Name != "amdgpu power" {
+		t.Errorf("expected the amdgpu power group, got %+v", groups)
+	}
+}
@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// pwmFullTicks is how many consecutive refreshes a fan may be driven at
+// full duty before it warns: a fan curve pinned at its maximum usually
+// means thermal pressure
+const pwmFullTicks = 5
+
+// pwmModes names the control modes of pwm*_enable; 2 and above are the
+// chip's automatic modes
+var pwmModes = map[int64]string{
+	0: "full speed",
+	1: "manual",
+}
+
+// PWMSensor reports the duty cycle a hwmon chip drives a fan header with
+// (pwm*, 0-255) as a percentage, and the control mode of pwm*_enable. It
+// warns when the header runs uncontrolled at full speed (mode 0), or at
+// full duty for pwmFullTicks refreshes in a row.
+type PWMSensor struct {
+	path string // the pwm* file
+	name string
+	duty int64
+	// mode is pwm*_enable, -1 when the chip has none
+	mode int64
+	// full counts the consecutive refreshes at full duty
+	full int
+}
+
+// ReadPWMGroups returns a group for each hwmon chip with fan headers under
+// PWM control, named after the chip, e.g. "nct6775 PWM"
+func ReadPWMGroups() []SensorGroup {
+	return readPWMGroups(sysfsRoot)
+}
+
+func readPWMGroups(root string) []SensorGroup {
+	return readChipGroups(root, "PWM", readPWMChannels)
+}
+
+// readPWMChannels returns a refreshed sensor for each PWM output of an
+// hwmon chip
+func readPWMChannels(hwmonPath string) []Sensor {
+	var sensors []Sensor
+	files, _ := filepath.Glob(filepath.Join(hwmonPath, "pwm*"))
+	for _, path := range files {
+		// pwm1_enable, pwm1_freq and the like are attributes of pwm1
+		base := filepath.Base(path)
+		if strings.Contains(base, "_") {
+			continue
+		}
+		channel := &PWMSensor{path: path, name: base}
+		if err := channel.Refresh(); err != nil {
+			continue
+		}
+		sensors = append(sensors, channel)
+	}
+	return sensors
+}
+
+func (p *PWMSensor) Name() string {
+	return p.name
+}
+
+func (p *PWMSensor) attribute() string {
+	return p.path
+}
+
+// Value is the duty and the mode, e.g. "45% auto"
+func (p *PWMSensor) Value() string {
+	value := formatMeasurement(KindPercentage, p.percent())
+	if mode := p.modeName(); mode != "" {
+		value += " " + mode
+	}
+	return value
+}
+
+func (p *PWMSensor) Kind() Kind {
+	return KindPercentage
+}
+
+func (p *PWMSensor) Measurement() (float64, bool) {
+	return p.percent(), true
+}
+
+func (p *PWMSensor) Warning() bool {
+	return p.mode == 0 || p.full >= pwmFullTicks
+}
+
+func (p *PWMSensor) Critical() bool {
+	return false
+}
+
+func (p *PWMSensor) Refresh() error {
+	duty, err := readSysfsInt(p.path)
+	if err != nil {
+		return err
+	}
+	p.duty = duty
+	p.mode = -1
+	if mode, err := readSysfsInt(p.path + "_enable"); err == nil {
+		p.mode = mode
+	}
+	if duty >= 255 {
+		p.full++
+	} else {
+		p.full = 0
+	}
+	return nil
+}
+
+// percent is the duty as a percentage of 255
+func (p *PWMSensor) percent() float64 {
+	return float64(p.duty) * 100 / 255
+}
+
+// modeName names the control mode, empty when the chip doesn't tell
+func (p *PWMSensor) modeName() string {
+	switch {
+	case p.mode < 0:
+		return ""
+	case p.mode >= 2:
+		return "auto"
+	}
+	return pwmModes[p.mode]
+}
@@ -0,0 +1,63 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPWMGroups(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":        "nct6775\n",
+		"class/hwmon/hwmon0/pwm1":        "115\n",
+		"class/hwmon/hwmon0/pwm1_enable": "2\n",
+		"class/hwmon/hwmon0/pwm1_freq":   "25000\n",
+		"class/hwmon/hwmon0/pwm2":        "200\n",
+		"class/hwmon/hwmon0/pwm2_enable": "1\n",
+		"class/hwmon/hwmon0/pwm3":        "255\n",
+		"class/hwmon/hwmon0/pwm3_enable": "0\n",
+		"class/hwmon/hwmon1/name":        "thinkpad\n",
+		"class/hwmon/hwmon1/pwm1":        "255\n",
+		"class/hwmon/hwmon2/name":        "coretemp\n",
+		"class/hwmon/hwmon2/temp1_input": "50000\n",
+	})
+
+	groups := readPWMGroups(root)
+	if len(groups) != 2 || groups[0].Name != "nct6775 PWM" || groups[1].Name != "thinkpad PWM" {
+		t.Fatalf("expected a group per chip with PWM outputs, got %+v", groups)
+	}
+	var values []string
+	for _, s := range groups[0].Sensors {
+		values = append(values, s.Name()+" "+s.Value())
+	}
+	if len(values) != 3 || values[0] != "pwm1 45% auto" || values[1] != "pwm2 78% manual" || values[2] != "pwm3 100% full speed" {
+		t.Fatalf("unexpected readings %q", values)
+	}
+	auto, full := groups[0].Sensors[0], groups[0].Sensors[2]
+	if auto.Warning() || !full.Warning() {
+		t.Errorf("expected the uncontrolled header highlighted alone")
+	}
+
+	// Full duty warns once sustained
+	thinkpad := groups[1].Sensors[0]
+	if thinkpad.Value() != "100%" {
+		t.Errorf("expected the duty alone without pwm1_enable, got %s", thinkpad.Value())
+	}
+	for i := 1; i < pwmFullTicks; i++ {
+		if thinkpad.Warning() {
+			t.Fatalf("expected no warning after %d refreshes at full duty", i)
+		}
+		thinkpad.Refresh()
+	}
+	if !thinkpad.Warning() {
+		t.Errorf("expected a warning after %d refreshes at full duty", pwmFullTicks)
+	}
+	if err := os.WriteFile(filepath.Join(root, "class/hwmon/hwmon1/pwm1"), []byte("128\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	thinkpad.Refresh()
+	if thinkpad.Warning() {
+		t.Error("expected the warning gone once the duty drops")
+	}
+}
@@ -0,0 +1,222 @@
+package monitor
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+const (
+	// DefaultMaxHeldFiles caps the number of sysfs value files a
+	// TemperatureReader keeps open. Sensors beyond the cap are re-opened on
+	// every refresh, exactly like ReadTemperatures does.
+	DefaultMaxHeldFiles = 64
+
+	// rediscoverEvery is the number of refreshes between full rediscoveries,
+	// so hot-plugged sensors still show up eventually.
+	rediscoverEvery = 30
+)
+
+// TemperatureReader splits temperature reading into a discovery pass, which
+// reads the static files (names, labels, thresholds), and a refresh pass,
+// which only re-reads the value files. Value files are held open and re-read
+// with ReadAt, so a refresh costs one pread per sensor instead of a stat,
+// open, read and close for every attribute.
+type TemperatureReader struct {
+	root    string
+	maxHeld int
+	sensors []TemperatureSensor
+	files   map[string]valueFile
+	ticks   int
+
+	// wake lists the suspended devices read anyway (see chipAsleep)
+	wake []string
+
+	// Permission failures of the last discovery, and of the last refresh
+	// including those (see degraded.go)
+	discoveryDenied []error
+	denied          []error
+
+	// open opens value files; tests replace it to inject failing reads
+	open func(path string) (valueFile, error)
+}
+
+// valueFile is a held value file
+type valueFile interface {
+	io.ReaderAt
+	io.Closer
+}
+
+func openValueFile(path string) (valueFile, error) {
+	return os.Open(path)
+}
+
+// NewTemperatureReader creates a reader holding at most maxHeld files open.
+// A non-positive maxHeld uses DefaultMaxHeldFiles.
+func NewTemperatureReader(maxHeld int) *TemperatureReader {
+	return newTemperatureReader(sysfsRoot, maxHeld)
+}
+
+func newTemperatureReader(root string, maxHeld int) *TemperatureReader {
+	if maxHeld <= 0 {
+		maxHeld = DefaultMaxHeldFiles
+	}
+	return &TemperatureReader{
+		root:    root,
+		maxHeld: maxHeld,
+		files:   make(map[string]valueFile),
+		open:    openValueFile,
+	}
+}
+
+// Discover re-reads the full sensor list and releases files of sensors that
+// disappeared.
+func (r *TemperatureReader) Discover() {
+	var err error
+	r.sensors, err = discoverTemperatures(r.root, r.wake)
+	r.discoveryDenied = permissionErrors(err)
+	present := make(map[string]bool, len(r.sensors))
+	for _, sensor := range r.sensors {
+		present[valueFilePath(sensor)] = true
+	}
+	for path, f := range r.files {
+		if !present[path] {
+			f.Close()
+			delete(r.files, path)
+		}
+	}
+}
+
+// Refresh updates the values of the discovered sensors and returns them.
+// As in ReadTemperatures, sensors whose value can't be read are omitted,
+// unless the error is transient: those are returned marked Stale. Sensors
+// of suspended devices are returned Asleep without a read, and a device
+// suspending or resuming makes the refresh rediscover first.
+func (r *TemperatureReader) Refresh() []TemperatureSensor {
+	if r.sensors == nil || r.ticks%rediscoverEvery == 0 || r.sleepChanged() {
+		r.Discover()
+	}
+	r.ticks++
+	r.denied = slices.Clip(r.discoveryDenied)
+
+	sensors := make([]TemperatureSensor, 0, len(r.sensors))
+	buf := make([]byte, 32)
+	for _, sensor := range r.sensors {
+		if sensor.Asleep {
+			sensors = append(sensors, sensor)
+			continue
+		}
+		data, err := r.readValue(valueFilePath(sensor), buf)
+		switch {
+		case isTransientReadError(err):
+			sensor.Value, sensor.Raw, sensor.Stale = 0, "", true
+		case err != nil:
+			r.denied = append(r.denied, permissionErrors(err)...)
+			continue
+		default:
+			value, err := parseMillidegrees(data)
+			if err != nil {
+				continue
+			}
+			sensor.Value, sensor.Raw, sensor.Stale = value, rawReading(data), false
+		}
+		sensor.Alarm = readAlarms(sensor.alarms)
+		sensors = append(sensors, sensor)
+	}
+	return sensors
+}
+
+// sleepChanged reports whether an hwmon chip was suspended or resumed since
+// the discovery, which skipped the thresholds of the chips asleep then.
+// Costs one read of runtime_status per chip.
+func (r *TemperatureReader) sleepChanged() bool {
+	checked := make(map[string]bool)
+	for _, sensor := range r.sensors {
+		if !strings.HasSuffix(sensor.Path, "_input") {
+			continue
+		}
+		dir := filepath.Dir(sensor.Path)
+		if checked[dir] {
+			continue
+		}
+		checked[dir] = true
+		if chipAsleep(dir, r.wake) != sensor.Asleep {
+			return true
+		}
+	}
+	return false
+}
+
+// readValue reads a value file through a held descriptor, re-opening it once
+// when the kernel reports the handle went stale (device re-registered).
+func (r *TemperatureReader) readValue(path string, buf []byte) ([]byte, error) {
+	f, ok := r.files[path]
+	if !ok {
+		if len(r.files) >= r.maxHeld {
+			return r.readFile(path, buf)
+		}
+		var err error
+		if f, err = r.open(path); err != nil {
+			return nil, err
+		}
+		r.files[path] = f
+	}
+
+	// A short read ends with io.EOF, which is the normal case for sysfs
+	n, err := f.ReadAt(buf, 0)
+	if n > 0 {
+		return buf[:n], nil
+	}
+	if !errors.Is(err, syscall.ESTALE) && !errors.Is(err, syscall.ENOENT) && !errors.Is(err, syscall.ENODEV) {
+		return nil, err
+	}
+
+	f.Close()
+	delete(r.files, path)
+	if f, err = r.open(path); err != nil {
+		return nil, err
+	}
+	r.files[path] = f
+	n, err = f.ReadAt(buf, 0)
+	if n > 0 {
+		return buf[:n], nil
+	}
+	return nil, err
+}
+
+// readFile reads a value file without holding it open
+func (r *TemperatureReader) readFile(path string, buf []byte) ([]byte, error) {
+	f, err := r.open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	n, err := f.ReadAt(buf, 0)
+	if n > 0 {
+		return buf[:n], nil
+	}
+	return nil, err
+}
+
+// isTransientReadError reports whether a failed sysfs read is likely to
+// succeed on the next refresh: SMBus-attached and EC-backed sensors report
+// ENXIO, EAGAIN, EBUSY or ETIMEDOUT for a tick while the bus is contended.
+// Other errors, such as ENOENT for a removed device, are permanent.
+func isTransientReadError(err error) bool {
+	return errors.Is(err, syscall.ENXIO) || errors.Is(err, syscall.EAGAIN) ||
+		errors.Is(err, syscall.EBUSY) || errors.Is(err, syscall.ETIMEDOUT)
+}
+
+// Close releases all held files.
+func (r *TemperatureReader) Close() error {
+	var errs []error
+	for path, f := range r.files {
+		errs = append(errs, f.Close())
+		delete(r.files, path)
+	}
+	return errors.Join(errs...)
+}
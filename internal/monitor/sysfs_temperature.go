@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,54 +10,168 @@ import (
 )
 
 const (
-	thermalBasePath = "/sys/class/thermal"
+	thermalClassPath = "class/thermal"
+	hwmonClassPath   = "class/hwmon"
 )
 
+// ReadTemperatures reads the thermal zones and hwmon temperatures, skipping
+// those that fail to read
 func ReadTemperatures() []TemperatureSensor {
-	var sensors []TemperatureSensor
+	return readTemperatures(sysfsRoot)
+}
 
-	// Check if thermal directory exists
-	if _, err := os.Stat(thermalBasePath); os.IsNotExist(err) {
-		return sensors
-	}
+// ReadTemperaturesE is ReadTemperatures reporting why sensors were skipped:
+// the error joins one error per unreadable sensor, or says sysfs couldn't
+// be read at all. The sensors that could be read are returned either way.
+func ReadTemperaturesE() ([]TemperatureSensor, error) {
+	return readTemperaturesE(sysfsRoot)
+}
 
-	// List thermal zones
-	thermalZones, err := filepath.Glob(filepath.Join(thermalBasePath, "thermal_zone*"))
-	if err != nil {
-		return sensors
+func readTemperatures(root string) []TemperatureSensor {
+	sensors, _ := readTemperaturesE(root)
+	return sensors
+}
+
+func readTemperaturesE(root string) ([]TemperatureSensor, error) {
+	return discoverTemperatures(root, nil)
+}
+
+// discoverTemperatures reads the temperatures under root. The channels of
+// runtime-suspended hwmon devices are listed asleep without being read,
+// unless the device matches one of the wake globs (see chipAsleep).
+func discoverTemperatures(root string, wake []string) ([]TemperatureSensor, error) {
+	if _, err := os.Stat(filepath.Join(root, "class")); err != nil {
+		return nil, err
 	}
+	var sensors []TemperatureSensor
+	var errs []error
 
-	for _, zonePath := range thermalZones {
-		sensor, err := readThermalZone(zonePath)
-		if err == nil {
+	// List thermal zones
+	thermalBasePath := filepath.Join(root, thermalClassPath)
+	if _, err := os.Stat(thermalBasePath); err == nil {
+		thermalZones, _ := filepath.Glob(filepath.Join(thermalBasePath, "thermal_zone*"))
+		for _, zonePath := range thermalZones {
+			sensor, err := readThermalZone(zonePath)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
 			sensors = append(sensors, sensor)
 		}
+		ignoreBogusTripPoints(sensors)
+		zoneKeys(sensors)
 	}
 
 	// Also try hwmon sensors (commonly used for CPU, motherboard temperatures)
-	hwmonPaths, _ := filepath.Glob("/sys/class/hwmon/hwmon*")
+	// Battery hwmon chips are skipped since readBatteryStatus reports them
+	hwmonPaths, _ := filepath.Glob(filepath.Join(root, hwmonClassPath, "hwmon*"))
+	battery := batteryDevice(root)
 	for _, hwmonPath := range hwmonPaths {
-		sensors = append(sensors, readHwmonSensors(hwmonPath)...)
+		if !duplicatesBattery(hwmonPath, battery) {
+			chip, err := readHwmonSensors(hwmonPath, wake)
+			sensors = append(sensors, chip...)
+			errs = append(errs, err)
+		}
 	}
 
-	return sensors
+	disambiguateNames(sensors)
+	return sensors, errors.Join(errs...)
+}
+
+// duplicatesBattery reports whether an hwmon chip belongs to the battery
+// device (as returned by batteryDevice): the power_supply core and some EC
+// drivers register one whose channels repeat the battery's own readings.
+func duplicatesBattery(hwmonPath, battery string) bool {
+	if battery == "" {
+		return false
+	}
+	target, err := filepath.EvalSymlinks(filepath.Join(hwmonPath, "device"))
+	if err != nil {
+		return false
+	}
+	return target == battery || strings.HasPrefix(target, battery+string(filepath.Separator))
+}
+
+// disambiguateNames appends a device-derived suffix to sensors sharing a name,
+// e.g. two identical NVMe drives both reporting "Composite". Sensors sharing
+// a name on one device all get the channel too, e.g. "zone (hwmon5/temp1)"
+// and "zone (hwmon5/temp2)". Thermal zones sharing a type are told apart by
+// their keys instead.
+func disambiguateNames(sensors []TemperatureSensor) {
+	counts := make(map[string]int)
+	for _, s := range sensors {
+		if s.Key == "" {
+			counts[s.Name]++
+		}
+	}
+	tags := make(map[int]string)
+	onDevice := make(map[string]int)
+	for i, s := range sensors {
+		if s.Key != "" || counts[s.Name] < 2 {
+			continue
+		}
+		tags[i] = fmt.Sprintf("%s (%s", s.Name, deviceTag(sensorDir(s.Path)))
+		onDevice[tags[i]]++
+	}
+	for i, tag := range tags {
+		if onDevice[tag] > 1 {
+			sensors[i].Name = fmt.Sprintf("%s/%s)", tag, channelBase(sensors[i].Path))
+		} else {
+			sensors[i].Name = tag + ")"
+		}
+	}
+}
+
+// sensorDir returns the sysfs directory owning a sensor: the thermal zone
+// itself, or the hwmon directory containing a temp*_input file.
+func sensorDir(path string) string {
+	if strings.HasSuffix(path, "_input") {
+		return filepath.Dir(path)
+	}
+	return path
+}
+
+func channelBase(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), "_input")
+}
+
+// deviceTag derives a short, reboot-stable identifier for the device behind
+// a hwmon or thermal directory. It prefers the block device name, then the
+// device node name (usually a PCI address), and falls back to the directory
+// name (hwmonN, thermal_zoneN) which may change across reboots.
+func deviceTag(dir string) string {
+	devicePath := filepath.Join(dir, "device")
+	if blocks, _ := filepath.Glob(filepath.Join(devicePath, "block", "*")); len(blocks) > 0 {
+		return filepath.Base(blocks[0])
+	}
+	if namespaces, _ := filepath.Glob(filepath.Join(devicePath, "nvme*n*")); len(namespaces) > 0 {
+		return filepath.Base(namespaces[0])
+	}
+	if target, err := filepath.EvalSymlinks(devicePath); err == nil {
+		return filepath.Base(target)
+	}
+	return filepath.Base(dir)
 }
 
 func readThermalZone(zonePath string) (TemperatureSensor, error) {
 	sensor := TemperatureSensor{}
 
 	// Read temperature (in millidegree Celsius)
+	// Transient failures keep the zone, marked stale, for the monitor to
+	// fill in the previous value
 	tempPath := filepath.Join(zonePath, "temp")
 	data, err := os.ReadFile(tempPath)
-	if err != nil {
-		return sensor, err
-	}
-	tempStr := strings.TrimSpace(string(data))
-	tempMilli, err := strconv.ParseInt(tempStr, 10, 64)
-	if err != nil {
+	switch {
+	case isTransientReadError(err):
+		sensor.Stale = true
+	case err != nil:
 		return sensor, err
+	default:
+		if sensor.Value, err = parseMillidegrees(data); err != nil {
+			return sensor, fmt.Errorf("%s: %w", tempPath, err)
+		}
+		sensor.Raw = rawReading(data)
 	}
-	sensor.Value = float64(tempMilli) / 1000.0
 	sensor.Path = zonePath
 
 	// Read sensor name
@@ -87,6 +202,8 @@ func readThermalZone(zonePath string) (TemperatureSensor, error) {
 		}
 	}
 
+	sensor.Cooling = readCoolingBindings(zonePath)
+
 	// If thresholds not set, use sensible defaults
 	if sensor.High == 0 {
 		sensor.High = 80.0
@@ -98,17 +215,64 @@ func readThermalZone(zonePath string) (TemperatureSensor, error) {
 	return sensor, nil
 }
 
-func readHwmonSensors(hwmonPath string) []TemperatureSensor {
+// bogusTripPoint is the lowest trip point suspected of being a placeholder
+// when most zones share it
+const bogusTripPoint = 115.0
+
+// ignoreBogusTripPoints resets the thresholds of thermal zones to the
+// defaults when more than half of them share an identical trip point at or
+// above bogusTripPoint. Android-derived kernels declare dozens of zones with
+// the same 125°C placeholder, which would never warn.
+func ignoreBogusTripPoints(zones []TemperatureSensor) {
+	shared := func(trip func(TemperatureSensor) float64) (float64, bool) {
+		counts := make(map[float64]int)
+		for _, zone := range zones {
+			if t := trip(zone); t >= bogusTripPoint {
+				counts[t]++
+			}
+		}
+		for t, n := range counts {
+			if n >= 2 && n*2 > len(zones) {
+				return t, true
+			}
+		}
+		return 0, false
+	}
+	if high, ok := shared(func(z TemperatureSensor) float64 { return z.High }); ok {
+		for i := range zones {
+			if zones[i].High == high {
+				zones[i].High = 80.0
+			}
+		}
+	}
+	if critical, ok := shared(func(z TemperatureSensor) float64 { return z.Critical }); ok {
+		for i := range zones {
+			if zones[i].Critical == critical {
+				zones[i].Critical = 100.0
+			}
+		}
+	}
+}
+
+// readHwmonSensors reads the temperatures of an hwmon chip, returning the
+// channels that could be read and the errors of the others
+func readHwmonSensors(hwmonPath string, wake []string) ([]TemperatureSensor, error) {
 	var sensors []TemperatureSensor
+	var errs []error
 
 	// Read hwmon name
 	namePath := filepath.Join(hwmonPath, "name")
 	nameData, err := os.ReadFile(namePath)
 	if err != nil {
-		return sensors
+		return nil, err
 	}
 	hwmonName := strings.TrimSpace(string(nameData))
 
+	// Values and thresholds of a suspended device would wake it up (a
+	// discrete GPU, an NVMe drive in deep sleep); its channels are listed
+	// asleep from the labels alone
+	asleep := chipAsleep(hwmonPath, wake)
+
 	// Find temperature input files
 	tempInputs, _ := filepath.Glob(filepath.Join(hwmonPath, "temp*_input"))
 	for _, inputPath := range tempInputs {
@@ -117,17 +281,30 @@ func readHwmonSensors(hwmonPath string) []TemperatureSensor {
 		critPath := filepath.Join(hwmonPath, base+"_crit")
 		maxPath := filepath.Join(hwmonPath, base+"_max")
 
-		// Read temperature value
-		data, err := os.ReadFile(inputPath)
-		if err != nil {
-			continue
+		// Read temperature value; SMBus and EC-backed chips fail now and
+		// then, which keeps the channel as a stale reading
+		var value float64
+		var raw string
+		stale := false
+		var data []byte
+		var err error
+		if !asleep {
+			data, err = os.ReadFile(inputPath)
 		}
-		tempStr := strings.TrimSpace(string(data))
-		tempMilli, err := strconv.ParseInt(tempStr, 10, 64)
-		if err != nil {
+		switch {
+		case asleep:
+		case isTransientReadError(err):
+			stale = true
+		case err != nil:
+			errs = append(errs, err)
 			continue
+		default:
+			if value, err = parseMillidegrees(data); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", inputPath, err))
+				continue
+			}
+			raw = rawReading(data)
 		}
-		value := float64(tempMilli) / 1000.0
 
 		// Determine sensor name
 		var name string
@@ -143,7 +320,17 @@ func readHwmonSensors(hwmonPath string) []TemperatureSensor {
 			High:     80.0,
 			Critical: 100.0,
 			Path:     inputPath,
+			Raw:      raw,
+			Stale:    stale,
+			Asleep:   asleep,
+			chip:     hwmonName,
 		}
+		if asleep {
+			sensors = append(sensors, sensor)
+			continue
+		}
+		sensor.alarms = channelAlarms(hwmonPath, base)
+		sensor.Alarm = readAlarms(sensor.alarms)
 
 		// Read critical threshold
 		if critData, err := os.ReadFile(critPath); err == nil {
@@ -154,6 +341,16 @@ func readHwmonSensors(hwmonPath string) []TemperatureSensor {
 			}
 		}
 
+		// Emergency and too-cold thresholds; lcrit may be negative
+		if milli, err := readSysfsInt(filepath.Join(hwmonPath, base+"_emergency")); err == nil && milli > 0 {
+			emergency := float64(milli) / 1000.0
+			sensor.Emergency = &emergency
+		}
+		if milli, err := readSysfsInt(filepath.Join(hwmonPath, base+"_lcrit")); err == nil {
+			lcrit := float64(milli) / 1000.0
+			sensor.LowCritical = &lcrit
+		}
+
 		// Read max threshold as high
 		if maxData, err := os.ReadFile(maxPath); err == nil {
 			if maxMilli, err := strconv.ParseInt(strings.TrimSpace(string(maxData)), 10, 64); err == nil {
@@ -165,5 +362,62 @@ func readHwmonSensors(hwmonPath string) []TemperatureSensor {
 
 		sensors = append(sensors, sensor)
 	}
-	return sensors
+	return sensors, errors.Join(errs...)
+}
+
+// parseMillidegrees converts a sysfs millidegree Celsius reading to Celsius
+func parseMillidegrees(data []byte) (float64, error) {
+	tempMilli, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return float64(tempMilli) / 1000.0, nil
+}
+
+// rawReading returns a value file's integer as the file has it, kept for
+// the raw display mode
+func rawReading(data []byte) string {
+	return strings.TrimSpace(string(data))
+}
+
+// runtimeStatus returns the runtime power management state of the device
+// behind a sysfs directory, such as "active" or "suspended", empty when it
+// has none
+func runtimeStatus(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "device", "power", "runtime_status"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// chipAsleep reports whether an hwmon chip's device is runtime suspended
+// and may not be woken up: wake lists the config's wake_on_read globs,
+// matched against the chip name and the device tag (see deviceTag)
+func chipAsleep(hwmonPath string, wake []string) bool {
+	if runtimeStatus(hwmonPath) != "suspended" {
+		return false
+	}
+	if len(wake) == 0 {
+		return true
+	}
+	name, _ := os.ReadFile(filepath.Join(hwmonPath, "name"))
+	chip, tag := strings.TrimSpace(string(name)), deviceTag(hwmonPath)
+	for _, pattern := range wake {
+		if ok, _ := filepath.Match(pattern, chip); ok {
+			return false
+		}
+		if ok, _ := filepath.Match(pattern, tag); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// valueFilePath returns the file holding a sensor's current reading
+func valueFilePath(sensor TemperatureSensor) string {
+	if strings.HasSuffix(sensor.Path, "_input") {
+		return sensor.Path
+	}
+	return filepath.Join(sensor.Path, "temp")
 }
@@ -0,0 +1,404 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeSysfs creates a fake sysfs tree under root from relative path -> content.
+func writeSysfs(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// linkSysfs creates a symlink at root/rel pointing to root/target.
+func linkSysfs(t *testing.T, root, rel, target string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, target), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, target), path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadTemperaturesDisambiguatesDuplicateLabels(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":         "nvme\n",
+		"class/hwmon/hwmon0/temp1_input":  "41850\n",
+		"class/hwmon/hwmon0/temp1_label":  "Composite\n",
+		"class/hwmon/hwmon1/name":         "nvme\n",
+		"class/hwmon/hwmon1/temp1_input":  "38850\n",
+		"class/hwmon/hwmon1/temp1_label":  "Composite\n",
+		"class/hwmon/hwmon2/name":         "amdgpu\n",
+		"class/hwmon/hwmon2/temp1_input":  "52000\n",
+		"class/hwmon/hwmon2/temp1_label":  "edge\n",
+		"class/hwmon/hwmon3/name":         "amdgpu\n",
+		"class/hwmon/hwmon3/temp1_input":  "50000\n",
+		"class/hwmon/hwmon3/temp1_label":  "edge\n",
+		"class/hwmon/hwmon4/name":         "k10temp\n",
+		"class/hwmon/hwmon4/temp1_input":  "60000\n",
+		"class/hwmon/hwmon4/temp1_label":  "Tctl\n",
+		"class/hwmon/hwmon5/name":         "acpitz\n",
+		"class/hwmon/hwmon5/temp1_input":  "30000\n",
+		"class/hwmon/hwmon5/temp2_input":  "31000\n",
+		"class/hwmon/hwmon5/temp1_label":  "zone\n",
+		"class/hwmon/hwmon5/temp2_label":  "zone\n",
+		"devices/nvme/nvme0/nvme0n1/size": "0\n",
+		"devices/nvme/nvme1/nvme1n1/size": "0\n",
+	})
+	linkSysfs(t, root, "class/hwmon/hwmon0/device", "devices/nvme/nvme0")
+	linkSysfs(t, root, "class/hwmon/hwmon1/device", "devices/nvme/nvme1")
+	linkSysfs(t, root, "class/hwmon/hwmon2/device", "devices/pci0000:00/0000:03:00.0")
+	linkSysfs(t, root, "class/hwmon/hwmon3/device", "devices/pci0000:00/0000:0a:00.0")
+
+	sensors := readTemperatures(root)
+	want := []string{
+		"Composite (nvme0n1)",
+		"Composite (nvme1n1)",
+		"edge (0000:03:00.0)",
+		"edge (0000:0a:00.0)",
+		"Tctl",
+		"zone (hwmon5/temp1)",
+		"zone (hwmon5/temp2)",
+	}
+	if len(sensors) != len(want) {
+		t.Fatalf("expected %d sensors, got %d: %+v", len(want), len(sensors), sensors)
+	}
+	for i, name := range want {
+		if sensors[i].Name != name {
+			t.Errorf("sensor %d: expected name %q, got %q", i, name, sensors[i].Name)
+		}
+	}
+
+	// Names must be stable across ticks
+	again := readTemperatures(root)
+	for i := range sensors {
+		if again[i].Name != sensors[i].Name {
+			t.Errorf("sensor %d name changed between reads: %q -> %q", i, sensors[i].Name, again[i].Name)
+		}
+	}
+}
+
+func TestReadTemperaturesSkipsBatteryHwmon(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"devices/PNP0C0A:00/power_supply/BAT0/type": "Battery\n",
+		"devices/PNP0C0A:00/power_supply/BAT0/temp": "312\n",
+		"devices/PNP0C0A:00/power_supply/BAT1/type": "Battery\n",
+		"class/hwmon/hwmon0/name":                   "BAT0\n",
+		"class/hwmon/hwmon0/temp1_input":            "31200\n",
+		"class/hwmon/hwmon1/name":                   "ec_bat\n",
+		"class/hwmon/hwmon1/temp1_input":            "31000\n",
+		"class/hwmon/hwmon2/name":                   "BAT1\n",
+		"class/hwmon/hwmon2/temp1_input":            "29000\n",
+		"class/hwmon/hwmon3/name":                   "coretemp\n",
+		"class/hwmon/hwmon3/temp1_input":            "50000\n",
+	})
+	linkSysfs(t, root, "class/power_supply/BAT0", "devices/PNP0C0A:00/power_supply/BAT0")
+	linkSysfs(t, root, "class/power_supply/BAT1", "devices/PNP0C0A:00/power_supply/BAT1")
+	// The power_supply core's own chip, and an EC chip below the battery
+	linkSysfs(t, root, "class/hwmon/hwmon0/device", "devices/PNP0C0A:00/power_supply/BAT0")
+	linkSysfs(t, root, "class/hwmon/hwmon1/device", "devices/PNP0C0A:00/power_supply/BAT0/ec")
+	// Only the first battery is read, so the second one's chip stays
+	linkSysfs(t, root, "class/hwmon/hwmon2/device", "devices/PNP0C0A:00/power_supply/BAT1")
+	// A dangling device link is not a duplicate
+	if err := os.Symlink(filepath.Join(root, "devices/gone"), filepath.Join(root, "class/hwmon/hwmon3/device")); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, s := range readTemperatures(root) {
+		names = append(names, s.Name)
+	}
+	if got := fmt.Sprint(names); got != "[BAT1_temp1 coretemp_temp1]" {
+		t.Errorf("expected the BAT0 chips to be skipped, got %s", got)
+	}
+}
+
+func TestReadHwmonEmergencyAndLowCritical(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":            "outdoor\n",
+		"class/hwmon/hwmon0/temp1_input":     "-42000\n",
+		"class/hwmon/hwmon0/temp1_crit":      "70000\n",
+		"class/hwmon/hwmon0/temp1_emergency": "85000\n",
+		"class/hwmon/hwmon0/temp1_lcrit":     "-40000\n",
+		"class/hwmon/hwmon0/temp2_input":     "45000\n",
+		"class/hwmon/hwmon0/temp3_input":     "-1000\n",
+		"class/hwmon/hwmon0/temp3_lcrit":     "0\n",
+	})
+	sensors := readTemperatures(root)
+	if len(sensors) != 3 {
+		t.Fatalf("expected 3 sensors, got %+v", sensors)
+	}
+	cold, plain, freezing := sensors[0], sensors[1], sensors[2]
+	if cold.Emergency == nil || *cold.Emergency != 85 || cold.LowCritical == nil || *cold.LowCritical != -40 {
+		t.Errorf("expected emergency 85 and lcrit -40, got %+v", cold)
+	}
+	if cold.State() != StateCritical {
+		t.Error("expected a reading below lcrit to be critical")
+	}
+	cold.Value = 20
+	if cold.State() != StateOK {
+		t.Errorf("expected an in-range reading to be OK, got %v", cold.State())
+	}
+	if plain.LowCritical != nil || plain.Emergency != nil || plain.State() != StateOK {
+		t.Errorf("expected no lcrit without the file, got %+v", plain)
+	}
+	// An lcrit of 0°C is a threshold like any other
+	if freezing.LowCritical == nil || *freezing.LowCritical != 0 || freezing.State() != StateCritical {
+		t.Errorf("expected a reading below an lcrit of 0°C to be critical, got %+v", freezing)
+	}
+}
+
+// writeManyChannels creates a fixture with count hwmon temperature channels
+// spread over chips of 8 channels each, including static threshold files.
+func writeManyChannels(t testing.TB, root string, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		dir := filepath.Join(root, "class/hwmon", fmt.Sprintf("hwmon%d", i/8))
+		base := fmt.Sprintf("temp%d", i%8+1)
+		files := map[string]string{
+			"name":          "chip\n",
+			base + "_input": fmt.Sprintf("%d\n", 40000+i),
+			base + "_label": fmt.Sprintf("Core %d\n", i),
+			base + "_crit":  "100000\n",
+			base + "_max":   "85000\n",
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestTemperatureReaderMatchesReadTemperatures(t *testing.T) {
+	root := t.TempDir()
+	writeManyChannels(t, root, 20)
+
+	r := newTemperatureReader(root, 4)
+	defer r.Close()
+	got := r.Refresh()
+	want := readTemperatures(root)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d sensors, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("sensor %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+	if len(r.files) != 4 {
+		t.Errorf("expected held files capped at 4, got %d", len(r.files))
+	}
+
+	// Values change in place and are picked up through the held files
+	path := filepath.Join(root, "class/hwmon/hwmon0/temp1_input")
+	if err := os.WriteFile(path, []byte("77000\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Refresh(); got[0].Value != 77.0 || got[0].Raw != "77000" {
+		t.Errorf("expected refreshed value 77.0 read from 77000, got %.1f from %q", got[0].Value, got[0].Raw)
+	}
+}
+
+func BenchmarkReadTemperatures(b *testing.B) {
+	root := b.TempDir()
+	writeManyChannels(b, root, 200)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		readTemperatures(root)
+	}
+}
+
+func BenchmarkTemperatureReaderRefresh(b *testing.B) {
+	root := b.TempDir()
+	writeManyChannels(b, root, 200)
+	r := newTemperatureReader(root, 256)
+	defer r.Close()
+	r.Refresh()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Refresh()
+	}
+}
+
+func TestNegativeTemperatures(t *testing.T) {
+	sensors := readTemperatures(filepath.Join("testdata", "machines", "outdoor-probe"))
+
+	m := NewMonitor(WithUIState("", false))
+	m.sortMode = SortValue
+	got := m.sortTemperatures(m.dropBogus(sensors))
+	if len(got) != 2 || got[0].Value != 35 || got[1].Value != -12.5 {
+		t.Fatalf("expected the bogus -273.2 dropped and -12.5 sorted last, got %+v", got)
+	}
+	if got[1].State() != StateOK {
+		t.Errorf("expected a sub-zero reading to be OK, got %v", got[1].State())
+	}
+
+	strict := NewMonitor(WithMinTemperature(-10))
+	if kept := strict.dropBogus(sensors); len(kept) != 1 {
+		t.Errorf("expected -12.5 dropped with a -10 lower bound, got %+v", kept)
+	}
+
+	// The value column keeps its width for negative readings
+	for _, v := range []float64{65, -12.5, -99.9} {
+		if got := formatTemp(v, Celsius, 6); len([]rune(got)) != 8 {
+			t.Errorf("formatTemp(%v) = %q, expected 8 columns", v, got)
+		}
+	}
+}
+
+func TestReadTemperaturesErrors(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/thermal/thermal_zone0/type": "acpitz\n",
+		"class/thermal/thermal_zone0/temp": "45000\n",
+		"class/thermal/thermal_zone1/type": "broken\n",
+		"class/thermal/thermal_zone1/temp": "N/A\n",
+		"class/hwmon/hwmon0/name":          "coretemp\n",
+		"class/hwmon/hwmon0/temp1_input":   "50000\n",
+		"class/hwmon/hwmon0/temp2_input":   "\n",
+		"class/hwmon/hwmon1/temp1_input":   "60000\n",
+	})
+	sensors, err := readTemperaturesE(root)
+	if len(sensors) != 2 {
+		t.Errorf("expected the 2 readable sensors, got %+v", sensors)
+	}
+	for _, want := range []string{"thermal_zone1/temp", "hwmon0/temp2_input", "hwmon1/name"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected an error naming %s, got %v", want, err)
+		}
+	}
+	if len(readTemperatures(root)) != 2 {
+		t.Error("expected the lenient reader to return the same sensors")
+	}
+
+	if _, err := readTemperaturesE(filepath.Join(root, "missing")); err == nil {
+		t.Error("expected an error without sysfs")
+	}
+	empty := t.TempDir()
+	writeSysfs(t, empty, map[string]string{"class/power_supply/AC/type": "Mains\n"})
+	if sensors, err := readTemperaturesE(empty); err != nil || len(sensors) != 0 {
+		t.Errorf("expected no sensors and no error on a machine without any, got %+v, %v", sensors, err)
+	}
+}
+
+func TestTemperatureSensorAdapterRefresh(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/thermal/thermal_zone0/type": "acpitz\n",
+		"class/thermal/thermal_zone0/temp": "45000\n",
+		"class/hwmon/hwmon0/name":          "coretemp\n",
+		"class/hwmon/hwmon0/temp1_input":   "50000\n",
+		"class/hwmon/hwmon0/temp1_max":     "80000\n",
+	})
+	groups := createSensorGroups(root, readTemperatures(root), BatteryStatus{})
+	if len(groups) != 1 || len(groups[0].Sensors) != 2 {
+		t.Fatalf("expected the two temperatures, got %+v", groups)
+	}
+
+	writeSysfs(t, root, map[string]string{
+		"class/thermal/thermal_zone0/temp": "47500\n",
+		"class/hwmon/hwmon0/temp1_input":   "85000\n",
+		"class/hwmon/hwmon0/temp1_max":     "90000\n",
+	})
+	for _, sensor := range groups[0].Sensors {
+		if err := sensor.Refresh(); err != nil {
+			t.Fatalf("%s: %v", sensor.Name(), err)
+		}
+	}
+	values := map[string]string{}
+	for _, sensor := range groups[0].Sensors {
+		values[sensor.Name()] = sensor.Value()
+	}
+	if values["acpitz"] != "47.5°C" || values["coretemp_temp1"] != "85.0°C" {
+		t.Errorf("expected the new values, got %v", values)
+	}
+	// Thresholds are read once
+	if hwmon := groups[0].Sensors[1]; hwmon.Name() != "coretemp_temp1" || !hwmon.Warning() {
+		t.Errorf("expected 85°C over the discovered 80°C High, got %s", hwmon.Value())
+	}
+
+	writeSysfs(t, root, map[string]string{"class/hwmon/hwmon0/temp1_input": "N/A\n"})
+	os.Remove(filepath.Join(root, "class/thermal/thermal_zone0/temp"))
+	for _, sensor := range groups[0].Sensors {
+		if err := sensor.Refresh(); err == nil {
+			t.Errorf("%s: expected a read error", sensor.Name())
+		}
+	}
+	if values := groups[0].Sensors[0].Value() + " " + groups[0].Sensors[1].Value(); values != "47.5°C 85.0°C" {
+		t.Errorf("expected failed reads to keep the previous values, got %s", values)
+	}
+}
+
+func TestTemperatureReaderFollowsRuntimeStatus(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":                   "amdgpu\n",
+		"class/hwmon/hwmon0/temp1_input":            "41000\n",
+		"class/hwmon/hwmon0/temp1_label":            "edge\n",
+		"class/hwmon/hwmon0/temp1_crit":             "95000\n",
+		"devices/0000:03:00.0/power/runtime_status": "suspended\n",
+		"class/hwmon/hwmon1/name":                   "coretemp\n",
+		"class/hwmon/hwmon1/temp1_input":            "52000\n",
+	})
+	linkSysfs(t, root, "class/hwmon/hwmon0/device", "devices/0000:03:00.0")
+	statusPath := filepath.Join(root, "devices/0000:03:00.0/power/runtime_status")
+
+	r := newTemperatureReader(root, 4)
+	defer r.Close()
+	got := r.Refresh()
+	if len(got) != 2 || !got[0].Asleep || got[0].Value != 0 || got[0].Critical != 100 || got[1].Asleep {
+		t.Fatalf("expected the suspended GPU asleep with default thresholds, got %+v", got)
+	}
+	if _, held := r.files[got[0].Path]; held {
+		t.Error("the suspended GPU's value file must not be opened")
+	}
+
+	// Resuming rediscovers, picking up the thresholds skipped while asleep
+	if err := os.WriteFile(statusPath, []byte("active\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Refresh(); got[0].Asleep || got[0].Value != 41 || got[0].Critical != 95 {
+		t.Errorf("expected the resumed GPU read, got %+v", got[0])
+	}
+	if err := os.WriteFile(statusPath, []byte("suspended\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Refresh(); !got[0].Asleep {
+		t.Errorf("expected the GPU asleep again, got %+v", got[0])
+	}
+
+	// wake_on_read matches the chip name or the device
+	for _, wake := range []string{"amdgpu", "0000:03:00.*"} {
+		r.wake = []string{wake}
+		if got := r.Refresh(); got[0].Asleep || got[0].Value != 41 {
+			t.Errorf("wake %q: expected the GPU read anyway, got %+v", wake, got[0])
+		}
+		r.wake = nil
+		r.Refresh()
+	}
+}
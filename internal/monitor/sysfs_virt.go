@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const dmiIDPath = "class/dmi/id"
+
+// dmiVendors maps DMI vendor and product strings to hypervisor names, in the
+// spirit of systemd-detect-virt. Entries are matched as prefixes.
+var dmiVendors = []struct {
+	prefix, name string
+}{
+	{"KVM", "KVM"},
+	{"Amazon EC2", "Amazon EC2"},
+	{"QEMU", "QEMU"},
+	{"VMware", "VMware"},
+	{"VMW", "VMware"},
+	{"innotek GmbH", "VirtualBox"},
+	{"VirtualBox", "VirtualBox"},
+	{"Oracle Corporation", "VirtualBox"},
+	{"Xen", "Xen"},
+	{"Bochs", "Bochs"},
+	{"Parallels", "Parallels"},
+	{"BHYVE", "bhyve"},
+	{"Google Compute Engine", "Google Compute Engine"},
+	{"Microsoft Corporation Virtual Machine", "Hyper-V"},
+}
+
+// DetectVirtualization returns the hypervisor the system runs under, such as
+// "KVM" or "VMware", or "" on bare metal or when it can't be told.
+func DetectVirtualization() string {
+	return detectVirtualization(sysfsRoot)
+}
+
+func detectVirtualization(root string) string {
+	dmi := filepath.Join(root, dmiIDPath)
+	vendor := readTrimmed(filepath.Join(dmi, "sys_vendor"))
+	product := readTrimmed(filepath.Join(dmi, "product_name"))
+	// Hyper-V only identifies itself by vendor and product together
+	candidates := []string{
+		product,
+		vendor,
+		vendor + " " + product,
+		readTrimmed(filepath.Join(dmi, "board_vendor")),
+		readTrimmed(filepath.Join(dmi, "bios_vendor")),
+	}
+	for _, candidate := range candidates {
+		for _, v := range dmiVendors {
+			if candidate != "" && strings.HasPrefix(candidate, v.prefix) {
+				return v.name
+			}
+		}
+	}
+	// Xen PV guests have no DMI table
+	if hypervisor := readTrimmed(filepath.Join(root, "hypervisor", "type")); hypervisor == "xen" {
+		return "Xen"
+	}
+	return ""
+}
+
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,46 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectVirtualization(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"kvm", map[string]string{"class/dmi/id/sys_vendor": "QEMU\n", "class/dmi/id/product_name": "Standard PC (Q35 + ICH9, 2009)\n"}, "QEMU"},
+		{"kvm product", map[string]string{"class/dmi/id/sys_vendor": "Red Hat\n", "class/dmi/id/product_name": "KVM\n"}, "KVM"},
+		{"vmware", map[string]string{"class/dmi/id/sys_vendor": "VMware, Inc.\n", "class/dmi/id/product_name": "VMware Virtual Platform\n"}, "VMware"},
+		{"virtualbox", map[string]string{"class/dmi/id/sys_vendor": "innotek GmbH\n", "class/dmi/id/product_name": "VirtualBox\n"}, "VirtualBox"},
+		{"hyper-v", map[string]string{"class/dmi/id/sys_vendor": "Microsoft Corporation\n", "class/dmi/id/product_name": "Virtual Machine\n"}, "Hyper-V"},
+		{"xen pv", map[string]string{"hypervisor/type": "xen\n"}, "Xen"},
+		{"bare metal", map[string]string{"class/dmi/id/sys_vendor": "LENOVO\n", "class/dmi/id/product_name": "21CB\n", "class/dmi/id/bios_vendor": "LENOVO\n"}, ""},
+		{"surface", map[string]string{"class/dmi/id/sys_vendor": "Microsoft Corporation\n", "class/dmi/id/product_name": "Surface Laptop 4\n"}, ""},
+		{"no dmi", map[string]string{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeSysfs(t, root, tt.files)
+			if got := detectVirtualization(root); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestViewExplainsVirtualMachine(t *testing.T) {
+	m := NewMonitor()
+	m.width, m.height = 80, 24
+	m.virtualization = "KVM"
+	if !strings.Contains(m.View(), "Running in a virtual machine (KVM)") {
+		t.Errorf("expected VM note with no sensors:\n%s", m.View())
+	}
+	m.temperatureSensors = []TemperatureSensor{{Name: "CPU", Value: 40}}
+	if strings.Contains(m.View(), "virtual machine") {
+		t.Error("expected no VM note once sensors are present")
+	}
+}
@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// voltageGroupName is the group of the voltage rails of hwmon chips
+const voltageGroupName = "Voltages"
+
+// VoltageSensor reports a voltage rail of an hwmon chip (in*_input, in
+// millivolts). It warns outside the chip's in*_min and in*_max limits and
+// is critical past in*_lcrit and in*_crit, each when set, or while the
+// chip's alarm flags for the rail are.
+type VoltageSensor struct {
+	path string // the in*_input file
+	name string
+	// Limits in millivolts, 0 when the chip doesn't set them
+	min, max, lcrit, crit int64
+	millivolts            int64
+	alarms                []alarmFile
+	alarm                 State
+}
+
+// ReadVoltages returns a refreshed sensor for each voltage rail of the
+// hwmon chips
+func ReadVoltages() []Sensor {
+	return readVoltages(sysfsRoot)
+}
+
+func readVoltages(root string) []Sensor {
+	var sensors []Sensor
+	battery := batteryDevice(root)
+	hwmonPaths, _ := filepath.Glob(filepath.Join(root, hwmonClassPath, "hwmon*"))
+	for _, hwmonPath := range hwmonPaths {
+		nameData, err := os.ReadFile(filepath.Join(hwmonPath, "name"))
+		if err != nil || duplicatesBattery(hwmonPath, battery) {
+			continue
+		}
+		chip := strings.TrimSpace(string(nameData))
+		inputs, _ := filepath.Glob(filepath.Join(hwmonPath, "in*_input"))
+		for _, input := range inputs {
+			base := strings.TrimSuffix(filepath.Base(input), "_input")
+			rail := &VoltageSensor{path: input, name: fmt.Sprintf("%s_%s", chip, base)}
+			label, labelErr := os.ReadFile(filepath.Join(hwmonPath, base+"_label"))
+			if labelErr == nil {
+				rail.name = strings.TrimSpace(string(label))
+			}
+			limits := []struct {
+				suffix string
+				value  *int64
+			}{{"_min", &rail.min}, {"_max", &rail.max}, {"_lcrit", &rail.lcrit}, {"_crit", &rail.crit}}
+			for _, limit := range limits {
+				if mv, err := readSysfsInt(filepath.Join(hwmonPath, base+limit.suffix)); err == nil && mv > 0 {
+					*limit.value = mv
+				}
+			}
+			rail.alarms = channelAlarms(hwmonPath, base)
+			if err := rail.Refresh(); err != nil {
+				continue
+			}
+			// Chips expose their unconnected inputs as unlabeled 0 mV rails
+			if rail.millivolts == 0 && labelErr != nil {
+				continue
+			}
+			sensors = append(sensors, rail)
+		}
+	}
+	return sensors
+}
+
+func (v *VoltageSensor) Name() string {
+	return v.name
+}
+
+func (v *VoltageSensor) Value() string {
+	return formatMeasurement(KindVoltage, float64(v.millivolts)/1000)
+}
+
+func (v *VoltageSensor) Kind() Kind {
+	return KindVoltage
+}
+
+func (v *VoltageSensor) Measurement() (float64, bool) {
+	return float64(v.millivolts) / 1000, true
+}
+
+func (v *VoltageSensor) attribute() string {
+	return v.path
+}
+
+func (v *VoltageSensor) Warning() bool {
+	return v.min > 0 && v.millivolts < v.min || v.max > 0 && v.millivolts > v.max || v.alarm == StateWarning
+}
+
+func (v *VoltageSensor) Critical() bool {
+	return v.lcrit > 0 && v.millivolts < v.lcrit || v.crit > 0 && v.millivolts > v.crit || v.alarm == StateCritical
+}
+
+func (v *VoltageSensor) Alarm() State {
+	return v.alarm
+}
+
+func (v *VoltageSensor) Refresh() error {
+	mv, err := readSysfsInt(v.path)
+	if err != nil {
+		return err
+	}
+	v.millivolts = mv
+	v.alarm = readAlarms(v.alarms)
+	return nil
+}
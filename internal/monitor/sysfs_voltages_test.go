@@ -0,0 +1,68 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVoltageRails(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":        "nct6775\n",
+		"class/hwmon/hwmon0/in0_input":   "1104\n",
+		"class/hwmon/hwmon0/in0_label":   "Vcore\n",
+		"class/hwmon/hwmon0/in0_min":     "800\n",
+		"class/hwmon/hwmon0/in0_max":     "1500\n",
+		"class/hwmon/hwmon0/in1_input":   "3344\n",
+		"class/hwmon/hwmon0/in1_crit":    "3600\n",
+		"class/hwmon/hwmon0/in2_input":   "0\n",
+		"class/hwmon/hwmon0/in3_input":   "0\n",
+		"class/hwmon/hwmon0/in3_label":   "VBAT\n",
+		"class/hwmon/hwmon1/name":        "BAT0\n",
+		"class/hwmon/hwmon1/in0_input":   "12560\n",
+		"class/power_supply/BAT0/type":   "Battery\n",
+		"class/power_supply/BAT0/status": "Discharging\n",
+	})
+	linkSysfs(t, root, "class/hwmon/hwmon1/device", "class/power_supply/BAT0")
+
+	rails := readVoltages(root)
+	var names []string
+	for _, rail := range rails {
+		names = append(names, rail.Name()+" "+rail.Value())
+	}
+	// The unlabeled 0 mV input is unconnected; the battery's chip repeats
+	// the battery's voltage
+	if len(rails) != 3 || names[0] != "Vcore 1.10V" || names[1] != "nct6775_in1 3.34V" || names[2] != "VBAT 0.00V" {
+		t.Fatalf("expected Vcore, in1 and VBAT, got %q", names)
+	}
+	if SensorKind(rails[0]) != KindVoltage {
+		t.Errorf("expected a voltage, got %s", SensorKind(rails[0]))
+	}
+
+	write := func(rel, mv string) {
+		if err := os.WriteFile(filepath.Join(root, "class/hwmon/hwmon0", rel), []byte(mv+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	vcore, in1 := rails[0], rails[1]
+	for _, tc := range []struct {
+		mv                string
+		warning, critical bool
+	}{
+		{"1104", false, false},
+		{"1550", true, false},
+		{"700", true, false},
+	} {
+		write("in0_input", tc.mv)
+		vcore.Refresh()
+		if vcore.Warning() != tc.warning || vcore.Critical() != tc.critical {
+			t.Errorf("Vcore at %s mV: expected warning %v critical %v", tc.mv, tc.warning, tc.critical)
+		}
+	}
+	write("in1_input", "3700")
+	in1.Refresh()
+	if !in1.Critical() || in1.Warning() {
+		t.Errorf("expected in1 critical past in1_crit, got %s", in1.Value())
+	}
+}
@@ -0,0 +1,156 @@
+//go:build ignore
+
+// capture copies the sysfs attributes read by the monitor from a live system
+// into a fixture directory, so real-machine layouts can be used in tests.
+//
+// Usage (from internal/monitor):
+//
+//	go run testdata/capture.go -o testdata/machines/<name>
+//	go test -run TestMachineFixtures -update
+//
+// Only the classes the monitor reads are copied, one level deep. Device
+// symlinks are replaced by small stand-in directories preserving the names
+// used for disambiguation (PCI address, block device, NVMe namespace).
+// Identifying attributes such as serial numbers are sanitized; review the
+// result before committing it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var classes = []string{"class/thermal", "class/hwmon", "class/power_supply"}
+
+// sanitized lists attributes replaced by a placeholder in the fixture
+var sanitized = map[string]bool{
+	"serial_number": true,
+	"model_name":    true,
+	"manufacturer":  true,
+}
+
+const maxAttrSize = 4096
+
+func main() {
+	root := flag.String("root", "/sys", "sysfs root to capture from")
+	out := flag.String("o", "", "fixture directory to write")
+	flag.Parse()
+	if *out == "" {
+		fmt.Fprintln(os.Stderr, "capture: -o is required")
+		os.Exit(2)
+	}
+
+	for _, class := range classes {
+		entries, err := os.ReadDir(filepath.Join(*root, class))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), "cooling_device") {
+				if err := captureEntry(*root, *out, filepath.Join(class, entry.Name())); err != nil {
+					fmt.Fprintf(os.Stderr, "capture: %s: %v\n", entry.Name(), err)
+				}
+			}
+		}
+	}
+}
+
+func captureEntry(root, out, rel string) error {
+	src := filepath.Join(root, rel)
+	dst := filepath.Join(out, rel)
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(src, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxAttrSize {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// Write-only or erroring attributes are left out, which the
+			// readers treat the same way as a failing read.
+			continue
+		}
+		if sanitized[entry.Name()] {
+			data = []byte("sanitized\n")
+		}
+		if err := os.WriteFile(filepath.Join(dst, entry.Name()), data, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return captureDevice(src, out, dst)
+}
+
+// captureDevice recreates the entry's device symlink as a link to a stand-in
+// directory under devices/ holding the child names used to tag duplicates
+// and the device's runtime PM status.
+// A device that is itself a power supply (the battery's own hwmon chip)
+// links to the captured supply instead, so the readers can tell it apart.
+func captureDevice(src, out, dst string) error {
+	target, err := filepath.EvalSymlinks(filepath.Join(src, "device"))
+	if err != nil {
+		return nil
+	}
+	if filepath.Base(filepath.Dir(target)) == "power_supply" {
+		link, err := filepath.Rel(dst, filepath.Join(out, "class", "power_supply", filepath.Base(target)))
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, filepath.Join(dst, "device"))
+	}
+	device := filepath.Join(out, "devices", moduleSafe(filepath.Base(target)))
+	var leaves []string
+	children, _ := filepath.Glob(filepath.Join(target, "block", "*"))
+	namespaces, _ := filepath.Glob(filepath.Join(target, "nvme*n*"))
+	for _, child := range children {
+		leaves = append(leaves, filepath.Join(device, "block", filepath.Base(child)))
+	}
+	for _, ns := range namespaces {
+		leaves = append(leaves, filepath.Join(device, filepath.Base(ns)))
+	}
+	if len(leaves) == 0 {
+		leaves = append(leaves, device)
+	}
+	// git doesn't track empty directories
+	for _, leaf := range leaves {
+		if err := os.MkdirAll(leaf, 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(leaf, ".keep"), nil, 0o644); err != nil {
+			return err
+		}
+	}
+
+	// The runtime PM state decides whether the chip is read at all
+	if status, err := os.ReadFile(filepath.Join(target, "power", "runtime_status")); err == nil {
+		if err := os.MkdirAll(filepath.Join(device, "power"), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(device, "power", "runtime_status"), status, 0o644); err != nil {
+			return err
+		}
+	}
+
+	link, err := filepath.Rel(dst, device)
+	if err != nil {
+		return err
+	}
+	return os.Symlink(link, filepath.Join(dst, "device"))
+}
+
+// moduleSafe replaces colons (PCI addresses, SCSI ids), which are not allowed
+// in Go module file paths.
+func moduleSafe(name string) string {
+	return strings.ReplaceAll(name, ":", "_")
+}
@@ -0,0 +1,66 @@
+package monitor
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// lastIndex matches the last number in a thermal zone type, with the
+// separator before it
+var lastIndex = regexp.MustCompile(`[-_ ]?\d+([^\d]*)$`)
+
+// zoneCluster returns the cluster a thermal zone belongs to: its name
+// without the last index, e.g. "cpu-1-usr" for "cpu-1-3-usr". Names
+// without a number belong to no cluster.
+func zoneCluster(name string) (string, bool) {
+	loc := lastIndex.FindStringSubmatchIndex(name)
+	if loc == nil {
+		return "", false
+	}
+	return name[:loc[0]] + name[loc[2]:loc[3]], true
+}
+
+// collapseZones merges thermal zones whose names differ only by their last
+// index into one reading per cluster, the hottest of its zones, as found on
+// ARM and Android kernels exposing dozens of zones and no hwmon chips.
+// Lists with anything but thermal zones, such as hwmon channels, are
+// returned unchanged, as are zones alone in their cluster.
+// A cluster takes the place of its first zone and the thresholds and path
+// of its hottest one.
+func collapseZones(sensors []TemperatureSensor) []TemperatureSensor {
+	members := make(map[string]int)
+	for _, sensor := range sensors {
+		if !strings.HasPrefix(filepath.Base(sensor.Path), "thermal_zone") {
+			return sensors
+		}
+		if cluster, ok := zoneCluster(sensor.Name); ok {
+			members[cluster]++
+		}
+	}
+
+	collapsed := make([]TemperatureSensor, 0, len(sensors))
+	position := make(map[string]int)
+	for _, sensor := range sensors {
+		cluster, ok := zoneCluster(sensor.Name)
+		if !ok || members[cluster] < 2 {
+			collapsed = append(collapsed, sensor)
+			continue
+		}
+		i, seen := position[cluster]
+		if !seen {
+			position[cluster] = len(collapsed)
+			sensor.Name, sensor.Key, sensor.Zones, sensor.Cooling = cluster, "", 1, nil
+			collapsed = append(collapsed, sensor)
+			continue
+		}
+		hottest := &collapsed[i]
+		zones := hottest.Zones + 1
+		if sensor.Value > hottest.Value {
+			sensor.Name, sensor.Key, sensor.Cooling = cluster, "", nil
+			*hottest = sensor
+		}
+		hottest.Zones = zones
+	}
+	return collapsed
+}
@@ -0,0 +1,49 @@
+package monitor
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestZoneCluster(t *testing.T) {
+	tests := map[string]string{
+		"cpu-0-3-usr":      "cpu-0-usr",
+		"gpuss-1-usr":      "gpuss-usr",
+		"tsens_tz_sensor7": "tsens_tz_sensor",
+		"cpu 2":            "cpu",
+		"battery":          "",
+	}
+	for name, want := range tests {
+		if got, ok := zoneCluster(name); got != want || ok != (want != "") {
+			t.Errorf("zoneCluster(%q) = %q, %v; expected %q", name, got, ok, want)
+		}
+	}
+}
+
+func TestExpandZones(t *testing.T) {
+	m := NewMonitor()
+	m.temperatureSensors = readTemperatures(filepath.Join("testdata", "machines", "android-phone"))
+	m.adjustTemperatures()
+	if len(m.temperatureSensors) != 13 {
+		t.Fatalf("expected 13 clustered readings, got %d", len(m.temperatureSensors))
+	}
+	if cpu := m.temperatureSensors[1]; cpu.Name != "cpu-0-usr" || cpu.Zones != 4 || cpu.Value != 43.1 {
+		t.Errorf("expected the hottest of the cpu-0 cluster, got %+v", cpu)
+	}
+
+	m = sendKeys(m, "z")
+	if len(m.temperatureSensors) != 20 || !m.UIState().ExpandZones {
+		t.Errorf("expected every zone after expanding, got %d", len(m.temperatureSensors))
+	}
+	m = sendKeys(m, "z")
+	if len(m.temperatureSensors) != 13 {
+		t.Errorf("expected the clusters back, got %d", len(m.temperatureSensors))
+	}
+
+	// Hwmon channels are never merged
+	sensors := temps(40, 41)
+	sensors[0].Name, sensors[1].Name = "Core 0", "Core 1"
+	if got := collapseZones(sensors); len(got) != 2 {
+		t.Errorf("expected hwmon channels kept apart, got %+v", got)
+	}
+}
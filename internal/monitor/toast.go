@@ -0,0 +1,54 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// toastDuration is how long a battery status toast stays above the footer
+const toastDuration = 5 * time.Second
+
+// setBattery stores a new battery reading, records power events (see
+// power_events.go), which every status change produces, and raises a toast
+// for it as well
+func (m *Monitor) setBattery(status BatteryStatus, now time.Time) {
+	prev := m.batteryStatus
+	m.trackPower(prev, status, m.batteryRead, now)
+	m.trackEnergy(prev, status, m.batteryRead, now)
+	m.batteryStatus = status
+	m.batteryRead = now
+	if prev.Status == "" || status.Status == prev.Status {
+		return
+	}
+	m.toast = batteryToast(prev, status)
+	m.toastUntil = now.Add(toastDuration)
+}
+
+// batteryToast describes a status transition. A charge limit holding the
+// battery ("Not charging" with the adapter online) is reported apart from
+// the adapter being unplugged.
+func batteryToast(prev, cur BatteryStatus) string {
+	switch cur.Status {
+	case "Full":
+		return "Battery fully charged"
+	case "Charging":
+		return fmt.Sprintf("Charging (%d%%)", cur.Capacity)
+	case "Not charging":
+		if cur.ACOnline {
+			return fmt.Sprintf("Charge limit reached, not charging (%d%%)", cur.Capacity)
+		}
+	case "Discharging":
+		if prev.ACOnline && !cur.ACOnline {
+			return fmt.Sprintf("Charger unplugged, discharging (%d%%)", cur.Capacity)
+		}
+	}
+	return fmt.Sprintf("Battery %s → %s (%d%%)", prev.Status, cur.Status, cur.Capacity)
+}
+
+// activeToast returns the toast to show at now, if it hasn't expired
+func (m Monitor) activeToast(now time.Time) string {
+	if m.toast == "" || !now.Before(m.toastUntil) {
+		return ""
+	}
+	return m.toast
+}
@@ -0,0 +1,51 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatteryToast(t *testing.T) {
+	tests := []struct {
+		name      string
+		prev, cur BatteryStatus
+		want      string
+	}{
+		{"full", BatteryStatus{Status: "Charging", ACOnline: true}, BatteryStatus{Status: "Full", Capacity: 100, ACOnline: true}, "Battery fully charged"},
+		{"plugged in", BatteryStatus{Status: "Discharging"}, BatteryStatus{Status: "Charging", Capacity: 40, ACOnline: true}, "Charging (40%)"},
+		{"unplugged", BatteryStatus{Status: "Charging", ACOnline: true}, BatteryStatus{Status: "Discharging", Capacity: 40}, "Charger unplugged, discharging (40%)"},
+		{"charge limit", BatteryStatus{Status: "Charging", ACOnline: true}, BatteryStatus{Status: "Not charging", Capacity: 80, ACOnline: true}, "Charge limit reached, not charging (80%)"},
+		{"discharging on AC", BatteryStatus{Status: "Not charging", ACOnline: true}, BatteryStatus{Status: "Discharging", Capacity: 80, ACOnline: true}, "Battery Not charging → Discharging (80%)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := batteryToast(tt.prev, tt.cur); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBatteryToastExpires(t *testing.T) {
+	m := NewMonitor()
+	m.width, m.height = 80, 24
+	now := time.Now()
+
+	// The first reading is not a transition
+	m.setBattery(BatteryStatus{Status: "Charging", Capacity: 99, ACOnline: true}, now)
+	if m.activeToast(now) != "" {
+		t.Fatal("expected no toast for the first reading")
+	}
+	m.setBattery(BatteryStatus{Status: "Full", Capacity: 100, ACOnline: true}, now)
+	if !strings.Contains(m.View(), "Battery fully charged") {
+		t.Errorf("expected toast in view:\n%s", m.View())
+	}
+	m.setBattery(BatteryStatus{Status: "Full", Capacity: 100, ACOnline: true}, now.Add(time.Second))
+	if m.activeToast(now.Add(time.Second)) == "" {
+		t.Error("expected an unchanged status to keep the toast")
+	}
+	if m.activeToast(now.Add(toastDuration)) != "" {
+		t.Error("expected toast to expire")
+	}
+}
@@ -0,0 +1,193 @@
+package monitor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// uiStateVersion is bumped when the state file format changes incompatibly;
+// files with another version are ignored.
+const uiStateVersion = 1
+
+// uiStateDebounce delays saving after a change so rapid toggling writes once
+const uiStateDebounce = time.Second
+
+// SortMode orders the temperature list
+type SortMode int
+
+const (
+	SortSysfs SortMode = iota // discovery order
+	SortValue                 // hottest first
+)
+
+// ViewMode forces a layout instead of choosing by terminal height
+type ViewMode int
+
+const (
+	ViewAuto ViewMode = iota
+	ViewFull
+	ViewCompact
+)
+
+var (
+	sortModeNames = []string{"sysfs", "value"}
+	viewModeNames = []string{"auto", "full", "compact"}
+)
+
+// UIState holds volatile UI preferences persisted between runs. It is kept
+// separate from the user-edited config file.
+type UIState struct {
+	Version   int      `json:"version"`
+	Collapsed []string `json:"collapsed,omitempty"`
+	Sort      string   `json:"sort,omitempty"`
+	Unit      string   `json:"unit,omitempty"`
+	View      string   `json:"view,omitempty"`
+	// ExpandZones lists thermal zones one by one instead of by cluster
+	ExpandZones bool `json:"expand_zones,omitempty"`
+	// Muted lists the sensors whose alerts were muted with m, by mute key
+	Muted []string `json:"muted,omitempty"`
+}
+
+// DefaultUIStatePath returns $XDG_STATE_HOME/sysfs-monitor-tui/state.json,
+// falling back to ~/.local/state.
+func DefaultUIStatePath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "sysfs-monitor-tui", "state.json")
+}
+
+// loadUIState reads the state file; a missing, corrupt or incompatible file
+// yields ok == false and is otherwise ignored.
+func loadUIState(path string) (state UIState, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state, false
+	}
+	if err := json.Unmarshal(data, &state); err != nil || state.Version != uiStateVersion {
+		return UIState{}, false
+	}
+	return state, true
+}
+
+func (s UIState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write atomically so a crash never leaves a truncated file behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// WithViewMode starts in the given view mode; restored UI preferences given
+// after it replace it
+func WithViewMode(mode ViewMode) Option {
+	return func(m *Monitor) {
+		m.viewMode = mode
+	}
+}
+
+// WithUIState restores UI preferences from path (unless fresh is set) and
+// saves them there on change and on quit. An empty path disables persistence.
+func WithUIState(path string, fresh bool) Option {
+	return func(m *Monitor) {
+		m.statePath = path
+		if path == "" || fresh {
+			return
+		}
+		if state, ok := loadUIState(path); ok {
+			m.applyUIState(state)
+		}
+	}
+}
+
+func (m *Monitor) applyUIState(state UIState) {
+	m.collapsed = make(map[string]bool)
+	for _, name := range state.Collapsed {
+		m.collapsed[name] = true
+	}
+	m.unit = parseTempUnit(state.Unit)
+	m.sortMode = SortMode(indexOf(sortModeNames, state.Sort))
+	m.viewMode = ViewMode(indexOf(viewModeNames, state.View))
+	m.expandZones = state.ExpandZones
+	m.muted = make(map[string]bool)
+	for _, key := range state.Muted {
+		m.muted[key] = true
+	}
+}
+
+// UIState returns the current UI preferences
+func (m Monitor) UIState() UIState {
+	state := UIState{
+		Version: uiStateVersion,
+		Sort:    sortModeNames[m.sortMode],
+		Unit:    m.unit.String(),
+		View:    viewModeNames[m.viewMode],
+	}
+	for name, collapsed := range m.collapsed {
+		if collapsed {
+			state.Collapsed = append(state.Collapsed, name)
+		}
+	}
+	sort.Strings(state.Collapsed)
+	state.ExpandZones = m.expandZones
+	for key, muted := range m.muted {
+		if muted {
+			state.Muted = append(state.Muted, key)
+		}
+	}
+	sort.Strings(state.Muted)
+	return state
+}
+
+// SaveUIState writes the UI preferences to the state file, if configured
+func (m Monitor) SaveUIState() error {
+	if m.statePath == "" {
+		return nil
+	}
+	return m.UIState().save(m.statePath)
+}
+
+// saveUIStateMsg triggers a debounced save; only the latest change saves
+type saveUIStateMsg struct {
+	seq int
+}
+
+// uiStateChanged schedules a debounced save after a preference changed
+func (m Monitor) uiStateChanged() (Monitor, tea.Cmd) {
+	if m.statePath == "" {
+		return m, nil
+	}
+	m.stateSeq++
+	seq := m.stateSeq
+	return m, m.clock.Tick(uiStateDebounce, func(time.Time) tea.Msg {
+		return saveUIStateMsg{seq: seq}
+	})
+}
+
+// indexOf returns the position of s in names, or 0 if absent
+func indexOf(names []string, s string) int {
+	for i, name := range names {
+		if name == s {
+			return i
+		}
+	}
+	return 0
+}
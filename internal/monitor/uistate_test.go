@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUIStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "state.json")
+	m := NewMonitor(WithUIState(path, false))
+	m.width, m.height = 80, 24
+	m.temperatureSensors = []TemperatureSensor{
+		{Name: "CPU", Value: 65.0, High: 80.0, Critical: 100.0, Path: "thermal_zone0"},
+		{Name: "GPU", Value: 72.5, High: 85.0, Critical: 105.0, Path: "thermal_zone1"},
+	}
+	m.RegisterSensorGroup(SensorGroup{Name: "Custom", Sensors: []Sensor{newStaticSensor("s1", false, false)}})
+
+	m = sendKeys(m, "u", "o", "v", "down", "down", "down", "c")
+	if !strings.Contains(m.View(), "°F") {
+		t.Error("expected Fahrenheit after toggling unit")
+	}
+	if m.temperatureSensors[0].Name != "GPU" {
+		t.Error("expected hottest sensor first after toggling sort")
+	}
+	if err := m.SaveUIState(); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewMonitor(WithUIState(path, false))
+	if got, want := restored.UIState(), m.UIState(); !equalUIState(got, want) {
+		t.Errorf("restored state %+v, want %+v", got, want)
+	}
+	if restored.unit != Fahrenheit || restored.sortMode != SortValue || restored.viewMode != ViewFull || !restored.collapsed["Custom"] {
+		t.Errorf("unexpected restored monitor state: %+v", restored.UIState())
+	}
+
+	fresh := NewMonitor(WithUIState(path, true))
+	if fresh.unit != Celsius || len(fresh.collapsed) != 0 {
+		t.Error("--fresh should skip restoring state")
+	}
+}
+
+func TestUIStateIgnoresBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"corrupt.json": "{not json",
+		"future.json":  `{"version": 99, "unit": "fahrenheit"}`,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		m := NewMonitor(WithUIState(path, false))
+		if m.unit != Celsius {
+			t.Errorf("%s: expected state to be ignored", name)
+		}
+	}
+}
+
+func TestUIStateDebouncedSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	m := NewMonitor(WithUIState(path, false))
+	m, _ = m.Update(keyMsg("u"))
+	m, _ = m.Update(keyMsg("u"))
+	m, _ = m.Update(keyMsg("u"))
+
+	// A stale save message is dropped; only the latest one writes
+	m, _ = m.Update(saveUIStateMsg{seq: 1})
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("stale save message should not write the state file")
+	}
+	m.Update(saveUIStateMsg{seq: m.stateSeq})
+	if state, ok := loadUIState(path); !ok || state.Unit != "raw" {
+		t.Errorf("expected saved raw state, got %+v (ok %v)", state, ok)
+	}
+}
+
+func equalUIState(a, b UIState) bool {
+	return a.Version == b.Version && a.Sort == b.Sort && a.Unit == b.Unit && a.View == b.View &&
+		strings.Join(a.Collapsed, ",") == strings.Join(b.Collapsed, ",")
+}
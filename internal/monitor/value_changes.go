@@ -0,0 +1,110 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// changeEpsilons are the smallest moves of a measurement that count as a
+// change, by kind, so sensor jitter doesn't reset the time since the last
+// change. Readings of other kinds, or without a number, compare their
+// displayed values.
+var changeEpsilons = map[Kind]float64{
+	KindTemperature: 0.5,
+	KindFan:         50,
+	KindPower:       0.1,
+	KindVoltage:     0.02,
+	KindCurrent:     0.02,
+	KindPercentage:  0.5,
+	KindRate:        1024,
+}
+
+// slowKinds are the kinds of slow-moving readings, such as battery wear,
+// disk usage or charge limits, which the full view marks with the time
+// they have been unchanged when Config.UnchangedTimes is set
+var slowKinds = map[Kind]bool{
+	KindPercentage: true,
+	KindInfo:       true,
+}
+
+// minUnchanged is how long a slow-moving reading holds its value before
+// the full view marks it
+const minUnchanged = time.Minute
+
+// valueChange is a reading's value when it last changed
+type valueChange struct {
+	value    float64
+	measured bool
+	text     string
+	at       time.Time
+}
+
+// differs reports whether a reading moved away from the value of the change
+func (c valueChange) differs(kind Kind, value float64, measured bool, text string) bool {
+	if epsilon, ok := changeEpsilons[kind]; ok && measured && c.measured {
+		return math.Abs(value-c.value) >= epsilon
+	}
+	return text != c.text || measured != c.measured
+}
+
+// trackChanges notes the readings whose value changed since their last
+// change, by the keys of the state maps. Called by Refresh; readings gone
+// from the display are forgotten, asleep temperatures keep their entry.
+func (m *Monitor) trackChanges(now time.Time) {
+	changes := make(map[string]valueChange, len(m.valueChanges))
+	track := func(key string, kind Kind, value float64, measured bool, text string) {
+		c, ok := m.valueChanges[key]
+		if !ok || c.differs(kind, value, measured, text) {
+			c = valueChange{value: value, measured: measured, text: text, at: now}
+		}
+		changes[key] = c
+	}
+	for _, sensor := range m.temperatureSensors {
+		key := temperatureStateKey(sensor.key())
+		if sensor.Asleep {
+			if c, ok := m.valueChanges[key]; ok {
+				changes[key] = c
+			}
+			continue
+		}
+		track(key, KindTemperature, sensor.Value, true, "")
+	}
+	for _, group := range m.extraGroups {
+		for _, sensor := range group.Sensors {
+			value, measured := 0.0, false
+			if reading, ok := sensor.(Measured); ok {
+				value, measured = reading.Measurement()
+			}
+			track(groupStateKey(group.Name, sensor.Name()), SensorKind(sensor), value, measured, m.sensorValue(sensor))
+		}
+	}
+	m.valueChanges = changes
+}
+
+// lastChange returns when the reading with the state key last changed value
+func (m Monitor) lastChange(key string) (time.Time, bool) {
+	c, ok := m.valueChanges[key]
+	return c.at, ok
+}
+
+// formatElapsed formats a time span, such as how long a value has held, to
+// the minute past one, e.g. "2h13m"
+func formatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(d.Truncate(time.Minute).String(), "0s")
+}
+
+// unchangedMarker is the full view's suffix of a slow-moving group reading
+// unchanged for minUnchanged or longer
+func unchangedMarker(reading SensorReading, view ViewState, now time.Time) string {
+	if !view.Unchanged || !slowKinds[reading.Kind] || reading.Changed.IsZero() || now.Sub(reading.Changed) < minUnchanged {
+		return ""
+	}
+	return " " + lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("(unchanged %s)", formatElapsed(now.Sub(reading.Changed))))
+}
@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestValueChanges(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	start := clock.now
+	wear, power := 12.0, 5.0
+	m := NewMonitor(WithClock(clock), WithDemo(DefaultDemoSeed), WithInterval(2*time.Second),
+		WithConfig("", Config{UnchangedTimes: true}), func(m *Monitor) {
+			m.extraGroups = []SensorGroup{{Name: "Disk", Sensors: []Sensor{
+				NewGenericSensor("Wear", func() (string, bool, bool, error) {
+					return fmt.Sprintf("%.1f%%", wear), false, false, nil
+				}).SetKind(KindPercentage),
+				NewGenericSensor("Power", func() (string, bool, bool, error) {
+					return fmt.Sprintf("%.2fW", power), false, false, nil
+				}).SetKind(KindPower),
+			}}}
+		})
+	m.Init()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	changed := func(m Monitor, i int) time.Time {
+		return m.Snapshot().Groups[0].Readings[i].Changed
+	}
+
+	m = clock.advance(m, 2*time.Second)
+	if got := changed(m, 0); !got.Equal(start.Add(2 * time.Second)) {
+		t.Fatalf("expected the first refresh to start the clock, got %v", got)
+	}
+
+	// Jitter below the kind's epsilon keeps the value unchanged
+	wear, power = 12.2, 5.05
+	m = clock.advance(m, 2*time.Minute)
+	if got := changed(m, 0); !got.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("expected jitter ignored, got %v", got)
+	}
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "12.2% (unchanged 2m)") {
+		t.Errorf("expected the slow-moving reading marked:\n%s", view)
+	}
+	if strings.Contains(view, "5.05W (unchanged") {
+		t.Errorf("expected power readings unmarked:\n%s", view)
+	}
+
+	m = sendKeys(m, strings.Split(strings.Repeat("j", len(m.temperatureSensors)+1), "")...)
+	m = sendKeys(m, "enter")
+	if view := m.View(); !strings.Contains(view, "Stable:   unchanged for 2m") {
+		t.Errorf("expected the time unchanged in the detail view:\n%s", view)
+	}
+	m = sendKeys(m, "esc")
+
+	wear = 13
+	m = clock.advance(m, 2*time.Second)
+	if got := changed(m, 0); !got.Equal(clock.now) {
+		t.Errorf("expected a change past the epsilon to restart the clock, got %v", got)
+	}
+	if view := ansi.Strip(m.View()); strings.Contains(view, "(unchanged") {
+		t.Errorf("expected no marker right after a change:\n%s", view)
+	}
+
+	m.config.UnchangedTimes = false
+	m = clock.advance(m, 5*time.Minute)
+	if view := ansi.Strip(m.View()); strings.Contains(view, "(unchanged") {
+		t.Errorf("expected no marker unless enabled:\n%s", view)
+	}
+}
+
+func TestUnchangedFor(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		42 * time.Second:                             "42s",
+		time.Minute + 30*time.Second:                 "1m",
+		2*time.Hour + 13*time.Minute + 5*time.Second: "2h13m",
+	} {
+		if got := formatElapsed(d); got != want {
+			t.Errorf("formatElapsed(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// DefaultSensorWait is how long --wait-for-sensors waits when given no
+	// duration
+	DefaultSensorWait = 30 * time.Second
+
+	// sensorPollInterval is the time between two looks for sensors while
+	// waiting for them
+	sensorPollInterval = time.Second
+)
+
+// SensorWait is the value of the --wait-for-sensors flag. Like a boolean
+// flag it may be given alone, waiting DefaultSensorWait, or with a duration
+// as --wait-for-sensors=10s.
+type SensorWait time.Duration
+
+func (w *SensorWait) String() string {
+	return time.Duration(*w).String()
+}
+
+func (w *SensorWait) Set(s string) error {
+	switch s {
+	case "true":
+		*w = SensorWait(DefaultSensorWait)
+		return nil
+	case "false":
+		*w = 0
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return fmt.Errorf("expected a duration such as 30s, got %q", s)
+	}
+	*w = SensorWait(d)
+	return nil
+}
+
+// IsBoolFlag lets the flag package accept the flag without a value
+func (w *SensorWait) IsBoolFlag() bool {
+	return true
+}
+
+// sensorsPresent reports whether a temperature or a battery can be read
+// under root, skipping the disabled one
+func sensorsPresent(root string, thermal, battery bool) bool {
+	return thermal && len(readTemperatures(root)) > 0 || battery && batteryDevice(root) != ""
+}
+
+// WaitForSensors blocks until a temperature or a battery appears, looking
+// every second, for at most timeout. It reports whether one appeared. Early
+// in boot the hwmon and power supply drivers may not be loaded yet.
+func WaitForSensors(timeout time.Duration) bool {
+	return waitForSensors(sysfsRoot, timeout, time.Sleep)
+}
+
+func waitForSensors(root string, timeout time.Duration, sleep func(time.Duration)) bool {
+	for waited := time.Duration(0); ; waited += sensorPollInterval {
+		if sensorsPresent(root, true, true) {
+			return true
+		}
+		if waited >= timeout {
+			return false
+		}
+		sleep(sensorPollInterval)
+	}
+}
+
+// WithSensorWait holds back the first refresh until a temperature or a
+// battery appears, for at most timeout, showing a waiting message instead
+// of an empty monitor. Groups are discovered once the wait is over.
+func WithSensorWait(timeout time.Duration) Option {
+	return func(m *Monitor) {
+		m.sensorWait = timeout
+		m.waitRoot = sysfsRoot
+	}
+}
+
+// sensorWaitMsg triggers a look for sensors while waiting for them
+type sensorWaitMsg struct{}
+
+// waiting reports whether the first refresh is held back for sensors
+func (m Monitor) waiting() bool {
+	return !m.waitUntil.IsZero()
+}
+
+func (m Monitor) pollSensors(d time.Duration) tea.Cmd {
+	return m.clock.Tick(d, func(time.Time) tea.Msg {
+		return sensorWaitMsg{}
+	})
+}
+
+// handleSensorWait looks for sensors, refreshing and starting the regular
+// ticks once some appeared or the wait timed out
+func (m Monitor) handleSensorWait() (Monitor, tea.Cmd) {
+	if !m.waiting() {
+		return m, nil
+	}
+	present := sensorsPresent(m.waitRoot, m.providerEnabled("thermal"), m.providerEnabled("battery"))
+	if !present && m.clock.Now().Before(m.waitUntil) {
+		return m, m.pollSensors(sensorPollInterval)
+	}
+	if !present {
+		m.status = fmt.Sprintf("No sensors appeared within %s", m.sensorWait)
+	}
+	m.waitUntil = time.Time{}
+	m = m.Refresh()
+	return m, tea.Batch(m.tick(), m.emitSnapshot())
+}
+
+// waitingView replaces the readings while waiting for sensors
+func (m Monitor) waitingView() string {
+	left := max(m.waitUntil.Sub(m.clock.Now()), 0).Round(time.Second)
+	return fmt.Sprintf("Waiting for sensors… (%s left)", left)
+}
@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSensorWaitFlag(t *testing.T) {
+	tests := map[string]time.Duration{
+		"--wait-for-sensors":       DefaultSensorWait,
+		"--wait-for-sensors=10s":   10 * time.Second,
+		"--wait-for-sensors=false": 0,
+	}
+	for arg, want := range tests {
+		var wait SensorWait
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.Var(&wait, "wait-for-sensors", "")
+		if err := flags.Parse([]string{arg}); err != nil || time.Duration(wait) != want {
+			t.Errorf("%s: got %v, %v; expected %v", arg, time.Duration(wait), err, want)
+		}
+	}
+	var wait SensorWait
+	if err := wait.Set("soon"); err == nil {
+		t.Error("expected an error for a bad duration")
+	}
+}
+
+func TestWaitForSensors(t *testing.T) {
+	root := t.TempDir()
+	var slept time.Duration
+	sleep := func(d time.Duration) {
+		slept += d
+		if slept == 3*time.Second {
+			writeSysfs(t, root, map[string]string{
+				"class/hwmon/hwmon0/name":        "coretemp\n",
+				"class/hwmon/hwmon0/temp1_input": "45000\n",
+			})
+		}
+	}
+	if !waitForSensors(root, 30*time.Second, sleep) || slept != 3*time.Second {
+		t.Errorf("expected the sensor found after 3s, slept %v", slept)
+	}
+
+	slept = 0
+	if waitForSensors(t.TempDir(), 5*time.Second, sleep) || slept != 5*time.Second {
+		t.Errorf("expected to give up after 5s, slept %v", slept)
+	}
+}
+
+func TestMonitorWaitsForSensors(t *testing.T) {
+	root := t.TempDir()
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	m := NewMonitor(
+		WithClock(clock),
+		WithConfig("", Config{DisabledProviders: []string{"battery", "network"}}),
+		WithSensorWait(30*time.Second),
+	)
+	m.waitRoot = root
+	m.tempReader = newTemperatureReader(root, 0)
+	m.width, m.height = 80, 24
+	m.Init()
+
+	m = clock.advance(m, 2500*time.Millisecond)
+	if !m.waiting() || m.discovered {
+		t.Fatalf("expected the monitor still waiting without sensors, got %v %v %q", m.waiting(), m.discovered, m.status)
+	}
+	if view := m.View(); !strings.Contains(view, "Waiting for sensors… (28s left)") {
+		t.Errorf("expected the waiting message, got:\n%s", view)
+	}
+
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":        "coretemp\n",
+		"class/hwmon/hwmon0/temp1_input": "45000\n",
+		"class/hwmon/hwmon0/temp1_label": "Package id 0\n",
+	})
+	m = clock.advance(m, 500*time.Millisecond)
+	if m.waiting() || len(m.temperatureSensors) != 1 || !m.lastUpdate.Equal(clock.now) {
+		t.Fatalf("expected a refresh once the sensor appeared, got %+v", m.temperatureSensors)
+	}
+	m = clock.advance(m, DefaultInterval)
+	if !m.lastUpdate.Equal(clock.now) {
+		t.Error("expected the regular refreshes to follow")
+	}
+}
+
+func TestMonitorSensorWaitTimesOut(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	m := NewMonitor(
+		WithClock(clock),
+		WithConfig("", Config{DisabledProviders: []string{"battery", "network"}}),
+		WithSensorWait(5*time.Second),
+	)
+	m.waitRoot = t.TempDir()
+	m.tempReader = newTemperatureReader(m.waitRoot, 0)
+	m.Init()
+
+	m = clock.advance(m, 5*time.Second)
+	if m.waiting() || m.status != "No sensors appeared within 5s" {
+		t.Errorf("expected to give up after 5s, got %q", m.status)
+	}
+}
@@ -0,0 +1,133 @@
+package monitor
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Widgets are single panes of the monitor for embedding in another
+// bubbletea program. Each runs a Monitor limited to its section, so the
+// readers, thresholds, profiles and states are the Monitor's own, and draws
+// the pane RenderFull and RenderCompact draw for that section. They take
+// the Monitor's options: WithInterval, WithBatteryThresholds, WithConfig for
+// temperature overrides and offsets, WithViewMode(ViewCompact) for the
+// one-line form, WithTheme, WithClock and so on. The embedding program owns
+// the keys; widgets don't handle any.
+
+// widgetOf limits a monitor to one built-in section: the other providers,
+// the network rates and the scripts are turned off
+func widgetOf(provider string) Option {
+	return func(m *Monitor) {
+		m.disabledProviders = map[string]bool{"network": true}
+		for _, p := range providers {
+			if p.Name() != provider {
+				m.disabledProviders[p.Name()] = true
+			}
+		}
+		m.scriptDir = ""
+	}
+}
+
+// initWidget starts a widget's refreshes, without the footer countdown
+// and the SIGHUP reload of the full view
+func (m Monitor) initWidget() tea.Cmd {
+	return tea.Batch(m.start(), m.watchBattery())
+}
+
+// updateWidget handles the refresh messages of a widget. Other messages,
+// including those of other widgets, are left alone.
+func (m Monitor) updateWidget(msg tea.Msg) (Monitor, tea.Cmd) {
+	switch msg.(type) {
+	case tickMsg, sensorWaitMsg, preloadMsg, batteryEventMsg:
+		return m.Update(msg)
+	}
+	return m, nil
+}
+
+// BatteryWidget is the Battery pane as a model of its own
+type BatteryWidget struct {
+	mon Monitor
+}
+
+// NewBatteryWidget creates a battery pane configured with monitor options
+func NewBatteryWidget(opts ...Option) BatteryWidget {
+	return BatteryWidget{mon: NewMonitor(append(opts, widgetOf("battery"))...)}
+}
+
+// Init starts the refreshes
+func (w BatteryWidget) Init() tea.Cmd {
+	return w.mon.initWidget()
+}
+
+func (w BatteryWidget) Update(msg tea.Msg) (BatteryWidget, tea.Cmd) {
+	var cmd tea.Cmd
+	w.mon, cmd = w.mon.updateWidget(msg)
+	return w, cmd
+}
+
+// View draws the pane, or the compact view's battery segment in
+// ViewCompact. It has no readings until the first refresh.
+func (w BatteryWidget) View() string {
+	snap := w.mon.Snapshot()
+	view := w.mon.viewState(w.mon.clock.Now())
+	if w.mon.viewMode == ViewCompact {
+		return view.Numbers.localize(compactBattery(snap, w.mon.theme))
+	}
+	return strings.TrimSuffix(view.Numbers.localize(batteryPane(snap, w.mon.theme, view, view.Now)), "\n")
+}
+
+// Snapshot returns the readings of the last refresh
+func (w BatteryWidget) Snapshot() Snapshot {
+	return w.mon.Snapshot()
+}
+
+// TemperatureWidget is the Temperatures pane as a model of its own
+type TemperatureWidget struct {
+	mon Monitor
+}
+
+// NewTemperatureWidget creates a temperature pane configured with monitor
+// options
+func NewTemperatureWidget(opts ...Option) TemperatureWidget {
+	return TemperatureWidget{mon: NewMonitor(append(opts, widgetOf("thermal"))...)}
+}
+
+// Init starts the refreshes
+func (w TemperatureWidget) Init() tea.Cmd {
+	return w.mon.initWidget()
+}
+
+func (w TemperatureWidget) Update(msg tea.Msg) (TemperatureWidget, tea.Cmd) {
+	var cmd tea.Cmd
+	w.mon, cmd = w.mon.updateWidget(msg)
+	return w, cmd
+}
+
+// SetWidth sets the width the pane may take: long lists flow into columns
+// and the compact form keeps the hottest sensors that fit. Zero leaves the
+// width unlimited.
+func (w TemperatureWidget) SetWidth(width int) TemperatureWidget {
+	w.mon.width = width
+	return w
+}
+
+// View draws the pane, or the compact view's temperatures in ViewCompact.
+// It has no readings until the first refresh.
+func (w TemperatureWidget) View() string {
+	snap := w.mon.Snapshot()
+	view := w.mon.viewState(w.mon.clock.Now())
+	if w.mon.viewMode == ViewCompact {
+		return compactTemperatures(snap.Temperatures, view.Unit, view.Numbers, w.mon.theme, w.mon.width, w.mon.width > 0)
+	}
+	heading, lines := temperaturePane(snap, w.mon.theme, view, view.Now)
+	if w.mon.width > 0 {
+		lines = view.layout.flow(lines, w.mon.width)
+	}
+	return strings.TrimSuffix(heading+strings.Join(lines, "\n"), "\n")
+}
+
+// Snapshot returns the readings of the last refresh
+func (w TemperatureWidget) Snapshot() Snapshot {
+	return w.mon.Snapshot()
+}
@@ -0,0 +1,68 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestWidgetsDrawTheFullViewPanes(t *testing.T) {
+	full, fullClock := newClockedMonitor(2 * time.Second)
+	full = fullClock.advance(full, 5*time.Second)
+	full.width, full.height = 120, 40
+	view := ansi.Strip(full.View())
+
+	clock := &fakeClock{now: fullClock.now.Add(-5 * time.Second)}
+	battery := NewBatteryWidget(WithClock(clock), WithDemo(DefaultDemoSeed), WithInterval(2*time.Second))
+	temps := NewTemperatureWidget(WithClock(clock), WithDemo(DefaultDemoSeed), WithInterval(2*time.Second))
+	battery.Init()
+	temps.Init()
+	for range 5 {
+		clock.now = clock.now.Add(time.Second)
+		for len(clock.timers) > 0 && !clock.timers[0].at.After(clock.now) {
+			timer := clock.timers[0]
+			clock.timers = clock.timers[1:]
+			msg := timer.fn(timer.at)
+			battery, _ = battery.Update(msg)
+			temps, _ = temps.Update(msg)
+		}
+	}
+
+	if got, want := battery.Snapshot().Battery, full.Snapshot().Battery; got != want {
+		t.Fatalf("expected the widget to read the same battery, got %+v, expected %+v", got, want)
+	}
+	temps = temps.SetWidth(60)
+	for name, pane := range map[string]string{"battery": battery.View(), "temperatures": temps.View()} {
+		lines := strings.Split(ansi.Strip(pane), "\n")
+		if len(lines) < 3 {
+			t.Errorf("expected a %s pane, got:\n%s", name, pane)
+		}
+		for _, line := range lines {
+			if !strings.Contains(view, strings.TrimSpace(line)) {
+				t.Errorf("%s line %q is not in the full view:\n%s", name, line, view)
+			}
+		}
+	}
+}
+
+func TestWidgetCompactAndIgnoredMessages(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	battery := NewBatteryWidget(WithClock(clock), WithDemo(DefaultDemoSeed), WithViewMode(ViewCompact),
+		WithBatteryThresholds(BatteryThresholds{Warning: 95, Critical: 90}))
+	battery.Init()
+	if len(clock.timers) != 1 {
+		t.Fatalf("expected only the refresh tick, got %d timers", len(clock.timers))
+	}
+	battery, _ = battery.Update(clock.timers[0].fn(clock.now))
+	if view := ansi.Strip(battery.View()); !strings.HasPrefix(view, "🔋 ") || strings.Contains(view, "\n") {
+		t.Errorf("expected the compact battery segment, got %q", view)
+	}
+	if state := battery.Snapshot().BatteryCapacityState; state != StateCritical {
+		t.Errorf("expected the widget's thresholds to apply, got %v", state)
+	}
+	if _, cmd := battery.Update(countdownMsg{}); cmd != nil {
+		t.Error("expected widgets to leave the countdown alone")
+	}
+}
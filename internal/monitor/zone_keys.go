@@ -0,0 +1,48 @@
+package monitor
+
+import (
+	"cmp"
+	"path/filepath"
+	"strings"
+)
+
+// Boards often expose several thermal zones of one type, such as three
+// "acpitz". They keep that name on screen, where their paths tell them
+// apart, but get a unique key of the type and zone number, "acpitz@zone2",
+// under which the history, overrides, offsets and mutes know them. Config
+// entries may name either: the type applies to every zone sharing it.
+
+// zoneKeys gives the thermal zones sharing a type their keys
+func zoneKeys(zones []TemperatureSensor) {
+	counts := make(map[string]int)
+	for _, zone := range zones {
+		counts[zone.Name]++
+	}
+	for i := range zones {
+		if counts[zones[i].Name] > 1 {
+			zone := strings.TrimPrefix(filepath.Base(zones[i].Path), "thermal_")
+			zones[i].Key = zones[i].Name + "@" + zone
+		}
+	}
+}
+
+// key identifies a temperature: its Key, or its Name when that is unique
+func (t TemperatureSensor) key() string {
+	return cmp.Or(t.Key, t.Name)
+}
+
+// lookupSensor returns the entry for a temperature, by key first, then by
+// name
+func lookupSensor[V any](entries map[string]V, sensor TemperatureSensor) (V, bool) {
+	if v, ok := entries[sensor.key()]; ok {
+		return v, true
+	}
+	v, ok := entries[sensor.Name]
+	return v, ok
+}
+
+// matchSensor reports whether a glob matches a temperature's key, name or
+// value file path
+func matchSensor(pattern string, sensor TemperatureSensor) bool {
+	return match(pattern, sensor.key()) || match(pattern, sensor.Name) || match(pattern, sensor.Path)
+}
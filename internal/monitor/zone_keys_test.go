@@ -0,0 +1,109 @@
+package monitor
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+var acpiZonesRoot = filepath.Join("testdata", "machines", "acpi-zones")
+
+// zoneMonitor refreshes a monitor reading the acpi-zones fixture, three
+// "acpitz" zones and an x86_pkg_temp
+func zoneMonitor(t *testing.T, cfg Config) Monitor {
+	t.Helper()
+	cfg.DisabledProviders = []string{"battery", "network"}
+	m := NewMonitor(WithConfig("", cfg))
+	m.tempReader = newTemperatureReader(acpiZonesRoot, 0)
+	return m.Refresh()
+}
+
+// zoneByKey returns the displayed temperature with the key
+func zoneByKey(t *testing.T, m Monitor, key string) TemperatureSensor {
+	t.Helper()
+	for _, sensor := range m.temperatureSensors {
+		if sensor.key() == key {
+			return sensor
+		}
+	}
+	t.Fatalf("no temperature %q in %+v", key, m.temperatureSensors)
+	return TemperatureSensor{}
+}
+
+func TestZoneKeys(t *testing.T) {
+	var keys []string
+	for _, sensor := range readTemperatures(acpiZonesRoot) {
+		if sensor.Name != "acpitz" && sensor.Key != "" {
+			t.Errorf("expected no key for the unique %q, got %q", sensor.Name, sensor.Key)
+		}
+		keys = append(keys, sensor.key())
+	}
+	want := []string{"acpitz@zone0", "acpitz@zone1", "acpitz@zone2", "x86_pkg_temp"}
+	if len(keys) != len(want) {
+		t.Fatalf("expected keys %v, got %v", want, keys)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("expected keys %v, got %v", want, keys)
+			break
+		}
+	}
+}
+
+func TestOverrideByZoneKey(t *testing.T) {
+	m := zoneMonitor(t, Config{Overrides: map[string]ThresholdOverride{
+		"acpitz@zone2": {High: 40, Critical: 50},
+	}})
+	if zone := zoneByKey(t, m, "acpitz@zone2"); zone.High != 40 || zone.Critical != 50 || zone.State() != StateWarning {
+		t.Errorf("expected the override on zone2, got %+v", zone)
+	}
+	for _, key := range []string{"acpitz@zone0", "acpitz@zone1"} {
+		if zone := zoneByKey(t, m, key); zone.High != 119 || zone.State() != StateOK {
+			t.Errorf("expected %s left alone, got %+v", key, zone)
+		}
+	}
+	if !m.isOverridden(zoneByKey(t, m, "acpitz@zone2")) || m.isOverridden(zoneByKey(t, m, "acpitz@zone0")) {
+		t.Error("expected only zone2 marked overridden")
+	}
+
+	// The events and the history tell the zones apart
+	if len(m.history) != 1 || m.history[0].Sensor != "acpitz@zone2" {
+		t.Errorf("expected one event for acpitz@zone2, got %+v", m.history)
+	}
+}
+
+func TestOverrideByZoneName(t *testing.T) {
+	m := zoneMonitor(t, Config{Overrides: map[string]ThresholdOverride{
+		"acpitz":       {High: 60, Critical: 70},
+		"acpitz@zone1": {High: 20, Critical: 90},
+	}})
+	for key, high := range map[string]float64{"acpitz@zone0": 60, "acpitz@zone1": 20, "acpitz@zone2": 60, "x86_pkg_temp": 100} {
+		if zone := zoneByKey(t, m, key); zone.High != high {
+			t.Errorf("expected %s's high at %v, the key winning over the name, got %v", key, high, zone.High)
+		}
+	}
+}
+
+func TestMuteAndOffsetByZoneKey(t *testing.T) {
+	m := zoneMonitor(t, Config{
+		Mute:    []string{"acpitz@zone1"},
+		Offsets: map[string]float64{"acpitz@zone0": -5, "x86_*": 1},
+	})
+	for key, muted := range map[string]bool{"acpitz@zone0": false, "acpitz@zone1": true, "acpitz@zone2": false} {
+		if zone := zoneByKey(t, m, key); zone.Muted != muted {
+			t.Errorf("expected %s muted %v", key, muted)
+		}
+	}
+	for key, value := range map[string]float64{"acpitz@zone0": 22.8, "acpitz@zone1": 29.8, "x86_pkg_temp": 53} {
+		if zone := zoneByKey(t, m, key); zone.Value != value {
+			t.Errorf("expected %s at %v, got %v", key, value, zone.Value)
+		}
+	}
+
+	// Muting by name mutes every zone of the type
+	m = zoneMonitor(t, Config{Mute: []string{"acpi*"}})
+	for _, key := range []string{"acpitz@zone0", "acpitz@zone1", "acpitz@zone2"} {
+		if !zoneByKey(t, m, key).Muted {
+			t.Errorf("expected %s muted by its name", key)
+		}
+	}
+}
@@ -1,31 +1,339 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/wallacegibbon/sysfs-monitor-tui/internal/monitor"
+	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	p := tea.NewProgram(initialModel())
-	if _, err := p.Run(); err != nil {
+	heldFiles := flag.Int("held-files", 0, "keep up to N temperature files open between refreshes (0 disables)")
+	configPath := flag.String("config", monitor.DefaultConfigPath(), "path to the config file")
+	watchBattery := flag.Bool("watch-battery", false, "refresh the battery immediately on kernel power supply events")
+	enableControl := flag.Bool("enable-control", false, "allow keybindings that write to sysfs (brightness, platform profile, intrusion alarm)")
+	fresh := flag.Bool("fresh", false, "start with default UI preferences instead of restoring the saved ones")
+	interval := flag.Duration("interval", monitor.DefaultInterval, "time between sensor refreshes")
+	hostname := flag.String("hostname", "", "host name labeling snapshots, events and metrics (default: the system host name)")
+	prometheusAddr := flag.String("prometheus", "", "serve Prometheus metrics on ADDR (e.g. :9101) at /metrics")
+	dbus := flag.Bool("dbus", false, "serve the readings on the session D-Bus as "+monitor.DBusName)
+	self := flag.Bool("self", false, "show the monitor's own memory, open files and goroutines")
+	eventsPath := flag.String("events", "", "write state transitions as JSON lines to a file or FIFO (\"-\" for stdout without the TUI)")
+	scriptDir := flag.String("scripts", monitor.DefaultScriptDir(), "directory of sensor scripts (empty disables them)")
+	history := flag.Bool("history", false, "append readings to the history file read by sysfs-check report")
+	demo := flag.Bool("demo", os.Getenv("SYSFS_MONITOR_DEMO") != "", "show a synthetic dataset instead of reading sysfs (also enabled by SYSFS_MONITOR_DEMO)")
+	title := flag.String("title", monitor.DefaultTitle, "title of the full view (empty hides it, leaving its lines to the readings)")
+	locale := flag.String("locale", "", "locale of displayed numbers, e.g. de_DE for \"64,5 °C\" (default: LC_ALL, LC_NUMERIC or LANG)")
+	idleInterval := flag.Duration("idle-interval", monitor.DefaultIdleInterval, "time between refreshes while the terminal doesn't have focus (0 disables, for terminals that don't report focus)")
+	preload := flag.Bool("preload", false, "discover the sensors and read them once before starting, waiting up to 2s, so the first frame has readings")
+	var sensorWait monitor.SensorWait
+	flag.Var(&sensorWait, "wait-for-sensors", "wait up to 30s (or =DURATION) for a temperature or battery to appear before the first refresh, for starts early in boot")
+	demoSeed := flag.Int64("demo-seed", demoSeedFromEnv(), "seed of the --demo dataset; SYSFS_MONITOR_DEMO may also hold one")
+	singleInstance := flag.String("single-instance", "", "hold a lock so that one instance reads sysfs, and when another holds it: fail, read-only (no history or sockets) or client (show its readings)")
+	accessible := flag.Bool("accessible", false, "show one reading per line with its state in words, without emoji, boxes or colors, for screen readers; printed after every refresh when stdout isn't a terminal")
+	flag.Parse()
+
+	cfg, err := monitor.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Printf("Invalid config file %s: %v\n", *configPath, err)
+		os.Exit(1)
+	}
+	// The file's interval applies unless --interval is given
+	if d := cfg.RefreshInterval(); d > 0 && !flagGiven("interval") {
+		*interval = d
+	}
+
+	// Only the lock holder writes the history and binds sockets
+	var lock *monitor.InstanceLock
+	var client *monitor.InstanceClient
+	exclusive := true
+	if *singleInstance != "" {
+		lock, client = acquireInstance(*singleInstance)
+		defer lock.Release()
+		exclusive = lock != nil
+	}
+
+	opts := []monitor.Option{
+		monitor.WithConfig(*configPath, cfg),
+		monitor.WithUIState(monitor.DefaultUIStatePath(), *fresh),
+		monitor.WithInterval(*interval),
+		monitor.WithScriptDir(*scriptDir),
+		monitor.WithTitle(*title),
+	}
+	numbers := monitor.NumberFormatFromEnv()
+	if *locale != "" {
+		numbers = monitor.ParseNumberFormat(*locale)
+	}
+	opts = append(opts, monitor.WithNumberFormat(numbers))
+	if *hostname != "" {
+		opts = append(opts, monitor.WithHostname(*hostname))
+	}
+	if *heldFiles > 0 {
+		opts = append(opts, monitor.WithHeldFiles(*heldFiles))
+	}
+	if *enableControl {
+		opts = append(opts, monitor.WithControl())
+	}
+	if *watchBattery {
+		opts = append(opts, monitor.WithBatteryWatch())
+	}
+	if *self {
+		opts = append(opts, monitor.WithSelfSensors(cfg.SelfRSSLimitMB<<20))
+	}
+	if *history && exclusive {
+		opts = append(opts, monitor.WithHistoryFile(monitor.DefaultHistoryPath()))
+	}
+	if client != nil {
+		opts = append(opts, monitor.WithInstanceClient(client))
+	}
+	if *demo {
+		opts = append(opts, monitor.WithDemo(*demoSeed))
+	}
+
+	if *eventsPath != "" && *eventsPath != "-" {
+		// Opening a FIFO blocks until a reader attaches
+		f, err := os.OpenFile(*eventsPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+		if err != nil {
+			fmt.Printf("Cannot open event stream: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		opts = append(opts, monitor.WithEventWriter(f))
+	}
+	var services readingServices
+	if exclusive {
+		services = readingServices{prometheusAddr: *prometheusAddr, dbus: *dbus}
+	}
+
+	if *eventsPath == "-" {
+		if sensorWait > 0 && !*demo && client == nil {
+			monitor.WaitForSensors(time.Duration(sensorWait))
+		}
+		runEvents(*interval, lock, services, nil, append(opts, monitor.WithEventWriter(os.Stdout))...)
+		return
+	}
+	if *accessible && !stdoutIsTerminal() {
+		if sensorWait > 0 && !*demo && client == nil {
+			monitor.WaitForSensors(time.Duration(sensorWait))
+		}
+		runEvents(*interval, lock, services, func(m monitor.Monitor) {
+			fmt.Printf("%s\n\n", m.AccessibleView())
+		}, opts...)
+		return
+	}
+	if *accessible {
+		opts = append(opts, monitor.WithAccessible())
+	}
+
+	if sensorWait > 0 {
+		opts = append(opts, monitor.WithSensorWait(time.Duration(sensorWait)))
+	}
+	if *preload {
+		opts = append(opts, monitor.WithSynchronousFirstRefresh())
+	}
+	var programOpts []tea.ProgramOption
+	if *idleInterval > 0 {
+		opts = append(opts, monitor.WithIdleInterval(*idleInterval))
+		programOpts = append(programOpts, tea.WithReportFocus())
+	}
+	m := initialModel(append(opts, monitor.WithHangupReload())...)
+	// Listen before starting the TUI so errors can still be printed
+	m.dbus = services.start(m.latestSnapshot)
+	if m.dbus != nil {
+		defer m.dbus.Close()
+	}
+	if lock != nil {
+		m.instance = monitor.NewInstanceServer(m.latestSnapshot)
+		if err := m.instance.Listen(lock.SocketPath()); err != nil {
+			fmt.Printf("Cannot serve other instances: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	p := tea.NewProgram(m, programOpts...)
+	final, err := p.Run()
+	if fm, ok := final.(model); ok {
+		fm.mon.SaveUIState()
+	}
+	m.mon.Close()
+	// os.Exit skips the deferred calls; the kernel would drop the lock
+	// anyway, but not the socket
+	if m.instance != nil {
+		m.instance.Close()
+	}
+	lock.Release()
+	if err != nil {
 		fmt.Printf("Alas, there's been an error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// stdoutIsTerminal reports whether the output goes to a terminal rather
+// than a pipe or a file
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// demoSeedFromEnv returns the seed in SYSFS_MONITOR_DEMO when it holds a
+// number rather than just enabling the demo
+func demoSeedFromEnv() int64 {
+	if seed, err := strconv.ParseInt(os.Getenv("SYSFS_MONITOR_DEMO"), 10, 64); err == nil {
+		return seed
+	}
+	return monitor.DefaultDemoSeed
+}
+
+// readingServices are the --prometheus and --dbus services, set only for
+// the instance holding the lock
+type readingServices struct {
+	prometheusAddr string
+	dbus           bool
+}
+
+// start serves the snapshots latest returns, exiting when a service can't
+// start. It returns the D-Bus service to publish each refresh to, if any.
+func (s readingServices) start(latest func() (monitor.Snapshot, bool)) *monitor.DBusService {
+	if s.prometheusAddr != "" {
+		ln, err := net.Listen("tcp", s.prometheusAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot serve metrics: %v\n", err)
+			os.Exit(1)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", monitor.PrometheusHandler(latest))
+		go http.Serve(ln, mux)
+	}
+	if !s.dbus {
+		return nil
+	}
+	service := monitor.NewDBusService(latest, monitor.DefaultDBusSensors)
+	if err := service.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot serve on the session bus: %v\n", err)
+		os.Exit(1)
+	}
+	return service
+}
+
+// runEvents refreshes the sensors every interval without the TUI, for
+// scripts consuming the event stream, calling print after each refresh
+// unless nil. It serves the snapshots to services and, holding the
+// instance lock, to the other instances too.
+func runEvents(interval time.Duration, lock *monitor.InstanceLock, services readingServices, print func(monitor.Monitor), opts ...monitor.Option) {
+	m := monitor.NewMonitor(opts...)
+	defer m.Close()
+	var latest atomic.Pointer[monitor.Snapshot]
+	latestSnapshot := func() (monitor.Snapshot, bool) {
+		if snap := latest.Load(); snap != nil {
+			return *snap, true
+		}
+		return monitor.Snapshot{}, false
+	}
+	var server *monitor.InstanceServer
+	if lock != nil {
+		server = monitor.NewInstanceServer(latestSnapshot)
+		if err := server.Listen(lock.SocketPath()); err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot serve other instances: %v\n", err)
+			os.Exit(1)
+		}
+		defer server.Close()
+	}
+	bus := services.start(latestSnapshot)
+	if bus != nil {
+		defer bus.Close()
+	}
+	for {
+		m = m.Refresh()
+		if print != nil {
+			print(m)
+		}
+		snap := m.Snapshot()
+		latest.Store(&snap)
+		if server != nil {
+			server.Publish(snap)
+		}
+		if bus != nil {
+			bus.Publish(snap)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// acquireInstance applies --single-instance: it returns the held lock, or
+// the client of the running instance in the client mode, or neither in the
+// read-only mode. It exits in the fail mode or when the mode is unknown.
+func acquireInstance(mode string) (*monitor.InstanceLock, *monitor.InstanceClient) {
+	switch mode {
+	case "fail", "read-only", "client":
+	default:
+		fmt.Printf("Invalid --single-instance %q: use fail, read-only or client\n", mode)
+		os.Exit(2)
+	}
+	dir := monitor.DefaultInstanceDir()
+	lock, err := monitor.AcquireInstanceLock(dir)
+	switch {
+	case err == nil:
+		return lock, nil
+	case !errors.Is(err, monitor.ErrInstanceRunning):
+		fmt.Printf("Cannot take the instance lock: %v\n", err)
+		os.Exit(1)
+	case mode == "read-only":
+		return nil, nil
+	case mode == "client":
+		client, err := monitor.DialInstance(monitor.InstanceSocketPath(dir))
+		if err != nil {
+			fmt.Printf("Cannot connect to the running instance: %v\n", err)
+			os.Exit(1)
+		}
+		return nil, client
+	}
+	fmt.Printf("%v: stop it first, or pass --single-instance=read-only or =client\n", err)
+	os.Exit(1)
+	return nil, nil
+}
+
 type model struct {
 	mon monitor.Monitor
+	// latest is shared with the metrics handler, which runs outside the
+	// program's goroutine
+	latest *atomic.Pointer[monitor.Snapshot]
+	// dbus is told about every refresh when --dbus is set
+	dbus *monitor.DBusService
+	// instance streams every refresh to the clients of --single-instance
+	instance *monitor.InstanceServer
 }
 
-func initialModel() model {
+// flagGiven reports whether the named flag was set on the command line
+func flagGiven(name string) bool {
+	given := false
+	flag.Visit(func(f *flag.Flag) {
+		given = given || f.Name == name
+	})
+	return given
+}
+
+func initialModel(opts ...monitor.Option) model {
 	return model{
-		mon: monitor.NewMonitor(),
+		mon:    monitor.NewMonitor(opts...),
+		latest: new(atomic.Pointer[monitor.Snapshot]),
 	}
 }
 
+func (m model) latestSnapshot() (monitor.Snapshot, bool) {
+	snap := m.latest.Load()
+	if snap == nil {
+		return monitor.Snapshot{}, false
+	}
+	return *snap, true
+}
+
 func (m model) Init() tea.Cmd {
 	return m.mon.Init()
 }
@@ -34,9 +342,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "ctrl+c", "q":
+		case "ctrl+c":
 			return m, tea.Quit
+		case "q":
+			// Typed into the threshold editor, which has focus
+			if !m.mon.Editing() {
+				return m, tea.Quit
+			}
+		}
+	case monitor.SnapshotMsg:
+		snap := monitor.Snapshot(msg)
+		m.latest.Store(&snap)
+		if m.dbus != nil {
+			m.dbus.Publish(snap)
+		}
+		if m.instance != nil {
+			m.instance.Publish(snap)
 		}
+		return m, nil
 	}
 	updatedMonitor, cmd := m.mon.Update(msg)
 	m.mon = updatedMonitor